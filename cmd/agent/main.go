@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/kubeadmiral/pkg/agent"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/federatedcluster"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+	"github.com/kubewharf/kubeadmiral/pkg/util/signals"
+)
+
+func main() {
+	var (
+		hostKubeConfig, memberKubeConfig string
+		memberMaster                     string
+		clusterName                      string
+		fedSystemNamespace               string
+		heartbeatPeriod                  time.Duration
+		workerCount                      int
+		kubeAPIQPS                       float32
+		kubeAPIBurst                     int
+	)
+
+	flags := pflag.NewFlagSet(os.Args[0], pflag.ExitOnError)
+	flags.StringVar(&hostKubeConfig, "host-kubeconfig", "", "The path of the kubeconfig for the KubeAdmiral control plane.")
+	flags.StringVar(&memberMaster, "master", "", "The address of the member Kubernetes cluster.")
+	flags.StringVar(
+		&memberKubeConfig,
+		"kubeconfig",
+		"",
+		"The path of the kubeconfig for the member Kubernetes cluster. The in-cluster config is used if unset.",
+	)
+	flags.StringVar(&clusterName, "cluster-name", "", "The name of the FederatedCluster of the member cluster.")
+	flags.StringVar(
+		&fedSystemNamespace,
+		"fed-system-namespace",
+		common.DefaultFedSystemNamespace,
+		"The fed system namespace of the KubeAdmiral control plane.",
+	)
+	flags.DurationVar(
+		&heartbeatPeriod,
+		"heartbeat-period",
+		federatedcluster.DefaultAgentHeartbeatPeriod,
+		"The period of reporting the cluster status.",
+	)
+	flags.IntVar(&workerCount, "worker-count", 5, "The number of workers applying objects of each type.")
+	flags.Float32Var(&kubeAPIQPS, "kube-api-qps", 50, "The maximum QPS from each Kubernetes client.")
+	flags.IntVar(&kubeAPIBurst, "kube-api-burst", 100, "The maximum burst from each Kubernetes client.")
+
+	flags.Parse(os.Args[1:])
+	flags.VisitAll(func(f *pflag.Flag) {
+		klog.Infof("Flag: %v=%v", f.Name, f.Value.String())
+	})
+
+	if len(clusterName) == 0 {
+		klog.Fatal("--cluster-name is required")
+	}
+	if len(hostKubeConfig) == 0 {
+		klog.Fatal("--host-kubeconfig is required")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signals.SetupSignalHandler(cancel)
+
+	hostConfig, err := clientcmd.BuildConfigFromFlags("", hostKubeConfig)
+	if err != nil {
+		klog.Fatalf("Failed to create host rest config: %v", err)
+	}
+	hostConfig.QPS = kubeAPIQPS
+	hostConfig.Burst = kubeAPIBurst
+
+	memberConfig, err := clientcmd.BuildConfigFromFlags(memberMaster, memberKubeConfig)
+	if err != nil {
+		klog.Fatalf("Failed to create member rest config: %v", err)
+	}
+	memberConfig.QPS = kubeAPIQPS
+	memberConfig.Burst = kubeAPIBurst
+
+	a, err := agent.NewAgent(
+		klog.Background(),
+		hostConfig,
+		memberConfig,
+		clusterName,
+		fedSystemNamespace,
+		heartbeatPeriod,
+		workerCount,
+		stats.NewMock("", agent.AgentName, false),
+	)
+	if err != nil {
+		klog.Fatalf("Failed to create agent: %v", err)
+	}
+
+	a.Run(ctx)
+}
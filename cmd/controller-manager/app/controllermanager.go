@@ -22,6 +22,9 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/klog/v2"
@@ -33,6 +36,9 @@ import (
 	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/healthcheck"
 	fedleaderelection "github.com/kubewharf/kubeadmiral/pkg/controllermanager/leaderelection"
 	controllercontext "github.com/kubewharf/kubeadmiral/pkg/controllers/context"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+	"github.com/kubewharf/kubeadmiral/pkg/util/delegatedauth"
 )
 
 const (
@@ -42,6 +48,9 @@ const (
 	FollowerControllerName         = "follower"
 )
 
+// MetricsNamespace is the prefix of all metrics exported by the controller manager.
+const MetricsNamespace = "kubeadmiral"
+
 var knownControllers = map[string]controllermanager.StartControllerFunc{
 	FederatedClusterControllerName: startFederatedClusterController,
 	TypeConfigControllerName:       startTypeConfigController,
@@ -53,7 +62,14 @@ var controllersDisabledByDefault = sets.New(MonitorControllerName)
 
 // Run starts the controller manager according to the given options.
 func Run(ctx context.Context, opts *options.Options) {
-	controllerCtx, err := createControllerContext(opts)
+	metricsRegistry := prometheus.NewRegistry()
+	metricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	metrics := stats.NewPrometheus(MetricsNamespace, metricsRegistry)
+
+	controllerCtx, err := createControllerContext(opts, metrics)
 	if err != nil {
 		klog.Fatalf("Error creating controller context: %v", err)
 	}
@@ -83,21 +99,50 @@ func Run(ctx context.Context, opts *options.Options) {
 		}
 
 		controllerCtx.StartFactories(ctx)
+		go controllerCtx.RolloutAuditSink.Run(ctx)
 
 		<-ctx.Done()
 	}
 
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+	mux.Handle("/", healthCheckHandler)
+
 	go func() {
 		server := &http.Server{
 			Addr:              fmt.Sprintf("0.0.0.0:%d", opts.Port),
 			ReadHeaderTimeout: time.Second * 3,
-			Handler:           healthCheckHandler,
+			Handler:           mux,
 		}
 		if err := server.ListenAndServe(); err != nil {
-			klog.Fatalf("Failed to start health check server: %v", err)
+			klog.Fatalf("Failed to start health check and metrics server: %v", err)
 		}
 	}()
 
+	if len(opts.SchedulerSimulationBindAddress) > 0 {
+		if len(opts.SchedulerSimulationTLSCertFile) == 0 || len(opts.SchedulerSimulationTLSKeyFile) == 0 {
+			klog.Fatalf("The TLS certificate and key files are required to serve scheduler simulation requests")
+		}
+
+		simulationMux := http.NewServeMux()
+		simulationMux.Handle(
+			scheduler.SimulationPathPrefix,
+			delegatedauth.WithAuth(schedulerSimulationHandler, controllerCtx.KubeClientset),
+		)
+
+		go func() {
+			server := &http.Server{
+				Addr:              opts.SchedulerSimulationBindAddress,
+				ReadHeaderTimeout: time.Second * 3,
+				Handler:           simulationMux,
+			}
+			err := server.ListenAndServeTLS(opts.SchedulerSimulationTLSCertFile, opts.SchedulerSimulationTLSKeyFile)
+			if err != nil {
+				klog.Fatalf("Failed to start scheduler simulation server: %v", err)
+			}
+		}()
+	}
+
 	if opts.EnableLeaderElect {
 		healthzAdaptor := leaderelection.NewLeaderHealthzAdaptor(time.Second * 20)
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 
+	"k8s.io/client-go/informers"
 	"k8s.io/klog/v2"
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
@@ -42,12 +43,18 @@ func startFederatedClusterController(ctx context.Context, controllerCtx *control
 		controllerCtx.FedClientset,
 		controllerCtx.KubeClientset,
 		controllerCtx.FedInformerFactory.Core().V1alpha1().FederatedClusters(),
+		controllerCtx.FedInformerFactory.Core().V1alpha1().FederatedTypeConfigs(),
+		controllerCtx.DynamicInformerFactory,
 		controllerCtx.FederatedClientFactory,
 		controllerCtx.Metrics,
 		controllerCtx.FedSystemNamespace,
 		controllerCtx.RestConfig,
+		controllerCtx.ClusterAuthConfig,
 		controllerCtx.WorkerCount,
 		controllerCtx.ComponentConfig.ClusterJoinTimeout,
+		controllerCtx.ComponentConfig.ClusterUnreachableTaintGracePeriod,
+		controllerCtx.ComponentConfig.ClusterCredentialsExpiryWarningPeriod,
+		controllerCtx.ComponentConfig.ClusterAgentHeartbeatTimeout,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error creating federated cluster controller: %w", err)
@@ -127,7 +134,9 @@ func controllerConfigFromControllerContext(controllerCtx *controllercontext.Cont
 		WorkerCount:                           controllerCtx.WorkerCount,
 		NamespaceAutoPropagationExcludeRegexp: controllerCtx.ComponentConfig.NSAutoPropExcludeRegexp,
 		CreateCrdForFtcs:                      controllerCtx.ComponentConfig.FederatedTypeConfigCreateCRDsForFTCs,
+		ClusterAuthConfig:                     controllerCtx.ClusterAuthConfig,
 		Metrics:                               controllerCtx.Metrics,
+		RolloutAuditSink:                      controllerCtx.RolloutAuditSink,
 	}
 }
 
@@ -139,6 +148,11 @@ func startGlobalScheduler(
 	federatedAPIResource := typeConfig.GetFederatedType()
 	federatedGVR := schemautil.APIResourceToGVR(&federatedAPIResource)
 
+	var federatedStatusInformer informers.GenericInformer
+	if statusAPIResource := typeConfig.GetStatusType(); statusAPIResource != nil && typeConfig.GetStatusEnabled() {
+		federatedStatusInformer = controllerCtx.DynamicInformerFactory.ForResource(schemautil.APIResourceToGVR(statusAPIResource))
+	}
+
 	scheduler, err := scheduler.NewScheduler(
 		klog.FromContext(ctx),
 		typeConfig,
@@ -146,6 +160,7 @@ func startGlobalScheduler(
 		controllerCtx.FedClientset,
 		controllerCtx.DynamicClientset,
 		controllerCtx.DynamicInformerFactory.ForResource(federatedGVR),
+		federatedStatusInformer,
 		controllerCtx.FedInformerFactory.Core().V1alpha1().PropagationPolicies(),
 		controllerCtx.FedInformerFactory.Core().V1alpha1().ClusterPropagationPolicies(),
 		controllerCtx.FedInformerFactory.Core().V1alpha1().FederatedClusters(),
@@ -160,6 +175,12 @@ func startGlobalScheduler(
 
 	go scheduler.Run(ctx)
 
+	schedulerSimulationHandler.Register(typeConfig.Name, scheduler)
+	go func() {
+		<-ctx.Done()
+		schedulerSimulationHandler.Unregister(typeConfig.Name)
+	}()
+
 	return scheduler, nil
 }
 
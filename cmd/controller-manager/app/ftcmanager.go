@@ -33,6 +33,7 @@ import (
 	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/healthcheck"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 	controllercontext "github.com/kubewharf/kubeadmiral/pkg/controllers/context"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
@@ -60,6 +61,9 @@ var knownFTCSubControllers = map[string]controllermanager.FTCSubControllerInitFu
 	},
 }
 
+// schedulerSimulationHandler serves what-if scheduling requests for the running global schedulers.
+var schedulerSimulationHandler = scheduler.NewSimulationHandler()
+
 type FederatedTypeConfigManager struct {
 	informer fedcorev1a1informers.FederatedTypeConfigInformer
 	handle   cache.ResourceEventHandlerRegistration
@@ -25,6 +25,8 @@ import (
 
 	"github.com/spf13/pflag"
 	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/kubeadmiral/pkg/rolloutaudit"
 )
 
 const (
@@ -50,12 +52,26 @@ type Options struct {
 	LogVerbosity    int
 	KlogVerbosity   int
 
-	NSAutoPropExcludeRegexp string
-	CreateCRDsForFTCs       bool
-	ClusterJoinTimeout      time.Duration
+	NSAutoPropExcludeRegexp               string
+	CreateCRDsForFTCs                     bool
+	ClusterJoinTimeout                    time.Duration
+	ClusterUnreachableTaintGracePeriod    time.Duration
+	ClusterCredentialsExpiryWarningPeriod time.Duration
+	ClusterAgentHeartbeatTimeout          time.Duration
+	ClusterAuthExecPolicyFile             string
 
 	MaxPodListers    int64
 	EnablePodPruning bool
+
+	RolloutAuditSinks                        []string
+	RolloutAuditElasticsearchURL             string
+	RolloutAuditElasticsearchIndex           string
+	RolloutAuditElasticsearchCredentialsFile string
+	RolloutAuditFile                         string
+
+	SchedulerSimulationBindAddress string
+	SchedulerSimulationTLSCertFile string
+	SchedulerSimulationTLSKeyFile  string
 }
 
 func NewOptions() *Options {
@@ -66,7 +82,7 @@ func NewOptions() *Options {
 
 //nolint:lll
 func (o *Options) AddFlags(flags *pflag.FlagSet, allControllers []string, disabledByDefaultControllers []string) {
-	flags.IntVar(&o.Port, "port", DefaultPort, "The port for kubeadmiral controller-manager to listen on.")
+	flags.IntVar(&o.Port, "port", DefaultPort, "The port for kubeadmiral controller-manager to serve health checks and metrics on.")
 
 	defaultControllers := []string{"*"}
 	for _, c := range disabledByDefaultControllers {
@@ -111,11 +127,97 @@ func (o *Options) AddFlags(flags *pflag.FlagSet, allControllers []string, disabl
 		time.Minute*10,
 		"The maximum amount of time to wait for a new cluster to join the federation before timing out.",
 	)
+	flags.DurationVar(
+		&o.ClusterUnreachableTaintGracePeriod,
+		"cluster-unreachable-taint-grace-period",
+		0,
+		"The amount of time a cluster must be unreachable before it is tainted with NoExecute, which evicts the "+
+			"federated objects that do not tolerate the taint from the cluster. The taint is disabled if 0.",
+	)
+	flags.DurationVar(
+		&o.ClusterCredentialsExpiryWarningPeriod,
+		"cluster-credentials-expiry-warning-period",
+		time.Hour*24*7,
+		"The amount of time before the credentials of a cluster expire in which the CredentialsExpiring condition "+
+			"of the cluster is set to true.",
+	)
+	flags.DurationVar(
+		&o.ClusterAgentHeartbeatTimeout,
+		"cluster-agent-heartbeat-timeout",
+		3*time.Minute,
+		"The amount of time after the last heartbeat of the agent of a cluster in Pull mode before the cluster is "+
+			"considered offline. It must be at least twice the heartbeat period of the agents, and is extended "+
+			"for agents reporting a longer heartbeat period.",
+	)
+	flags.StringVar(
+		&o.ClusterAuthExecPolicyFile,
+		"cluster-auth-exec-policy-file",
+		"",
+		"The path of a YAML file listing the commands that the exec plugins configured in the auth of clusters may run, "+
+			"together with the patterns their args must match and the environment variables they may set. Exec plugins "+
+			"are rejected if empty.",
+	)
 
 	flags.Int64Var(&o.MaxPodListers, "max-pod-listers", 0, "The maximum number of concurrent pod listing requests to member clusters. "+
 		"A non-positive number means unlimited, but may increase the instantaneous memory usage.")
 	flags.BoolVar(&o.EnablePodPruning, "enable-pod-pruning", false, "Enable pod pruning for pod informer. "+
 		"Enabling this can reduce memory usage of the pod informer, but will disable pod propagation.")
+
+	flags.StringSliceVar(
+		&o.RolloutAuditSinks,
+		"rollout-audit-sinks",
+		nil,
+		"A list of sinks that the rollout plans of federated objects are recorded to. "+
+			"Valid sinks are 'elasticsearch', 'file' and 'event'.",
+	)
+	flags.StringVar(
+		&o.RolloutAuditElasticsearchURL,
+		"rollout-audit-elasticsearch-url",
+		"",
+		"The URL of the Elasticsearch or OpenSearch cluster that rollout plans are written to by the elasticsearch "+
+			"sink. Credentials must not be included in the URL; use --rollout-audit-elasticsearch-credentials-file instead.",
+	)
+	flags.StringVar(
+		&o.RolloutAuditElasticsearchIndex,
+		"rollout-audit-elasticsearch-index",
+		rolloutaudit.DefaultElasticsearchIndex,
+		"The index that rollout plans are written to by the elasticsearch sink.",
+	)
+	flags.StringVar(
+		&o.RolloutAuditElasticsearchCredentialsFile,
+		"rollout-audit-elasticsearch-credentials-file",
+		"",
+		"The path of a file containing the basic auth credentials of the Elasticsearch or OpenSearch cluster "+
+			"in the format 'username:password'.",
+	)
+	flags.StringVar(
+		&o.RolloutAuditFile,
+		"rollout-audit-file",
+		"",
+		"The path of the file that rollout plans are appended to as JSON lines by the file sink.",
+	)
+
+	flags.StringVar(
+		&o.SchedulerSimulationBindAddress,
+		"scheduler-simulation-bind-address",
+		"",
+		"The address to serve what-if scheduling simulation requests on, e.g. 0.0.0.0:11258. Disabled if empty. "+
+			"Requests must carry a bearer token of a user allowed to post the non-resource URL /scheduler/simulate/<ftc name> "+
+			"in the host cluster. Simulations skip preemption, cluster drains and auto migration, so results may differ from "+
+			"the placements of the scheduler.",
+	)
+	flags.StringVar(
+		&o.SchedulerSimulationTLSCertFile,
+		"scheduler-simulation-tls-cert-file",
+		"",
+		"The TLS certificate file of the scheduler simulation server. Required if --scheduler-simulation-bind-address is set.",
+	)
+	flags.StringVar(
+		&o.SchedulerSimulationTLSKeyFile,
+		"scheduler-simulation-tls-key-file",
+		"",
+		"The TLS private key file of the scheduler simulation server. Required if --scheduler-simulation-bind-address is set.",
+	)
 	o.addKlogFlags(flags)
 }
 
@@ -34,8 +34,11 @@ import (
 	fedinformers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 	controllercontext "github.com/kubewharf/kubeadmiral/pkg/controllers/context"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/federatedcluster"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/eventsink"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/federatedclient"
+	"github.com/kubewharf/kubeadmiral/pkg/rolloutaudit"
 	"github.com/kubewharf/kubeadmiral/pkg/stats"
 )
 
@@ -77,7 +80,7 @@ func isControllerEnabled(name string, disabledByDefaultControllers sets.Set[stri
 	return true
 }
 
-func createControllerContext(opts *options.Options) (*controllercontext.Context, error) {
+func createControllerContext(opts *options.Options, metrics stats.Metrics) (*controllercontext.Context, error) {
 	restConfig, err := clientcmd.BuildConfigFromFlags(opts.Master, opts.KubeConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create controller context: %w", err)
@@ -90,8 +93,6 @@ func createControllerContext(opts *options.Options) (*controllercontext.Context,
 		return nil, fmt.Errorf("failed to create component config: %w", err)
 	}
 
-	metrics := stats.NewMock("", "kube-federation-manager", false)
-
 	kubeClientset, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kube clientset: %w", err)
@@ -110,16 +111,38 @@ func createControllerContext(opts *options.Options) (*controllercontext.Context,
 	dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClientset, informerResyncPeriod)
 	fedInformerFactory := fedinformers.NewSharedInformerFactory(fedClientset, informerResyncPeriod)
 
+	clusterAuthConfig := &util.ClusterAuthConfig{SecretsClient: kubeClientset.CoreV1()}
+	if len(opts.ClusterAuthExecPolicyFile) > 0 {
+		clusterAuthConfig.AllowedExecCommands, err = util.LoadAllowedExecCommands(opts.ClusterAuthExecPolicyFile)
+		if err != nil {
+			return nil, err
+		}
+	}
 	federatedClientFactory := federatedclient.NewFederatedClientsetFactory(
 		fedClientset,
 		kubeClientset,
 		fedInformerFactory.Core().V1alpha1().FederatedClusters(),
 		common.DefaultFedSystemNamespace,
 		restConfig,
+		clusterAuthConfig,
 		opts.MaxPodListers,
 		opts.EnablePodPruning,
 	)
 
+	rolloutAuditSink, err := rolloutaudit.NewSink(
+		&rolloutaudit.Config{
+			Sinks:                        opts.RolloutAuditSinks,
+			ElasticsearchURL:             opts.RolloutAuditElasticsearchURL,
+			ElasticsearchIndex:           opts.RolloutAuditElasticsearchIndex,
+			ElasticsearchCredentialsFile: opts.RolloutAuditElasticsearchCredentialsFile,
+			FilePath:                     opts.RolloutAuditFile,
+		},
+		eventsink.NewDefederatingRecorderMux(kubeClientset, "rollout-audit", 4),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rollout audit sink: %w", err)
+	}
+
 	return &controllercontext.Context{
 		FedSystemNamespace: common.DefaultFedSystemNamespace,
 		TargetNamespace:    metav1.NamespaceAll,
@@ -128,10 +151,12 @@ func createControllerContext(opts *options.Options) (*controllercontext.Context,
 		ClusterAvailableDelay:   20 * time.Second,
 		ClusterUnavailableDelay: 60 * time.Second,
 
-		RestConfig:      restConfig,
-		ComponentConfig: componentConfig,
+		RestConfig:        restConfig,
+		ClusterAuthConfig: clusterAuthConfig,
+		ComponentConfig:   componentConfig,
 
-		Metrics: metrics,
+		Metrics:          metrics,
+		RolloutAuditSink: rolloutAuditSink,
 
 		KubeClientset:          kubeClientset,
 		DynamicClientset:       dynamicClientset,
@@ -146,8 +171,21 @@ func createControllerContext(opts *options.Options) (*controllercontext.Context,
 
 func getComponentConfig(opts *options.Options) (*controllercontext.ComponentConfig, error) {
 	componentConfig := &controllercontext.ComponentConfig{
-		FederatedTypeConfigCreateCRDsForFTCs: opts.CreateCRDsForFTCs,
-		ClusterJoinTimeout:                   opts.ClusterJoinTimeout,
+		FederatedTypeConfigCreateCRDsForFTCs:  opts.CreateCRDsForFTCs,
+		ClusterJoinTimeout:                    opts.ClusterJoinTimeout,
+		ClusterUnreachableTaintGracePeriod:    opts.ClusterUnreachableTaintGracePeriod,
+		ClusterCredentialsExpiryWarningPeriod: opts.ClusterCredentialsExpiryWarningPeriod,
+		ClusterAgentHeartbeatTimeout:          opts.ClusterAgentHeartbeatTimeout,
+	}
+
+	minAgentHeartbeatTimeout := federatedcluster.MinAgentHeartbeatTimeoutPeriods * federatedcluster.DefaultAgentHeartbeatPeriod
+	if opts.ClusterAgentHeartbeatTimeout < minAgentHeartbeatTimeout {
+		return nil, fmt.Errorf(
+			"cluster agent heartbeat timeout %v must be at least %d times the agent heartbeat period %v",
+			opts.ClusterAgentHeartbeatTimeout,
+			federatedcluster.MinAgentHeartbeatTimeoutPeriods,
+			federatedcluster.DefaultAgentHeartbeatPeriod,
+		)
 	}
 
 	if opts.NSAutoPropExcludeRegexp != "" {
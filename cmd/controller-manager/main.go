@@ -19,6 +19,7 @@ package main
 import (
 	"context"
 	_ "net/http/pprof"
+	"net/url"
 	"os"
 
 	"github.com/spf13/pflag"
@@ -36,7 +37,7 @@ func main() {
 
 	flags.Parse(os.Args[1:])
 	flags.VisitAll(func(f *pflag.Flag) {
-		klog.Infof("Flag: %v=%v", f.Name, f.Value.String())
+		klog.Infof("Flag: %v=%v", f.Name, redactFlagValue(f.Value.String()))
 	})
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -44,3 +45,11 @@ func main() {
 
 	app.Run(ctx, opts)
 }
+
+// redactFlagValue redacts the password in flag values that are URLs with user info.
+func redactFlagValue(value string) string {
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+	return value
+}
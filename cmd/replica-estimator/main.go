@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/kubeadmiral/pkg/replicaestimator"
+	"github.com/kubewharf/kubeadmiral/pkg/util/signals"
+)
+
+func main() {
+	var (
+		master, kubeConfig      string
+		bindAddress             string
+		tlsCertFile, tlsKeyFile string
+		clientCAFile            string
+		allowUnauthenticated    bool
+		kubeAPIQPS              float32
+		kubeAPIBurst            int
+		informerResyncPeriod    time.Duration
+		gracefulShutdownPeriod  time.Duration
+	)
+
+	flags := pflag.NewFlagSet(os.Args[0], pflag.ExitOnError)
+	flags.StringVar(&master, "master", "", "The address of the member Kubernetes cluster.")
+	flags.StringVar(&kubeConfig, "kubeconfig", "", "The path of the kubeconfig for the member Kubernetes cluster.")
+	flags.StringVar(&bindAddress, "bind-address", ":10350", "The address to serve replica estimation requests on.")
+	flags.StringVar(&tlsCertFile, "tls-cert-file", "", "The certificate to serve with. Requests are served over HTTP if unset.")
+	flags.StringVar(&tlsKeyFile, "tls-private-key-file", "", "The private key of the certificate to serve with.")
+	flags.StringVar(
+		&clientCAFile,
+		"client-ca-file",
+		"",
+		"The CA bundle used to verify client certificates. Only clients presenting a certificate signed by it are served. "+
+			"Requires --tls-cert-file.",
+	)
+	flags.BoolVar(
+		&allowUnauthenticated,
+		"allow-unauthenticated",
+		false,
+		"Serve requests without verifying client certificates if --client-ca-file is unset. Not recommended.",
+	)
+	flags.Float32Var(&kubeAPIQPS, "kube-api-qps", 50, "The maximum QPS from the Kubernetes client.")
+	flags.IntVar(&kubeAPIBurst, "kube-api-burst", 100, "The maximum burst from the Kubernetes client.")
+	flags.DurationVar(&informerResyncPeriod, "informer-resync-period", 0, "The resync period of the node and pod informers.")
+	flags.DurationVar(
+		&gracefulShutdownPeriod,
+		"graceful-shutdown-period",
+		10*time.Second,
+		"The time to wait for requests to complete on shutdown.",
+	)
+
+	flags.Parse(os.Args[1:])
+	flags.VisitAll(func(f *pflag.Flag) {
+		klog.Infof("Flag: %v=%v", f.Name, f.Value.String())
+	})
+
+	var tlsConfig *tls.Config
+	switch {
+	case len(clientCAFile) > 0:
+		if len(tlsCertFile) == 0 {
+			klog.Fatal("--tls-cert-file is required when --client-ca-file is set")
+		}
+		var err error
+		if tlsConfig, err = replicaestimator.ClientAuthTLSConfig(clientCAFile); err != nil {
+			klog.Fatalf("Failed to create TLS config: %v", err)
+		}
+	case !allowUnauthenticated:
+		klog.Fatal("--client-ca-file is required unless --allow-unauthenticated is set")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signals.SetupSignalHandler(cancel)
+
+	restConfig, err := clientcmd.BuildConfigFromFlags(master, kubeConfig)
+	if err != nil {
+		klog.Fatalf("Failed to create rest config: %v", err)
+	}
+	restConfig.QPS = kubeAPIQPS
+	restConfig.Burst = kubeAPIBurst
+
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		klog.Fatalf("Failed to create kube clientset: %v", err)
+	}
+
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, informerResyncPeriod)
+	server := replicaestimator.NewServer(klog.Background(), informerFactory)
+	informerFactory.Start(ctx.Done())
+	if !cache.WaitForNamedCacheSync("replica-estimator", ctx.Done(), server.HasSynced) {
+		klog.Fatal("Timed out waiting for caches to sync")
+	}
+
+	httpServer := &http.Server{
+		Addr:              bindAddress,
+		Handler:           server.Handler(),
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), gracefulShutdownPeriod)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("Failed to shut down server: %v", err)
+		}
+	}()
+
+	klog.Infof("Serving replica estimation requests on %s", bindAddress)
+	if len(tlsCertFile) > 0 {
+		err = httpServer.ListenAndServeTLS(tlsCertFile, tlsKeyFile)
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		klog.Fatalf("Failed to serve: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	kubeclient "k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
+	genscheme "github.com/kubewharf/kubeadmiral/pkg/client/generic/scheme"
+	fedinformers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/federatedcluster"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/federatedtypeconfig"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+)
+
+const AgentName = "kubeadmiral-agent"
+
+// Agent runs in a member cluster in Pull mode. It watches the PulledObjects in the pull namespace of its cluster in the
+// control plane, applies their objects in its cluster and reports the results in their status, and reports the status
+// of its cluster to the control plane. Only outbound connections to the control plane are made, so the member cluster
+// does not need to be reachable from the control plane.
+type Agent struct {
+	clusterName        string
+	fedSystemNamespace string
+	heartbeatPeriod    time.Duration
+	workerCount        int
+
+	hostClient   fedclient.Interface
+	memberConfig *rest.Config
+
+	memberKubeClient          kubeclient.Interface
+	memberKubeInformerFactory informers.SharedInformerFactory
+	ftcInformer               cache.SharedIndexInformer
+
+	eventRecorder record.EventRecorder
+	metrics       stats.Metrics
+	logger        klog.Logger
+
+	// typeAgentsLock guards typeAgents, which holds the stop channels of the running type agents by FTC name.
+	typeAgentsLock sync.Mutex
+	typeAgents     map[string]chan struct{}
+}
+
+func NewAgent(
+	logger klog.Logger,
+	hostConfig, memberConfig *rest.Config,
+	clusterName string,
+	fedSystemNamespace string,
+	heartbeatPeriod time.Duration,
+	workerCount int,
+	metrics stats.Metrics,
+) (*Agent, error) {
+	hostClient, err := fedclient.NewForConfig(hostConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create host fed clientset: %w", err)
+	}
+	hostKubeClient, err := kubeclient.NewForConfig(hostConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create host kube clientset: %w", err)
+	}
+	memberKubeClient, err := kubeclient.NewForConfig(memberConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create member kube clientset: %w", err)
+	}
+
+	a := &Agent{
+		clusterName:               clusterName,
+		fedSystemNamespace:        fedSystemNamespace,
+		heartbeatPeriod:           heartbeatPeriod,
+		workerCount:               workerCount,
+		hostClient:                hostClient,
+		memberConfig:              memberConfig,
+		memberKubeClient:          memberKubeClient,
+		memberKubeInformerFactory: informers.NewSharedInformerFactory(memberKubeClient, util.NoResyncPeriod),
+		metrics:                   metrics,
+		logger:                    logger.WithValues("component", AgentName, "cluster-name", clusterName),
+		typeAgents:                map[string]chan struct{}{},
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(
+		&corev1client.EventSinkImpl{Interface: hostKubeClient.CoreV1().Events("")},
+	)
+	broadcaster.StartLogging(klog.V(4).Infof)
+	a.eventRecorder = broadcaster.NewRecorder(
+		genscheme.Scheme,
+		corev1.EventSource{Component: AgentName, Host: clusterName},
+	)
+
+	a.ftcInformer = fedinformers.NewSharedInformerFactory(hostClient, util.NoResyncPeriod).
+		Core().V1alpha1().FederatedTypeConfigs().Informer()
+	a.ftcInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { a.reconcileTypeConfig(obj) },
+		UpdateFunc: func(_, obj interface{}) { a.reconcileTypeConfig(obj) },
+		DeleteFunc: func(obj interface{}) {
+			if deleted, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = deleted.Obj
+			}
+			if typeConfig, ok := obj.(*fedcorev1a1.FederatedTypeConfig); ok {
+				a.stopTypeAgent(typeConfig.Name)
+			}
+		},
+	})
+
+	// the informers are used by the status collection
+	a.memberKubeInformerFactory.Core().V1().Nodes().Informer()
+	a.memberKubeInformerFactory.Core().V1().Pods().Informer()
+
+	return a, nil
+}
+
+func (a *Agent) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+
+	a.logger.Info("Starting agent")
+	defer a.logger.Info("Stopping agent")
+
+	a.memberKubeInformerFactory.Start(ctx.Done())
+	go a.ftcInformer.Run(ctx.Done())
+
+	go wait.UntilWithContext(ctx, a.reportClusterStatus, a.heartbeatPeriod)
+
+	<-ctx.Done()
+
+	a.typeAgentsLock.Lock()
+	defer a.typeAgentsLock.Unlock()
+	for name, stopChan := range a.typeAgents {
+		close(stopChan)
+		delete(a.typeAgents, name)
+	}
+}
+
+// reportClusterStatus collects the status of the member cluster and reports it to the control plane together with a
+// heartbeat. The control plane considers the cluster offline if no heartbeat is reported for some time.
+func (a *Agent) reportClusterStatus(ctx context.Context) {
+	logger := a.logger.WithValues("control-loop", "report-status")
+	ctx = klog.NewContext(ctx, logger)
+
+	cluster, err := a.hostClient.CoreV1alpha1().FederatedClusters().Get(ctx, a.clusterName, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(err, "Failed to get cluster from control plane")
+		return
+	}
+	if !cluster.IsPullMode() {
+		logger.Error(nil, "Cluster is not in pull mode, skipping status report")
+		return
+	}
+
+	federatedcluster.CollectClusterStatus(ctx, cluster, a.memberKubeClient, a.memberKubeInformerFactory)
+	cluster.Status.Agent = &fedcorev1a1.ClusterAgentStatus{
+		LastHeartbeatTime: metav1.Now(),
+		HeartbeatPeriod:   &metav1.Duration{Duration: a.heartbeatPeriod},
+	}
+
+	if err := federatedcluster.UpdateCollectedClusterStatus(ctx, a.hostClient, cluster); err != nil {
+		logger.Error(err, "Failed to report cluster status")
+		return
+	}
+	logger.V(3).Info("Reported cluster status")
+}
+
+// reconcileTypeConfig starts or stops the type agent of the FTC depending on whether propagation is enabled.
+func (a *Agent) reconcileTypeConfig(obj interface{}) {
+	typeConfig, ok := obj.(*fedcorev1a1.FederatedTypeConfig)
+	if !ok {
+		return
+	}
+	typeConfig = typeConfig.DeepCopy()
+	federatedtypeconfig.SetFederatedTypeConfigDefaults(typeConfig)
+
+	if typeConfig.DeletionTimestamp != nil || !typeConfig.GetPropagationEnabled() {
+		a.stopTypeAgent(typeConfig.Name)
+		return
+	}
+
+	a.typeAgentsLock.Lock()
+	defer a.typeAgentsLock.Unlock()
+	if _, running := a.typeAgents[typeConfig.Name]; running {
+		return
+	}
+
+	typeAgent, err := newTypeAgent(a, typeConfig)
+	if err != nil {
+		a.logger.Error(err, "Failed to start type agent", "ftc", typeConfig.Name)
+		return
+	}
+	stopChan := make(chan struct{})
+	typeAgent.Run(stopChan)
+	a.typeAgents[typeConfig.Name] = stopChan
+}
+
+func (a *Agent) stopTypeAgent(name string) {
+	a.typeAgentsLock.Lock()
+	defer a.typeAgentsLock.Unlock()
+	if stopChan, running := a.typeAgents[name]; running {
+		close(stopChan)
+		delete(a.typeAgents, name)
+	}
+}
@@ -0,0 +1,415 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	fedinformers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions"
+	fedcorev1a1listers "github.com/kubewharf/kubeadmiral/pkg/client/listers/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/sync/dispatch"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	annotationutil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/annotation"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/finalizers"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/managedlabel"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+)
+
+const (
+	EventReasonApplyFailed  = "AgentApplyFailed"
+	EventReasonRemoveFailed = "AgentRemoveFailed"
+)
+
+// typeAgent propagates the objects of a single FTC to the member cluster of the agent. The objects are read from the
+// PulledObjects in the pull namespace of the cluster, and the result of applying each object is reported in the status
+// of its PulledObject.
+type typeAgent struct {
+	agent      *Agent
+	typeConfig *fedcorev1a1.FederatedTypeConfig
+	namespace  string
+
+	pulledObjectInformer cache.SharedIndexInformer
+	pulledObjectLister   fedcorev1a1listers.PulledObjectLister
+	memberClient         util.ResourceClient
+	memberStore          cache.Store
+	memberController     cache.Controller
+
+	worker worker.ReconcileWorker
+	logger klog.Logger
+}
+
+func newTypeAgent(agent *Agent, typeConfig *fedcorev1a1.FederatedTypeConfig) (*typeAgent, error) {
+	targetType := typeConfig.GetTargetType()
+	memberClient, err := util.NewResourceClient(agent.memberConfig, &targetType)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &typeAgent{
+		agent:        agent,
+		typeConfig:   typeConfig,
+		namespace:    util.PullNamespace(agent.clusterName),
+		memberClient: memberClient,
+		logger:       agent.logger.WithValues("ftc", typeConfig.Name),
+	}
+
+	t.worker = worker.NewReconcileWorker(
+		t.reconcile,
+		worker.WorkerTiming{},
+		agent.workerCount,
+		agent.metrics,
+		delayingdeliver.NewFTCMetricTags("agent-worker", targetType.Kind, typeConfig.Name),
+	)
+	pulledObjectInformer := fedinformers.NewSharedInformerFactoryWithOptions(
+		agent.hostClient,
+		util.NoResyncPeriod,
+		fedinformers.WithNamespace(t.namespace),
+		fedinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = util.PulledObjectTypeSelector(typeConfig.Name).String()
+		}),
+	).Core().V1alpha1().PulledObjects()
+	t.pulledObjectInformer = pulledObjectInformer.Informer()
+	t.pulledObjectInformer.AddEventHandler(util.NewTriggerOnAllChanges(t.enqueuePulledObject))
+	t.pulledObjectLister = pulledObjectInformer.Lister()
+	t.memberStore, t.memberController = util.NewManagedResourceInformer(
+		memberClient,
+		metav1.NamespaceAll,
+		t.worker.EnqueueObject,
+		map[string]string{"member_cluster": agent.clusterName},
+		agent.metrics,
+	)
+
+	return t, nil
+}
+
+func (t *typeAgent) Run(stopChan <-chan struct{}) {
+	go t.pulledObjectInformer.Run(stopChan)
+	go t.memberController.Run(stopChan)
+
+	go func() {
+		// Objects without a PulledObject are removed from the cluster, so both caches must be synced before
+		// reconciling to avoid removing objects whose PulledObjects are not cached yet.
+		if !cache.WaitForNamedCacheSync(
+			AgentName,
+			stopChan,
+			t.pulledObjectInformer.HasSynced,
+			t.memberController.HasSynced,
+		) {
+			return
+		}
+		t.logger.Info("Starting type agent")
+		t.worker.Run(stopChan)
+	}()
+}
+
+func (t *typeAgent) enqueuePulledObject(obj pkgruntime.Object) {
+	pulledObject, ok := obj.(*fedcorev1a1.PulledObject)
+	if !ok {
+		return
+	}
+	targetName, err := util.PulledObjectTargetName(pulledObject)
+	if err != nil {
+		t.logger.Error(err, "Failed to get target of pulled object", "pulled-object", common.NewQualifiedName(obj))
+		return
+	}
+	t.worker.Enqueue(targetName)
+}
+
+func (t *typeAgent) reconcile(qualifiedName common.QualifiedName) worker.Result {
+	logger := t.logger.WithValues("object", qualifiedName.String())
+	ctx := klog.NewContext(context.TODO(), logger)
+	startTime := time.Now()
+
+	logger.V(3).Info("Starting reconcile")
+	defer func() {
+		logger.WithValues("duration", time.Since(startTime)).V(3).Info("Finished reconcile")
+	}()
+
+	if t.typeConfig.IsNamespace() && t.isSystemNamespace(qualifiedName.Name) {
+		return worker.StatusAllOK
+	}
+
+	pulledObject, err := t.pulledObjectLister.PulledObjects(t.namespace).
+		Get(util.PulledObjectName(t.typeConfig.Name, qualifiedName))
+	if err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "Failed to get pulled object from cache")
+		return worker.StatusError
+	}
+	if apierrors.IsNotFound(err) || pulledObject.DeletionTimestamp != nil {
+		pulledObject = nil
+	}
+	clusterObject, err := util.ObjFromCache(t.memberStore, t.typeConfig.GetTargetType().Kind, qualifiedName.String())
+	if err != nil {
+		return worker.StatusError
+	}
+
+	if pulledObject == nil {
+		return t.removeFromCluster(ctx, clusterObject)
+	}
+
+	propStatus, err := t.applyToCluster(ctx, pulledObject, clusterObject)
+	if apierrors.IsConflict(err) {
+		return worker.StatusConflict
+	}
+	if err != nil {
+		logger.Error(err, "Failed to apply object to cluster", "status", propStatus)
+		t.recordEvent(pulledObject, EventReasonApplyFailed, "Failed to apply object to cluster %s: %v", err)
+	}
+
+	if result := t.reportStatus(ctx, pulledObject, clusterObject, propStatus, err); result != worker.StatusAllOK {
+		return result
+	}
+	if isRetriable(propStatus) {
+		return worker.StatusError
+	}
+	return worker.StatusAllOK
+}
+
+// applyToCluster creates or updates the object of the PulledObject in the cluster. It returns the propagation status
+// to report and the error, if any.
+func (t *typeAgent) applyToCluster(
+	ctx context.Context,
+	pulledObject *fedcorev1a1.PulledObject,
+	clusterObject *unstructured.Unstructured,
+) (fedtypesv1a1.PropagationStatus, error) {
+	desiredObject, err := util.PulledObjectTemplate(pulledObject)
+	if err != nil {
+		return fedtypesv1a1.ComputeResourceFailed, err
+	}
+	if err := setDesiredObjectAnnotations(desiredObject); err != nil {
+		return fedtypesv1a1.ComputeResourceFailed, err
+	}
+
+	if clusterObject == nil {
+		return t.createInCluster(ctx, pulledObject, desiredObject)
+	}
+	return t.updateInCluster(ctx, pulledObject, desiredObject, clusterObject)
+}
+
+func (t *typeAgent) createInCluster(
+	ctx context.Context,
+	pulledObject *fedcorev1a1.PulledObject,
+	desiredObject *unstructured.Unstructured,
+) (fedtypesv1a1.PropagationStatus, error) {
+	logger := klog.FromContext(ctx)
+
+	obj := desiredObject.DeepCopy()
+	if err := dispatch.PrepareObjectForCluster(obj, nil, pulledObject.Spec.RetainReplicas, t.typeConfig); err != nil {
+		return fedtypesv1a1.ComputeResourceFailed, err
+	}
+
+	logger.V(1).Info("Creating target object in cluster")
+	_, err := t.memberClient.Resources(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+	if err == nil {
+		return fedtypesv1a1.ClusterPropagationOK, nil
+	}
+	// Creating a namespace that already exists may fail with ServerTimeout instead of AlreadyExists.
+	alreadyExists := apierrors.IsAlreadyExists(err) || t.typeConfig.IsNamespace() && apierrors.IsServerTimeout(err)
+	if !alreadyExists {
+		return fedtypesv1a1.CreationFailed, err
+	}
+
+	// The object exists but is not managed, so it is adopted if allowed.
+	clusterObject, err := t.memberClient.Resources(obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return fedtypesv1a1.RetrievalFailed, err
+	}
+	if managedlabel.IsExplicitlyUnmanaged(clusterObject) {
+		return fedtypesv1a1.ManagedLabelFalse, fmt.Errorf("object is explicitly unmanaged in the cluster")
+	}
+	if !managedlabel.HasManagedLabel(clusterObject) {
+		if !pulledObject.Spec.AdoptResources {
+			return fedtypesv1a1.AlreadyExists, fmt.Errorf("object pre-exists in the cluster")
+		}
+		if _, err := annotationutil.AddAnnotation(clusterObject, util.AdoptedAnnotation, common.AnnotationValueTrue); err != nil {
+			return fedtypesv1a1.ComputeResourceFailed, err
+		}
+	}
+
+	return t.updateInCluster(ctx, pulledObject, desiredObject, clusterObject)
+}
+
+func (t *typeAgent) updateInCluster(
+	ctx context.Context,
+	pulledObject *fedcorev1a1.PulledObject,
+	desiredObject, clusterObject *unstructured.Unstructured,
+) (fedtypesv1a1.PropagationStatus, error) {
+	logger := klog.FromContext(ctx)
+
+	if clusterObject.GetAnnotations()[AppliedHashAnnotation] == desiredObject.GetAnnotations()[AppliedHashAnnotation] {
+		return fedtypesv1a1.ClusterPropagationOK, nil
+	}
+
+	if err := dispatch.PrepareObjectForCluster(
+		desiredObject,
+		clusterObject,
+		pulledObject.Spec.RetainReplicas,
+		t.typeConfig,
+	); err != nil {
+		return fedtypesv1a1.FieldRetentionFailed, err
+	}
+
+	logger.V(1).Info("Updating target object in cluster")
+	_, err := t.memberClient.Resources(desiredObject.GetNamespace()).Update(ctx, desiredObject, metav1.UpdateOptions{})
+	if err != nil {
+		return fedtypesv1a1.UpdateFailed, err
+	}
+	return fedtypesv1a1.ClusterPropagationOK, nil
+}
+
+// reportStatus records the result of applying the PulledObject and the fields collected from the object in the
+// cluster in the status of the PulledObject.
+func (t *typeAgent) reportStatus(
+	ctx context.Context,
+	pulledObject *fedcorev1a1.PulledObject,
+	clusterObject *unstructured.Unstructured,
+	propStatus fedtypesv1a1.PropagationStatus,
+	applyErr error,
+) worker.Result {
+	logger := klog.FromContext(ctx)
+
+	status, err := newPulledObjectStatus(pulledObject.Generation, propStatus, applyErr, clusterObject, t.typeConfig)
+	if err != nil {
+		logger.Error(err, "Failed to compute status of pulled object")
+		return worker.StatusErrorNoRetry
+	}
+	if apiequality.Semantic.DeepEqual(pulledObject.Status, status) {
+		return worker.StatusAllOK
+	}
+
+	pulledObject = pulledObject.DeepCopy()
+	pulledObject.Status = status
+	logger.V(1).Info("Updating status of pulled object", "status", propStatus)
+	if _, err := t.agent.hostClient.CoreV1alpha1().PulledObjects(t.namespace).UpdateStatus(
+		ctx,
+		pulledObject,
+		metav1.UpdateOptions{},
+	); err != nil {
+		if apierrors.IsConflict(err) {
+			return worker.StatusConflict
+		}
+		if apierrors.IsNotFound(err) {
+			return worker.StatusAllOK
+		}
+		logger.Error(err, "Failed to update status of pulled object")
+		return worker.StatusError
+	}
+	return worker.StatusAllOK
+}
+
+// removeFromCluster deletes the object from the cluster, or orphans it according to the orphaning behavior recorded on
+// the object. The sync controller records the orphaning behavior before deleting the PulledObject of the object.
+func (t *typeAgent) removeFromCluster(
+	ctx context.Context,
+	clusterObject *unstructured.Unstructured,
+) worker.Result {
+	logger := klog.FromContext(ctx)
+
+	if clusterObject == nil {
+		return worker.StatusAllOK
+	}
+	client := t.memberClient.Resources(clusterObject.GetNamespace())
+
+	if shouldBeOrphaned(util.GetOrphaningBehavior(clusterObject), clusterObject) {
+		logger.V(1).Info("Removing managed label from target object in cluster")
+		managedlabel.RemoveManagedLabel(clusterObject)
+		if _, err := finalizers.RemoveFinalizers(
+			clusterObject,
+			sets.NewString(dispatch.RetainTerminatingObjectFinalizer),
+		); err != nil {
+			return worker.StatusErrorNoRetry
+		}
+		if _, err := client.Update(ctx, clusterObject, metav1.UpdateOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			if apierrors.IsConflict(err) {
+				return worker.StatusConflict
+			}
+			logger.Error(err, "Failed to remove managed label from target object in cluster")
+			return worker.StatusError
+		}
+		return worker.StatusAllOK
+	}
+
+	needUpdate, err := finalizers.RemoveFinalizers(clusterObject, sets.NewString(dispatch.RetainTerminatingObjectFinalizer))
+	if err != nil {
+		return worker.StatusErrorNoRetry
+	}
+	if needUpdate {
+		if _, err := client.Update(ctx, clusterObject, metav1.UpdateOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			if apierrors.IsConflict(err) {
+				return worker.StatusConflict
+			}
+			logger.Error(err, "Failed to remove finalizer from target object in cluster")
+			return worker.StatusError
+		}
+	}
+
+	// Avoid deleting a deleted object again.
+	if clusterObject.GetDeletionTimestamp() != nil {
+		return worker.StatusAllOK
+	}
+
+	logger.V(1).Info("Deleting target object in cluster")
+	// The propagation policy is set explicitly since some resources such as jobs default to orphaning their
+	// dependents.
+	err = client.Delete(
+		ctx,
+		clusterObject.GetName(),
+		metav1.DeleteOptions{PropagationPolicy: &deletePropagationBackground},
+	)
+	if err != nil && !apierrors.IsNotFound(err) {
+		// The PulledObject is gone, so the failure can only be logged.
+		logger.Error(err, "Failed to delete target object in cluster")
+		return worker.StatusError
+	}
+	return worker.StatusAllOK
+}
+
+// recordEvent records a warning event on the PulledObject. The message is formatted with the cluster name followed by
+// the given arguments.
+func (t *typeAgent) recordEvent(
+	pulledObject *fedcorev1a1.PulledObject,
+	reason, messageFmt string,
+	args ...interface{},
+) {
+	args = append([]interface{}{t.agent.clusterName}, args...)
+	t.agent.eventRecorder.Eventf(pulledObject, corev1.EventTypeWarning, reason, messageFmt, args...)
+}
+
+func (t *typeAgent) isSystemNamespace(namespace string) bool {
+	switch namespace {
+	case "kube-system", "kube-public", "default", t.agent.fedSystemNamespace:
+		return true
+	default:
+		return false
+	}
+}
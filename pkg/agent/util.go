@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	//nolint:gosec
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	annotationutil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/annotation"
+)
+
+// AppliedHashAnnotation records the hash of the object last applied by the agent, so that objects in the cluster are
+// only updated when their desired state changes.
+var AppliedHashAnnotation = common.DefaultPrefix + "agent-applied-hash"
+
+var deletePropagationBackground = metav1.DeletePropagationBackground
+
+// setDesiredObjectAnnotations records the hash of the desired object on it.
+func setDesiredObjectAnnotations(desiredObject *unstructured.Unstructured) error {
+	hash, err := hashObject(desiredObject)
+	if err != nil {
+		return err
+	}
+	_, err = annotationutil.AddAnnotation(desiredObject, AppliedHashAnnotation, hash)
+	return err
+}
+
+func hashObject(obj *unstructured.Unstructured) (string, error) {
+	raw, err := json.Marshal(obj.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal object: %w", err)
+	}
+	//nolint:gosec
+	hash := md5.Sum(raw)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func shouldBeOrphaned(behavior util.OrphanManagedResourcesBehavior, clusterObject *unstructured.Unstructured) bool {
+	return behavior == util.OrphanManagedResourcesAll ||
+		behavior == util.OrphanManagedResourcesAdopted && util.HasAdoptedAnnotation(clusterObject)
+}
+
+// newPulledObjectStatus returns the status of a PulledObject of the given generation with the given result of applying
+// it and the fields collected from the object in the cluster.
+func newPulledObjectStatus(
+	generation int64,
+	propStatus fedtypesv1a1.PropagationStatus,
+	applyErr error,
+	clusterObject *unstructured.Unstructured,
+	typeConfig *fedcorev1a1.FederatedTypeConfig,
+) (fedcorev1a1.PulledObjectStatus, error) {
+	status := fedcorev1a1.PulledObjectStatus{
+		ObservedGeneration: generation,
+		PropagationStatus:  string(propStatus),
+	}
+	if applyErr != nil {
+		status.Message = applyErr.Error()
+	}
+
+	if clusterObject != nil {
+		status.ClusterGeneration = clusterObject.GetGeneration()
+	}
+	if clusterObject != nil && typeConfig.Spec.StatusCollection != nil {
+		// The fields that cannot be collected are reported by the status controller.
+		collectedFields, _ := util.CollectStatusFields(clusterObject.Object, typeConfig.Spec.StatusCollection.Fields)
+		raw, err := json.Marshal(collectedFields)
+		if err != nil {
+			return status, fmt.Errorf("failed to marshal collected fields: %w", err)
+		}
+		status.CollectedFields = &apiextensionsv1.JSON{Raw: raw}
+	}
+	return status, nil
+}
+
+// isRetriable returns whether applying an object with the given result should be retried.
+func isRetriable(propStatus fedtypesv1a1.PropagationStatus) bool {
+	switch propStatus {
+	case fedtypesv1a1.CreationFailed, fedtypesv1a1.UpdateFailed, fedtypesv1a1.RetrievalFailed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+func Test_setDesiredObjectAnnotations(t *testing.T) {
+	newObject := func(annotations map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		obj.SetAPIVersion("apps/v1")
+		obj.SetKind("Deployment")
+		obj.SetName("test")
+		obj.SetAnnotations(annotations)
+		return obj
+	}
+
+	desired := newObject(nil)
+	assert.NoError(t, setDesiredObjectAnnotations(desired))
+	hash := desired.GetAnnotations()[AppliedHashAnnotation]
+	assert.NotEmpty(t, hash)
+
+	again := newObject(nil)
+	assert.NoError(t, setDesiredObjectAnnotations(again))
+	assert.Equal(t, hash, again.GetAnnotations()[AppliedHashAnnotation], "the hash should be stable")
+
+	orphaned := newObject(map[string]string{util.OrphanManagedResourcesInternalAnnotation: string(util.OrphanManagedResourcesAll)})
+	assert.NoError(t, setDesiredObjectAnnotations(orphaned))
+	assert.NotEqual(t, hash, orphaned.GetAnnotations()[AppliedHashAnnotation], "the hash should change with the orphaning behavior")
+}
+
+func Test_newPulledObjectStatus(t *testing.T) {
+	typeConfig := &fedcorev1a1.FederatedTypeConfig{
+		Spec: fedcorev1a1.FederatedTypeConfigSpec{
+			StatusCollection: &fedcorev1a1.StatusCollection{Fields: []string{"status.replicas", "status.missing"}},
+		},
+	}
+	clusterObject := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"replicas": int64(3)},
+		"status": map[string]interface{}{"replicas": int64(2)},
+	}}
+	clusterObject.SetGeneration(5)
+
+	status, err := newPulledObjectStatus(2, fedtypesv1a1.ClusterPropagationOK, nil, clusterObject, typeConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), status.ObservedGeneration)
+	assert.Equal(t, string(fedtypesv1a1.ClusterPropagationOK), status.PropagationStatus)
+	assert.Empty(t, status.Message)
+	assert.Equal(t, int64(5), status.ClusterGeneration)
+	if assert.NotNil(t, status.CollectedFields) {
+		assert.JSONEq(t, `{"status":{"replicas":2}}`, string(status.CollectedFields.Raw))
+	}
+
+	status, err = newPulledObjectStatus(3, fedtypesv1a1.AlreadyExists, errors.New("object pre-exists"), nil, typeConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), status.ObservedGeneration)
+	assert.Equal(t, string(fedtypesv1a1.AlreadyExists), status.PropagationStatus)
+	assert.Equal(t, "object pre-exists", status.Message)
+	assert.Zero(t, status.ClusterGeneration)
+	assert.Nil(t, status.CollectedFields)
+}
+
+func Test_shouldBeOrphaned(t *testing.T) {
+	adopted := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	adopted.SetAnnotations(map[string]string{util.AdoptedAnnotation: common.AnnotationValueTrue})
+	created := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+	assert.False(t, shouldBeOrphaned(util.OrphanManagedResourcesNone, adopted))
+	assert.True(t, shouldBeOrphaned(util.OrphanManagedResourcesAll, created))
+	assert.True(t, shouldBeOrphaned(util.OrphanManagedResourcesAdopted, adopted))
+	assert.False(t, shouldBeOrphaned(util.OrphanManagedResourcesAdopted, created))
+}
@@ -19,3 +19,8 @@ package v1alpha1
 func (fcluster *FederatedCluster) String() string {
 	return fcluster.Name
 }
+
+// IsPullMode returns whether objects are pulled into the cluster by its agent.
+func (fcluster *FederatedCluster) IsPullMode() bool {
+	return fcluster.Spec.Mode == ClusterModePull
+}
@@ -92,6 +92,13 @@ func (f *FederatedTypeConfig) GetRolloutPlanEnabled() bool {
 		*f.Spec.RolloutPlan == RolloutPlanEnabled
 }
 
+func (f *FederatedTypeConfig) GetDriftDetectionMode() DriftDetectionMode {
+	if f.Spec.DriftDetection == nil {
+		return DriftDetectionDisabled
+	}
+	return *f.Spec.DriftDetection
+}
+
 func (f *FederatedTypeConfig) GetControllers() [][]string {
 	return f.Spec.Controllers
 }
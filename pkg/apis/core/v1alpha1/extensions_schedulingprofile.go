@@ -28,6 +28,7 @@ func GetDefaultEnabledPlugins() *fedcore.EnabledPlugins {
 		names.ClusterResourcesFit,
 		names.PlacementFilter,
 		names.ClusterAffinity,
+		names.ClusterHealth,
 	}
 
 	scorePlugins := []string{
@@ -37,8 +38,9 @@ func GetDefaultEnabledPlugins() *fedcore.EnabledPlugins {
 		names.ClusterAffinity,
 	}
 
-	selectPlugins := []string{names.MaxCluster}
-	replicasPlugins := []string{names.ClusterCapacityWeight}
+	// TopologySpread only applies to scheduling units with a topology spread constraint, and is skipped otherwise.
+	selectPlugins := []string{names.TopologySpread, names.MaxCluster}
+	replicasPlugins := []string{names.TopologySpread, names.ClusterCapacityWeight}
 
 	return &fedcore.EnabledPlugins{
 		FilterPlugins:   filterPlugins,
@@ -73,6 +73,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&SchedulerPluginWebhookConfigurationList{},
 		&SchedulingProfile{},
 		&SchedulingProfileList{},
+		&PulledObject{},
+		&PulledObjectList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
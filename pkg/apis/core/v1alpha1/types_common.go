@@ -32,6 +32,14 @@ type OverrideRule struct {
 	// Overriders specify the overriders to be applied in the target clusters.
 	// +optional
 	Overriders *Overriders `json:"overriders,omitempty"`
+
+	// TemplateValues specifies whether the string values of the overriders in this rule are Go templates
+	// (https://pkg.go.dev/text/template) to be rendered for each target cluster.
+	// The target cluster is available as `.Cluster`, which has the fields `Name`, `Labels`, `Annotations`
+	// and `Taints` (a map from taint keys to values), e.g. `{{ index .Cluster.Labels "example.io/region" }}`.
+	// The functions `lower`, `upper`, `replace`, `trimPrefix`, `trimSuffix` and `default` are also available.
+	// +optional
+	TemplateValues bool `json:"templateValues,omitempty"`
 }
 
 type GenericRefCountedStatus struct {
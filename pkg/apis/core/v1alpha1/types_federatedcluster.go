@@ -33,6 +33,8 @@ import (
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name=ready,type=string,JSONPath=.status.conditions[?(@.type=='Ready')].status
 // +kubebuilder:printcolumn:name=joined,type=string,JSONPath=.status.conditions[?(@.type=='Joined')].status
+// +kubebuilder:printcolumn:name=mode,type=string,JSONPath=.spec.mode
+// +kubebuilder:printcolumn:name=drain,type=string,JSONPath=.status.drain.phase
 // +kubebuilder:printcolumn:name=age,type=date,JSONPath=.metadata.creationTimestamp
 // +kubebuilder:object:root=true
 
@@ -56,8 +58,18 @@ type FederatedClusterList struct {
 
 // FederatedClusterSpec defines the desired state of FederatedCluster
 type FederatedClusterSpec struct {
+	// Mode is how objects are propagated to the member cluster. In Push mode, the control plane connects to the API
+	// endpoint of the member cluster. In Pull mode, an agent in the member cluster connects to the control plane and
+	// applies the objects placed in the cluster, so that the member cluster does not have to be reachable from the
+	// control plane. Defaults to Push.
+	// +optional
+	// +kubebuilder:default:=Push
+	Mode ClusterMode `json:"mode,omitempty"`
+
 	// The API endpoint of the member cluster. This can be a hostname, hostname:port, IP or IP:port.
-	APIEndpoint string `json:"apiEndpoint"`
+	// Required in Push mode.
+	// +optional
+	APIEndpoint string `json:"apiEndpoint,omitempty"`
 
 	// Access API endpoint with security.
 	// +optional
@@ -68,12 +80,210 @@ type FederatedClusterSpec struct {
 	UseServiceAccountToken bool `json:"useServiceAccount"`
 
 	// Name of the secret containing the token required to access the member cluster.
-	// The secret needs to exist in the fed system namespace.
-	SecretRef LocalSecretReference `json:"secretRef"`
+	// The secret needs to exist in the fed system namespace. Required in Push mode.
+	// +optional
+	SecretRef LocalSecretReference `json:"secretRef,omitempty"`
+
+	// ProxyURL is the URL of the proxy through which the API endpoint of the member cluster is accessed. The http, https
+	// and socks5 schemes are supported. HTTP and HTTPS proxies tunnel the connection with HTTP CONNECT.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(http|https|socks5)://`
+	ProxyURL string `json:"proxyURL,omitempty"`
 
-	// If specified, the cluster's taints.
+	// Auth configures an alternative source of credentials for the member cluster, which is used instead of the client
+	// certificate in the cluster secret. The cluster secret still provides the CA of the member cluster. If
+	// useServiceAccount is true, the alternative credentials are only used to join the cluster and to refresh its
+	// service account token.
+	// +optional
+	Auth *ClusterAuth `json:"auth,omitempty"`
+
+	// ServiceAccountTokenExpiration, if specified together with useServiceAccount, makes the control plane access the
+	// member cluster with a bound service account token that expires after this duration, instead of a token that
+	// never expires. The token is refreshed before it expires. Must be at least 10 minutes.
+	// +optional
+	// +kubebuilder:validation:Format:=duration
+	ServiceAccountTokenExpiration *metav1.Duration `json:"serviceAccountTokenExpiration,omitempty"`
+
+	// If specified, the cluster's taints. The taints with the keys kubeadmiral.io/cluster-not-ready,
+	// kubeadmiral.io/cluster-unreachable and kubeadmiral.io/cluster-unschedulable are managed by the federated
+	// cluster controller.
 	// +optional
 	Taints []corev1.Taint `json:"taints,omitempty"`
+
+	// ReplicaEstimator is the replica estimator that estimates how many replicas of a workload can be scheduled in
+	// the cluster. If unspecified, the estimates are based on the resources in the cluster's status.
+	// +optional
+	ReplicaEstimator *ReplicaEstimatorConfig `json:"replicaEstimator,omitempty"`
+
+	// HealthCheck configures probes that check the health of the cluster beyond the /healthz endpoint of its API
+	// server. The result of each kind of probe is reported as a separate condition.
+	// +optional
+	HealthCheck *ClusterHealthCheck `json:"healthCheck,omitempty"`
+
+	// Unschedulable cordons the cluster, which prevents federated objects from being newly placed in the cluster.
+	// Existing placements are kept unless the cluster is drained.
+	// +optional
+	Unschedulable bool `json:"unschedulable,omitempty"`
+
+	// Drain, if specified, moves the placements of federated objects out of the cluster to other clusters. A drained
+	// cluster is also unschedulable. The progress of the drain is reported in the status of the cluster.
+	// +optional
+	Drain *ClusterDrain `json:"drain,omitempty"`
+}
+
+// ClusterAuth configures an alternative source of credentials for a member cluster. Exactly one of its fields must be
+// specified.
+// +kubebuilder:validation:MinProperties:=1
+// +kubebuilder:validation:MaxProperties:=1
+type ClusterAuth struct {
+	// Exec configures a credential plugin that is executed by the controller manager to obtain credentials, like the
+	// exec config of a user in a kubeconfig. The command must be available in the controller manager's container.
+	// +optional
+	Exec *ClusterExecAuth `json:"exec,omitempty"`
+
+	// OIDC configures OpenID Connect authentication with an ID token that is refreshed with a refresh token. The
+	// refresh token, the client secret and, optionally, an initial ID token and the CA of the issuer are read from
+	// the cluster secret.
+	// +optional
+	OIDC *ClusterOIDCAuth `json:"oidc,omitempty"`
+}
+
+// ClusterExecAuth configures a credential plugin that returns an ExecCredential.
+type ClusterExecAuth struct {
+	// APIVersion is the preferred API version of the ExecCredential returned by the plugin.
+	// +kubebuilder:validation:Enum=client.authentication.k8s.io/v1;client.authentication.k8s.io/v1beta1
+	APIVersion string `json:"apiVersion"`
+	// Command to execute.
+	// +kubebuilder:validation:MinLength=1
+	Command string `json:"command"`
+	// Args are the arguments to pass to the command.
+	// +optional
+	Args []string `json:"args,omitempty"`
+	// Env are additional environment variables to set for the command.
+	// +optional
+	Env []ExecEnvVar `json:"env,omitempty"`
+	// ProvideClusterInfo passes the API endpoint and CA of the member cluster to the plugin in the
+	// KUBERNETES_EXEC_INFO environment variable.
+	// +optional
+	ProvideClusterInfo bool `json:"provideClusterInfo,omitempty"`
+}
+
+// ExecEnvVar is an environment variable of a credential plugin.
+type ExecEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ClusterOIDCAuth configures OpenID Connect authentication for a member cluster.
+type ClusterOIDCAuth struct {
+	// IssuerURL is the URL of the OpenID Connect issuer, which is used to discover its token endpoint.
+	// +kubebuilder:validation:Pattern=`^https://`
+	IssuerURL string `json:"issuerURL"`
+	// ClientID is the OAuth 2.0 client ID of the control plane.
+	// +kubebuilder:validation:MinLength=1
+	ClientID string `json:"clientID"`
+}
+
+// +kubebuilder:validation:Enum=Push;Pull
+type ClusterMode string
+
+const (
+	// ClusterModePush means the control plane connects to the member cluster to propagate objects.
+	ClusterModePush ClusterMode = "Push"
+	// ClusterModePull means an agent in the member cluster connects to the control plane to pull objects.
+	ClusterModePull ClusterMode = "Pull"
+)
+
+// ClusterDrain configures how the placements of federated objects are moved out of a cluster. Objects in Duplicate
+// mode are removed from the cluster at once, while the replicas of objects in Divide mode are moved to other clusters
+// in steps. Objects with sticky clusters are not moved.
+type ClusterDrain struct {
+	// MaxUnavailablePercentage is the maximum percentage of an object's replicas in the cluster that are moved to
+	// other clusters in each step, similar to the maxUnavailable of a PodDisruptionBudget. At least one replica is
+	// moved in each step. Defaults to 100.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MaxUnavailablePercentage *int32 `json:"maxUnavailablePercentage,omitempty"`
+
+	// StepInterval is the minimum interval between the steps of the drain. Defaults to 1 minute. If the status of
+	// the object's type is collected, an object only proceeds to the next step once its replicas in the clusters that
+	// are not drained are available.
+	// +optional
+	// +kubebuilder:default:="1m"
+	// +kubebuilder:validation:Format:=duration
+	StepInterval metav1.Duration `json:"stepInterval,omitempty"`
+}
+
+// ClusterHealthCheck configures the probes that check the health of a member cluster.
+type ClusterHealthCheck struct {
+	// MinReadyNodesPercentage is the minimum percentage of the cluster's nodes that must be Ready. The result is
+	// reported in the NodesHealthy condition.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MinReadyNodesPercentage *int32 `json:"minReadyNodesPercentage,omitempty"`
+
+	// ReadyzChecks are the names of the /readyz checks of the API server that must pass, e.g. etcd or
+	// informer-sync. The result is reported in the APIServerHealthy condition.
+	// +optional
+	ReadyzChecks []string `json:"readyzChecks,omitempty"`
+
+	// Canary configures a probe that creates and deletes a canary object in the cluster. The result is reported in
+	// the CanaryHealthy condition.
+	// +optional
+	Canary *ClusterCanaryProbe `json:"canary,omitempty"`
+
+	// HTTPProbes are HTTP requests sent through the API server of the cluster, e.g. to the service proxy of an
+	// in-cluster component. The result is reported in the HTTPProbesHealthy condition.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	HTTPProbes []ClusterHTTPProbe `json:"httpProbes,omitempty"`
+}
+
+// ClusterCanaryProbe configures a probe that creates and deletes a canary ConfigMap in a member cluster.
+type ClusterCanaryProbe struct {
+	// Namespace in which the canary ConfigMap is created. Defaults to "default".
+	// +optional
+	// +kubebuilder:default:="default"
+	Namespace string `json:"namespace,omitempty"`
+	// MaxLatency is the maximum duration for creating and deleting the canary ConfigMap. Defaults to 5 seconds.
+	// +optional
+	// +kubebuilder:default:="5s"
+	// +kubebuilder:validation:Format:=duration
+	MaxLatency metav1.Duration `json:"maxLatency,omitempty"`
+}
+
+// ClusterHTTPProbe is an HTTP GET request sent through the API server of a member cluster. The probe succeeds if the
+// response has a 2xx status code.
+type ClusterHTTPProbe struct {
+	// Name of the probe.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Path of the request, e.g. /api/v1/namespaces/kube-system/services/kube-dns:dns-tcp/proxy/health.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+	// Timeout of the request. Defaults to 5 seconds.
+	// +optional
+	// +kubebuilder:default:="5s"
+	// +kubebuilder:validation:Format:=duration
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ReplicaEstimatorConfig defines a replica estimator service that simulates scheduling against the nodes of a member
+// cluster.
+type ReplicaEstimatorConfig struct {
+	// URL at which the replica estimator is available.
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+	// TLSConfig specifies the transport layer security config.
+	TLSConfig *WebhookTLSConfig `json:"tlsConfig,omitempty"`
+	// HTTPTimeout specifies the timeout duration for a call to the replica estimator.
+	// Defaults to 5 seconds.
+	// +kubebuilder:default:="5s"
+	// +kubebuilder:validation:Format:=duration
+	HTTPTimeout metav1.Duration `json:"httpTimeout,omitempty"`
 }
 
 // FederatedClusterStatus defines the observed state of FederatedCluster
@@ -93,8 +303,60 @@ type FederatedClusterStatus struct {
 	// If true, clean-up is required on cluster removal to undo the side-effects.
 	// +optional
 	JoinPerformed bool `json:"joinPerformed,omitempty"`
+	// Drain reports the progress of draining the cluster.
+	// +optional
+	Drain *ClusterDrainStatus `json:"drain,omitempty"`
+	// Agent reports the state of the agent of a cluster in Pull mode.
+	// +optional
+	Agent *ClusterAgentStatus `json:"agent,omitempty"`
+	// Credentials reports the credentials in the cluster secret that are used to access the cluster.
+	// +optional
+	Credentials *ClusterCredentialsStatus `json:"credentials,omitempty"`
+}
+
+// ClusterCredentialsStatus reports the credentials in the secret of a cluster.
+type ClusterCredentialsStatus struct {
+	// SecretResourceVersion is the resource version of the cluster secret when the credentials were last observed.
+	// Clients of the cluster are rebuilt when it changes.
+	// +optional
+	SecretResourceVersion string `json:"secretResourceVersion,omitempty"`
+	// ExpirationTime is the earliest time at which any of the credentials expires. Unset if none of them expires.
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+}
+
+// ClusterAgentStatus reports the state of the agent of a cluster in Pull mode.
+type ClusterAgentStatus struct {
+	// LastHeartbeatTime is the last time the agent reported the status of the cluster.
+	LastHeartbeatTime metav1.Time `json:"lastHeartbeatTime"`
+	// HeartbeatPeriod is the period at which the agent reports the status of the cluster.
+	// +optional
+	HeartbeatPeriod *metav1.Duration `json:"heartbeatPeriod,omitempty"`
 }
 
+// ClusterDrainStatus reports the progress of draining a cluster.
+type ClusterDrainStatus struct {
+	// Phase of the drain.
+	Phase ClusterDrainPhase `json:"phase"`
+	// StartTime is the time the drain started.
+	StartTime metav1.Time `json:"startTime"`
+	// CompletionTime is the time no federated objects were left in the cluster.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// RemainingObjects is the number of federated objects that are still placed in the cluster.
+	RemainingObjects int64 `json:"remainingObjects"`
+}
+
+// +kubebuilder:validation:Enum=Draining;Drained
+type ClusterDrainPhase string
+
+const (
+	// ClusterDraining means federated objects are still placed in the cluster.
+	ClusterDraining ClusterDrainPhase = "Draining"
+	// ClusterDrained means no federated objects are placed in the cluster.
+	ClusterDrained ClusterDrainPhase = "Drained"
+)
+
 // LocalSecretReference is a reference to a secret within the enclosing namespace.
 type LocalSecretReference struct {
 	// Name of a secret within the enclosing namespace
@@ -127,6 +389,39 @@ const (
 	ClusterReady ClusterConditionType = "Ready"
 	// ClusterOffline means the cluster is temporarily down or not reachable.
 	ClusterOffline ClusterConditionType = "Offline"
+
+	// ClusterNodesHealthy means enough nodes of the cluster are Ready.
+	ClusterNodesHealthy ClusterConditionType = "NodesHealthy"
+	// ClusterAPIServerHealthy means the configured /readyz checks of the cluster's API server pass.
+	ClusterAPIServerHealthy ClusterConditionType = "APIServerHealthy"
+	// ClusterCanaryHealthy means a canary object can be created and deleted in the cluster in time.
+	ClusterCanaryHealthy ClusterConditionType = "CanaryHealthy"
+	// ClusterHTTPProbesHealthy means the configured HTTP probes of the cluster succeed.
+	ClusterHTTPProbesHealthy ClusterConditionType = "HTTPProbesHealthy"
+
+	// ClusterCredentialsExpiring means the credentials used to access the cluster have expired or are close to
+	// expiring.
+	ClusterCredentialsExpiring ClusterConditionType = "CredentialsExpiring"
+)
+
+// ClusterHealthConditionTypes are the condition types reported by the health checks of a cluster.
+var ClusterHealthConditionTypes = []ClusterConditionType{
+	ClusterNodesHealthy,
+	ClusterAPIServerHealthy,
+	ClusterCanaryHealthy,
+	ClusterHTTPProbesHealthy,
+}
+
+// These are the taints that are added and removed by the federated cluster controller according to the spec and
+// conditions of a cluster.
+const (
+	// TaintClusterNotReady is added with the NoSchedule effect when the Ready condition of a cluster is not True.
+	TaintClusterNotReady = "kubeadmiral.io/cluster-not-ready"
+	// TaintClusterUnreachable is added with the NoExecute effect when a cluster has been offline for longer than a
+	// grace period. It is only added if the grace period is configured in the controller manager.
+	TaintClusterUnreachable = "kubeadmiral.io/cluster-unreachable"
+	// TaintClusterUnschedulable is added with the NoSchedule effect when a cluster is cordoned or drained.
+	TaintClusterUnschedulable = "kubeadmiral.io/cluster-unschedulable"
 )
 
 // Resources describes a cluster's resources
@@ -140,4 +435,17 @@ type Resources struct {
 	// Available represents the resources currently available for scheduling.
 	// +optional
 	Available corev1.ResourceList `json:"available,omitempty"`
+	// ResourceModel describes how the available cpu and memory are distributed across the schedulable nodes.
+	// It is used to estimate how many replicas of a given size fit in the cluster, since the aggregated
+	// available resources may be fragmented across many nodes.
+	// +optional
+	ResourceModel []ResourceModelGrade `json:"resourceModel,omitempty"`
+}
+
+// ResourceModelGrade is a group of nodes with similar available resources.
+type ResourceModelGrade struct {
+	// Available is the cpu and memory available on each node of the grade, rounded down to the grade's boundaries.
+	Available corev1.ResourceList `json:"available"`
+	// Nodes is the number of nodes in the grade.
+	Nodes int64 `json:"nodes"`
 }
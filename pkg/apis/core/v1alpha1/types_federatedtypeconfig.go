@@ -34,6 +34,10 @@ const (
 
 	RolloutPlanEnabled  RolloutPlanMode = "Enabled"
 	RolloutPlanDisabled RolloutPlanMode = "Disabled"
+
+	DriftDetectionDisabled    DriftDetectionMode = "Disabled"
+	DriftDetectionReportOnly  DriftDetectionMode = "ReportOnly"
+	DriftDetectionAutoCorrect DriftDetectionMode = "AutoCorrect"
 )
 
 // +genclient
@@ -88,11 +92,21 @@ type FederatedTypeConfigSpec struct {
 	StatusCollection *StatusCollection `json:"statusCollection,omitempty"`
 	// Whether or not Status should be aggregated to source type object
 	StatusAggregation *StatusAggregationMode `json:"statusAggregation,omitempty"`
+	// The webhook that aggregates statuses for source types without a built-in status aggregation plugin,
+	// e.g. custom resources. It is ignored for source types that have a built-in plugin.
+	// +optional
+	StatusAggregationWebhook *StatusAggregationWebhookConfig `json:"statusAggregationWebhook,omitempty"`
 	// Whether or not keep revisionHistory for the federatedType resource
 	RevisionHistory *RevisionHistoryMode `json:"revisionHistory,omitempty"`
 	// Whether or not to plan the rollout process
 	// +optional
 	RolloutPlan *RolloutPlanMode `json:"rolloutPlan,omitempty"`
+	// Whether or not to detect changes made directly to the objects in member clusters, and whether they should only be
+	// reported or also be reverted. It can be overridden for an object with the drift-detection annotation. Changes that
+	// are reintroduced after repeated reverts, e.g. by a mutating webhook, are reported as persistent and are not
+	// reverted again for a while. Defaults to Disabled.
+	// +optional
+	DriftDetection *DriftDetectionMode `json:"driftDetection,omitempty"`
 	// Configurations for auto migration.
 	// +optional
 	AutoMigration *AutoMigrationConfig `json:"autoMigration,omitempty"`
@@ -133,6 +147,11 @@ type PathDefinition struct {
 	// E.g. `status.readyReplicas` for Deployment and ReplicaSet.
 	// +optional
 	ReadyReplicasStatus string `json:"readyReplicasStatus,omitempty"`
+
+	// Path to a corev1.PodSpec field that describes the pods created for this object.
+	// E.g. `spec.template.spec` for Deployment and `spec.jobTemplate.spec.template.spec` for CronJob.
+	// +optional
+	PodSpec string `json:"podSpec,omitempty"`
 }
 
 // FederatedTypeConfigStatus defines the observed state of FederatedTypeConfig
@@ -152,10 +171,38 @@ type StatusCollection struct {
 // StatusAggregationMode defines the state of status aggregation.
 type StatusAggregationMode string
 
+// StatusAggregationWebhookConfig defines a webhook that receives the statuses of the member cluster objects and
+// returns the aggregated status of the source object.
+type StatusAggregationWebhookConfig struct {
+	// PayloadVersions is an ordered list of preferred request and response
+	// versions the webhook expects.
+	// The status aggregator will try to use the first version in
+	// the list which it supports. If none of the versions specified in this list
+	// supported by the status aggregator, statuses will not be aggregated for this type.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	PayloadVersions []string `json:"payloadVersions"`
+	// URL at which the webhook is available.
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+	// TLSConfig specifies the transport layer security config.
+	TLSConfig *WebhookTLSConfig `json:"tlsConfig,omitempty"`
+	// HTTPTimeout specifies the timeout duration for a call to the webhook.
+	// Defaults to 5 seconds.
+	// +kubebuilder:default:="5s"
+	// +kubebuilder:validation:Format:=duration
+	HTTPTimeout metav1.Duration `json:"httpTimeout,omitempty"`
+}
+
 type RevisionHistoryMode string
 
 type RolloutPlanMode string
 
+// DriftDetectionMode defines how the differences between the objects in member clusters and their desired state are
+// handled when they are not caused by a change of the federated object.
+// +kubebuilder:validation:Enum=Disabled;ReportOnly;AutoCorrect
+type DriftDetectionMode string
+
 type AutoMigrationConfig struct {
 	// Whether or not to enable auto migration.
 	Enabled bool `json:"enabled"`
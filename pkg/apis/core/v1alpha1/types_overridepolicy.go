@@ -3,6 +3,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -82,11 +83,117 @@ type TargetClusters struct {
 }
 
 type Overriders struct {
+	// Image specifies overriders that apply to the images of the containers in the pod spec.
+	// +optional
+	Image []ImageOverrider `json:"image,omitempty"`
+
+	// Command specifies overriders that apply to the commands of the containers in the pod spec.
+	// +optional
+	Command []EntrypointOverrider `json:"command,omitempty"`
+
+	// Args specifies overriders that apply to the args of the containers in the pod spec.
+	// +optional
+	Args []EntrypointOverrider `json:"args,omitempty"`
+
+	// Env specifies overriders that apply to the environment variables of the containers in the pod spec.
+	// +optional
+	Env []EnvOverrider `json:"env,omitempty"`
+
+	// Labels specifies overriders that apply to the labels of the resource.
+	// +optional
+	Labels []StringMapOverrider `json:"labels,omitempty"`
+
+	// Annotations specifies overriders that apply to the annotations of the resource.
+	// +optional
+	Annotations []StringMapOverrider `json:"annotations,omitempty"`
+
 	// JsonPatch specifies overriders in a syntax similar to RFC6902 JSON Patch.
 	// +optional
 	JsonPatch []JsonPatchOverrider `json:"jsonpatch,omitempty"`
 }
 
+// ImageOverrider overrides the images of containers.
+// The containers are located with the pod spec path in the FederatedTypeConfig's path definition,
+// and the overrider is ignored for types that do not define one.
+type ImageOverrider struct {
+	// ContainerNames selects the containers and init containers whose images are overridden.
+	// Empty ContainerNames selects all containers and init containers.
+	// +optional
+	ContainerNames []string `json:"containerNames,omitempty"`
+
+	// Operations are the operations performed on the images, in order.
+	Operations []ImageOperation `json:"operations"`
+}
+
+type ImageOperation struct {
+	// Operator specifies the operation.
+	// If omitted, defaults to "overwrite".
+	// +kubebuilder:validation:Enum=addIfAbsent;overwrite;delete
+	// +optional
+	Operator string `json:"operator,omitempty"`
+
+	// ImageComponent is the component of the image that the operation is performed on.
+	// For example, the image "docker.io/library/nginx:1.25@sha256:..." has the Registry "docker.io",
+	// the Repository "library/nginx", the Tag "1.25" and the Digest "sha256:...".
+	// +kubebuilder:validation:Enum=Registry;Repository;Tag;Digest
+	ImageComponent string `json:"imageComponent"`
+
+	// Value is the value required by the operation.
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// EntrypointOverrider overrides the command or args of a container.
+// The containers are located with the pod spec path in the FederatedTypeConfig's path definition,
+// and the overrider is ignored for types that do not define one.
+type EntrypointOverrider struct {
+	// ContainerName selects the container or init container to override.
+	ContainerName string `json:"containerName"`
+
+	// Operator specifies the operation.
+	// If omitted, defaults to "overwrite".
+	// +kubebuilder:validation:Enum=append;overwrite
+	// +optional
+	Operator string `json:"operator,omitempty"`
+
+	// Value is the value(s) required by the operation.
+	// +optional
+	Value []string `json:"value,omitempty"`
+}
+
+// EnvOverrider overrides the environment variables of a container.
+// The containers are located with the pod spec path in the FederatedTypeConfig's path definition,
+// and the overrider is ignored for types that do not define one.
+type EnvOverrider struct {
+	// ContainerName selects the container or init container to override.
+	ContainerName string `json:"containerName"`
+
+	// Operator specifies the operation.
+	// If omitted, defaults to "overwrite".
+	// For "delete", only the names of the environment variables in Value are used.
+	// +kubebuilder:validation:Enum=addIfAbsent;overwrite;delete
+	// +optional
+	Operator string `json:"operator,omitempty"`
+
+	// Value is the environment variables required by the operation.
+	// +optional
+	Value []corev1.EnvVar `json:"value,omitempty"`
+}
+
+// StringMapOverrider overrides a map of strings, such as the labels or annotations of a resource.
+type StringMapOverrider struct {
+	// Operator specifies the operation.
+	// If omitted, defaults to "overwrite".
+	// For "delete", only the keys in Value are used.
+	// +kubebuilder:validation:Enum=addIfAbsent;overwrite;delete
+	// +optional
+	Operator string `json:"operator,omitempty"`
+
+	// Value is the entries required by the operation.
+	// +optional
+	Value map[string]string `json:"value,omitempty"`
+}
+
 type JsonPatchOverrider struct {
 	// Operator specifies the operation.
 	// If omitted, defaults to "replace".
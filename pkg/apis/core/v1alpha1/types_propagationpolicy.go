@@ -90,6 +90,21 @@ type PropagationPolicySpec struct {
 	// +optional
 	Placements []Placement `json:"placement,omitempty"`
 
+	// TopologySpread spreads the federated object across the topology domains of member clusters, e.g. regions or
+	// zones.
+	// +optional
+	TopologySpread *TopologySpreadConstraint `json:"topologySpread,omitempty"`
+
+	// Priority is the scheduling priority of the federated objects propagated by this policy. When the clusters
+	// selected for an object lack the capacity for its replicas, the replicas of objects with a lower priority in
+	// these clusters may be preempted according to PreemptionPolicy. Defaults to 0.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+	// PreemptionPolicy determines whether the federated objects propagated by this policy may preempt the replicas
+	// of objects with a lower priority. Defaults to PreemptLowerPriority.
+	// +optional
+	PreemptionPolicy *PreemptionPolicy `json:"preemptionPolicy,omitempty"`
+
 	// DisableFollowerScheduling is a boolean that determines if follower scheduling is disabled.
 	// Resources that depend on other resources (e.g. deployments) are called leaders,
 	// and resources that are depended on (e.g. configmaps and secrets) are called followers.
@@ -107,6 +122,12 @@ type PropagationPolicySpec struct {
 	// Default set via a post-generation patch.
 	// See patch file for details.
 	ReplicaRescheduling *ReplicaRescheduling `json:"replicaRescheduling,omitempty"`
+
+	// RolloutStrategy configures how a new revision of the template of a federated object is rolled out to its
+	// clusters, and whether failed rollouts are rolled back automatically. If absent, all clusters are updated at once
+	// and rollouts are not analyzed.
+	// +optional
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
 }
 
 type PropagationPolicyStatus struct {
@@ -124,6 +145,17 @@ const (
 	SchedulingModeDivide SchedulingMode = "Divide"
 )
 
+// PreemptionPolicy determines whether a federated object may preempt the replicas of objects with a lower priority.
+// +kubebuilder:validation:Enum=PreemptLowerPriority;Never
+type PreemptionPolicy string
+
+const (
+	// PreemptLowerPriority means the federated object may preempt the replicas of objects with a lower priority.
+	PreemptLowerPriority PreemptionPolicy = "PreemptLowerPriority"
+	// PreemptNever means the federated object never preempts the replicas of other objects.
+	PreemptNever PreemptionPolicy = "Never"
+)
+
 // Placement describes a cluster that a federated object can be propagated to and its propagation preferences.
 type Placement struct {
 	// Cluster is the name of the FederatedCluster to propagate to.
@@ -153,6 +185,28 @@ type Preferences struct {
 	Weight *int64 `json:"weight,omitempty"`
 }
 
+// TopologySpreadConstraint describes how to spread a federated object across topology domains.
+type TopologySpreadConstraint struct {
+	// TopologyKey is the key of the cluster labels. Clusters that have a label with this key and identical values are
+	// considered to be in the same topology domain. Clusters without this label are not selected.
+	// +kubebuilder:validation:MinLength=1
+	TopologyKey string `json:"topologyKey"`
+
+	// MaxSkew is the maximum permitted difference between the number of replicas in any two selected topology domains
+	// in Divide mode. When rescheduling with replica disruption avoided, the existing distribution is kept if its skew
+	// does not exceed MaxSkew; otherwise replicas are divided evenly between the domains.
+	// +optional
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	MaxSkew int64 `json:"maxSkew,omitempty"`
+
+	// MinDomains is the minimum number of topology domains that must be selected. The federated object is
+	// unschedulable if fewer domains are available.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinDomains int64 `json:"minDomains,omitempty"`
+}
+
 // Preferences regarding auto migration.
 type AutoMigration struct {
 	// When a replica should be subject to auto migration.
@@ -175,6 +229,37 @@ type AutoMigrationTrigger struct {
 	// +optional
 	// +kubebuilder:validation:Format:=duration
 	PodUnschedulableDuration *metav1.Duration `json:"podUnschedulableFor,omitempty"`
+
+	// A pod will be subject to auto migration if it is scheduled but remains not ready beyond this duration.
+	// Duration should be specified in a format that can be parsed by Go's time.ParseDuration.
+	// +optional
+	// +kubebuilder:validation:Format:=duration
+	PodNotReadyDuration *metav1.Duration `json:"podNotReadyFor,omitempty"`
+
+	// A pod will be subject to auto migration if any of its containers is waiting in CrashLoopBackOff,
+	// ImagePullBackOff or ErrImagePull, and the pod remains not ready beyond this duration.
+	// Duration should be specified in a format that can be parsed by Go's time.ParseDuration.
+	// +optional
+	// +kubebuilder:validation:Format:=duration
+	ContainerBackOffDuration *metav1.Duration `json:"containerBackOffFor,omitempty"`
+
+	// All pods in a cluster will be subject to auto migration if the cluster's Ready condition is flapping.
+	// +optional
+	ClusterReadyFlapping *ClusterReadyFlappingTrigger `json:"clusterReadyFlapping,omitempty"`
+}
+
+// Criteria for determining when a cluster's Ready condition is flapping.
+type ClusterReadyFlappingTrigger struct {
+	// The minimum number of transitions of the Ready condition within the window for the cluster to be considered
+	// flapping. At most 64 transitions are recorded for each cluster.
+	// +kubebuilder:validation:Minimum:=2
+	// +kubebuilder:validation:Maximum:=64
+	MinTransitions int64 `json:"minTransitions"`
+
+	// The window in which transitions are counted.
+	// Duration should be specified in a format that can be parsed by Go's time.ParseDuration.
+	// +kubebuilder:validation:Format:=duration
+	Window metav1.Duration `json:"window"`
 }
 
 // Preferences regarding replica rescheduling.
@@ -187,3 +272,76 @@ type ReplicaRescheduling struct {
 	// +kubebuilder:default:=true
 	AvoidDisruption bool `json:"avoidDisruption"`
 }
+
+// RolloutStrategy configures a staged rollout, in which a new revision of the template is rolled out to the clusters
+// of a federated object in ordered waves. A wave is only started after all clusters of the previous waves have been
+// updated and are available. Staged rollouts require the revision history of the federated type to be enabled. The
+// availability of clusters in Pull mode is determined from the status reported by their agents, which requires the
+// status collection of the federated type to collect the status field.
+type RolloutStrategy struct {
+	// Waves are the ordered waves of clusters, e.g. a canary wave followed by the remaining clusters. A cluster
+	// belongs to the first wave that selects it. Clusters that are not selected by any wave are updated in a final
+	// wave. If empty, all clusters are updated in a single wave.
+	// +optional
+	Waves []RolloutWave `json:"waves,omitempty"`
+
+	// BakeTime is the amount of time to wait after all clusters of a wave are available before starting the next
+	// wave. Defaults to 0.
+	// Duration should be specified in a format that can be parsed by Go's time.ParseDuration.
+	// +optional
+	// +kubebuilder:validation:Format:=duration
+	BakeTime *metav1.Duration `json:"bakeTime,omitempty"`
+
+	// AutoRollback configures the analysis of the health of a rollout. If the rollout of a revision fails, the
+	// template of the source object is reverted to the last revision that was rolled out successfully. Rollouts are
+	// only analyzed for objects with a rollout strategy; to roll back failed rollouts without staging them, set a
+	// rollout strategy without waves, which rolls out to all clusters in a single wave.
+	// +optional
+	AutoRollback *AutoRollback `json:"autoRollback,omitempty"`
+}
+
+// AutoRollback configures when the rollout of a revision is considered failed. The health of a rollout is only
+// analyzed until it has reached all clusters. A failed rollout is rolled back to the last revision that was rolled out
+// successfully. If there is no such revision, the rollout stays paused until the template is changed again, e.g. by
+// fixing the template or by requesting a rollback with the rollback-to annotation. At least one of
+// MinAvailablePercentage and ProgressDeadline must be set, otherwise auto rollback is disabled and an error is
+// reported in the events of the federated object.
+type AutoRollback struct {
+	// MinAvailablePercentage is the minimum percentage of the desired replicas of the clusters updated to the new
+	// revision that must be available. Only the replicas of the new revision are counted. If absent, the available
+	// replicas are not analyzed.
+	// +optional
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=100
+	MinAvailablePercentage *int32 `json:"minAvailablePercentage,omitempty"`
+
+	// AnalysisDelay is the amount of time to wait after a wave is started before the available replicas are
+	// analyzed, which gives the replicas of the new revision time to become available. Defaults to 5m.
+	// Duration should be specified in a format that can be parsed by Go's time.ParseDuration.
+	// +optional
+	// +kubebuilder:validation:Format:=duration
+	AnalysisDelay *metav1.Duration `json:"analysisDelay,omitempty"`
+
+	// ProgressDeadline is the maximum amount of time for the new revision to reach all clusters, after which the
+	// rollout is considered stalled. If absent, the progress of the rollout is not analyzed.
+	// Duration should be specified in a format that can be parsed by Go's time.ParseDuration.
+	// +optional
+	// +kubebuilder:validation:Format:=duration
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+}
+
+// RolloutWave selects the clusters of a wave by name or by labels. A cluster is selected if it is in Clusters or
+// matches ClusterSelector.
+type RolloutWave struct {
+	// Name is the name of the wave reported in events.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Clusters is an explicit list of the names of the clusters in the wave.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// ClusterSelector is a label query over the clusters in the wave. An empty ClusterSelector selects no clusters.
+	// +optional
+	ClusterSelector map[string]string `json:"clusterSelector,omitempty"`
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=pulledobjects,singular=pulledobject
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="status",type=string,JSONPath=`.status.propagationStatus`
+// +kubebuilder:printcolumn:name="age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// PulledObject holds an object placed in a member cluster in Pull mode, and the status of the object reported by the
+// agent of the cluster. PulledObjects are maintained by the sync controller in the pull namespace of their cluster, so
+// that the agent of a cluster is only granted access to the objects placed in its own cluster.
+type PulledObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PulledObjectSpec `json:"spec"`
+	// +optional
+	Status PulledObjectStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// PulledObjectList contains a list of PulledObject
+type PulledObjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PulledObject `json:"items"`
+}
+
+// PulledObjectSpec defines the desired state of PulledObject
+type PulledObjectSpec struct {
+	// Template is the object to apply in the cluster, with the overrides for the cluster applied.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Template apiextensionsv1.JSON `json:"template"`
+	// AdoptResources indicates whether an object that pre-exists in the cluster should be adopted.
+	// +optional
+	AdoptResources bool `json:"adoptResources,omitempty"`
+	// RetainReplicas indicates whether the replicas of the object in the cluster should be retained.
+	// +optional
+	RetainReplicas bool `json:"retainReplicas,omitempty"`
+}
+
+// PulledObjectStatus defines the observed state of PulledObject
+type PulledObjectStatus struct {
+	// ObservedGeneration is the generation of the PulledObject last applied by the agent.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// PropagationStatus is the result of applying the template in the cluster, using the propagation statuses of
+	// federated objects.
+	// +optional
+	PropagationStatus string `json:"propagationStatus,omitempty"`
+	// Message describes why the template could not be applied.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// ClusterGeneration is the generation of the object in the cluster when the status was last reported.
+	// +optional
+	ClusterGeneration int64 `json:"clusterGeneration,omitempty"`
+	// CollectedFields holds the fields of the object in the cluster configured by the status collection of the
+	// FederatedTypeConfig of the object.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	CollectedFields *apiextensionsv1.JSON `json:"collectedFields,omitempty"`
+}
@@ -7,6 +7,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -68,6 +69,21 @@ func (in *AutoMigrationTrigger) DeepCopyInto(out *AutoMigrationTrigger) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.PodNotReadyDuration != nil {
+		in, out := &in.PodNotReadyDuration, &out.PodNotReadyDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ContainerBackOffDuration != nil {
+		in, out := &in.ContainerBackOffDuration, &out.ContainerBackOffDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ClusterReadyFlapping != nil {
+		in, out := &in.ClusterReadyFlapping, &out.ClusterReadyFlapping
+		*out = new(ClusterReadyFlappingTrigger)
+		**out = **in
+	}
 	return
 }
 
@@ -81,6 +97,102 @@ func (in *AutoMigrationTrigger) DeepCopy() *AutoMigrationTrigger {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoRollback) DeepCopyInto(out *AutoRollback) {
+	*out = *in
+	if in.MinAvailablePercentage != nil {
+		in, out := &in.MinAvailablePercentage, &out.MinAvailablePercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AnalysisDelay != nil {
+		in, out := &in.AnalysisDelay, &out.AnalysisDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ProgressDeadline != nil {
+		in, out := &in.ProgressDeadline, &out.ProgressDeadline
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoRollback.
+func (in *AutoRollback) DeepCopy() *AutoRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAgentStatus) DeepCopyInto(out *ClusterAgentStatus) {
+	*out = *in
+	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
+	if in.HeartbeatPeriod != nil {
+		in, out := &in.HeartbeatPeriod, &out.HeartbeatPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAgentStatus.
+func (in *ClusterAgentStatus) DeepCopy() *ClusterAgentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAgentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAuth) DeepCopyInto(out *ClusterAuth) {
+	*out = *in
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(ClusterExecAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(ClusterOIDCAuth)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAuth.
+func (in *ClusterAuth) DeepCopy() *ClusterAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCanaryProbe) DeepCopyInto(out *ClusterCanaryProbe) {
+	*out = *in
+	out.MaxLatency = in.MaxLatency
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCanaryProbe.
+func (in *ClusterCanaryProbe) DeepCopy() *ClusterCanaryProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCanaryProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
 	*out = *in
@@ -99,6 +211,164 @@ func (in *ClusterCondition) DeepCopy() *ClusterCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCredentialsStatus) DeepCopyInto(out *ClusterCredentialsStatus) {
+	*out = *in
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCredentialsStatus.
+func (in *ClusterCredentialsStatus) DeepCopy() *ClusterCredentialsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCredentialsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDrain) DeepCopyInto(out *ClusterDrain) {
+	*out = *in
+	if in.MaxUnavailablePercentage != nil {
+		in, out := &in.MaxUnavailablePercentage, &out.MaxUnavailablePercentage
+		*out = new(int32)
+		**out = **in
+	}
+	out.StepInterval = in.StepInterval
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDrain.
+func (in *ClusterDrain) DeepCopy() *ClusterDrain {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDrain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDrainStatus) DeepCopyInto(out *ClusterDrainStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDrainStatus.
+func (in *ClusterDrainStatus) DeepCopy() *ClusterDrainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDrainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExecAuth) DeepCopyInto(out *ClusterExecAuth) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]ExecEnvVar, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExecAuth.
+func (in *ClusterExecAuth) DeepCopy() *ClusterExecAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExecAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHTTPProbe) DeepCopyInto(out *ClusterHTTPProbe) {
+	*out = *in
+	out.Timeout = in.Timeout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHTTPProbe.
+func (in *ClusterHTTPProbe) DeepCopy() *ClusterHTTPProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHTTPProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealthCheck) DeepCopyInto(out *ClusterHealthCheck) {
+	*out = *in
+	if in.MinReadyNodesPercentage != nil {
+		in, out := &in.MinReadyNodesPercentage, &out.MinReadyNodesPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReadyzChecks != nil {
+		in, out := &in.ReadyzChecks, &out.ReadyzChecks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(ClusterCanaryProbe)
+		**out = **in
+	}
+	if in.HTTPProbes != nil {
+		in, out := &in.HTTPProbes, &out.HTTPProbes
+		*out = make([]ClusterHTTPProbe, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealthCheck.
+func (in *ClusterHealthCheck) DeepCopy() *ClusterHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterOIDCAuth) DeepCopyInto(out *ClusterOIDCAuth) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterOIDCAuth.
+func (in *ClusterOIDCAuth) DeepCopy() *ClusterOIDCAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterOIDCAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterObjectVersion) DeepCopyInto(out *ClusterObjectVersion) {
 	*out = *in
@@ -297,6 +567,23 @@ func (in *ClusterPropagationPolicyList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterReadyFlappingTrigger) DeepCopyInto(out *ClusterReadyFlappingTrigger) {
+	*out = *in
+	out.Window = in.Window
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterReadyFlappingTrigger.
+func (in *ClusterReadyFlappingTrigger) DeepCopy() *ClusterReadyFlappingTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterReadyFlappingTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSelectorRequirement) DeepCopyInto(out *ClusterSelectorRequirement) {
 	*out = *in
@@ -348,6 +635,66 @@ func (in *ClusterSelectorTerm) DeepCopy() *ClusterSelectorTerm {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EntrypointOverrider) DeepCopyInto(out *EntrypointOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EntrypointOverrider.
+func (in *EntrypointOverrider) DeepCopy() *EntrypointOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(EntrypointOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvOverrider) DeepCopyInto(out *EnvOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvOverrider.
+func (in *EnvOverrider) DeepCopy() *EnvOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecEnvVar) DeepCopyInto(out *ExecEnvVar) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecEnvVar.
+func (in *ExecEnvVar) DeepCopy() *ExecEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FederatedCluster) DeepCopyInto(out *FederatedCluster) {
 	*out = *in
@@ -413,6 +760,16 @@ func (in *FederatedClusterList) DeepCopyObject() runtime.Object {
 func (in *FederatedClusterSpec) DeepCopyInto(out *FederatedClusterSpec) {
 	*out = *in
 	out.SecretRef = in.SecretRef
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(ClusterAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccountTokenExpiration != nil {
+		in, out := &in.ServiceAccountTokenExpiration, &out.ServiceAccountTokenExpiration
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.Taints != nil {
 		in, out := &in.Taints, &out.Taints
 		*out = make([]corev1.Taint, len(*in))
@@ -420,6 +777,21 @@ func (in *FederatedClusterSpec) DeepCopyInto(out *FederatedClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ReplicaEstimator != nil {
+		in, out := &in.ReplicaEstimator, &out.ReplicaEstimator
+		*out = new(ReplicaEstimatorConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(ClusterHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(ClusterDrain)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -449,6 +821,21 @@ func (in *FederatedClusterStatus) DeepCopyInto(out *FederatedClusterStatus) {
 		*out = make([]APIResource, len(*in))
 		copy(*out, *in)
 	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(ClusterDrainStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = new(ClusterAgentStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(ClusterCredentialsStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -548,6 +935,11 @@ func (in *FederatedTypeConfigSpec) DeepCopyInto(out *FederatedTypeConfigSpec) {
 		*out = new(StatusAggregationMode)
 		**out = **in
 	}
+	if in.StatusAggregationWebhook != nil {
+		in, out := &in.StatusAggregationWebhook, &out.StatusAggregationWebhook
+		*out = new(StatusAggregationWebhookConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RevisionHistory != nil {
 		in, out := &in.RevisionHistory, &out.RevisionHistory
 		*out = new(RevisionHistoryMode)
@@ -558,6 +950,11 @@ func (in *FederatedTypeConfigSpec) DeepCopyInto(out *FederatedTypeConfigSpec) {
 		*out = new(RolloutPlanMode)
 		**out = **in
 	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetectionMode)
+		**out = **in
+	}
 	if in.AutoMigration != nil {
 		in, out := &in.AutoMigration, &out.AutoMigration
 		*out = new(AutoMigrationConfig)
@@ -628,22 +1025,64 @@ func (in *GenericOverridePolicySpec) DeepCopy() *GenericOverridePolicySpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GenericRefCountedStatus) DeepCopyInto(out *GenericRefCountedStatus) {
+func (in *GenericRefCountedStatus) DeepCopyInto(out *GenericRefCountedStatus) {
+	*out = *in
+	if in.TypedRefCount != nil {
+		in, out := &in.TypedRefCount, &out.TypedRefCount
+		*out = make([]TypedRefCount, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericRefCountedStatus.
+func (in *GenericRefCountedStatus) DeepCopy() *GenericRefCountedStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GenericRefCountedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageOperation) DeepCopyInto(out *ImageOperation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageOperation.
+func (in *ImageOperation) DeepCopy() *ImageOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageOverrider) DeepCopyInto(out *ImageOverrider) {
 	*out = *in
-	if in.TypedRefCount != nil {
-		in, out := &in.TypedRefCount, &out.TypedRefCount
-		*out = make([]TypedRefCount, len(*in))
+	if in.ContainerNames != nil {
+		in, out := &in.ContainerNames, &out.ContainerNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]ImageOperation, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericRefCountedStatus.
-func (in *GenericRefCountedStatus) DeepCopy() *GenericRefCountedStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageOverrider.
+func (in *ImageOverrider) DeepCopy() *ImageOverrider {
 	if in == nil {
 		return nil
 	}
-	out := new(GenericRefCountedStatus)
+	out := new(ImageOverrider)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -788,6 +1227,48 @@ func (in *OverrideRule) DeepCopy() *OverrideRule {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Overriders) DeepCopyInto(out *Overriders) {
 	*out = *in
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = make([]ImageOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]EntrypointOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]EntrypointOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]EnvOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]StringMapOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]StringMapOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.JsonPatch != nil {
 		in, out := &in.JsonPatch, &out.JsonPatch
 		*out = make([]JsonPatchOverrider, len(*in))
@@ -1123,6 +1604,21 @@ func (in *PropagationPolicySpec) DeepCopyInto(out *PropagationPolicySpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TopologySpread != nil {
+		in, out := &in.TopologySpread, &out.TopologySpread
+		*out = new(TopologySpreadConstraint)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PreemptionPolicy != nil {
+		in, out := &in.PreemptionPolicy, &out.PreemptionPolicy
+		*out = new(PreemptionPolicy)
+		**out = **in
+	}
 	if in.AutoMigration != nil {
 		in, out := &in.AutoMigration, &out.AutoMigration
 		*out = new(AutoMigration)
@@ -1133,6 +1629,11 @@ func (in *PropagationPolicySpec) DeepCopyInto(out *PropagationPolicySpec) {
 		*out = new(ReplicaRescheduling)
 		**out = **in
 	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1163,6 +1664,127 @@ func (in *PropagationPolicyStatus) DeepCopy() *PropagationPolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulledObject) DeepCopyInto(out *PulledObject) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulledObject.
+func (in *PulledObject) DeepCopy() *PulledObject {
+	if in == nil {
+		return nil
+	}
+	out := new(PulledObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PulledObject) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulledObjectList) DeepCopyInto(out *PulledObjectList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PulledObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulledObjectList.
+func (in *PulledObjectList) DeepCopy() *PulledObjectList {
+	if in == nil {
+		return nil
+	}
+	out := new(PulledObjectList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PulledObjectList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulledObjectSpec) DeepCopyInto(out *PulledObjectSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulledObjectSpec.
+func (in *PulledObjectSpec) DeepCopy() *PulledObjectSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PulledObjectSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulledObjectStatus) DeepCopyInto(out *PulledObjectStatus) {
+	*out = *in
+	if in.CollectedFields != nil {
+		in, out := &in.CollectedFields, &out.CollectedFields
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulledObjectStatus.
+func (in *PulledObjectStatus) DeepCopy() *PulledObjectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PulledObjectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaEstimatorConfig) DeepCopyInto(out *ReplicaEstimatorConfig) {
+	*out = *in
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(WebhookTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	out.HTTPTimeout = in.HTTPTimeout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaEstimatorConfig.
+func (in *ReplicaEstimatorConfig) DeepCopy() *ReplicaEstimatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaEstimatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicaRescheduling) DeepCopyInto(out *ReplicaRescheduling) {
 	*out = *in
@@ -1179,6 +1801,29 @@ func (in *ReplicaRescheduling) DeepCopy() *ReplicaRescheduling {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceModelGrade) DeepCopyInto(out *ResourceModelGrade) {
+	*out = *in
+	if in.Available != nil {
+		in, out := &in.Available, &out.Available
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceModelGrade.
+func (in *ResourceModelGrade) DeepCopy() *ResourceModelGrade {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceModelGrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Resources) DeepCopyInto(out *Resources) {
 	*out = *in
@@ -1201,6 +1846,13 @@ func (in *Resources) DeepCopyInto(out *Resources) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.ResourceModel != nil {
+		in, out := &in.ResourceModel, &out.ResourceModel
+		*out = make([]ResourceModelGrade, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -1214,6 +1866,67 @@ func (in *Resources) DeepCopy() *Resources {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]RolloutWave, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BakeTime != nil {
+		in, out := &in.BakeTime, &out.BakeTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AutoRollback != nil {
+		in, out := &in.AutoRollback, &out.AutoRollback
+		*out = new(AutoRollback)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutWave) DeepCopyInto(out *RolloutWave) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWave.
+func (in *RolloutWave) DeepCopy() *RolloutWave {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutWave)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SchedulerPluginWebhookConfiguration) DeepCopyInto(out *SchedulerPluginWebhookConfiguration) {
 	*out = *in
@@ -1389,6 +2102,33 @@ func (in *SchedulingProfileSpec) DeepCopy() *SchedulingProfileSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusAggregationWebhookConfig) DeepCopyInto(out *StatusAggregationWebhookConfig) {
+	*out = *in
+	if in.PayloadVersions != nil {
+		in, out := &in.PayloadVersions, &out.PayloadVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(WebhookTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	out.HTTPTimeout = in.HTTPTimeout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusAggregationWebhookConfig.
+func (in *StatusAggregationWebhookConfig) DeepCopy() *StatusAggregationWebhookConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusAggregationWebhookConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatusCollection) DeepCopyInto(out *StatusCollection) {
 	*out = *in
@@ -1410,6 +2150,29 @@ func (in *StatusCollection) DeepCopy() *StatusCollection {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StringMapOverrider) DeepCopyInto(out *StringMapOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringMapOverrider.
+func (in *StringMapOverrider) DeepCopy() *StringMapOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(StringMapOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetClusters) DeepCopyInto(out *TargetClusters) {
 	*out = *in
@@ -1445,6 +2208,22 @@ func (in *TargetClusters) DeepCopy() *TargetClusters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologySpreadConstraint) DeepCopyInto(out *TopologySpreadConstraint) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologySpreadConstraint.
+func (in *TopologySpreadConstraint) DeepCopy() *TopologySpreadConstraint {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologySpreadConstraint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TypedRefCount) DeepCopyInto(out *TypedRefCount) {
 	*out = *in
@@ -0,0 +1,45 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PayloadVersion is the version of the payload that is used to communicate with replica estimators.
+const PayloadVersion = "v1alpha1"
+
+// MaxAvailableReplicasRequest asks a replica estimator for the maximum number of replicas of a workload that can
+// currently be scheduled in a member cluster.
+type MaxAvailableReplicasRequest struct {
+	// ClusterName is the name of the member cluster, so that an estimator may serve multiple clusters.
+	ClusterName string `json:"clusterName"`
+	// ResourceRequest is the list of resources requested by each replica.
+	ResourceRequest corev1.ResourceList `json:"resourceRequest,omitempty"`
+	// NodeSelector is the node selector of the replicas' pod template.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// NodeAffinity is the required node affinity of the replicas' pod template.
+	NodeAffinity *corev1.NodeSelector `json:"nodeAffinity,omitempty"`
+	// Tolerations are the tolerations of the replicas' pod template.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+type MaxAvailableReplicasResponse struct {
+	// MaxReplicas is the maximum number of additional replicas that can be scheduled in the member cluster.
+	MaxReplicas int64  `json:"maxReplicas"`
+	Error       string `json:"error"`
+}
@@ -63,6 +63,10 @@ type SchedulingUnit struct {
 	MaxClusters *int64 `json:"maxClusters,omitempty"`
 	// Placements is the placements set in the PropgationPolicy.
 	Placements []fedcorev1a1.Placement `json:"placements,omitempty"`
+	// TopologySpread is the topology spread constraint set in the PropagationPolicy.
+	TopologySpread *fedcorev1a1.TopologySpreadConstraint `json:"topologySpread,omitempty"`
+	// Priority is the scheduling priority of the object.
+	Priority int32 `json:"priority,omitempty"`
 }
 
 type FilterRequest struct {
@@ -0,0 +1,51 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+)
+
+// PayloadVersion is the version of the payload that is used to communicate with the status aggregation webhook.
+const PayloadVersion = "v1alpha1"
+
+// ClusterObjectStatus is the status of the object in a member cluster.
+type ClusterObjectStatus struct {
+	ClusterName string `json:"clusterName"`
+	// SyncedGeneration is the generation of the cluster object last synced by the sync controller. It is 0 if the
+	// cluster object has not been synced yet.
+	SyncedGeneration int64 `json:"syncedGeneration"`
+	// Status is the status of the cluster object. It is empty if the cluster object does not have a status yet.
+	Status map[string]interface{} `json:"status,omitempty"`
+}
+
+type AggregateStatusesRequest struct {
+	// SourceObject is the source object whose status is being aggregated. Its status is the last aggregated status.
+	SourceObject map[string]interface{} `json:"sourceObject"`
+	// ClusterObjectStatuses are the statuses of the cluster objects, sorted by cluster name.
+	ClusterObjectStatuses []ClusterObjectStatus `json:"clusterObjectStatuses"`
+	// ClusterObjectsUpToDate is true if all cluster objects have been synced from the latest generation of the source
+	// object. The webhook may use this to decide whether the source object's generation has been observed.
+	ClusterObjectsUpToDate bool `json:"clusterObjectsUpToDate"`
+}
+
+type AggregateStatusesResponse struct {
+	// Status is the aggregated status of the source object. The status of the source object is left unchanged if it is
+	// empty.
+	Status json.RawMessage `json:"status,omitempty"`
+	Error  string          `json:"error"`
+}
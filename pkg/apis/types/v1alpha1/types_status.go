@@ -63,6 +63,9 @@ type GenericClusterStatus struct {
 	Name       string            `json:"name"`
 	Status     PropagationStatus `json:"status,omitempty"`
 	Generation int64             `json:"generation,omitempty"`
+	// DriftedFields are the paths of the fields of the object in the cluster that differ from the desired object,
+	// reported when drift detection is enabled.
+	DriftedFields []string `json:"driftedFields,omitempty"`
 }
 
 type PropagationStatus string
@@ -70,6 +73,19 @@ type PropagationStatus string
 const (
 	ClusterPropagationOK PropagationStatus = "OK"
 	WaitingForRemoval    PropagationStatus = "WaitingForRemoval"
+	// WaitingForAgent means the cluster is in Pull mode and its agent has not reported applying the current version of
+	// the object.
+	WaitingForAgent PropagationStatus = "WaitingForAgent"
+	// WaitingForRolloutWave means the template of the object in the cluster is kept at its previous revision until
+	// the wave of the cluster is reached in a staged rollout.
+	WaitingForRolloutWave PropagationStatus = "WaitingForRolloutWave"
+	// Drifted means the object in the cluster was changed outside of the federated object and drift detection is in
+	// ReportOnly mode, so the changes are not reverted.
+	Drifted PropagationStatus = "Drifted"
+	// PersistentlyDrifted means drift detection is in AutoCorrect mode, but the changes to the object in the cluster
+	// were reintroduced after repeated corrections, e.g. by a mutating webhook, so they are no longer reverted for a
+	// while.
+	PersistentlyDrifted PropagationStatus = "PersistentlyDrifted"
 
 	// Cluster-specific errors
 
@@ -110,6 +126,11 @@ const (
 	CheckClusters          AggregateReason = "CheckClusters"
 	NamespaceNotFederated  AggregateReason = "NamespaceNotFederated"
 	EnsureDeletionFailed   AggregateReason = "EnsureDeletionFailed"
+
+	// StagedRolloutInProgress means the current revision has not been rolled out to all waves of a staged rollout.
+	StagedRolloutInProgress AggregateReason = "StagedRolloutInProgress"
+	// StagedRolloutFailed means the staged rollout of the current revision has failed and does not advance.
+	StagedRolloutFailed AggregateReason = "StagedRolloutFailed"
 )
 
 type ConditionType string
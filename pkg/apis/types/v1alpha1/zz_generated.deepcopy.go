@@ -154,6 +154,11 @@ func (in *GenericClusterReference) DeepCopy() *GenericClusterReference {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GenericClusterStatus) DeepCopyInto(out *GenericClusterStatus) {
 	*out = *in
+	if in.DriftedFields != nil {
+		in, out := &in.DriftedFields, &out.DriftedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -224,7 +229,9 @@ func (in *GenericFederatedStatus) DeepCopyInto(out *GenericFederatedStatus) {
 	if in.Clusters != nil {
 		in, out := &in.Clusters, &out.Clusters
 		*out = make([]GenericClusterStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
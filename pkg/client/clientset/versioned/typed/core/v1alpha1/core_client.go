@@ -18,6 +18,7 @@ type CoreV1alpha1Interface interface {
 	OverridePoliciesGetter
 	PropagatedVersionsGetter
 	PropagationPoliciesGetter
+	PulledObjectsGetter
 	SchedulerPluginWebhookConfigurationsGetter
 	SchedulingProfilesGetter
 }
@@ -59,6 +60,10 @@ func (c *CoreV1alpha1Client) PropagationPolicies(namespace string) PropagationPo
 	return newPropagationPolicies(c, namespace)
 }
 
+func (c *CoreV1alpha1Client) PulledObjects(namespace string) PulledObjectInterface {
+	return newPulledObjects(c, namespace)
+}
+
 func (c *CoreV1alpha1Client) SchedulerPluginWebhookConfigurations() SchedulerPluginWebhookConfigurationInterface {
 	return newSchedulerPluginWebhookConfigurations(c)
 }
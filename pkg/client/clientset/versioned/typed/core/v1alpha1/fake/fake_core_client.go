@@ -44,6 +44,10 @@ func (c *FakeCoreV1alpha1) PropagationPolicies(namespace string) v1alpha1.Propag
 	return &FakePropagationPolicies{c, namespace}
 }
 
+func (c *FakeCoreV1alpha1) PulledObjects(namespace string) v1alpha1.PulledObjectInterface {
+	return &FakePulledObjects{c, namespace}
+}
+
 func (c *FakeCoreV1alpha1) SchedulerPluginWebhookConfigurations() v1alpha1.SchedulerPluginWebhookConfigurationInterface {
 	return &FakeSchedulerPluginWebhookConfigurations{c}
 }
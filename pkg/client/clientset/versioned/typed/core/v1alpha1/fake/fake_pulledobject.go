@@ -0,0 +1,126 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakePulledObjects implements PulledObjectInterface
+type FakePulledObjects struct {
+	Fake *FakeCoreV1alpha1
+	ns   string
+}
+
+var pulledobjectsResource = schema.GroupVersionResource{Group: "core.kubeadmiral.io", Version: "v1alpha1", Resource: "pulledobjects"}
+
+var pulledobjectsKind = schema.GroupVersionKind{Group: "core.kubeadmiral.io", Version: "v1alpha1", Kind: "PulledObject"}
+
+// Get takes name of the pulledObject, and returns the corresponding pulledObject object, and an error if there is any.
+func (c *FakePulledObjects) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.PulledObject, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(pulledobjectsResource, c.ns, name), &v1alpha1.PulledObject{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.PulledObject), err
+}
+
+// List takes label and field selectors, and returns the list of PulledObjects that match those selectors.
+func (c *FakePulledObjects) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.PulledObjectList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(pulledobjectsResource, pulledobjectsKind, c.ns, opts), &v1alpha1.PulledObjectList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.PulledObjectList{ListMeta: obj.(*v1alpha1.PulledObjectList).ListMeta}
+	for _, item := range obj.(*v1alpha1.PulledObjectList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested pulledObjects.
+func (c *FakePulledObjects) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(pulledobjectsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a pulledObject and creates it.  Returns the server's representation of the pulledObject, and an error, if there is any.
+func (c *FakePulledObjects) Create(ctx context.Context, pulledObject *v1alpha1.PulledObject, opts v1.CreateOptions) (result *v1alpha1.PulledObject, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(pulledobjectsResource, c.ns, pulledObject), &v1alpha1.PulledObject{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.PulledObject), err
+}
+
+// Update takes the representation of a pulledObject and updates it. Returns the server's representation of the pulledObject, and an error, if there is any.
+func (c *FakePulledObjects) Update(ctx context.Context, pulledObject *v1alpha1.PulledObject, opts v1.UpdateOptions) (result *v1alpha1.PulledObject, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(pulledobjectsResource, c.ns, pulledObject), &v1alpha1.PulledObject{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.PulledObject), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakePulledObjects) UpdateStatus(ctx context.Context, pulledObject *v1alpha1.PulledObject, opts v1.UpdateOptions) (*v1alpha1.PulledObject, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(pulledobjectsResource, "status", c.ns, pulledObject), &v1alpha1.PulledObject{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.PulledObject), err
+}
+
+// Delete takes name of the pulledObject and deletes it. Returns an error if one occurs.
+func (c *FakePulledObjects) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(pulledobjectsResource, c.ns, name), &v1alpha1.PulledObject{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakePulledObjects) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(pulledobjectsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.PulledObjectList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched pulledObject.
+func (c *FakePulledObjects) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.PulledObject, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(pulledobjectsResource, c.ns, name, pt, data, subresources...), &v1alpha1.PulledObject{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.PulledObject), err
+}
@@ -18,6 +18,8 @@ type PropagatedVersionExpansion interface{}
 
 type PropagationPolicyExpansion interface{}
 
+type PulledObjectExpansion interface{}
+
 type SchedulerPluginWebhookConfigurationExpansion interface{}
 
 type SchedulingProfileExpansion interface{}
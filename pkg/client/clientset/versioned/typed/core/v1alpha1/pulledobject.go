@@ -0,0 +1,179 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	scheme "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// PulledObjectsGetter has a method to return a PulledObjectInterface.
+// A group's client should implement this interface.
+type PulledObjectsGetter interface {
+	PulledObjects(namespace string) PulledObjectInterface
+}
+
+// PulledObjectInterface has methods to work with PulledObject resources.
+type PulledObjectInterface interface {
+	Create(ctx context.Context, pulledObject *v1alpha1.PulledObject, opts v1.CreateOptions) (*v1alpha1.PulledObject, error)
+	Update(ctx context.Context, pulledObject *v1alpha1.PulledObject, opts v1.UpdateOptions) (*v1alpha1.PulledObject, error)
+	UpdateStatus(ctx context.Context, pulledObject *v1alpha1.PulledObject, opts v1.UpdateOptions) (*v1alpha1.PulledObject, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.PulledObject, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.PulledObjectList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.PulledObject, err error)
+	PulledObjectExpansion
+}
+
+// pulledObjects implements PulledObjectInterface
+type pulledObjects struct {
+	client rest.Interface
+	ns     string
+}
+
+// newPulledObjects returns a PulledObjects
+func newPulledObjects(c *CoreV1alpha1Client, namespace string) *pulledObjects {
+	return &pulledObjects{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the pulledObject, and returns the corresponding pulledObject object, and an error if there is any.
+func (c *pulledObjects) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.PulledObject, err error) {
+	result = &v1alpha1.PulledObject{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("pulledobjects").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of PulledObjects that match those selectors.
+func (c *pulledObjects) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.PulledObjectList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.PulledObjectList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("pulledobjects").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested pulledObjects.
+func (c *pulledObjects) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("pulledobjects").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a pulledObject and creates it.  Returns the server's representation of the pulledObject, and an error, if there is any.
+func (c *pulledObjects) Create(ctx context.Context, pulledObject *v1alpha1.PulledObject, opts v1.CreateOptions) (result *v1alpha1.PulledObject, err error) {
+	result = &v1alpha1.PulledObject{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("pulledobjects").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(pulledObject).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a pulledObject and updates it. Returns the server's representation of the pulledObject, and an error, if there is any.
+func (c *pulledObjects) Update(ctx context.Context, pulledObject *v1alpha1.PulledObject, opts v1.UpdateOptions) (result *v1alpha1.PulledObject, err error) {
+	result = &v1alpha1.PulledObject{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("pulledobjects").
+		Name(pulledObject.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(pulledObject).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *pulledObjects) UpdateStatus(ctx context.Context, pulledObject *v1alpha1.PulledObject, opts v1.UpdateOptions) (result *v1alpha1.PulledObject, err error) {
+	result = &v1alpha1.PulledObject{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("pulledobjects").
+		Name(pulledObject.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(pulledObject).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the pulledObject and deletes it. Returns an error if one occurs.
+func (c *pulledObjects) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("pulledobjects").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *pulledObjects) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("pulledobjects").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched pulledObject.
+func (c *pulledObjects) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.PulledObject, err error) {
+	result = &v1alpha1.PulledObject{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("pulledobjects").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
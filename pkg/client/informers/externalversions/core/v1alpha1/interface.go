@@ -24,6 +24,8 @@ type Interface interface {
 	PropagatedVersions() PropagatedVersionInformer
 	// PropagationPolicies returns a PropagationPolicyInformer.
 	PropagationPolicies() PropagationPolicyInformer
+	// PulledObjects returns a PulledObjectInformer.
+	PulledObjects() PulledObjectInformer
 	// SchedulerPluginWebhookConfigurations returns a SchedulerPluginWebhookConfigurationInformer.
 	SchedulerPluginWebhookConfigurations() SchedulerPluginWebhookConfigurationInformer
 	// SchedulingProfiles returns a SchedulingProfileInformer.
@@ -81,6 +83,11 @@ func (v *version) PropagationPolicies() PropagationPolicyInformer {
 	return &propagationPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// PulledObjects returns a PulledObjectInformer.
+func (v *version) PulledObjects() PulledObjectInformer {
+	return &pulledObjectInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // SchedulerPluginWebhookConfigurations returns a SchedulerPluginWebhookConfigurationInformer.
 func (v *version) SchedulerPluginWebhookConfigurations() SchedulerPluginWebhookConfigurationInformer {
 	return &schedulerPluginWebhookConfigurationInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
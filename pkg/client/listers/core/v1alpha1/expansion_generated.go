@@ -46,6 +46,14 @@ type PropagationPolicyListerExpansion interface{}
 // PropagationPolicyNamespaceLister.
 type PropagationPolicyNamespaceListerExpansion interface{}
 
+// PulledObjectListerExpansion allows custom methods to be added to
+// PulledObjectLister.
+type PulledObjectListerExpansion interface{}
+
+// PulledObjectNamespaceListerExpansion allows custom methods to be added to
+// PulledObjectNamespaceLister.
+type PulledObjectNamespaceListerExpansion interface{}
+
 // SchedulerPluginWebhookConfigurationListerExpansion allows custom methods to be added to
 // SchedulerPluginWebhookConfigurationLister.
 type SchedulerPluginWebhookConfigurationListerExpansion interface{}
@@ -0,0 +1,83 @@
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// PulledObjectLister helps list PulledObjects.
+// All objects returned here must be treated as read-only.
+type PulledObjectLister interface {
+	// List lists all PulledObjects in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.PulledObject, err error)
+	// PulledObjects returns an object that can list and get PulledObjects.
+	PulledObjects(namespace string) PulledObjectNamespaceLister
+	PulledObjectListerExpansion
+}
+
+// pulledObjectLister implements the PulledObjectLister interface.
+type pulledObjectLister struct {
+	indexer cache.Indexer
+}
+
+// NewPulledObjectLister returns a new PulledObjectLister.
+func NewPulledObjectLister(indexer cache.Indexer) PulledObjectLister {
+	return &pulledObjectLister{indexer: indexer}
+}
+
+// List lists all PulledObjects in the indexer.
+func (s *pulledObjectLister) List(selector labels.Selector) (ret []*v1alpha1.PulledObject, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.PulledObject))
+	})
+	return ret, err
+}
+
+// PulledObjects returns an object that can list and get PulledObjects.
+func (s *pulledObjectLister) PulledObjects(namespace string) PulledObjectNamespaceLister {
+	return pulledObjectNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// PulledObjectNamespaceLister helps list and get PulledObjects.
+// All objects returned here must be treated as read-only.
+type PulledObjectNamespaceLister interface {
+	// List lists all PulledObjects in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.PulledObject, err error)
+	// Get retrieves the PulledObject from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.PulledObject, error)
+	PulledObjectNamespaceListerExpansion
+}
+
+// pulledObjectNamespaceLister implements the PulledObjectNamespaceLister
+// interface.
+type pulledObjectNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all PulledObjects in the indexer for a given namespace.
+func (s pulledObjectNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.PulledObject, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.PulledObject))
+	})
+	return ret, err
+}
+
+// Get retrieves the PulledObject from the indexer for a given namespace and name.
+func (s pulledObjectNamespaceLister) Get(name string) (*v1alpha1.PulledObject, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("pulledobject"), name)
+	}
+	return obj.(*v1alpha1.PulledObject), nil
+}
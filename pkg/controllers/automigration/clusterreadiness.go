@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package automigration
+
+import (
+	"sync"
+	"time"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+// maxRecordedTransitions is the maximum number of Ready condition transitions recorded for each cluster. It must be
+// kept in sync with the maximum of ClusterReadyFlappingTrigger.MinTransitions.
+const maxRecordedTransitions = 64
+
+// clusterReadinessTracker records the transitions of the Ready condition of clusters observed by the controller.
+type clusterReadinessTracker struct {
+	lock        sync.Mutex
+	ready       map[string]bool
+	transitions map[string][]time.Time
+}
+
+func newClusterReadinessTracker() *clusterReadinessTracker {
+	return &clusterReadinessTracker{
+		ready:       map[string]bool{},
+		transitions: map[string][]time.Time{},
+	}
+}
+
+// observe records the readiness of a cluster and returns true if it has changed since the last observation.
+func (t *clusterReadinessTracker) observe(clusterName string, ready bool, now time.Time) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	lastReady, exists := t.ready[clusterName]
+	t.ready[clusterName] = ready
+	if !exists || lastReady == ready {
+		return false
+	}
+
+	transitions := append(t.transitions[clusterName], now)
+	if len(transitions) > maxRecordedTransitions {
+		transitions = transitions[len(transitions)-maxRecordedTransitions:]
+	}
+	t.transitions[clusterName] = transitions
+	return true
+}
+
+func (t *clusterReadinessTracker) forget(clusterName string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.ready, clusterName)
+	delete(t.transitions, clusterName)
+}
+
+// isFlapping returns true if the Ready condition of the cluster has transitioned at least trigger.MinTransitions times
+// within trigger.Window. If so, it also returns the time from now when the cluster will stop being considered flapping.
+func (t *clusterReadinessTracker) isFlapping(
+	clusterName string,
+	now time.Time,
+	trigger *fedcorev1a1.ClusterReadyFlappingTrigger,
+) (bool, time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	windowStart := now.Add(-trigger.Window.Duration)
+	transitions := t.transitions[clusterName]
+	inWindow := 0
+	for i := len(transitions) - 1; i >= 0 && transitions[i].After(windowStart); i-- {
+		inWindow++
+	}
+	if trigger.MinTransitions <= 0 || int64(inWindow) < trigger.MinTransitions {
+		return false, 0
+	}
+
+	// The cluster stops flapping when the oldest of the latest MinTransitions transitions leaves the window.
+	oldest := transitions[len(transitions)-int(trigger.MinTransitions)]
+	return true, oldest.Add(trigger.Window.Duration).Sub(now)
+}
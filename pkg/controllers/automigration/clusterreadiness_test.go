@@ -0,0 +1,58 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package automigration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+func TestClusterReadinessTracker(t *testing.T) {
+	now := time.Now()
+	trigger := &fedcorev1a1.ClusterReadyFlappingTrigger{
+		MinTransitions: 3,
+		Window:         metav1.Duration{Duration: 10 * time.Minute},
+	}
+	tracker := newClusterReadinessTracker()
+
+	// The first observation is not a transition.
+	assert.False(t, tracker.observe("c1", true, now.Add(-20*time.Minute)))
+	assert.False(t, tracker.observe("c1", true, now.Add(-15*time.Minute)))
+	assert.True(t, tracker.observe("c1", false, now.Add(-12*time.Minute)))
+	assert.True(t, tracker.observe("c1", true, now.Add(-8*time.Minute)))
+	assert.True(t, tracker.observe("c1", false, now.Add(-5*time.Minute)))
+
+	flapping, _ := tracker.isFlapping("c1", now, trigger)
+	assert.False(t, flapping, "only 2 transitions are within the window")
+
+	assert.True(t, tracker.observe("c1", true, now.Add(-time.Minute)))
+	flapping, recoversIn := tracker.isFlapping("c1", now, trigger)
+	assert.True(t, flapping)
+	assert.Equal(t, 2*time.Minute, recoversIn)
+
+	flapping, _ = tracker.isFlapping("c2", now, trigger)
+	assert.False(t, flapping)
+
+	tracker.forget("c1")
+	flapping, _ = tracker.isFlapping("c1", now, trigger)
+	assert.False(t, flapping)
+}
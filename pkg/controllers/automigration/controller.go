@@ -27,6 +27,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	dynamicclient "k8s.io/client-go/dynamic"
 	"k8s.io/client-go/informers"
@@ -43,6 +44,7 @@ import (
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/eventsink"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/replicaestimator"
 	utilunstructured "github.com/kubewharf/kubeadmiral/pkg/controllers/util/unstructured"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
 	"github.com/kubewharf/kubeadmiral/pkg/stats"
@@ -71,6 +73,8 @@ type Controller struct {
 	federatedObjectInformer informers.GenericInformer
 
 	federatedInformer util.FederatedInformer
+	clusterReadiness  *clusterReadinessTracker
+	replicaEstimator  replicaestimator.Estimator
 
 	worker worker.ReconcileWorker
 
@@ -101,6 +105,8 @@ func NewAutoMigrationController(
 
 		federatedObjectClient:   federatedObjectClient,
 		federatedObjectInformer: federatedObjectInformer,
+		clusterReadiness:        newClusterReadinessTracker(),
+		replicaEstimator:        replicaestimator.NewHTTPEstimator(replicaestimator.DefaultResultTTL),
 
 		metrics:       controllerConfig.Metrics,
 		logger:        klog.NewKlogr().WithValues("controller", "auto-migration", "ftc", typeConfig.Name),
@@ -112,18 +118,22 @@ func NewAutoMigrationController(
 		worker.WorkerTiming{},
 		controllerConfig.WorkerCount,
 		controllerConfig.Metrics,
-		delayingdeliver.NewMetricTags("auto-migration-worker", c.typeConfig.GetFederatedType().Kind),
+		delayingdeliver.NewFTCMetricTags("auto-migration-worker", c.typeConfig.GetFederatedType().Kind, c.typeConfig.Name),
 	)
 
 	federatedObjectInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		// Only need to handle UnschedulableThreshold updates
+		// Only need to handle auto migration trigger updates
 		// Addition and deletion will be triggered by the target resources.
 		UpdateFunc: func(oldUntyped, newUntyped interface{}) {
 			oldObj, newObj := oldUntyped.(*unstructured.Unstructured), newUntyped.(*unstructured.Unstructured)
-			oldThreshold := oldObj.GetAnnotations()[common.PodUnschedulableThresholdAnnotation]
-			newThreshold := newObj.GetAnnotations()[common.PodUnschedulableThresholdAnnotation]
-			if oldThreshold != newThreshold {
-				c.worker.Enqueue(common.NewQualifiedName(newObj))
+			for _, annotation := range []string{
+				common.AutoMigrationTriggerAnnotation,
+				common.PodUnschedulableThresholdAnnotation,
+			} {
+				if oldObj.GetAnnotations()[annotation] != newObj.GetAnnotations()[annotation] {
+					c.worker.Enqueue(common.NewQualifiedName(newObj))
+					return
+				}
 			}
 		},
 	})
@@ -139,7 +149,15 @@ func NewAutoMigrationController(
 			// enqueue with a delay to simulate a rudimentary rate limiter
 			c.worker.EnqueueWithDelay(common.NewQualifiedName(o), 10*time.Second)
 		},
-		&util.ClusterLifecycleHandlerFuncs{},
+		&util.ClusterLifecycleHandlerFuncs{
+			ClusterAvailable: func(cluster *fedcorev1a1.FederatedCluster) {
+				c.observeClusterReadiness(cluster.Name)
+			},
+			ClusterUnavailable: func(cluster *fedcorev1a1.FederatedCluster, _ []interface{}) {
+				c.replicaEstimator.Forget(cluster.Name)
+				c.observeClusterReadiness(cluster.Name)
+			},
+		},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create federated informer: %w", err)
@@ -181,10 +199,10 @@ func (c *Controller) reconcile(qualifiedName common.QualifiedName) (status worke
 	ctx := klog.NewContext(context.TODO(), keyedLogger)
 
 	startTime := time.Now()
-	c.metrics.Rate("auto-migration.throughput", 1)
+	c.metrics.Rate("auto-migration.throughput", 1, stats.Tag{Name: "ftc", Value: c.typeConfig.Name})
 	keyedLogger.V(3).Info("Start reconcile")
 	defer func() {
-		c.metrics.Duration(fmt.Sprintf("%s.latency", c.name), startTime)
+		c.metrics.Duration("auto-migration.latency", startTime, stats.Tag{Name: "ftc", Value: c.typeConfig.Name})
 		keyedLogger.V(3).Info("Finished reconcile", "duration", time.Since(startTime), "status", status.String())
 	}()
 
@@ -197,15 +215,11 @@ func (c *Controller) reconcile(qualifiedName common.QualifiedName) (status worke
 		return worker.StatusAllOK
 	}
 
-	// PodUnschedulableThresholdAnnotation is set by the scheduler. Its presence determines whether auto migration is enabled.
+	// AutoMigrationTriggerAnnotation is set by the scheduler. Its presence determines whether auto migration is enabled.
 	annotations := fedObject.GetAnnotations()
-	var unschedulableThreshold *time.Duration
-	if value, exists := annotations[common.PodUnschedulableThresholdAnnotation]; exists {
-		if duration, err := time.ParseDuration(value); err != nil {
-			keyedLogger.Error(err, "Failed to parse PodUnschedulableThresholdAnnotation")
-		} else {
-			unschedulableThreshold = &duration
-		}
+	autoMigrationTrigger, err := getAutoMigrationTrigger(annotations)
+	if err != nil {
+		keyedLogger.Error(err, "Failed to parse auto migration trigger")
 	}
 
 	// auto-migration controller sets AutoMigrationAnnotation to
@@ -214,7 +228,7 @@ func (c *Controller) reconcile(qualifiedName common.QualifiedName) (status worke
 	var estimatedCapacity map[string]int64
 	var result *worker.Result
 	needsUpdate := false
-	if unschedulableThreshold == nil {
+	if autoMigrationTrigger == nil {
 		// Clean up the annotation if auto migration is disabled.
 		keyedLogger.V(3).Info("Auto migration is disabled")
 		_, exists := annotations[common.AutoMigrationInfoAnnotation]
@@ -231,7 +245,7 @@ func (c *Controller) reconcile(qualifiedName common.QualifiedName) (status worke
 			return worker.StatusError
 		}
 
-		estimatedCapacity, result = c.estimateCapacity(ctx, clusterObjs, *unschedulableThreshold)
+		estimatedCapacity, result = c.estimateCapacity(ctx, fedObject, clusterObjs, autoMigrationTrigger)
 		autoMigrationInfo := &framework.AutoMigrationInfo{EstimatedCapacity: estimatedCapacity}
 
 		// Compare with the existing autoMigration annotation
@@ -291,8 +305,9 @@ func (c *Controller) reconcile(qualifiedName common.QualifiedName) (status worke
 
 func (c *Controller) estimateCapacity(
 	ctx context.Context,
+	fedObject *unstructured.Unstructured,
 	clusterObjs []util.FederatedObject,
-	unschedulableThreshold time.Duration,
+	trigger *fedcorev1a1.AutoMigrationTrigger,
 ) (map[string]int64, *worker.Result) {
 	keyedLogger := klog.FromContext(ctx)
 	needsBackoff := false
@@ -306,10 +321,27 @@ func (c *Controller) estimateCapacity(
 
 		unsClusterObj := clusterObj.Object.(*unstructured.Unstructured)
 
-		// This is an optimization to skip pod listing when there are no unschedulable pods.
+		if trigger.ClusterReadyFlapping != nil {
+			flapping, recoversIn := c.clusterReadiness.isFlapping(
+				clusterObj.ClusterName,
+				time.Now(),
+				trigger.ClusterReadyFlapping,
+			)
+			if flapping {
+				// All pods in a flapping cluster are subject to auto migration.
+				keyedLogger.V(2).Info("Cluster is flapping", "recoversIn", recoversIn)
+				estimatedCapacity[clusterObj.ClusterName] = 0
+				if retryAfter == nil || recoversIn < *retryAfter {
+					retryAfter = &recoversIn
+				}
+				continue
+			}
+		}
+
+		// This is an optimization to skip pod listing when there are no failing pods.
 		totalReplicas, readyReplicas, err := c.getTotalAndReadyReplicas(unsClusterObj)
 		if err == nil && totalReplicas == readyReplicas {
-			keyedLogger.V(3).Info("No unschedulable pods found, skip estimating capacity")
+			keyedLogger.V(3).Info("No failing pods found, skip estimating capacity")
 			continue
 		}
 
@@ -329,17 +361,23 @@ func (c *Controller) estimateCapacity(
 			continue
 		}
 
-		unschedulable, nextCrossIn := countUnschedulablePods(pods, time.Now(), unschedulableThreshold)
+		failing, nextCrossIn := countFailingPods(pods, time.Now(), trigger)
 
 		var clusterEstimatedCapacity int64
 		if len(pods) >= int(desiredReplicas) {
 			// When len(pods) >= desiredReplicas, we can immediately determine the capacity by taking the number of
-			// schedulable pods.
-			clusterEstimatedCapacity = int64(len(pods) - unschedulable)
+			// pods that are not failing.
+			clusterEstimatedCapacity = int64(len(pods) - failing)
 		} else {
 			// If len(pods) < desiredReplicas, we have uncreated pods. We must treat the uncreated pods as schedulable
-			// to prevent them from being unnecessarily migrated before creation.
-			clusterEstimatedCapacity = desiredReplicas - int64(unschedulable)
+			// to prevent them from being unnecessarily migrated before creation, unless the cluster's replica
+			// estimator tells us that they cannot be scheduled.
+			uncreatedReplicas := desiredReplicas - int64(len(pods))
+			if schedulable, ok := c.estimateSchedulableReplicas(ctx, fedObject, clusterObj.ClusterName); ok &&
+				schedulable < uncreatedReplicas {
+				uncreatedReplicas = schedulable
+			}
+			clusterEstimatedCapacity = int64(len(pods)-failing) + uncreatedReplicas
 		}
 
 		if clusterEstimatedCapacity >= desiredReplicas {
@@ -357,7 +395,7 @@ func (c *Controller) estimateCapacity(
 		keyedLogger.V(2).Info("Analyzed pods",
 			"total", len(pods),
 			"desired", desiredReplicas,
-			"unschedulable", unschedulable,
+			"failing", failing,
 		)
 
 		if nextCrossIn != nil && (retryAfter == nil || *nextCrossIn < *retryAfter) {
@@ -376,6 +414,34 @@ func (c *Controller) estimateCapacity(
 	return estimatedCapacity, result
 }
 
+// estimateSchedulableReplicas returns the number of additional replicas that can be scheduled in the cluster according
+// to its replica estimator. It returns false if the cluster has no replica estimator or the estimation failed.
+func (c *Controller) estimateSchedulableReplicas(
+	ctx context.Context,
+	fedObject *unstructured.Unstructured,
+	clusterName string,
+) (int64, bool) {
+	keyedLogger := klog.FromContext(ctx)
+
+	cluster, exists, err := c.federatedInformer.GetReadyCluster(clusterName)
+	if err != nil || !exists || cluster.Spec.ReplicaEstimator == nil {
+		return 0, false
+	}
+
+	podSpec, err := replicaestimator.GetPodSpec(fedObject, c.typeConfig.Spec.PathDefinition.PodSpec)
+	if err != nil {
+		keyedLogger.Error(err, "Failed to get pod spec for replica estimation")
+		return 0, false
+	}
+
+	replicas, err := c.replicaEstimator.MaxAvailableReplicas(ctx, cluster, replicaestimator.NewRequest(clusterName, podSpec))
+	if err != nil {
+		keyedLogger.Error(err, "Failed to estimate replicas")
+		return 0, false
+	}
+	return replicas, true
+}
+
 func (c *Controller) getTotalAndReadyReplicas(
 	unsObj *unstructured.Unstructured,
 ) (int64, int64, error) {
@@ -437,3 +503,54 @@ func (c *Controller) getPodsFromCluster(
 
 	return pods, false, nil
 }
+
+// getAutoMigrationTrigger returns the auto migration trigger set by the scheduler, or nil if auto migration is disabled.
+func getAutoMigrationTrigger(annotations map[string]string) (*fedcorev1a1.AutoMigrationTrigger, error) {
+	if value, exists := annotations[common.AutoMigrationTriggerAnnotation]; exists {
+		trigger := &fedcorev1a1.AutoMigrationTrigger{}
+		if err := json.Unmarshal([]byte(value), trigger); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", common.AutoMigrationTriggerAnnotation, err)
+		}
+		return trigger, nil
+	}
+
+	// Objects that have not been rescheduled since the introduction of AutoMigrationTriggerAnnotation only have the
+	// legacy PodUnschedulableThresholdAnnotation.
+	if value, exists := annotations[common.PodUnschedulableThresholdAnnotation]; exists {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", common.PodUnschedulableThresholdAnnotation, err)
+		}
+		return &fedcorev1a1.AutoMigrationTrigger{PodUnschedulableDuration: &metav1.Duration{Duration: duration}}, nil
+	}
+
+	return nil, nil
+}
+
+// observeClusterReadiness records the current readiness of the cluster, and enqueues all objects if it has changed so
+// that clusters with a flapping Ready condition are accounted for.
+func (c *Controller) observeClusterReadiness(clusterName string) {
+	cluster, exists, err := c.federatedInformer.GetCluster(clusterName)
+	if err != nil {
+		c.logger.Error(err, "Failed to get cluster", "cluster", clusterName)
+		return
+	}
+	if !exists {
+		c.clusterReadiness.forget(clusterName)
+		return
+	}
+
+	if !c.clusterReadiness.observe(clusterName, util.IsClusterReady(&cluster.Status), time.Now()) {
+		return
+	}
+
+	c.logger.V(2).Info("Observed cluster readiness transition", "cluster", clusterName)
+	fedObjects, err := c.federatedObjectInformer.Lister().List(labels.Everything())
+	if err != nil {
+		c.logger.Error(err, "Failed to list federated objects")
+		return
+	}
+	for _, fedObject := range fedObjects {
+		c.worker.Enqueue(common.NewQualifiedName(fedObject))
+	}
+}
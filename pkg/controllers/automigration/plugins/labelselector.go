@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	utilunstructured "github.com/kubewharf/kubeadmiral/pkg/controllers/util/unstructured"
+)
+
+// labelSelectorPlugin finds the pods of an object using the label selector at labelSelectorPath. Unlike the native
+// plugins, it does not check the owner references of the pods, since the pods of custom workloads are commonly owned
+// indirectly, e.g. through ReplicaSets.
+type labelSelectorPlugin struct {
+	labelSelectorPath string
+}
+
+func (p *labelSelectorPlugin) GetPodsForClusterObject(
+	ctx context.Context,
+	unsObj *unstructured.Unstructured,
+	handle ClusterHandle,
+) ([]*corev1.Pod, error) {
+	selectorMap, exists, err := unstructured.NestedMap(
+		unsObj.Object,
+		utilunstructured.SplitDotPath(p.labelSelectorPath, nil)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access label selector at %s: %w", p.labelSelectorPath, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("no label selector at %s", p.labelSelectorPath)
+	}
+
+	selector := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selectorMap, selector); err != nil {
+		return nil, fmt.Errorf("failed to convert label selector: %w", err)
+	}
+
+	return listPodsForSelector(ctx, handle, unsObj.GetNamespace(), selector)
+}
+
+var _ Plugin = &labelSelectorPlugin{}
@@ -43,7 +43,9 @@ type Plugin interface {
 }
 
 var nativePlugins = map[schema.GroupVersionResource]Plugin{
-	common.DeploymentGVR: &deploymentPlugin{},
+	common.DeploymentGVR:  &deploymentPlugin{},
+	common.StatefulSetGVR: &statefulSetPlugin{},
+	common.ReplicaSetGVR:  &replicaSetPlugin{},
 }
 
 func ResolvePlugin(typeConfig *fedcorev1a1.FederatedTypeConfig) (Plugin, error) {
@@ -54,5 +56,10 @@ func ResolvePlugin(typeConfig *fedcorev1a1.FederatedTypeConfig) (Plugin, error)
 		return plugin, nil
 	}
 
+	// Fall back to finding pods by the label selector of the object for other replica-based types.
+	if labelSelectorPath := typeConfig.Spec.PathDefinition.LabelSelector; labelSelectorPath != "" {
+		return &labelSelectorPlugin{labelSelectorPath: labelSelectorPath}, nil
+	}
+
 	return nil, fmt.Errorf("unsupported type %s", targetGVR.String())
 }
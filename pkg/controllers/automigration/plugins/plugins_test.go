@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/client/generic"
+)
+
+type fakePodClient struct {
+	generic.Client
+	pods []corev1.Pod
+}
+
+func (c *fakePodClient) ListWithOptions(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	selector, err := labels.Parse(listOpts.Raw.LabelSelector)
+	if err != nil {
+		return err
+	}
+
+	podList := obj.(*corev1.PodList)
+	for _, pod := range c.pods {
+		if pod.Namespace == listOpts.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+			podList.Items = append(podList.Items, pod)
+		}
+	}
+	return nil
+}
+
+func newPod(name string, podLabels map[string]string, owner types.UID) corev1.Pod {
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: podLabels}}
+	if owner != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{UID: owner, Controller: pointer.Bool(true)}}
+	}
+	return pod
+}
+
+func toUnstructured(t *testing.T, obj interface{}) *unstructured.Unstructured {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	return &unstructured.Unstructured{Object: content}
+}
+
+func podNames(pods []*corev1.Pod) []string {
+	names := []string{}
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+func TestGetPodsForClusterObject(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}}
+	objectMeta := metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "owner"}
+	handle := ClusterHandle{Client: &fakePodClient{pods: []corev1.Pod{
+		newPod("owned", map[string]string{"app": "test"}, "owner"),
+		newPod("owned-by-other", map[string]string{"app": "test"}, "other"),
+		newPod("orphan", map[string]string{"app": "test"}, ""),
+		newPod("unmatched", map[string]string{"app": "other"}, "owner"),
+	}}}
+
+	tests := []struct {
+		name          string
+		typeConfig    *fedcorev1a1.FederatedTypeConfig
+		obj           interface{}
+		expectedPods  []string
+		expectedError bool
+	}{
+		{
+			name:         "statefulset",
+			typeConfig:   newTypeConfig(appsv1.SchemeGroupVersion.WithResource("statefulsets"), ""),
+			obj:          &appsv1.StatefulSet{ObjectMeta: objectMeta, Spec: appsv1.StatefulSetSpec{Selector: selector}},
+			expectedPods: []string{"owned"},
+		},
+		{
+			name:         "replicaset",
+			typeConfig:   newTypeConfig(appsv1.SchemeGroupVersion.WithResource("replicasets"), ""),
+			obj:          &appsv1.ReplicaSet{ObjectMeta: objectMeta, Spec: appsv1.ReplicaSetSpec{Selector: selector}},
+			expectedPods: []string{"owned"},
+		},
+		{
+			name: "custom resource with label selector path",
+			typeConfig: newTypeConfig(
+				appsv1.SchemeGroupVersion.WithResource("daemonsets"),
+				"spec.selector",
+			),
+			obj:          &appsv1.DaemonSet{ObjectMeta: objectMeta, Spec: appsv1.DaemonSetSpec{Selector: selector}},
+			expectedPods: []string{"owned", "owned-by-other", "orphan"},
+		},
+		{
+			name: "empty label selector matches no pods",
+			typeConfig: newTypeConfig(
+				appsv1.SchemeGroupVersion.WithResource("daemonsets"),
+				"spec.selector",
+			),
+			obj: &appsv1.DaemonSet{
+				ObjectMeta: objectMeta,
+				Spec:       appsv1.DaemonSetSpec{Selector: &metav1.LabelSelector{}},
+			},
+			expectedPods: []string{},
+		},
+		{
+			name: "missing label selector",
+			typeConfig: newTypeConfig(
+				appsv1.SchemeGroupVersion.WithResource("daemonsets"),
+				"spec.selector",
+			),
+			obj:           &appsv1.DaemonSet{ObjectMeta: objectMeta},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin, err := ResolvePlugin(tt.typeConfig)
+			assert.NoError(t, err)
+
+			pods, err := plugin.GetPodsForClusterObject(context.Background(), toUnstructured(t, tt.obj), handle)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPods, podNames(pods))
+		})
+	}
+}
+
+func TestResolvePluginUnsupportedType(t *testing.T) {
+	_, err := ResolvePlugin(newTypeConfig(appsv1.SchemeGroupVersion.WithResource("daemonsets"), ""))
+	assert.Error(t, err)
+}
+
+func newTypeConfig(gvr schema.GroupVersionResource, labelSelectorPath string) *fedcorev1a1.FederatedTypeConfig {
+	return &fedcorev1a1.FederatedTypeConfig{
+		Spec: fedcorev1a1.FederatedTypeConfigSpec{
+			TargetType: fedcorev1a1.APIResource{
+				Group:      gvr.Group,
+				Version:    gvr.Version,
+				PluralName: gvr.Resource,
+			},
+			PathDefinition: fedcorev1a1.PathDefinition{LabelSelector: labelSelectorPath},
+		},
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// listPodsForSelector lists the pods in namespace that match selector. An empty selector matches no pods.
+func listPodsForSelector(
+	ctx context.Context,
+	handle ClusterHandle,
+	namespace string,
+	selector *metav1.LabelSelector,
+) ([]*corev1.Pod, error) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+	if labelSelector.Empty() {
+		return []*corev1.Pod{}, nil
+	}
+
+	podList := &corev1.PodList{}
+	listOpts, err := convertListOptions(namespace, &metav1.ListOptions{LabelSelector: labelSelector.String()})
+	if err != nil {
+		return nil, err
+	}
+	if err := handle.Client.ListWithOptions(ctx, podList, listOpts); err != nil {
+		return nil, err
+	}
+
+	ret := []*corev1.Pod{}
+	for i := range podList.Items {
+		ret = append(ret, &podList.Items[i])
+	}
+	return ret, nil
+}
+
+// listPodsControlledBy lists the pods that match selector and are controlled by owner.
+func listPodsControlledBy(
+	ctx context.Context,
+	handle ClusterHandle,
+	owner metav1.Object,
+	selector *metav1.LabelSelector,
+) ([]*corev1.Pod, error) {
+	pods, err := listPodsForSelector(ctx, handle, owner.GetNamespace(), selector)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []*corev1.Pod{}
+	for _, pod := range pods {
+		if metav1.IsControlledBy(pod, owner) {
+			ret = append(ret, pod)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type replicaSetPlugin struct{}
+
+func (*replicaSetPlugin) GetPodsForClusterObject(
+	ctx context.Context,
+	unsObj *unstructured.Unstructured,
+	handle ClusterHandle,
+) ([]*corev1.Pod, error) {
+	replicaSet := &appsv1.ReplicaSet{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unsObj.Object, replicaSet); err != nil {
+		return nil, err
+	}
+
+	return listPodsControlledBy(ctx, handle, replicaSet, replicaSet.Spec.Selector)
+}
+
+var _ Plugin = &replicaSetPlugin{}
@@ -20,45 +20,90 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+// containerBackOffReasons are the waiting reasons of containers that are failing to start.
+var containerBackOffReasons = sets.New(
+	"CrashLoopBackOff",
+	"ImagePullBackOff",
+	"ErrImagePull",
 )
 
-// Returns the number of unschedulable pods that remain
-// unschedulable for more than unschedulableThreshold,
+// Returns the number of pods that have been failing for longer than
+// the corresponding threshold in trigger,
 // and a time.Duration representing the time from now
-// when the new unschedulable pod will cross the threshold, if any.
-func countUnschedulablePods(
+// when the new failing pod will cross the threshold, if any.
+func countFailingPods(
 	podList []*corev1.Pod,
 	currentTime time.Time,
-	unschedulableThreshold time.Duration,
-) (unschedulableCount int, nextCrossIn *time.Duration) {
+	trigger *fedcorev1a1.AutoMigrationTrigger,
+) (failingCount int, nextCrossIn *time.Duration) {
 	for _, pod := range podList {
 		if pod.GetDeletionTimestamp() != nil {
 			continue
 		}
 
-		var scheduledCondition *corev1.PodCondition
-		for i := range pod.Status.Conditions {
-			condition := &pod.Status.Conditions[i]
-			if condition.Type == corev1.PodScheduled {
-				scheduledCondition = condition
-				break
+		var podCrossingThresholdIn *time.Duration
+		observeFailure := func(failingSince metav1.Time, threshold *metav1.Duration) {
+			if threshold == nil {
+				return
+			}
+			crossingThresholdIn := failingSince.Add(threshold.Duration).Sub(currentTime)
+			if podCrossingThresholdIn == nil || *podCrossingThresholdIn > crossingThresholdIn {
+				podCrossingThresholdIn = &crossingThresholdIn
+			}
+		}
+
+		scheduledCondition := getPodCondition(pod, corev1.PodScheduled)
+		if scheduledCondition != nil &&
+			scheduledCondition.Status == corev1.ConditionFalse &&
+			scheduledCondition.Reason == corev1.PodReasonUnschedulable {
+			observeFailure(scheduledCondition.LastTransitionTime, trigger.PodUnschedulableDuration)
+		}
+
+		readyCondition := getPodCondition(pod, corev1.PodReady)
+		if pod.Status.Phase != corev1.PodSucceeded && readyCondition != nil && readyCondition.Status == corev1.ConditionFalse {
+			if scheduledCondition != nil && scheduledCondition.Status == corev1.ConditionTrue {
+				observeFailure(readyCondition.LastTransitionTime, trigger.PodNotReadyDuration)
+			}
+			if hasContainerInBackOff(pod) {
+				observeFailure(readyCondition.LastTransitionTime, trigger.ContainerBackOffDuration)
 			}
 		}
-		if scheduledCondition == nil ||
-			scheduledCondition.Status != corev1.ConditionFalse ||
-			scheduledCondition.Reason != corev1.PodReasonUnschedulable {
+
+		if podCrossingThresholdIn == nil {
 			continue
 		}
+		if *podCrossingThresholdIn <= 0 {
+			failingCount++
+		} else if nextCrossIn == nil || *nextCrossIn > *podCrossingThresholdIn {
+			nextCrossIn = podCrossingThresholdIn
+		}
+	}
+
+	return failingCount, nextCrossIn
+}
 
-		timeBecameUnschedulable := scheduledCondition.LastTransitionTime
-		timeCrossingThreshold := timeBecameUnschedulable.Add(unschedulableThreshold)
-		crossingThresholdIn := timeCrossingThreshold.Sub(currentTime)
-		if crossingThresholdIn <= 0 {
-			unschedulableCount++
-		} else if nextCrossIn == nil || *nextCrossIn > crossingThresholdIn {
-			nextCrossIn = &crossingThresholdIn
+func getPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == conditionType {
+			return &pod.Status.Conditions[i]
 		}
 	}
+	return nil
+}
 
-	return unschedulableCount, nextCrossIn
+func hasContainerInBackOff(pod *corev1.Pod) bool {
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, status := range statuses {
+			if status.State.Waiting != nil && containerBackOffReasons.Has(status.State.Waiting.Reason) {
+				return true
+			}
+		}
+	}
+	return false
 }
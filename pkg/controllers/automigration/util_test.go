@@ -24,6 +24,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 )
 
 func doCheck(
@@ -37,7 +39,8 @@ func doCheck(
 	t.Helper()
 	assert := assert.New(t)
 
-	unschedulableCount, nextCrossIn := countUnschedulablePods(pods, now, threshold)
+	trigger := &fedcorev1a1.AutoMigrationTrigger{PodUnschedulableDuration: &metav1.Duration{Duration: threshold}}
+	unschedulableCount, nextCrossIn := countFailingPods(pods, now, trigger)
 	assert.Equal(expectedUnschedulable, unschedulableCount)
 	assert.Equal(expectedNextCrossIn, nextCrossIn)
 }
@@ -120,3 +123,73 @@ func newPod(terminating bool, schedulable bool, lastTransitionTimestamp time.Tim
 	}
 	return pod
 }
+
+func TestCountFailingPods(t *testing.T) {
+	now := time.Now()
+	threshold := time.Minute
+	trigger := &fedcorev1a1.AutoMigrationTrigger{
+		PodNotReadyDuration:      &metav1.Duration{Duration: 5 * threshold},
+		ContainerBackOffDuration: &metav1.Duration{Duration: threshold},
+	}
+
+	newNotReadyPod := func(notReadySince time.Time, waitingReason string) *corev1.Pod {
+		pod := newPod(false, true, now.Add(-time.Hour))
+		pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+			Type:               corev1.PodReady,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Time{Time: notReadySince},
+		})
+		if waitingReason != "" {
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: waitingReason}}},
+			}
+		}
+		return pod
+	}
+
+	readyPod := newPod(false, true, now.Add(-time.Hour))
+	readyPod.Status.Conditions = append(readyPod.Status.Conditions, corev1.PodCondition{
+		Type:   corev1.PodReady,
+		Status: corev1.ConditionTrue,
+	})
+	notReadyPod := newNotReadyPod(now.Add(-10*threshold), "")
+	notReadyIn30s := newNotReadyPod(now.Add(30*time.Second-5*threshold), "")
+	crashLoopPod := newNotReadyPod(now.Add(-2*threshold), "CrashLoopBackOff")
+	imagePullIn10s := newNotReadyPod(now.Add(10*time.Second-threshold), "ImagePullBackOff")
+	creatingPod := newNotReadyPod(now.Add(-2*threshold), "ContainerCreating")
+	// Unschedulable pods are not subject to auto migration without PodUnschedulableDuration.
+	unschedulablePod := newPod(false, false, now.Add(-time.Hour))
+
+	tests := []struct {
+		name                string
+		pods                []*corev1.Pod
+		expectedFailing     int
+		expectedNextCrossIn *time.Duration
+	}{
+		{
+			name:            "ready and unschedulable pods",
+			pods:            []*corev1.Pod{readyPod, unschedulablePod},
+			expectedFailing: 0,
+		},
+		{
+			name:                "not ready pods",
+			pods:                []*corev1.Pod{readyPod, notReadyPod, notReadyIn30s},
+			expectedFailing:     1,
+			expectedNextCrossIn: pointer.Duration(30 * time.Second),
+		},
+		{
+			name:                "container back off",
+			pods:                []*corev1.Pod{crashLoopPod, imagePullIn10s, creatingPod, notReadyIn30s},
+			expectedFailing:     1,
+			expectedNextCrossIn: pointer.Duration(10 * time.Second),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failing, nextCrossIn := countFailingPods(tt.pods, now, trigger)
+			assert.Equal(t, tt.expectedFailing, failing)
+			assert.Equal(t, tt.expectedNextCrossIn, nextCrossIn)
+		})
+	}
+}
@@ -84,13 +84,16 @@ const (
 	// Rolling Update
 
 	StrategyField       = "strategy"
+	UpdateStrategyField = "updateStrategy"
 	RollingUpdateField  = "rollingUpdate"
 	MaxSurgeField       = "maxSurge"
 	MaxUnavailableField = "maxUnavailable"
+	PartitionField      = "partition"
 
 	// Status
 
 	AvailableReplicasField = "availableReplicas"
+	ConditionsField        = "conditions"
 )
 
 var (
@@ -100,6 +103,10 @@ var (
 	FollowsPath    = []string{SpecField, FollowsField}
 )
 
+// RolledBackConditionType is the type of the condition set on the status of a source object whose template was rolled
+// back by the sync controller.
+const RolledBackConditionType = "RolledBack"
+
 // The following consts are annotatation key-values used by Kubeadmiral controllers.
 
 const (
@@ -127,7 +134,17 @@ const (
 	EnableFollowerSchedulingAnnotation = InternalPrefix + "enable-follower-scheduling"
 
 	// When a pod remains unschedulable beyond this threshold, it becomes eligible for automatic migration.
+	// Deprecated: superseded by AutoMigrationTriggerAnnotation, only read for objects that have not been rescheduled.
 	PodUnschedulableThresholdAnnotation = InternalPrefix + "pod-unschedulable-threshold"
+	// AutoMigrationTriggerAnnotation contains the JSON-encoded criteria for automatic migration.
+	AutoMigrationTriggerAnnotation = InternalPrefix + "auto-migration-trigger"
+	// RolloutStrategyAnnotation contains the JSON-encoded staged rollout strategy of the object.
+	RolloutStrategyAnnotation = InternalPrefix + "rollout-strategy"
+	// RollbackToAnnotation requests the template of an object to be rolled back to a revision, identified by the name
+	// or the number of its ControllerRevision. It is removed from the object once the rollback is done.
+	RollbackToAnnotation = DefaultPrefix + "rollback-to"
+	// DriftDetectionAnnotation overrides the drift detection mode of the federated type config for an object.
+	DriftDetectionAnnotation = DefaultPrefix + "drift-detection"
 	// AutoMigrationInfoAnnotation contains auto migration information.
 	AutoMigrationInfoAnnotation = DefaultPrefix + "auto-migration-info"
 	// ObservedAnnotationKeysAnnotation contains annotation keys observed in the last reconcile.
@@ -165,3 +182,15 @@ var DeploymentGVR = schema.GroupVersionResource{
 	Version:  "v1",
 	Resource: "deployments",
 }
+
+var StatefulSetGVR = schema.GroupVersionResource{
+	Group:    "apps",
+	Version:  "v1",
+	Resource: "statefulsets",
+}
+
+var ReplicaSetGVR = schema.GroupVersionResource{
+	Group:    "apps",
+	Version:  "v1",
+	Resource: "replicasets",
+}
@@ -29,7 +29,9 @@ import (
 
 	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
 	fedinformers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/federatedclient"
+	"github.com/kubewharf/kubeadmiral/pkg/rolloutaudit"
 	"github.com/kubewharf/kubeadmiral/pkg/stats"
 )
 
@@ -41,10 +43,12 @@ type Context struct {
 	ClusterAvailableDelay   time.Duration
 	ClusterUnavailableDelay time.Duration
 
-	RestConfig      *rest.Config
-	ComponentConfig *ComponentConfig
+	RestConfig        *rest.Config
+	ClusterAuthConfig *util.ClusterAuthConfig
+	ComponentConfig   *ComponentConfig
 
-	Metrics stats.Metrics
+	Metrics          stats.Metrics
+	RolloutAuditSink rolloutaudit.Sink
 
 	KubeClientset          kubeclient.Interface
 	DynamicClientset       dynamicclient.Interface
@@ -73,7 +77,10 @@ func (c *Context) StartFactories(ctx context.Context) {
 }
 
 type ComponentConfig struct {
-	NSAutoPropExcludeRegexp              *regexp.Regexp
-	FederatedTypeConfigCreateCRDsForFTCs bool
-	ClusterJoinTimeout                   time.Duration
+	NSAutoPropExcludeRegexp               *regexp.Regexp
+	FederatedTypeConfigCreateCRDsForFTCs  bool
+	ClusterJoinTimeout                    time.Duration
+	ClusterUnreachableTaintGracePeriod    time.Duration
+	ClusterCredentialsExpiryWarningPeriod time.Duration
+	ClusterAgentHeartbeatTimeout          time.Duration
 }
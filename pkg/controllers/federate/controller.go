@@ -118,7 +118,7 @@ func NewFederateController(
 		worker.WorkerTiming{},
 		workerCount,
 		metrics,
-		delayingdeliver.NewMetricTags("federate-controller-worker", c.typeConfig.GetFederatedType().Kind),
+		delayingdeliver.NewFTCMetricTags("federate-controller-worker", c.typeConfig.GetFederatedType().Kind, c.typeConfig.Name),
 	)
 	c.eventRecorder = eventsink.NewDefederatingRecorderMux(kubeClient, c.name, 6)
 
@@ -190,14 +190,14 @@ func (c *FederateController) HasSynced() bool {
 }
 
 func (c *FederateController) reconcile(qualifiedName common.QualifiedName) (status worker.Result) {
-	_ = c.metrics.Rate("federate.throughput", 1)
+	_ = c.metrics.Rate("federate.throughput", 1, stats.Tag{Name: "ftc", Value: c.typeConfig.Name})
 	logger := c.logger.WithValues("object", qualifiedName.String())
 	ctx := klog.NewContext(context.TODO(), logger)
 	startTime := time.Now()
 
 	logger.V(3).Info("Start reconcile")
 	defer func() {
-		c.metrics.Duration(fmt.Sprintf("%s.latency", c.name), startTime)
+		c.metrics.Duration("federate.latency", startTime, stats.Tag{Name: "ftc", Value: c.typeConfig.Name})
 		logger.WithValues("duration", time.Since(startTime), "status", status.String()).V(3).Info("Finished reconcile")
 	}()
 
@@ -230,6 +230,8 @@ var (
 		common.NoSchedulingAnnotation,
 		scheduler.FollowsObjectAnnotation,
 		common.FollowersAnnotation,
+		common.RollbackToAnnotation,
+		common.DriftDetectionAnnotation,
 	)
 
 	// TODO: Do we need to specify the internal annotations here?
@@ -26,6 +26,7 @@ import (
 	"reflect"
 	"time"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -41,6 +42,7 @@ import (
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 )
 
 const (
@@ -51,6 +53,9 @@ const (
 	ServiceAccountCAKey    = "service-account-ca-data"
 
 	serviceAccountSecretTimeout = 30 * time.Second
+
+	// rootCAConfigMapName is the name of the ConfigMap that contains the root CA of a cluster in every namespace.
+	rootCAConfigMapName = "kube-root-ca.crt"
 )
 
 const (
@@ -87,6 +92,7 @@ func handleNotJoinedCluster(
 	kubeClient kubeclient.Interface,
 	eventRecorder record.EventRecorder,
 	fedSystemNamespace string,
+	clusterAuthConfig *util.ClusterAuthConfig,
 	clusterJoinTimeout time.Duration,
 ) (c *fedcorev1a1.FederatedCluster, condition *fedcorev1a1.ClusterCondition, joinPerformed *bool, err error) {
 	logger := klog.FromContext(ctx).WithValues("process", "cluster-join")
@@ -116,7 +122,7 @@ func handleNotJoinedCluster(
 
 	// 2. The remaining steps require a cluster kube client, attempt to create one
 
-	_, clusterKubeClient, err := getClusterClient(ctx, kubeClient, fedSystemNamespace, cluster)
+	_, clusterKubeClient, err := getClusterClient(ctx, kubeClient, fedSystemNamespace, clusterAuthConfig, cluster)
 	if err != nil {
 		logger.Error(err, "Failed to create cluster client")
 		msg := fmt.Sprintf("Failed to create cluster client: %v", err.Error())
@@ -249,45 +255,79 @@ func getAndSaveClusterToken(
 	logger := klog.FromContext(ctx)
 
 	logger.V(2).Info("Creating authorized service account")
-	saTokenSecretName, err := createAuthorizedServiceAccount(ctx, clusterKubeClient, memberSystemNamespace, cluster.Name, false)
+	// A bound token is requested for the service account instead of reading the token of a token secret if the
+	// token should expire.
+	withTokenSecret := cluster.Spec.ServiceAccountTokenExpiration == nil
+	saTokenSecretName, err := createAuthorizedServiceAccount(
+		ctx,
+		clusterKubeClient,
+		memberSystemNamespace,
+		cluster.Name,
+		withTokenSecret,
+		false,
+	)
 	if err != nil {
 		return err
 	}
 
 	logger.V(1).Info("Updating cluster secret")
-	token, ca, err := getServiceAccountToken(ctx, clusterKubeClient, memberSystemNamespace.Name, saTokenSecretName)
+	var token, ca []byte
+	if withTokenSecret {
+		token, ca, err = getServiceAccountToken(ctx, clusterKubeClient, memberSystemNamespace.Name, saTokenSecretName)
+	} else {
+		token, ca, err = requestServiceAccountToken(
+			ctx,
+			clusterKubeClient,
+			memberSystemNamespace.Name,
+			cluster.Spec.ServiceAccountTokenExpiration.Duration,
+		)
+	}
 	if err != nil {
 		return fmt.Errorf("error getting service account token from joining cluster: %w", err)
 	}
 
-	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-		secret, err := kubeClient.CoreV1().Secrets(fedSystemNamespace).Get(ctx, cluster.Spec.SecretRef.Name, metav1.GetOptions{})
+	_, err = saveClusterToken(ctx, kubeClient, fedSystemNamespace, cluster.Spec.SecretRef.Name, token, ca)
+	return err
+}
+
+// saveClusterToken saves the service account token and CA of a cluster to its cluster secret and returns the updated
+// secret.
+func saveClusterToken(
+	ctx context.Context,
+	kubeClient kubeclient.Interface,
+	fedSystemNamespace, secretName string,
+	token, ca []byte,
+) (*corev1.Secret, error) {
+	var updatedSecret *corev1.Secret
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		secret, err := kubeClient.CoreV1().Secrets(fedSystemNamespace).Get(ctx, secretName, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
 		secret.Data[ServiceAccountTokenKey] = token
 		secret.Data[ServiceAccountCAKey] = ca
-		_, err = kubeClient.CoreV1().Secrets(fedSystemNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+		updatedSecret, err = kubeClient.CoreV1().Secrets(fedSystemNamespace).Update(ctx, secret, metav1.UpdateOptions{})
 		return err
 	})
 	if err != nil {
-		return fmt.Errorf(
-			"failed to update secret for joining cluster: %w",
-			err,
-		)
+		return nil, fmt.Errorf("failed to save service account token to cluster secret: %w", err)
 	}
 
-	return nil
+	return updatedSecret, nil
 }
 
-// createAuthorizedServiceAccount creates a service account and service account token secret
-// and grants the privileges required by the control plane to manage
+// createAuthorizedServiceAccount creates a service account and, if withTokenSecret is true, a service account token
+// secret and grants the privileges required by the control plane to manage
 // resources in the joining cluster.  The created secret name is returned on success.
 func createAuthorizedServiceAccount(
 	ctx context.Context,
 	clusterKubeClient kubeclient.Interface,
 	memberSystemNamespace *corev1.Namespace,
 	clusterName string,
+	withTokenSecret bool,
 	errorOnExisting bool,
 ) (string, error) {
 	logger := klog.FromContext(ctx).WithValues("member-service-account-name", MemberServiceAccountName)
@@ -301,19 +341,22 @@ func createAuthorizedServiceAccount(
 	}
 
 	// 2. create service account token secret
-	logger.V(1).Info("Creating service account token secret")
-	saTokenSecretName, err := createServiceAccountTokenSecret(
-		ctx,
-		clusterKubeClient,
-		memberSystemNamespace.Name,
-		MemberServiceAccountName,
-		clusterName,
-		errorOnExisting,
-	)
-	if err != nil {
-		return "", fmt.Errorf("error creating service account token secret %s : %w", MemberServiceAccountName, err)
+	var saTokenSecretName string
+	if withTokenSecret {
+		logger.V(1).Info("Creating service account token secret")
+		saTokenSecretName, err = createServiceAccountTokenSecret(
+			ctx,
+			clusterKubeClient,
+			memberSystemNamespace.Name,
+			MemberServiceAccountName,
+			clusterName,
+			errorOnExisting,
+		)
+		if err != nil {
+			return "", fmt.Errorf("error creating service account token secret %s : %w", MemberServiceAccountName, err)
+		}
+		logger.V(1).WithValues("sa-token-secret-name", saTokenSecretName).Info("Created service account token secret for service account")
 	}
-	logger.V(1).WithValues("sa-token-secret-name", saTokenSecretName).Info("Created service account token secret for service account")
 
 	// 3. create rbac
 	logger.V(1).Info("Creating RBAC for service account")
@@ -579,3 +622,32 @@ func getServiceAccountToken(
 
 	return token, ca, nil
 }
+
+// requestServiceAccountToken requests a bound token of the member service account that expires after the given
+// duration. The returned CA is the root CA of the cluster.
+func requestServiceAccountToken(
+	ctx context.Context,
+	clusterClientset kubeclient.Interface,
+	memberSystemNamespace string,
+	expiration time.Duration,
+) ([]byte, []byte, error) {
+	tokenRequest, err := clusterClientset.CoreV1().
+		ServiceAccounts(memberSystemNamespace).
+		CreateToken(ctx, MemberServiceAccountName, &authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{
+				ExpirationSeconds: pointer.Int64(int64(expiration / time.Second)),
+			},
+		}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not request service account token from joining cluster: %w", err)
+	}
+
+	rootCA, err := clusterClientset.CoreV1().
+		ConfigMaps(memberSystemNamespace).
+		Get(ctx, rootCAConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get root CA from joining cluster: %w", err)
+	}
+
+	return []byte(tokenRequest.Status.Token), []byte(rootCA.Data["ca.crt"]), nil
+}
@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/informers"
+	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
@@ -67,8 +68,6 @@ func collectIndividualClusterStatus(
 	fedClient fedclient.Interface,
 	federatedClient federatedclient.FederatedClientFactory,
 ) error {
-	logger := klog.FromContext(ctx)
-
 	clusterKubeClient, exists, err := federatedClient.KubeClientsetForCluster(cluster.Name)
 	if !exists {
 		return fmt.Errorf("federated client is not yet up to date")
@@ -84,9 +83,23 @@ func collectIndividualClusterStatus(
 		return fmt.Errorf("failed to get federated kube informer factory: %w", err)
 	}
 
-	discoveryClient := clusterKubeClient.Discovery()
 	cluster = cluster.DeepCopy()
+	CollectClusterStatus(ctx, cluster, clusterKubeClient, clusterKubeInformer)
+
+	return UpdateCollectedClusterStatus(ctx, fedClient, cluster)
+}
+
+// CollectClusterStatus probes the member cluster and sets its conditions, resources and API resources in the status
+// of the given cluster. It is used by both the control plane and the agents of clusters in Pull mode.
+func CollectClusterStatus(
+	ctx context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+	clusterKubeClient kubeclient.Interface,
+	clusterKubeInformer informers.SharedInformerFactory,
+) {
+	logger := klog.FromContext(ctx)
 
+	discoveryClient := clusterKubeClient.Discovery()
 	conditionTime := metav1.Now()
 
 	offlineStatus, readyStatus := checkReadyByHealthz(ctx, discoveryClient)
@@ -118,18 +131,52 @@ func collectIndividualClusterStatus(
 		}
 	}
 
+	// The transition times are preserved so that the duration of an outage can be measured for the cluster taints.
 	offlineCondition := getNewClusterOfflineCondition(offlineStatus, conditionTime)
+	preserveLastTransitionTime(&cluster.Status, &offlineCondition)
 	setClusterCondition(&cluster.Status, &offlineCondition)
 	readyCondition := getNewClusterReadyCondition(readyStatus, readyReason, readyMessage, conditionTime)
+	preserveLastTransitionTime(&cluster.Status, &readyCondition)
 	setClusterCondition(&cluster.Status, &readyCondition)
 
+	checkClusterHealth(
+		ctx,
+		cluster,
+		clusterKubeClient,
+		clusterKubeInformer.Core().V1().Nodes().Lister(),
+		readyStatus == corev1.ConditionTrue,
+		conditionTime,
+	)
+}
+
+// UpdateCollectedClusterStatus writes the status collected by CollectClusterStatus to the cluster. The joined and
+// credentials expiring conditions, the drain status and the credentials status are maintained separately by the
+// control plane and are left untouched.
+func UpdateCollectedClusterStatus(
+	ctx context.Context,
+	fedClient fedclient.Interface,
+	cluster *fedcorev1a1.FederatedCluster,
+) error {
 	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-		latestCluster, err := fedClient.CoreV1alpha1().FederatedClusters().Get(context.TODO(), cluster.Name, metav1.GetOptions{})
+		latestCluster, err := fedClient.CoreV1alpha1().FederatedClusters().Get(ctx, cluster.Name, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
+		drainStatus := latestCluster.Status.Drain
+		credentialsStatus := latestCluster.Status.Credentials
+		preservedConditions := []*fedcorev1a1.ClusterCondition{
+			getClusterCondition(&latestCluster.Status, fedcorev1a1.ClusterJoined),
+			getClusterCondition(&latestCluster.Status, fedcorev1a1.ClusterCredentialsExpiring),
+		}
 		cluster.Status.DeepCopyInto(&latestCluster.Status)
-		_, err = fedClient.CoreV1alpha1().FederatedClusters().UpdateStatus(context.TODO(), latestCluster, metav1.UpdateOptions{})
+		latestCluster.Status.Drain = drainStatus
+		latestCluster.Status.Credentials = credentialsStatus
+		for _, condition := range preservedConditions {
+			if condition != nil {
+				setClusterCondition(&latestCluster.Status, condition)
+			}
+		}
+		_, err = fedClient.CoreV1alpha1().FederatedClusters().UpdateStatus(ctx, latestCluster, metav1.UpdateOptions{})
 		return err
 	}); err != nil {
 		return fmt.Errorf("failed to update cluster status: %w", err)
@@ -196,6 +243,7 @@ func updateClusterResources(
 		SchedulableNodes: &schedulableNodes,
 		Allocatable:      allocatable,
 		Available:        available,
+		ResourceModel:    getResourceModel(nodes, pods),
 	}
 
 	return nil
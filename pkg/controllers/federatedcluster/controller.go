@@ -24,6 +24,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -33,6 +34,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	kubeclient "k8s.io/client-go/kubernetes"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
@@ -66,6 +68,15 @@ const (
 // ClusterHealthCheckConfig defines the configurable parameters for cluster health check
 type ClusterHealthCheckConfig struct {
 	Period time.Duration
+	// UnreachableTaintGracePeriod is the amount of time a cluster must be offline before it is tainted with NoExecute.
+	// Clusters are never tainted with NoExecute if it is 0.
+	UnreachableTaintGracePeriod time.Duration
+	// AgentHeartbeatTimeout is the amount of time after the last heartbeat of the agent of a cluster in Pull mode before
+	// the cluster is considered offline.
+	AgentHeartbeatTimeout time.Duration
+	// CredentialsExpiryWarningPeriod is the period before the credentials of a cluster expire in which they are
+	// reported as expiring.
+	CredentialsExpiryWarningPeriod time.Duration
 }
 
 // FederatedClusterController reconciles a FederatedCluster object
@@ -77,7 +88,18 @@ type FederatedClusterController struct {
 	clusterSynced   cache.InformerSynced
 	federatedClient federatedclient.FederatedClientFactory
 
+	ftcInformer            cache.SharedIndexInformer
+	ftcLister              fedcorev1a1listers.FederatedTypeConfigLister
+	ftcSynced              cache.InformerSynced
+	dynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory
+
+	// placementCounters count the federated objects placed in each cluster for every federated type, keyed by the
+	// name of the FTC. They are used to report the progress of cluster drains.
+	placementCountersLock sync.Mutex
+	placementCounters     map[string]*placementCounter
+
 	fedSystemNamespace       string
+	clusterAuthConfig        *util.ClusterAuthConfig
 	clusterHealthCheckConfig *ClusterHealthCheckConfig
 	clusterJoinTimeout       time.Duration
 
@@ -93,22 +115,37 @@ func NewFederatedClusterController(
 	client fedclient.Interface,
 	kubeClient kubeclient.Interface,
 	informer fedcorev1a1informers.FederatedClusterInformer,
+	ftcInformer fedcorev1a1informers.FederatedTypeConfigInformer,
+	dynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory,
 	federatedClient federatedclient.FederatedClientFactory,
 	metrics stats.Metrics,
 	fedsystemNamespace string,
 	restConfig *rest.Config,
+	clusterAuthConfig *util.ClusterAuthConfig,
 	workerCount int,
 	clusterJoinTimeout time.Duration,
+	clusterUnreachableTaintGracePeriod time.Duration,
+	clusterCredentialsExpiryWarningPeriod time.Duration,
+	clusterAgentHeartbeatTimeout time.Duration,
 ) (*FederatedClusterController, error) {
 	c := &FederatedClusterController{
-		client:             client,
-		kubeClient:         kubeClient,
-		clusterLister:      informer.Lister(),
-		clusterSynced:      informer.Informer().HasSynced,
-		federatedClient:    federatedClient,
-		fedSystemNamespace: fedsystemNamespace,
+		client:                 client,
+		kubeClient:             kubeClient,
+		clusterLister:          informer.Lister(),
+		clusterSynced:          informer.Informer().HasSynced,
+		federatedClient:        federatedClient,
+		ftcInformer:            ftcInformer.Informer(),
+		ftcLister:              ftcInformer.Lister(),
+		ftcSynced:              ftcInformer.Informer().HasSynced,
+		dynamicInformerFactory: dynamicInformerFactory,
+		placementCounters:      map[string]*placementCounter{},
+		fedSystemNamespace:     fedsystemNamespace,
+		clusterAuthConfig:      clusterAuthConfig,
 		clusterHealthCheckConfig: &ClusterHealthCheckConfig{
-			Period: time.Minute,
+			Period:                         time.Minute,
+			UnreachableTaintGracePeriod:    clusterUnreachableTaintGracePeriod,
+			AgentHeartbeatTimeout:          clusterAgentHeartbeatTimeout,
+			CredentialsExpiryWarningPeriod: clusterCredentialsExpiryWarningPeriod,
 		},
 		clusterJoinTimeout: clusterJoinTimeout,
 		metrics:            metrics,
@@ -158,7 +195,7 @@ func NewFederatedClusterController(
 }
 
 func (c *FederatedClusterController) IsControllerReady() bool {
-	return c.clusterSynced()
+	return c.clusterSynced() && c.ftcSynced()
 }
 
 func (c *FederatedClusterController) Run(ctx context.Context) {
@@ -167,7 +204,12 @@ func (c *FederatedClusterController) Run(ctx context.Context) {
 	c.logger.Info("Starting controller")
 	defer c.logger.Info("Stopping controller")
 
-	if !cache.WaitForNamedCacheSync("federated-controller", ctx.Done(), c.clusterSynced) {
+	if !cache.WaitForNamedCacheSync("federated-controller", ctx.Done(), c.clusterSynced, c.ftcSynced) {
+		return
+	}
+
+	if err := c.startPlacementCounters(ctx); err != nil {
+		c.logger.Error(err, "Failed to start counting federated objects for cluster drains")
 		return
 	}
 
@@ -212,6 +254,7 @@ func (c *FederatedClusterController) reconcile(qualifiedName common.QualifiedNam
 			c.kubeClient,
 			c.eventRecorder,
 			c.fedSystemNamespace,
+			c.clusterAuthConfig,
 		)
 		if err != nil {
 			if apierrors.IsConflict(err) {
@@ -233,20 +276,31 @@ func (c *FederatedClusterController) reconcile(qualifiedName common.QualifiedNam
 	}
 
 	if joined, alreadyFailed := isClusterJoined(&cluster.Status); joined || alreadyFailed {
+		if joined && !alreadyFailed {
+			// update the taints and drain status of the cluster in case it was cordoned or drained
+			c.statusCollectWorker.EnqueueObject(cluster)
+		}
 		return worker.StatusAllOK
 	}
 
 	// not joined yet and not failed, so we try to join
 	logger.V(2).Info("Handle unjoined cluster")
-	cluster, newCondition, newJoinPerformed, err := handleNotJoinedCluster(
-		ctx,
-		cluster,
-		c.client,
-		c.kubeClient,
-		c.eventRecorder,
-		c.fedSystemNamespace,
-		c.clusterJoinTimeout,
-	)
+	var newCondition *fedcorev1a1.ClusterCondition
+	var newJoinPerformed *bool
+	if cluster.IsPullMode() {
+		newCondition, err = handleNotJoinedPullCluster(ctx, cluster, c.kubeClient, c.eventRecorder, c.clusterJoinTimeout)
+	} else {
+		cluster, newCondition, newJoinPerformed, err = handleNotJoinedCluster(
+			ctx,
+			cluster,
+			c.client,
+			c.kubeClient,
+			c.eventRecorder,
+			c.fedSystemNamespace,
+			c.clusterAuthConfig,
+			c.clusterJoinTimeout,
+		)
+	}
 
 	needsUpdate := false
 	if newCondition != nil {
@@ -318,11 +372,46 @@ func (c *FederatedClusterController) collectClusterStatus(qualifiedName common.Q
 	}
 
 	cluster = cluster.DeepCopy()
-	if shouldCollectClusterStatus(cluster, c.clusterHealthCheckConfig.Period) {
-		if err := collectIndividualClusterStatus(ctx, cluster, c.client, c.federatedClient); err != nil {
-			logger.Error(err, "Failed to collect cluster status")
+	// the status of the cluster is still collected with the current credentials if they cannot be updated
+	var credentialsErr error
+	if cluster.IsPullMode() {
+		// the status of the cluster is reported by its agent
+		if err := updatePullClusterStatus(ctx, cluster.Name, c.client, c.clusterHealthCheckConfig.AgentHeartbeatTimeout); err != nil {
+			logger.Error(err, "Failed to update status of cluster in pull mode")
 			return worker.StatusError
 		}
+	} else {
+		if err := updateClusterCredentials(
+			ctx,
+			cluster,
+			c.client,
+			c.kubeClient,
+			c.eventRecorder,
+			c.fedSystemNamespace,
+			c.clusterAuthConfig,
+			c.clusterHealthCheckConfig.CredentialsExpiryWarningPeriod,
+		); err != nil {
+			logger.Error(err, "Failed to update cluster credentials")
+			credentialsErr = err
+		}
+		if shouldCollectClusterStatus(cluster, c.clusterHealthCheckConfig.Period) {
+			if err := collectIndividualClusterStatus(ctx, cluster, c.client, c.federatedClient); err != nil {
+				logger.Error(err, "Failed to collect cluster status")
+				return worker.StatusError
+			}
+		}
+	}
+
+	if err := updateClusterTaints(ctx, cluster.Name, c.client, c.clusterHealthCheckConfig.UnreachableTaintGracePeriod); err != nil {
+		logger.Error(err, "Failed to update cluster taints")
+		return worker.StatusError
+	}
+	if err := c.updateClusterDrainStatus(ctx, cluster.Name); err != nil {
+		logger.Error(err, "Failed to update cluster drain status")
+		return worker.StatusError
+	}
+	if credentialsErr != nil {
+		return worker.StatusError
 	}
 
 	return worker.StatusAllOK
@@ -357,6 +446,7 @@ func handleTerminatingCluster(
 	kubeClient kubeclient.Interface,
 	eventRecorder record.EventRecorder,
 	fedSystemNamespace string,
+	clusterAuthConfig *util.ClusterAuthConfig,
 ) error {
 	finalizers := sets.New(cluster.GetFinalizers()...)
 	if !finalizers.Has(FinalizerFederatedClusterController) {
@@ -378,7 +468,7 @@ func handleTerminatingCluster(
 
 	// Only perform clean-up if we made any effectual changes to the cluster during join.
 	if cluster.Status.JoinPerformed {
-		clusterSecret, clusterKubeClient, err := getClusterClient(ctx, kubeClient, fedSystemNamespace, cluster)
+		clusterSecret, clusterKubeClient, err := getClusterClient(ctx, kubeClient, fedSystemNamespace, clusterAuthConfig, cluster)
 		if err != nil {
 			eventRecorder.Eventf(
 				cluster,
@@ -432,6 +522,13 @@ func handleTerminatingCluster(
 		}
 	}
 
+	// The pulled objects of a cluster in pull mode were removed before the other finalizers were removed.
+	if cluster.IsPullMode() {
+		if err := deletePullNamespace(ctx, kubeClient, cluster.Name); err != nil {
+			return err
+		}
+	}
+
 	// we have already checked that we are the last finalizer so we can simply set finalizers to be empty
 	cluster.SetFinalizers(nil)
 	_, err := client.CoreV1alpha1().
@@ -448,6 +545,7 @@ func getClusterClient(
 	ctx context.Context,
 	hostClient kubeclient.Interface,
 	fedSystemNamespace string,
+	clusterAuthConfig *util.ClusterAuthConfig,
 	cluster *fedcorev1a1.FederatedCluster,
 ) (*corev1.Secret, kubeclient.Interface, error) {
 	restConfig := &rest.Config{Host: cluster.Spec.APIEndpoint}
@@ -462,7 +560,7 @@ func getClusterClient(
 		return nil, nil, fmt.Errorf("failed to get cluster secret: %w", err)
 	}
 
-	if err := util.PopulateAuthDetailsFromSecret(restConfig, cluster.Spec.Insecure, clusterSecret, false); err != nil {
+	if err := util.PopulateClusterConfig(restConfig, cluster, clusterSecret, false, clusterAuthConfig); err != nil {
 		return nil, nil, fmt.Errorf("cluster secret malformed: %w", err)
 	}
 
@@ -0,0 +1,276 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+const (
+	CredentialsValidReason           = "CredentialsValid"
+	CredentialsNotExpiringMessage    = "The credentials of the cluster do not expire"
+	CredentialsExpiringReason        = "CredentialsExpiring"
+	CredentialsExpiredReason         = "CredentialsExpired"
+	CredentialsExpiryMessageTemplate = "The %s of the cluster expires at %s"
+
+	EventReasonRefreshServiceAccountTokenFailed = "RefreshServiceAccountTokenFailed"
+
+	// tokenRefreshLifetimeFraction is the fraction of the lifetime of a bound service account token after which it is
+	// refreshed.
+	tokenRefreshLifetimeFraction = 0.8
+	// tokenExpiringLifetimeFraction is the fraction of the lifetime of a bound service account token before its
+	// expiration in which it is considered expiring, which only happens if refreshing it has failed.
+	tokenExpiringLifetimeFraction = 0.1
+)
+
+// credentialExpiry is the expiration of one of the credentials in the secret of a cluster.
+type credentialExpiry struct {
+	description    string
+	expirationTime time.Time
+	// warningPeriod is the period before the expiration time in which the credential is considered expiring.
+	warningPeriod time.Duration
+}
+
+// updateClusterCredentials refreshes the bound service account token of the cluster if it is due, and records the
+// expiration of the credentials in the cluster secret in the status of the cluster. Credentials are considered
+// expiring within the warning period before their expiration, except for bound service account tokens, which are
+// only considered expiring if they could not be refreshed.
+func updateClusterCredentials(
+	ctx context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+	fedClient fedclient.Interface,
+	kubeClient kubeclient.Interface,
+	eventRecorder record.EventRecorder,
+	fedSystemNamespace string,
+	clusterAuthConfig *util.ClusterAuthConfig,
+	expiryWarningPeriod time.Duration,
+) error {
+	logger := klog.FromContext(ctx)
+
+	if cluster.IsPullMode() || len(cluster.Spec.SecretRef.Name) == 0 {
+		return nil
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(fedSystemNamespace).Get(ctx, cluster.Spec.SecretRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get cluster secret: %w", err)
+	}
+
+	// The expiration of the current credentials is still recorded if the token cannot be refreshed.
+	var refreshErr error
+	if shouldRefreshServiceAccountToken(cluster, secret, time.Now()) {
+		logger.V(2).Info("Refreshing service account token")
+		refreshedSecret, err := refreshServiceAccountToken(ctx, cluster, kubeClient, fedSystemNamespace, clusterAuthConfig)
+		if err != nil {
+			eventRecorder.Eventf(
+				cluster,
+				corev1.EventTypeWarning,
+				EventReasonRefreshServiceAccountTokenFailed,
+				"Failed to refresh service account token: %v",
+				err,
+			)
+			refreshErr = fmt.Errorf("failed to refresh service account token: %w", err)
+		} else {
+			secret = refreshedSecret
+		}
+	}
+
+	expiries, err := getCredentialsExpiries(cluster, secret, expiryWarningPeriod)
+	if err != nil {
+		return err
+	}
+
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latestCluster, err := fedClient.CoreV1alpha1().FederatedClusters().Get(ctx, cluster.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if !setCredentialsStatus(&latestCluster.Status, secret.ResourceVersion, expiries, metav1.Now()) {
+			return nil
+		}
+
+		logger.V(2).Info("Updating cluster credentials status")
+		_, err = fedClient.CoreV1alpha1().FederatedClusters().UpdateStatus(ctx, latestCluster, metav1.UpdateOptions{})
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to update cluster credentials status: %w", err)
+	}
+
+	return refreshErr
+}
+
+// refreshServiceAccountToken requests a new bound service account token for the cluster and saves it to the cluster
+// secret. The token is requested with the credentials used to join the cluster, so that an expired token can still be
+// replaced.
+func refreshServiceAccountToken(
+	ctx context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+	kubeClient kubeclient.Interface,
+	fedSystemNamespace string,
+	clusterAuthConfig *util.ClusterAuthConfig,
+) (*corev1.Secret, error) {
+	_, clusterKubeClient, err := getClusterClient(ctx, kubeClient, fedSystemNamespace, clusterAuthConfig, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cluster client: %w", err)
+	}
+
+	token, ca, err := requestServiceAccountToken(
+		ctx,
+		clusterKubeClient,
+		fedSystemNamespace,
+		cluster.Spec.ServiceAccountTokenExpiration.Duration,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return saveClusterToken(ctx, kubeClient, fedSystemNamespace, cluster.Spec.SecretRef.Name, token, ca)
+}
+
+// shouldRefreshServiceAccountToken returns whether the cluster uses a bound service account token that has passed
+// the refresh point of its lifetime, or whose lifetime cannot be determined.
+func shouldRefreshServiceAccountToken(cluster *fedcorev1a1.FederatedCluster, secret *corev1.Secret, now time.Time) bool {
+	if !cluster.Spec.UseServiceAccountToken || cluster.Spec.ServiceAccountTokenExpiration == nil {
+		return false
+	}
+
+	claims, ok := util.ParseTokenClaims(secret.Data[util.ServiceAccountTokenKey])
+	if !ok || claims.IssuedAt == nil || claims.Expiry == nil {
+		return true
+	}
+
+	issuedAt, expiry := time.Unix(*claims.IssuedAt, 0), time.Unix(*claims.Expiry, 0)
+	refreshTime := issuedAt.Add(time.Duration(float64(expiry.Sub(issuedAt)) * tokenRefreshLifetimeFraction))
+	return !now.Before(refreshTime)
+}
+
+// getCredentialsExpiries returns the expirations of the credentials in the cluster secret that expire. The client
+// certificate is always included since it is used to join the cluster and to refresh its token.
+func getCredentialsExpiries(
+	cluster *fedcorev1a1.FederatedCluster,
+	secret *corev1.Secret,
+	expiryWarningPeriod time.Duration,
+) ([]credentialExpiry, error) {
+	var expiries []credentialExpiry
+
+	if certData := secret.Data[util.ClientCertificateKey]; len(certData) > 0 {
+		certs, err := certutil.ParseCertsPEM(certData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse client certificate of cluster: %w", err)
+		}
+		expiries = append(expiries, credentialExpiry{
+			description:    "client certificate",
+			expirationTime: certs[0].NotAfter,
+			warningPeriod:  expiryWarningPeriod,
+		})
+	}
+
+	if cluster.Spec.UseServiceAccountToken {
+		// Tokens that are not JWTs or do not have an expiry are assumed to not expire.
+		if claims, ok := util.ParseTokenClaims(secret.Data[util.ServiceAccountTokenKey]); ok && claims.Expiry != nil {
+			expiry := credentialExpiry{
+				description:    "service account token",
+				expirationTime: time.Unix(*claims.Expiry, 0),
+				warningPeriod:  expiryWarningPeriod,
+			}
+			if cluster.Spec.ServiceAccountTokenExpiration != nil && claims.IssuedAt != nil {
+				lifetime := expiry.expirationTime.Sub(time.Unix(*claims.IssuedAt, 0))
+				expiry.warningPeriod = time.Duration(float64(lifetime) * tokenExpiringLifetimeFraction)
+			}
+			expiries = append(expiries, expiry)
+		}
+	}
+
+	sort.Slice(expiries, func(i, j int) bool {
+		return expiries[i].expirationTime.Before(expiries[j].expirationTime)
+	})
+	return expiries, nil
+}
+
+// setCredentialsStatus sets the credentials status and the credentials expiring condition of the cluster according
+// to the expirations of its credentials sorted by expiration time, and returns whether the status was changed.
+func setCredentialsStatus(
+	status *fedcorev1a1.FederatedClusterStatus,
+	secretResourceVersion string,
+	expiries []credentialExpiry,
+	now metav1.Time,
+) bool {
+	credentials := &fedcorev1a1.ClusterCredentialsStatus{SecretResourceVersion: secretResourceVersion}
+	if len(expiries) > 0 {
+		expirationTime := metav1.NewTime(expiries[0].expirationTime).Rfc3339Copy()
+		credentials.ExpirationTime = &expirationTime
+	}
+
+	condition := fedcorev1a1.ClusterCondition{
+		Type:               fedcorev1a1.ClusterCredentialsExpiring,
+		Status:             corev1.ConditionFalse,
+		Reason:             CredentialsValidReason,
+		Message:            CredentialsNotExpiringMessage,
+		LastProbeTime:      now,
+		LastTransitionTime: now,
+	}
+	for i, expiry := range expiries {
+		message := fmt.Sprintf(
+			CredentialsExpiryMessageTemplate,
+			expiry.description,
+			expiry.expirationTime.UTC().Format(time.RFC3339),
+		)
+		if i == 0 {
+			condition.Message = message
+		}
+		if !now.Time.Before(expiry.expirationTime) {
+			condition.Status, condition.Reason, condition.Message = corev1.ConditionTrue, CredentialsExpiredReason, message
+			break
+		}
+		if expiry.expirationTime.Sub(now.Time) <= expiry.warningPeriod {
+			condition.Status, condition.Reason, condition.Message = corev1.ConditionTrue, CredentialsExpiringReason, message
+			break
+		}
+	}
+
+	existingCondition := getClusterCondition(status, fedcorev1a1.ClusterCredentialsExpiring)
+	if status.Credentials != nil &&
+		status.Credentials.SecretResourceVersion == credentials.SecretResourceVersion &&
+		status.Credentials.ExpirationTime.Equal(credentials.ExpirationTime) &&
+		existingCondition != nil &&
+		existingCondition.Status == condition.Status &&
+		existingCondition.Reason == condition.Reason &&
+		existingCondition.Message == condition.Message {
+		return false
+	}
+
+	status.Credentials = credentials
+	preserveLastTransitionTime(status, &condition)
+	setClusterCondition(status, &condition)
+	return true
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+func newTestToken(issuedAt, expiry time.Time) []byte {
+	encode := base64.RawURLEncoding.EncodeToString
+	claims := fmt.Sprintf(`{"iat":%d,"exp":%d,"sub":"system:serviceaccount:kube-admiral-system:kubeadmiral-member"}`,
+		issuedAt.Unix(), expiry.Unix())
+	return []byte(encode([]byte(`{"alg":"RS256"}`)) + "." + encode([]byte(claims)) + ".signature")
+}
+
+func newTestCertificate(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "admin"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func Test_shouldRefreshServiceAccountToken(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	expiration := &metav1.Duration{Duration: time.Hour}
+
+	testCases := []struct {
+		name           string
+		useSAToken     bool
+		expiration     *metav1.Duration
+		token          []byte
+		expectedResult bool
+	}{
+		{
+			name:           "token that never expires",
+			useSAToken:     true,
+			token:          []byte("legacy-token"),
+			expectedResult: false,
+		},
+		{
+			name:           "service account token not used",
+			expiration:     expiration,
+			token:          newTestToken(now.Add(-time.Hour), now),
+			expectedResult: false,
+		},
+		{
+			name:           "fresh bound token",
+			useSAToken:     true,
+			expiration:     expiration,
+			token:          newTestToken(now.Add(-10*time.Minute), now.Add(50*time.Minute)),
+			expectedResult: false,
+		},
+		{
+			name:           "bound token past refresh point",
+			useSAToken:     true,
+			expiration:     expiration,
+			token:          newTestToken(now.Add(-50*time.Minute), now.Add(10*time.Minute)),
+			expectedResult: true,
+		},
+		{
+			name:           "expired bound token",
+			useSAToken:     true,
+			expiration:     expiration,
+			token:          newTestToken(now.Add(-2*time.Hour), now.Add(-time.Hour)),
+			expectedResult: true,
+		},
+		{
+			name:           "token that is not a JWT",
+			useSAToken:     true,
+			expiration:     expiration,
+			token:          []byte("legacy-token"),
+			expectedResult: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &fedcorev1a1.FederatedCluster{
+				Spec: fedcorev1a1.FederatedClusterSpec{
+					UseServiceAccountToken:        tc.useSAToken,
+					ServiceAccountTokenExpiration: tc.expiration,
+				},
+			}
+			secret := &corev1.Secret{Data: map[string][]byte{util.ServiceAccountTokenKey: tc.token}}
+			assert.Equal(t, tc.expectedResult, shouldRefreshServiceAccountToken(cluster, secret, now))
+		})
+	}
+}
+
+func Test_getCredentialsExpiries(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	warningPeriod := 7 * 24 * time.Hour
+	certExpiry := now.Add(30 * 24 * time.Hour)
+	tokenExpiry := now.Add(30 * time.Minute)
+
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			util.ClientCertificateKey:   newTestCertificate(t, certExpiry),
+			util.ServiceAccountTokenKey: newTestToken(now.Add(-30*time.Minute), tokenExpiry),
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		spec             fedcorev1a1.FederatedClusterSpec
+		expectedExpiries []credentialExpiry
+	}{
+		{
+			name: "client certificate",
+			expectedExpiries: []credentialExpiry{
+				{description: "client certificate", expirationTime: certExpiry, warningPeriod: warningPeriod},
+			},
+		},
+		{
+			name: "unmanaged service account token",
+			spec: fedcorev1a1.FederatedClusterSpec{UseServiceAccountToken: true},
+			expectedExpiries: []credentialExpiry{
+				{description: "service account token", expirationTime: tokenExpiry, warningPeriod: warningPeriod},
+				{description: "client certificate", expirationTime: certExpiry, warningPeriod: warningPeriod},
+			},
+		},
+		{
+			name: "bound service account token",
+			spec: fedcorev1a1.FederatedClusterSpec{
+				UseServiceAccountToken:        true,
+				ServiceAccountTokenExpiration: &metav1.Duration{Duration: time.Hour},
+			},
+			expectedExpiries: []credentialExpiry{
+				{description: "service account token", expirationTime: tokenExpiry, warningPeriod: 6 * time.Minute},
+				{description: "client certificate", expirationTime: certExpiry, warningPeriod: warningPeriod},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &fedcorev1a1.FederatedCluster{Spec: tc.spec}
+			expiries, err := getCredentialsExpiries(cluster, secret, warningPeriod)
+			assert.NoError(t, err)
+			for i := range expiries {
+				expiries[i].expirationTime = expiries[i].expirationTime.Local()
+			}
+			assert.Equal(t, tc.expectedExpiries, expiries)
+		})
+	}
+}
+
+func Test_setCredentialsStatus(t *testing.T) {
+	now := metav1.NewTime(time.Unix(1700000000, 0))
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	certExpiry := now.Add(30 * 24 * time.Hour)
+	certExpiryTime := metav1.NewTime(certExpiry)
+
+	certificate := credentialExpiry{
+		description:    "client certificate",
+		expirationTime: certExpiry,
+		warningPeriod:  7 * 24 * time.Hour,
+	}
+	expiringCertificate := certificate
+	expiringCertificate.warningPeriod = 60 * 24 * time.Hour
+	expiredToken := credentialExpiry{
+		description:    "service account token",
+		expirationTime: now.Add(-time.Minute),
+		warningPeriod:  6 * time.Minute,
+	}
+	certificateMessage := "The client certificate of the cluster expires at 2023-12-14T22:13:20Z"
+
+	testCases := []struct {
+		name               string
+		credentials        *fedcorev1a1.ClusterCredentialsStatus
+		conditions         []fedcorev1a1.ClusterCondition
+		expiries           []credentialExpiry
+		expectedChanged    bool
+		expectedCondition  fedcorev1a1.ClusterCondition
+		expectedExpiration *metav1.Time
+	}{
+		{
+			name:            "credentials that do not expire",
+			expectedChanged: true,
+			expectedCondition: fedcorev1a1.ClusterCondition{
+				Status:             corev1.ConditionFalse,
+				Reason:             CredentialsValidReason,
+				Message:            CredentialsNotExpiringMessage,
+				LastTransitionTime: now,
+			},
+		},
+		{
+			name:               "valid credentials",
+			expiries:           []credentialExpiry{certificate},
+			expectedChanged:    true,
+			expectedExpiration: &certExpiryTime,
+			expectedCondition: fedcorev1a1.ClusterCondition{
+				Status:             corev1.ConditionFalse,
+				Reason:             CredentialsValidReason,
+				Message:            certificateMessage,
+				LastTransitionTime: now,
+			},
+		},
+		{
+			name:               "expiring credentials",
+			expiries:           []credentialExpiry{expiringCertificate},
+			expectedChanged:    true,
+			expectedExpiration: &certExpiryTime,
+			expectedCondition: fedcorev1a1.ClusterCondition{
+				Status:             corev1.ConditionTrue,
+				Reason:             CredentialsExpiringReason,
+				Message:            certificateMessage,
+				LastTransitionTime: now,
+			},
+		},
+		{
+			name:            "expired credentials",
+			expiries:        []credentialExpiry{expiredToken, certificate},
+			expectedChanged: true,
+			expectedExpiration: func() *metav1.Time {
+				t := metav1.NewTime(expiredToken.expirationTime)
+				return &t
+			}(),
+			expectedCondition: fedcorev1a1.ClusterCondition{
+				Status:             corev1.ConditionTrue,
+				Reason:             CredentialsExpiredReason,
+				Message:            "The service account token of the cluster expires at 2023-11-14T22:12:20Z",
+				LastTransitionTime: now,
+			},
+		},
+		{
+			name: "unchanged status",
+			credentials: &fedcorev1a1.ClusterCredentialsStatus{
+				SecretResourceVersion: "1",
+				ExpirationTime:        &certExpiryTime,
+			},
+			conditions: []fedcorev1a1.ClusterCondition{{
+				Type:               fedcorev1a1.ClusterCredentialsExpiring,
+				Status:             corev1.ConditionFalse,
+				Reason:             CredentialsValidReason,
+				Message:            certificateMessage,
+				LastProbeTime:      earlier,
+				LastTransitionTime: earlier,
+			}},
+			expiries:           []credentialExpiry{certificate},
+			expectedChanged:    false,
+			expectedExpiration: &certExpiryTime,
+			expectedCondition: fedcorev1a1.ClusterCondition{
+				Status:             corev1.ConditionFalse,
+				Reason:             CredentialsValidReason,
+				Message:            certificateMessage,
+				LastProbeTime:      earlier,
+				LastTransitionTime: earlier,
+			},
+		},
+		{
+			name: "rotated secret",
+			credentials: &fedcorev1a1.ClusterCredentialsStatus{
+				SecretResourceVersion: "0",
+				ExpirationTime:        &certExpiryTime,
+			},
+			conditions: []fedcorev1a1.ClusterCondition{{
+				Type:               fedcorev1a1.ClusterCredentialsExpiring,
+				Status:             corev1.ConditionFalse,
+				Reason:             CredentialsValidReason,
+				Message:            certificateMessage,
+				LastProbeTime:      earlier,
+				LastTransitionTime: earlier,
+			}},
+			expiries:           []credentialExpiry{certificate},
+			expectedChanged:    true,
+			expectedExpiration: &certExpiryTime,
+			expectedCondition: fedcorev1a1.ClusterCondition{
+				Status:             corev1.ConditionFalse,
+				Reason:             CredentialsValidReason,
+				Message:            certificateMessage,
+				LastTransitionTime: earlier,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := &fedcorev1a1.FederatedClusterStatus{Credentials: tc.credentials, Conditions: tc.conditions}
+			changed := setCredentialsStatus(status, "1", tc.expiries, now)
+			assert.Equal(t, tc.expectedChanged, changed)
+
+			assert.Equal(t, "1", status.Credentials.SecretResourceVersion)
+			assert.True(t, tc.expectedExpiration.Equal(status.Credentials.ExpirationTime))
+
+			condition := getClusterCondition(status, fedcorev1a1.ClusterCredentialsExpiring)
+			if assert.NotNil(t, condition) {
+				tc.expectedCondition.Type = fedcorev1a1.ClusterCredentialsExpiring
+				if tc.expectedCondition.LastProbeTime.IsZero() {
+					tc.expectedCondition.LastProbeTime = now
+				}
+				assert.Equal(t, tc.expectedCondition, *condition)
+			}
+		})
+	}
+}
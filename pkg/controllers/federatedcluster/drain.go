@@ -0,0 +1,288 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
+)
+
+// updateClusterDrainStatus reports the progress of draining the cluster, which is carried out by the scheduler. The
+// drain status is removed once the cluster is no longer drained.
+func (c *FederatedClusterController) updateClusterDrainStatus(ctx context.Context, clusterName string) error {
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		cluster, err := c.client.CoreV1alpha1().FederatedClusters().Get(ctx, clusterName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		var drainStatus *fedcorev1a1.ClusterDrainStatus
+		if cluster.Spec.Drain != nil {
+			remainingObjects, err := c.countFederatedObjectsInCluster(clusterName)
+			if err != nil {
+				return err
+			}
+			drainStatus = getNewClusterDrainStatus(cluster.Status.Drain, remainingObjects, metav1.Now())
+		}
+
+		if reflect.DeepEqual(drainStatus, cluster.Status.Drain) {
+			return nil
+		}
+
+		klog.FromContext(ctx).V(2).Info("Updating cluster drain status", "drainStatus", drainStatus)
+		cluster.Status.Drain = drainStatus
+		_, err = c.client.CoreV1alpha1().FederatedClusters().UpdateStatus(ctx, cluster, metav1.UpdateOptions{})
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to update cluster drain status: %w", err)
+	}
+
+	return nil
+}
+
+func getNewClusterDrainStatus(
+	existing *fedcorev1a1.ClusterDrainStatus,
+	remainingObjects int64,
+	now metav1.Time,
+) *fedcorev1a1.ClusterDrainStatus {
+	drainStatus := &fedcorev1a1.ClusterDrainStatus{StartTime: now}
+	if existing != nil {
+		drainStatus = existing.DeepCopy()
+	}
+
+	drainStatus.RemainingObjects = remainingObjects
+	if remainingObjects > 0 {
+		drainStatus.Phase = fedcorev1a1.ClusterDraining
+		drainStatus.CompletionTime = nil
+	} else {
+		drainStatus.Phase = fedcorev1a1.ClusterDrained
+		if drainStatus.CompletionTime == nil {
+			drainStatus.CompletionTime = &now
+		}
+	}
+	return drainStatus
+}
+
+// startPlacementCounters starts counting the federated objects placed in each cluster for every federated type. The
+// informers of the federated types are shared with the controllers of the federated types and are stopped with ctx.
+func (c *FederatedClusterController) startPlacementCounters(ctx context.Context) error {
+	_, err := c.ftcInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.ensurePlacementCounter(ctx, obj.(*fedcorev1a1.FederatedTypeConfig))
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			c.ensurePlacementCounter(ctx, obj.(*fedcorev1a1.FederatedTypeConfig))
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if typeConfig, ok := obj.(*fedcorev1a1.FederatedTypeConfig); ok {
+				c.placementCountersLock.Lock()
+				defer c.placementCountersLock.Unlock()
+				c.removePlacementCounter(typeConfig.Name)
+			}
+		},
+	})
+	return err
+}
+
+func (c *FederatedClusterController) ensurePlacementCounter(ctx context.Context, typeConfig *fedcorev1a1.FederatedTypeConfig) {
+	federatedType := typeConfig.GetFederatedType()
+	gvr := schemautil.APIResourceToGVR(&federatedType)
+
+	c.placementCountersLock.Lock()
+	defer c.placementCountersLock.Unlock()
+
+	if counter, exists := c.placementCounters[typeConfig.Name]; exists {
+		if counter.gvr == gvr {
+			return
+		}
+		c.removePlacementCounter(typeConfig.Name)
+	}
+
+	informer := c.dynamicInformerFactory.ForResource(gvr).Informer()
+	counter := newPlacementCounter(gvr, informer, c.logger.WithValues("ftc", typeConfig.Name))
+	registration, err := informer.AddEventHandler(counter)
+	if err != nil {
+		c.logger.Error(err, "Failed to count federated objects", "ftc", typeConfig.Name)
+		return
+	}
+	counter.registration = registration
+	c.placementCounters[typeConfig.Name] = counter
+
+	// Start is a no-op for informers that are already running.
+	c.dynamicInformerFactory.Start(ctx.Done())
+}
+
+// removePlacementCounter must be called with placementCountersLock held.
+func (c *FederatedClusterController) removePlacementCounter(ftcName string) {
+	counter, exists := c.placementCounters[ftcName]
+	if !exists {
+		return
+	}
+	if err := counter.informer.RemoveEventHandler(counter.registration); err != nil {
+		c.logger.Error(err, "Failed to stop counting federated objects", "ftc", ftcName)
+	}
+	delete(c.placementCounters, ftcName)
+}
+
+// countFederatedObjectsInCluster returns the number of federated objects of all federated types that are placed in
+// the cluster.
+func (c *FederatedClusterController) countFederatedObjectsInCluster(clusterName string) (int64, error) {
+	typeConfigs, err := c.ftcLister.List(labels.Everything())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list federated type configs: %w", err)
+	}
+
+	c.placementCountersLock.Lock()
+	defer c.placementCountersLock.Unlock()
+
+	count := int64(0)
+	for _, typeConfig := range typeConfigs {
+		counter, exists := c.placementCounters[typeConfig.Name]
+		if !exists || !counter.hasSynced() {
+			// Counting from an unsynced cache would report the cluster as drained prematurely.
+			return 0, fmt.Errorf("federated objects of type %s are not counted yet", typeConfig.GetFederatedType().Name)
+		}
+		count += counter.count(clusterName)
+	}
+
+	return count, nil
+}
+
+// placementCounter counts the federated objects of a federated type placed in each cluster from the events of the
+// informer of the federated type, so that counting does not require listing all objects.
+type placementCounter struct {
+	gvr          schema.GroupVersionResource
+	informer     cache.SharedIndexInformer
+	registration cache.ResourceEventHandlerRegistration
+	logger       klog.Logger
+
+	lock sync.RWMutex
+	// placedClusters maps the key of each observed object to the clusters it is placed in.
+	placedClusters map[string]sets.String
+	counts         map[string]int64
+}
+
+var _ cache.ResourceEventHandler = &placementCounter{}
+
+func newPlacementCounter(
+	gvr schema.GroupVersionResource,
+	informer cache.SharedIndexInformer,
+	logger klog.Logger,
+) *placementCounter {
+	return &placementCounter{
+		gvr:            gvr,
+		informer:       informer,
+		logger:         logger,
+		placedClusters: map[string]sets.String{},
+		counts:         map[string]int64{},
+	}
+}
+
+func (p *placementCounter) OnAdd(obj interface{}) {
+	p.observe(obj)
+}
+
+func (p *placementCounter) OnUpdate(_, newObj interface{}) {
+	p.observe(newObj)
+}
+
+func (p *placementCounter) OnDelete(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		p.logger.Error(err, "Failed to get key of deleted federated object")
+		return
+	}
+	p.set(key, nil, false)
+}
+
+func (p *placementCounter) observe(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		p.logger.Error(err, "Failed to get key of federated object")
+		return
+	}
+
+	clusters := sets.String{}
+	if fedObject, ok := obj.(*unstructured.Unstructured); ok {
+		placements, err := util.UnmarshalGenericPlacements(fedObject)
+		if err != nil {
+			p.logger.Error(err, "Failed to unmarshal placements", "object", key)
+		} else {
+			for cluster := range placements.ClusterNameUnion() {
+				clusters.Insert(cluster)
+			}
+		}
+	}
+	p.set(key, clusters, true)
+}
+
+func (p *placementCounter) set(key string, clusters sets.String, exists bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for cluster := range p.placedClusters[key] {
+		p.counts[cluster]--
+		if p.counts[cluster] <= 0 {
+			delete(p.counts, cluster)
+		}
+	}
+	delete(p.placedClusters, key)
+
+	if !exists {
+		return
+	}
+	p.placedClusters[key] = clusters
+	for cluster := range clusters {
+		p.counts[cluster]++
+	}
+}
+
+// hasSynced returns true once the informer has synced and the counter has observed all objects in its store.
+func (p *placementCounter) hasSynced() bool {
+	if !p.informer.HasSynced() {
+		return false
+	}
+	storedObjects := len(p.informer.GetStore().ListKeys())
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return len(p.placedClusters) >= storedObjects
+}
+
+func (p *placementCounter) count(clusterName string) int64 {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.counts[clusterName]
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+func Test_getNewClusterDrainStatus(t *testing.T) {
+	startTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	completionTime := metav1.NewTime(time.Now().Add(-time.Minute))
+	now := metav1.Now()
+
+	testCases := []struct {
+		name             string
+		existing         *fedcorev1a1.ClusterDrainStatus
+		remainingObjects int64
+		expected         *fedcorev1a1.ClusterDrainStatus
+	}{
+		{
+			name:             "drain started",
+			remainingObjects: 3,
+			expected: &fedcorev1a1.ClusterDrainStatus{
+				Phase:            fedcorev1a1.ClusterDraining,
+				StartTime:        now,
+				RemainingObjects: 3,
+			},
+		},
+		{
+			name: "drain in progress",
+			existing: &fedcorev1a1.ClusterDrainStatus{
+				Phase:            fedcorev1a1.ClusterDraining,
+				StartTime:        startTime,
+				RemainingObjects: 3,
+			},
+			remainingObjects: 1,
+			expected: &fedcorev1a1.ClusterDrainStatus{
+				Phase:            fedcorev1a1.ClusterDraining,
+				StartTime:        startTime,
+				RemainingObjects: 1,
+			},
+		},
+		{
+			name: "drain completed",
+			existing: &fedcorev1a1.ClusterDrainStatus{
+				Phase:            fedcorev1a1.ClusterDraining,
+				StartTime:        startTime,
+				RemainingObjects: 1,
+			},
+			remainingObjects: 0,
+			expected: &fedcorev1a1.ClusterDrainStatus{
+				Phase:            fedcorev1a1.ClusterDrained,
+				StartTime:        startTime,
+				CompletionTime:   &now,
+				RemainingObjects: 0,
+			},
+		},
+		{
+			name: "drained cluster with new placements",
+			existing: &fedcorev1a1.ClusterDrainStatus{
+				Phase:          fedcorev1a1.ClusterDrained,
+				StartTime:      startTime,
+				CompletionTime: &completionTime,
+			},
+			remainingObjects: 1,
+			expected: &fedcorev1a1.ClusterDrainStatus{
+				Phase:            fedcorev1a1.ClusterDraining,
+				StartTime:        startTime,
+				RemainingObjects: 1,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getNewClusterDrainStatus(tc.existing, tc.remainingObjects, now))
+		})
+	}
+}
+
+func newPlacedObject(t *testing.T, name string, clusters ...string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	clusterSet := map[string]struct{}{}
+	for _, cluster := range clusters {
+		clusterSet[cluster] = struct{}{}
+	}
+	_, err := util.SetPlacementClusterNames(obj, "kubeadmiral.io/global-scheduler", clusterSet)
+	assert.NoError(t, err)
+	return obj
+}
+
+func Test_placementCounter(t *testing.T) {
+	counter := newPlacementCounter(schema.GroupVersionResource{}, nil, klog.Background())
+
+	objA := newPlacedObject(t, "a", "cluster1", "cluster2")
+	objB := newPlacedObject(t, "b", "cluster1")
+	counter.OnAdd(objA)
+	counter.OnAdd(objB)
+	assert.Equal(t, int64(2), counter.count("cluster1"))
+	assert.Equal(t, int64(1), counter.count("cluster2"))
+	assert.Equal(t, int64(0), counter.count("cluster3"))
+
+	counter.OnUpdate(objA, newPlacedObject(t, "a", "cluster3"))
+	assert.Equal(t, int64(1), counter.count("cluster1"))
+	assert.Equal(t, int64(0), counter.count("cluster2"))
+	assert.Equal(t, int64(1), counter.count("cluster3"))
+
+	counter.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/b", Obj: objB})
+	assert.Equal(t, int64(0), counter.count("cluster1"))
+	assert.Equal(t, int64(1), counter.count("cluster3"))
+	assert.Len(t, counter.placedClusters, 1)
+}
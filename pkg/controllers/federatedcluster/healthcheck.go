@@ -0,0 +1,311 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+const (
+	HealthCheckClusterNotReadyReason  = "ClusterNotReady"
+	HealthCheckClusterNotReadyMessage = "Health check skipped because the cluster is not ready"
+
+	EnoughReadyNodesReason              = "EnoughReadyNodes"
+	InsufficientReadyNodesReason        = "InsufficientReadyNodes"
+	ReadyNodesMessageTemplate           = "%d of %d nodes are ready, at least %d%% required"
+	NodeCollectionFailedReason          = "NodeCollectionFailed"
+	NodeCollectionFailedMessageTemplate = "Failed to list nodes: %v"
+
+	ReadyzChecksPassedReason  = "ReadyzChecksPassed"
+	ReadyzChecksPassedMessage = "All /readyz checks passed"
+	ReadyzChecksFailedReason  = "ReadyzChecksFailed"
+
+	CanarySucceededReason                = "CanarySucceeded"
+	CanarySucceededMessageTemplate       = "Canary object created and deleted in %v"
+	CanaryFailedReason                   = "CanaryFailed"
+	CanaryLatencyExceededReason          = "CanaryLatencyExceeded"
+	CanaryLatencyExceededMessageTemplate = "Canary object created and deleted in %v, exceeding %v"
+
+	HTTPProbesSucceededReason  = "HTTPProbesSucceeded"
+	HTTPProbesSucceededMessage = "All HTTP probes succeeded"
+	HTTPProbesFailedReason     = "HTTPProbesFailed"
+
+	defaultHealthCheckTimeout = 5 * time.Second
+	canaryNamePrefix          = "kubeadmiral-canary-"
+)
+
+type healthCheckResult struct {
+	status  corev1.ConditionStatus
+	reason  string
+	message string
+}
+
+// checkClusterHealth runs the health checks configured for the cluster and sets their conditions in the cluster's
+// status. The conditions of the configured checks are Unknown if the cluster is not ready, and the conditions of
+// checks that are not configured are removed.
+func checkClusterHealth(
+	ctx context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+	clusterKubeClient kubernetes.Interface,
+	nodeLister corev1listers.NodeLister,
+	clusterReady bool,
+	conditionTime metav1.Time,
+) {
+	healthCheck := cluster.Spec.HealthCheck
+	if healthCheck == nil {
+		healthCheck = &fedcorev1a1.ClusterHealthCheck{}
+	}
+
+	checks := map[fedcorev1a1.ClusterConditionType]func() healthCheckResult{}
+	if healthCheck.MinReadyNodesPercentage != nil {
+		checks[fedcorev1a1.ClusterNodesHealthy] = func() healthCheckResult {
+			nodes, err := nodeLister.List(labels.Everything())
+			if err != nil {
+				return healthCheckResult{
+					status:  corev1.ConditionUnknown,
+					reason:  NodeCollectionFailedReason,
+					message: fmt.Sprintf(NodeCollectionFailedMessageTemplate, err),
+				}
+			}
+			return checkNodesHealth(nodes, *healthCheck.MinReadyNodesPercentage)
+		}
+	}
+	if len(healthCheck.ReadyzChecks) > 0 {
+		checks[fedcorev1a1.ClusterAPIServerHealthy] = func() healthCheckResult {
+			return checkReadyz(ctx, clusterKubeClient, healthCheck.ReadyzChecks)
+		}
+	}
+	if healthCheck.Canary != nil {
+		checks[fedcorev1a1.ClusterCanaryHealthy] = func() healthCheckResult {
+			return checkCanary(ctx, clusterKubeClient, healthCheck.Canary)
+		}
+	}
+	if len(healthCheck.HTTPProbes) > 0 {
+		checks[fedcorev1a1.ClusterHTTPProbesHealthy] = func() healthCheckResult {
+			return checkHTTPProbes(ctx, clusterKubeClient, healthCheck.HTTPProbes)
+		}
+	}
+
+	logger := klog.FromContext(ctx)
+	for _, conditionType := range fedcorev1a1.ClusterHealthConditionTypes {
+		check, exists := checks[conditionType]
+		if !exists {
+			removeClusterCondition(&cluster.Status, conditionType)
+			continue
+		}
+
+		result := healthCheckResult{
+			status:  corev1.ConditionUnknown,
+			reason:  HealthCheckClusterNotReadyReason,
+			message: HealthCheckClusterNotReadyMessage,
+		}
+		if clusterReady {
+			result = check()
+		}
+		if result.status != corev1.ConditionTrue {
+			logger.V(2).Info("Cluster health check did not pass", "condition", conditionType, "reason", result.reason)
+		}
+
+		condition := getNewClusterHealthCondition(&cluster.Status, conditionType, result, conditionTime)
+		setClusterCondition(&cluster.Status, &condition)
+	}
+}
+
+func checkNodesHealth(nodes []*corev1.Node, minReadyNodesPercentage int32) healthCheckResult {
+	readyNodes := 0
+	for _, node := range nodes {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				readyNodes++
+				break
+			}
+		}
+	}
+
+	result := healthCheckResult{
+		status:  corev1.ConditionTrue,
+		reason:  EnoughReadyNodesReason,
+		message: fmt.Sprintf(ReadyNodesMessageTemplate, readyNodes, len(nodes), minReadyNodesPercentage),
+	}
+	// a cluster without nodes only passes the check if no ready nodes are required
+	if readyNodes*100 < int(minReadyNodesPercentage)*len(nodes) || (len(nodes) == 0 && minReadyNodesPercentage > 0) {
+		result.status = corev1.ConditionFalse
+		result.reason = InsufficientReadyNodesReason
+	}
+	return result
+}
+
+func checkReadyz(ctx context.Context, clusterKubeClient kubernetes.Interface, checks []string) healthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, defaultHealthCheckTimeout)
+	defer cancel()
+
+	var failures []string
+	for _, check := range checks {
+		body, err := clusterKubeClient.Discovery().RESTClient().Get().AbsPath("/readyz", check).Do(ctx).Raw()
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", check, err))
+		} else if !strings.EqualFold(strings.TrimSpace(string(body)), "ok") {
+			failures = append(failures, fmt.Sprintf("%s: responded without ok", check))
+		}
+	}
+
+	if len(failures) > 0 {
+		return healthCheckResult{
+			status:  corev1.ConditionFalse,
+			reason:  ReadyzChecksFailedReason,
+			message: strings.Join(failures, "; "),
+		}
+	}
+	return healthCheckResult{
+		status:  corev1.ConditionTrue,
+		reason:  ReadyzChecksPassedReason,
+		message: ReadyzChecksPassedMessage,
+	}
+}
+
+func checkCanary(
+	ctx context.Context,
+	clusterKubeClient kubernetes.Interface,
+	canary *fedcorev1a1.ClusterCanaryProbe,
+) healthCheckResult {
+	namespace := canary.Namespace
+	if len(namespace) == 0 {
+		namespace = metav1.NamespaceDefault
+	}
+	maxLatency := canary.MaxLatency.Duration
+	if maxLatency == 0 {
+		maxLatency = defaultHealthCheckTimeout
+	}
+
+	// allow the canary to exceed its maximum latency so that we can tell a slow cluster from a broken one
+	ctx, cancel := context.WithTimeout(ctx, 2*maxLatency)
+	defer cancel()
+
+	start := time.Now()
+	configMap, err := clusterKubeClient.CoreV1().ConfigMaps(namespace).Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: canaryNamePrefix},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return healthCheckResult{
+			status:  corev1.ConditionFalse,
+			reason:  CanaryFailedReason,
+			message: fmt.Sprintf("Failed to create canary object: %v", err),
+		}
+	}
+	if err := clusterKubeClient.CoreV1().ConfigMaps(namespace).Delete(ctx, configMap.Name, metav1.DeleteOptions{}); err != nil {
+		return healthCheckResult{
+			status:  corev1.ConditionFalse,
+			reason:  CanaryFailedReason,
+			message: fmt.Sprintf("Failed to delete canary object %s/%s: %v", namespace, configMap.Name, err),
+		}
+	}
+	latency := time.Since(start).Round(time.Millisecond)
+
+	if latency > maxLatency {
+		return healthCheckResult{
+			status:  corev1.ConditionFalse,
+			reason:  CanaryLatencyExceededReason,
+			message: fmt.Sprintf(CanaryLatencyExceededMessageTemplate, latency, maxLatency),
+		}
+	}
+	return healthCheckResult{
+		status:  corev1.ConditionTrue,
+		reason:  CanarySucceededReason,
+		message: fmt.Sprintf(CanarySucceededMessageTemplate, latency),
+	}
+}
+
+func checkHTTPProbes(
+	ctx context.Context,
+	clusterKubeClient kubernetes.Interface,
+	probes []fedcorev1a1.ClusterHTTPProbe,
+) healthCheckResult {
+	var failures []string
+	for _, probe := range probes {
+		timeout := probe.Timeout.Duration
+		if timeout == 0 {
+			timeout = defaultHealthCheckTimeout
+		}
+
+		var statusCode int
+		err := clusterKubeClient.Discovery().RESTClient().Get().
+			AbsPath(probe.Path).
+			Timeout(timeout).
+			Do(ctx).
+			StatusCode(&statusCode).
+			Error()
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", probe.Name, err))
+		} else if statusCode < 200 || statusCode >= 300 {
+			failures = append(failures, fmt.Sprintf("%s: responded with status code %d", probe.Name, statusCode))
+		}
+	}
+
+	if len(failures) > 0 {
+		return healthCheckResult{
+			status:  corev1.ConditionFalse,
+			reason:  HTTPProbesFailedReason,
+			message: strings.Join(failures, "; "),
+		}
+	}
+	return healthCheckResult{
+		status:  corev1.ConditionTrue,
+		reason:  HTTPProbesSucceededReason,
+		message: HTTPProbesSucceededMessage,
+	}
+}
+
+// getNewClusterHealthCondition returns the condition for a health check result, keeping the last transition time of
+// the existing condition if its status has not changed.
+func getNewClusterHealthCondition(
+	status *fedcorev1a1.FederatedClusterStatus,
+	conditionType fedcorev1a1.ClusterConditionType,
+	result healthCheckResult,
+	conditionTime metav1.Time,
+) fedcorev1a1.ClusterCondition {
+	condition := fedcorev1a1.ClusterCondition{
+		Type:               conditionType,
+		Status:             result.status,
+		Reason:             result.reason,
+		Message:            result.message,
+		LastProbeTime:      conditionTime,
+		LastTransitionTime: conditionTime,
+	}
+	preserveLastTransitionTime(status, &condition)
+	return condition
+}
+
+func removeClusterCondition(status *fedcorev1a1.FederatedClusterStatus, conditionType fedcorev1a1.ClusterConditionType) {
+	for i, existingCondition := range status.Conditions {
+		if existingCondition.Type == conditionType {
+			status.Conditions = append(status.Conditions[:i], status.Conditions[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+func makeNode(ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+		},
+	}
+}
+
+func Test_checkNodesHealth(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		nodes                   []*corev1.Node
+		minReadyNodesPercentage int32
+		expectedStatus          corev1.ConditionStatus
+		expectedMessage         string
+	}{
+		{
+			name:                    "enough ready nodes",
+			nodes:                   []*corev1.Node{makeNode(corev1.ConditionTrue), makeNode(corev1.ConditionFalse)},
+			minReadyNodesPercentage: 50,
+			expectedStatus:          corev1.ConditionTrue,
+			expectedMessage:         "1 of 2 nodes are ready, at least 50% required",
+		},
+		{
+			name: "insufficient ready nodes",
+			nodes: []*corev1.Node{
+				makeNode(corev1.ConditionTrue),
+				makeNode(corev1.ConditionFalse),
+				makeNode(corev1.ConditionUnknown),
+			},
+			minReadyNodesPercentage: 50,
+			expectedStatus:          corev1.ConditionFalse,
+			expectedMessage:         "1 of 3 nodes are ready, at least 50% required",
+		},
+		{
+			name:                    "no nodes",
+			nodes:                   nil,
+			minReadyNodesPercentage: 1,
+			expectedStatus:          corev1.ConditionFalse,
+			expectedMessage:         "0 of 0 nodes are ready, at least 1% required",
+		},
+		{
+			name:                    "no ready nodes required",
+			nodes:                   []*corev1.Node{makeNode(corev1.ConditionFalse)},
+			minReadyNodesPercentage: 0,
+			expectedStatus:          corev1.ConditionTrue,
+			expectedMessage:         "0 of 1 nodes are ready, at least 0% required",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := checkNodesHealth(tc.nodes, tc.minReadyNodesPercentage)
+			assert.Equal(t, tc.expectedStatus, result.status)
+			assert.Equal(t, tc.expectedMessage, result.message)
+		})
+	}
+}
+
+func Test_checkCanary(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	result := checkCanary(context.Background(), client, &fedcorev1a1.ClusterCanaryProbe{
+		Namespace:  "kube-public",
+		MaxLatency: metav1.Duration{Duration: time.Minute},
+	})
+	assert.Equal(t, corev1.ConditionTrue, result.status)
+	assert.Equal(t, CanarySucceededReason, result.reason)
+
+	configMaps, err := client.CoreV1().ConfigMaps("kube-public").List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, configMaps.Items, "the canary object should be deleted")
+}
+
+type fakeNodeLister struct {
+	nodes []*corev1.Node
+}
+
+func (l *fakeNodeLister) List(_ labels.Selector) ([]*corev1.Node, error) {
+	return l.nodes, nil
+}
+
+func (l *fakeNodeLister) Get(name string) (*corev1.Node, error) {
+	return nil, nil
+}
+
+func Test_checkClusterHealth(t *testing.T) {
+	oldTime := metav1.NewTime(time.Unix(0, 0))
+	conditionTime := metav1.NewTime(time.Unix(100, 0))
+
+	cluster := &fedcorev1a1.FederatedCluster{
+		Spec: fedcorev1a1.FederatedClusterSpec{
+			HealthCheck: &fedcorev1a1.ClusterHealthCheck{MinReadyNodesPercentage: pointer.Int32(100)},
+		},
+		Status: fedcorev1a1.FederatedClusterStatus{
+			Conditions: []fedcorev1a1.ClusterCondition{
+				{Type: fedcorev1a1.ClusterNodesHealthy, Status: corev1.ConditionTrue, LastTransitionTime: oldTime},
+				{Type: fedcorev1a1.ClusterCanaryHealthy, Status: corev1.ConditionFalse, LastTransitionTime: oldTime},
+			},
+		},
+	}
+	lister := &fakeNodeLister{nodes: []*corev1.Node{makeNode(corev1.ConditionTrue)}}
+
+	checkClusterHealth(context.Background(), cluster, fake.NewSimpleClientset(), lister, true, conditionTime)
+	assert.Len(t, cluster.Status.Conditions, 1, "conditions of checks that are not configured should be removed")
+	condition := cluster.Status.Conditions[0]
+	assert.Equal(t, fedcorev1a1.ClusterNodesHealthy, condition.Type)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, oldTime, condition.LastTransitionTime)
+	assert.Equal(t, conditionTime, condition.LastProbeTime)
+
+	checkClusterHealth(context.Background(), cluster, fake.NewSimpleClientset(), lister, false, conditionTime)
+	condition = cluster.Status.Conditions[0]
+	assert.Equal(t, corev1.ConditionUnknown, condition.Status)
+	assert.Equal(t, HealthCheckClusterNotReadyReason, condition.Reason)
+	assert.Equal(t, conditionTime, condition.LastTransitionTime)
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+const (
+	AgentNotConnectedReason  = "AgentNotConnected"
+	AgentNotConnectedMessage = "Waiting for the agent of the cluster to report its first heartbeat"
+
+	AgentHeartbeatTimeoutReason          = "AgentHeartbeatTimeout"
+	AgentHeartbeatTimeoutMessageTemplate = "The agent of the cluster has not reported a heartbeat for more than %v"
+
+	// DefaultAgentHeartbeatPeriod is the default period at which the agent reports the status of its cluster.
+	DefaultAgentHeartbeatPeriod = 30 * time.Second
+	// MinAgentHeartbeatTimeoutPeriods is the minimum number of heartbeat periods of the agent of a cluster after its
+	// last heartbeat before the cluster is considered offline, so that a single delayed heartbeat does not mark the
+	// cluster as offline.
+	MinAgentHeartbeatTimeoutPeriods = 2
+)
+
+// handleNotJoinedPullCluster processes a cluster in Pull mode that has not joined. Such a cluster joins when its agent
+// reports the first heartbeat, since the control plane does not connect to the cluster. The returned condition and
+// error have the same meaning as those returned by handleNotJoinedCluster.
+func handleNotJoinedPullCluster(
+	ctx context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+	kubeClient kubeclient.Interface,
+	eventRecorder record.EventRecorder,
+	clusterJoinTimeout time.Duration,
+) (*fedcorev1a1.ClusterCondition, error) {
+	logger := klog.FromContext(ctx).WithValues("process", "cluster-join")
+
+	// the agent watches the pulled objects in the namespace, so it is created before the agent connects
+	if err := ensurePullNamespace(ctx, kubeClient, cluster.Name); err != nil {
+		return nil, err
+	}
+
+	if cluster.Status.Agent != nil {
+		logger.V(2).Info("Cluster joined successfully")
+		eventRecorder.Eventf(
+			cluster,
+			corev1.EventTypeNormal, EventReasonJoinClusterSuccess, "Cluster joined successfully",
+		)
+		return &fedcorev1a1.ClusterCondition{
+			Status:  corev1.ConditionTrue,
+			Reason:  ClusterJoinedReason,
+			Message: ClusterJoinedMessage,
+		}, nil
+	}
+
+	joinedCondition := getClusterCondition(&cluster.Status, fedcorev1a1.ClusterJoined)
+	if joinedCondition != nil &&
+		joinedCondition.Status == corev1.ConditionFalse &&
+		time.Since(joinedCondition.LastTransitionTime.Time) > clusterJoinTimeout {
+		logger.Error(nil, "Cluster join timed out")
+		eventRecorder.Eventf(
+			cluster,
+			corev1.EventTypeWarning,
+			EventReasonJoinClusterTimeoutExceeded,
+			"Cluster join timed out",
+		)
+		return &fedcorev1a1.ClusterCondition{
+			Status:  corev1.ConditionFalse,
+			Reason:  JoinTimeoutExceededReason,
+			Message: fmt.Sprintf(JoinTimeoutExceededMessageTemplate, joinedCondition.Message),
+		}, nil
+	}
+
+	// the error makes the caller retry until the agent connects or the join times out
+	return &fedcorev1a1.ClusterCondition{
+		Status:  corev1.ConditionFalse,
+		Reason:  AgentNotConnectedReason,
+		Message: AgentNotConnectedMessage,
+	}, fmt.Errorf("agent of cluster has not connected")
+}
+
+// ensurePullNamespace creates the namespace holding the pulled objects of the cluster in Pull mode. The agent of the
+// cluster is only granted access to this namespace.
+func ensurePullNamespace(ctx context.Context, kubeClient kubeclient.Interface, clusterName string) error {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: util.PullNamespace(clusterName)}}
+	_, err := kubeClient.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create pull namespace: %w", err)
+	}
+	return nil
+}
+
+func deletePullNamespace(ctx context.Context, kubeClient kubeclient.Interface, clusterName string) error {
+	err := kubeClient.CoreV1().Namespaces().Delete(ctx, util.PullNamespace(clusterName), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete pull namespace: %w", err)
+	}
+	return nil
+}
+
+// updatePullClusterStatus marks a cluster in Pull mode as offline if its agent has not reported a heartbeat within
+// the timeout. The status of a cluster in Pull mode is otherwise collected and reported by its agent.
+func updatePullClusterStatus(
+	ctx context.Context,
+	clusterName string,
+	fedClient fedclient.Interface,
+	heartbeatTimeout time.Duration,
+) error {
+	logger := klog.FromContext(ctx)
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		cluster, err := fedClient.CoreV1alpha1().FederatedClusters().Get(ctx, clusterName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if !setAgentHeartbeatTimeoutConditions(&cluster.Status, heartbeatTimeout, metav1.Now()) {
+			return nil
+		}
+
+		logger.V(2).Info("Agent heartbeat timed out, marking cluster as offline")
+		_, err = fedClient.CoreV1alpha1().FederatedClusters().UpdateStatus(ctx, cluster, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// setAgentHeartbeatTimeoutConditions sets the offline and ready conditions of the cluster if the last heartbeat of
+// its agent is older than the timeout, and returns whether the status was changed. The timeout is extended to
+// MinAgentHeartbeatTimeoutPeriods heartbeat periods if the agent reports a longer heartbeat period.
+func setAgentHeartbeatTimeoutConditions(
+	status *fedcorev1a1.FederatedClusterStatus,
+	heartbeatTimeout time.Duration,
+	now metav1.Time,
+) bool {
+	if status.Agent == nil {
+		return false
+	}
+	if period := status.Agent.HeartbeatPeriod; period != nil &&
+		heartbeatTimeout < MinAgentHeartbeatTimeoutPeriods*period.Duration {
+		heartbeatTimeout = MinAgentHeartbeatTimeoutPeriods * period.Duration
+	}
+	if now.Sub(status.Agent.LastHeartbeatTime.Time) <= heartbeatTimeout {
+		return false
+	}
+
+	readyCondition := getClusterCondition(status, fedcorev1a1.ClusterReady)
+	if readyCondition != nil && readyCondition.Reason == AgentHeartbeatTimeoutReason {
+		return false
+	}
+
+	offlineCondition := getNewClusterOfflineCondition(corev1.ConditionTrue, now)
+	preserveLastTransitionTime(status, &offlineCondition)
+	setClusterCondition(status, &offlineCondition)
+
+	newReadyCondition := getNewClusterReadyCondition(
+		corev1.ConditionUnknown,
+		AgentHeartbeatTimeoutReason,
+		fmt.Sprintf(AgentHeartbeatTimeoutMessageTemplate, heartbeatTimeout),
+		now,
+	)
+	preserveLastTransitionTime(status, &newReadyCondition)
+	setClusterCondition(status, &newReadyCondition)
+
+	return true
+}
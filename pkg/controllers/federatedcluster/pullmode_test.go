@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+func Test_setAgentHeartbeatTimeoutConditions(t *testing.T) {
+	now := metav1.Now()
+	timeout := 3 * time.Minute
+	readySince := metav1.NewTime(now.Add(-time.Hour))
+
+	readyConditions := []fedcorev1a1.ClusterCondition{
+		{Type: fedcorev1a1.ClusterOffline, Status: corev1.ConditionFalse, LastTransitionTime: readySince},
+		{Type: fedcorev1a1.ClusterReady, Status: corev1.ConditionTrue, LastTransitionTime: readySince},
+	}
+	timedOutConditions := []fedcorev1a1.ClusterCondition{
+		{
+			Type:               fedcorev1a1.ClusterOffline,
+			Status:             corev1.ConditionTrue,
+			Reason:             ClusterNotReachableReason,
+			Message:            ClusterNotReachableMsg,
+			LastProbeTime:      now,
+			LastTransitionTime: now,
+		},
+		{
+			Type:               fedcorev1a1.ClusterReady,
+			Status:             corev1.ConditionUnknown,
+			Reason:             AgentHeartbeatTimeoutReason,
+			Message:            "The agent of the cluster has not reported a heartbeat for more than 3m0s",
+			LastProbeTime:      now,
+			LastTransitionTime: now,
+		},
+	}
+
+	testCases := []struct {
+		name               string
+		agent              *fedcorev1a1.ClusterAgentStatus
+		conditions         []fedcorev1a1.ClusterCondition
+		expectedConditions []fedcorev1a1.ClusterCondition
+		expectedChanged    bool
+	}{
+		{
+			name:               "agent not connected",
+			expectedConditions: nil,
+			expectedChanged:    false,
+		},
+		{
+			name:               "recent heartbeat",
+			agent:              &fedcorev1a1.ClusterAgentStatus{LastHeartbeatTime: metav1.NewTime(now.Add(-time.Minute))},
+			conditions:         readyConditions,
+			expectedConditions: readyConditions,
+			expectedChanged:    false,
+		},
+		{
+			name:               "heartbeat timed out",
+			agent:              &fedcorev1a1.ClusterAgentStatus{LastHeartbeatTime: metav1.NewTime(now.Add(-time.Hour))},
+			conditions:         readyConditions,
+			expectedConditions: timedOutConditions,
+			expectedChanged:    true,
+		},
+		{
+			name: "heartbeat within the reported heartbeat period",
+			agent: &fedcorev1a1.ClusterAgentStatus{
+				LastHeartbeatTime: metav1.NewTime(now.Add(-5 * time.Minute)),
+				HeartbeatPeriod:   &metav1.Duration{Duration: 3 * time.Minute},
+			},
+			conditions:         readyConditions,
+			expectedConditions: readyConditions,
+			expectedChanged:    false,
+		},
+		{
+			name:               "heartbeat timeout already recorded",
+			agent:              &fedcorev1a1.ClusterAgentStatus{LastHeartbeatTime: metav1.NewTime(now.Add(-time.Hour))},
+			conditions:         timedOutConditions,
+			expectedConditions: timedOutConditions,
+			expectedChanged:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := &fedcorev1a1.FederatedClusterStatus{
+				Agent:      tc.agent,
+				Conditions: append([]fedcorev1a1.ClusterCondition(nil), tc.conditions...),
+			}
+			changed := setAgentHeartbeatTimeoutConditions(status, timeout, now)
+			assert.Equal(t, tc.expectedChanged, changed)
+			assert.Equal(t, tc.expectedConditions, status.Conditions)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
+)
+
+// managedTaints are the taints added and removed by the controller according to the conditions of a cluster.
+var managedTaints = []corev1.Taint{
+	{Key: fedcorev1a1.TaintClusterNotReady, Effect: corev1.TaintEffectNoSchedule},
+	{Key: fedcorev1a1.TaintClusterUnreachable, Effect: corev1.TaintEffectNoExecute},
+	{Key: fedcorev1a1.TaintClusterUnschedulable, Effect: corev1.TaintEffectNoSchedule},
+}
+
+// updateClusterTaints adds and removes the managed taints of the cluster according to its latest spec and conditions.
+func updateClusterTaints(
+	ctx context.Context,
+	clusterName string,
+	fedClient fedclient.Interface,
+	unreachableTaintGracePeriod time.Duration,
+) error {
+	logger := klog.FromContext(ctx)
+
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		cluster, err := fedClient.CoreV1alpha1().FederatedClusters().Get(ctx, clusterName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		taints, changed := getDesiredClusterTaints(cluster, unreachableTaintGracePeriod, time.Now())
+		if !changed {
+			return nil
+		}
+
+		logger.V(2).Info("Updating cluster taints", "taints", taints)
+		cluster.Spec.Taints = taints
+		_, err = fedClient.CoreV1alpha1().FederatedClusters().Update(ctx, cluster, metav1.UpdateOptions{})
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to update cluster taints: %w", err)
+	}
+
+	return nil
+}
+
+// getDesiredClusterTaints returns the taints of the cluster with the managed taints added or removed according to
+// its spec and conditions, and whether they differ from the current taints. Taints that are not managed are left
+// untouched. The unreachable taint is never added if unreachableTaintGracePeriod is not positive.
+func getDesiredClusterTaints(
+	cluster *fedcorev1a1.FederatedCluster,
+	unreachableTaintGracePeriod time.Duration,
+	now time.Time,
+) ([]corev1.Taint, bool) {
+	desired := map[string]bool{
+		fedcorev1a1.TaintClusterUnschedulable: cluster.Spec.Unschedulable || cluster.Spec.Drain != nil,
+	}
+
+	// The condition taints are left untouched until the status of the cluster has been collected.
+	if readyCondition := getClusterCondition(&cluster.Status, fedcorev1a1.ClusterReady); readyCondition != nil {
+		desired[fedcorev1a1.TaintClusterNotReady] = readyCondition.Status != corev1.ConditionTrue
+		offlineCondition := getClusterCondition(&cluster.Status, fedcorev1a1.ClusterOffline)
+		desired[fedcorev1a1.TaintClusterUnreachable] = unreachableTaintGracePeriod > 0 &&
+			offlineCondition != nil &&
+			offlineCondition.Status == corev1.ConditionTrue &&
+			now.Sub(offlineCondition.LastTransitionTime.Time) >= unreachableTaintGracePeriod
+	}
+
+	taints := make([]corev1.Taint, 0, len(cluster.Spec.Taints)+len(managedTaints))
+	changed := false
+	present := map[string]bool{}
+	for _, taint := range cluster.Spec.Taints {
+		shouldExist, managed := desired[taint.Key]
+		if managed && (!shouldExist || present[taint.Key] || !isManagedTaint(&taint)) {
+			changed = true
+			continue
+		}
+		present[taint.Key] = true
+		taints = append(taints, taint)
+	}
+
+	timeAdded := metav1.NewTime(now)
+	for _, taint := range managedTaints {
+		if desired[taint.Key] && !present[taint.Key] {
+			taint.TimeAdded = &timeAdded
+			taints = append(taints, taint)
+			changed = true
+		}
+	}
+
+	if len(taints) == 0 {
+		taints = nil
+	}
+	return taints, changed
+}
+
+// isManagedTaint returns whether the taint matches the managed taint with the same key, including its effect.
+func isManagedTaint(taint *corev1.Taint) bool {
+	for i := range managedTaints {
+		if managedTaints[i].MatchTaint(taint) && taint.Value == managedTaints[i].Value {
+			return true
+		}
+	}
+	return false
+}
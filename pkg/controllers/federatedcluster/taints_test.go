@@ -0,0 +1,174 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+func Test_getDesiredClusterTaints(t *testing.T) {
+	now := time.Now()
+	added := metav1.NewTime(now.Add(-time.Hour))
+	nowTime := metav1.NewTime(now)
+	gracePeriod := 5 * time.Minute
+
+	userTaint := corev1.Taint{Key: "example.io/dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+	notReadyTaint := corev1.Taint{
+		Key:       fedcorev1a1.TaintClusterNotReady,
+		Effect:    corev1.TaintEffectNoSchedule,
+		TimeAdded: &added,
+	}
+	unreachableTaint := corev1.Taint{
+		Key:       fedcorev1a1.TaintClusterUnreachable,
+		Effect:    corev1.TaintEffectNoExecute,
+		TimeAdded: &added,
+	}
+
+	makeConditions := func(ready, offline corev1.ConditionStatus, offlineFor time.Duration) []fedcorev1a1.ClusterCondition {
+		return []fedcorev1a1.ClusterCondition{
+			{Type: fedcorev1a1.ClusterReady, Status: ready},
+			{Type: fedcorev1a1.ClusterOffline, Status: offline, LastTransitionTime: metav1.NewTime(now.Add(-offlineFor))},
+		}
+	}
+
+	testCases := []struct {
+		name               string
+		taints             []corev1.Taint
+		conditions         []fedcorev1a1.ClusterCondition
+		unschedulable      bool
+		disableUnreachable bool
+		expectedTaints     []corev1.Taint
+		expectedChanged    bool
+	}{
+		{
+			name:            "status not collected",
+			taints:          []corev1.Taint{userTaint},
+			expectedTaints:  []corev1.Taint{userTaint},
+			expectedChanged: false,
+		},
+		{
+			name:            "ready cluster",
+			taints:          []corev1.Taint{userTaint},
+			conditions:      makeConditions(corev1.ConditionTrue, corev1.ConditionFalse, time.Hour),
+			expectedTaints:  []corev1.Taint{userTaint},
+			expectedChanged: false,
+		},
+		{
+			name:       "not ready cluster",
+			taints:     []corev1.Taint{userTaint},
+			conditions: makeConditions(corev1.ConditionFalse, corev1.ConditionFalse, time.Hour),
+			expectedTaints: []corev1.Taint{
+				userTaint,
+				{Key: fedcorev1a1.TaintClusterNotReady, Effect: corev1.TaintEffectNoSchedule, TimeAdded: &nowTime},
+			},
+			expectedChanged: true,
+		},
+		{
+			name:            "offline cluster within grace period",
+			taints:          []corev1.Taint{notReadyTaint},
+			conditions:      makeConditions(corev1.ConditionUnknown, corev1.ConditionTrue, time.Minute),
+			expectedTaints:  []corev1.Taint{notReadyTaint},
+			expectedChanged: false,
+		},
+		{
+			name:       "offline cluster after grace period",
+			taints:     []corev1.Taint{notReadyTaint},
+			conditions: makeConditions(corev1.ConditionUnknown, corev1.ConditionTrue, 10*time.Minute),
+			expectedTaints: []corev1.Taint{
+				notReadyTaint,
+				{Key: fedcorev1a1.TaintClusterUnreachable, Effect: corev1.TaintEffectNoExecute, TimeAdded: &nowTime},
+			},
+			expectedChanged: true,
+		},
+		{
+			name:               "offline cluster with unreachable taint disabled",
+			taints:             []corev1.Taint{notReadyTaint},
+			conditions:         makeConditions(corev1.ConditionUnknown, corev1.ConditionTrue, 10*time.Minute),
+			disableUnreachable: true,
+			expectedTaints:     []corev1.Taint{notReadyTaint},
+			expectedChanged:    false,
+		},
+		{
+			name:               "unreachable taint removed after being disabled",
+			taints:             []corev1.Taint{notReadyTaint, unreachableTaint},
+			conditions:         makeConditions(corev1.ConditionUnknown, corev1.ConditionTrue, 10*time.Minute),
+			disableUnreachable: true,
+			expectedTaints:     []corev1.Taint{notReadyTaint},
+			expectedChanged:    true,
+		},
+		{
+			name:            "recovered cluster",
+			taints:          []corev1.Taint{notReadyTaint, userTaint, unreachableTaint},
+			conditions:      makeConditions(corev1.ConditionTrue, corev1.ConditionFalse, time.Minute),
+			expectedTaints:  []corev1.Taint{userTaint},
+			expectedChanged: true,
+		},
+		{
+			name:          "cordoned cluster",
+			taints:        []corev1.Taint{userTaint},
+			unschedulable: true,
+			expectedTaints: []corev1.Taint{
+				userTaint,
+				{Key: fedcorev1a1.TaintClusterUnschedulable, Effect: corev1.TaintEffectNoSchedule, TimeAdded: &nowTime},
+			},
+			expectedChanged: true,
+		},
+		{
+			name: "uncordoned cluster",
+			taints: []corev1.Taint{
+				{Key: fedcorev1a1.TaintClusterUnschedulable, Effect: corev1.TaintEffectNoSchedule, TimeAdded: &added},
+			},
+			conditions:      makeConditions(corev1.ConditionTrue, corev1.ConditionFalse, time.Hour),
+			expectedTaints:  nil,
+			expectedChanged: true,
+		},
+		{
+			name: "managed taint with modified effect",
+			taints: []corev1.Taint{
+				{Key: fedcorev1a1.TaintClusterNotReady, Effect: corev1.TaintEffectNoExecute, TimeAdded: &added},
+			},
+			conditions: makeConditions(corev1.ConditionFalse, corev1.ConditionFalse, time.Hour),
+			expectedTaints: []corev1.Taint{
+				{Key: fedcorev1a1.TaintClusterNotReady, Effect: corev1.TaintEffectNoSchedule, TimeAdded: &nowTime},
+			},
+			expectedChanged: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &fedcorev1a1.FederatedCluster{
+				Spec:   fedcorev1a1.FederatedClusterSpec{Taints: tc.taints, Unschedulable: tc.unschedulable},
+				Status: fedcorev1a1.FederatedClusterStatus{Conditions: tc.conditions},
+			}
+			gracePeriod := gracePeriod
+			if tc.disableUnreachable {
+				gracePeriod = 0
+			}
+			taints, changed := getDesiredClusterTaints(cluster, gracePeriod, now)
+			assert.Equal(t, tc.expectedChanged, changed)
+			assert.Equal(t, tc.expectedTaints, taints)
+		})
+	}
+}
@@ -17,10 +17,14 @@ limitations under the License.
 package federatedcluster
 
 import (
+	"sort"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 )
 
 func getClusterCondition(
@@ -50,6 +54,16 @@ func setClusterCondition(
 	status.Conditions = append(status.Conditions, *newCondition)
 }
 
+// preserveLastTransitionTime keeps the last transition time of the existing condition if its status is unchanged.
+func preserveLastTransitionTime(
+	status *fedcorev1a1.FederatedClusterStatus,
+	newCondition *fedcorev1a1.ClusterCondition,
+) {
+	if existing := getClusterCondition(status, newCondition.Type); existing != nil && existing.Status == newCondition.Status {
+		newCondition.LastTransitionTime = existing.LastTransitionTime
+	}
+}
+
 func getNewClusterOfflineCondition(
 	status corev1.ConditionStatus,
 	conditionTime metav1.Time,
@@ -142,36 +156,6 @@ func addResources(src, dest corev1.ResourceList) {
 	}
 }
 
-// maxResources sets dst to the greater of dst/src for every resource in src
-func maxResources(src, dst corev1.ResourceList) {
-	for name, srcQuantity := range src {
-		if dstQuantity, ok := dst[name]; !ok || srcQuantity.Cmp(dstQuantity) > 0 {
-			dst[name] = srcQuantity.DeepCopy()
-		}
-	}
-}
-
-// podResourceRequest = max(sum(podSpec.Containers), podSpec.InitContainers...) + overHead
-func getPodResourceRequests(pod *corev1.Pod) corev1.ResourceList {
-	reqs := make(corev1.ResourceList)
-
-	for _, container := range pod.Spec.Containers {
-		addResources(container.Resources.Requests, reqs)
-	}
-
-	for _, container := range pod.Spec.InitContainers {
-		maxResources(container.Resources.Requests, reqs)
-	}
-
-	// if PodOverhead feature is supported, add overhead for running a pod
-	// to the sum of requests and to non-zero limits:
-	if pod.Spec.Overhead != nil {
-		addResources(pod.Spec.Overhead, reqs)
-	}
-
-	return reqs
-}
-
 // aggregateResources returns
 //   - allocatable resources from the nodes and,
 //   - available resources after considering allocations to the given pods.
@@ -201,7 +185,7 @@ func aggregateResources(
 			continue
 		}
 
-		podRequests := getPodResourceRequests(pod)
+		podRequests := util.GetPodResourceRequests(&pod.Spec)
 		for name, requestedQuantity := range podRequests {
 			if availableQuantity, ok := available[name]; ok {
 				availableQuantity.Sub(requestedQuantity)
@@ -212,3 +196,98 @@ func aggregateResources(
 
 	return allocatable, available
 }
+
+const (
+	// cpuGradeUnit and memoryGradeUnit are the smallest non-zero grade boundaries of the resource model.
+	cpuGradeUnit    int64 = 100               // 0.1 core
+	memoryGradeUnit int64 = 128 * 1024 * 1024 // 128 Mi
+)
+
+// getResourceModel returns the resource model of the given nodes, which groups the schedulable nodes into grades by
+// the cpu and memory available on them after considering allocations to the given pods.
+func getResourceModel(nodes []*corev1.Node, pods []*corev1.Pod) []fedcorev1a1.ResourceModelGrade {
+	nodeAvailable := make(map[string]corev1.ResourceList, len(nodes))
+	for _, node := range nodes {
+		if !isNodeSchedulable(node) {
+			continue
+		}
+
+		available := make(corev1.ResourceList, 2)
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if quantity, ok := node.Status.Allocatable[name]; ok {
+				available[name] = quantity.DeepCopy()
+			}
+		}
+		nodeAvailable[node.Name] = available
+	}
+
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		available, ok := nodeAvailable[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+		for name, requestedQuantity := range util.GetPodResourceRequests(&pod.Spec) {
+			if availableQuantity, ok := available[name]; ok {
+				availableQuantity.Sub(requestedQuantity)
+				available[name] = availableQuantity
+			}
+		}
+	}
+
+	type grade struct {
+		milliCPU int64
+		memory   int64
+	}
+	nodesPerGrade := make(map[grade]int64)
+	for _, available := range nodeAvailable {
+		nodesPerGrade[grade{
+			milliCPU: getGradeBoundary(available.Cpu().MilliValue(), cpuGradeUnit),
+			memory:   getGradeBoundary(available.Memory().Value(), memoryGradeUnit),
+		}]++
+	}
+
+	grades := make([]grade, 0, len(nodesPerGrade))
+	for g := range nodesPerGrade {
+		grades = append(grades, g)
+	}
+	sort.Slice(grades, func(i, j int) bool {
+		if grades[i].milliCPU != grades[j].milliCPU {
+			return grades[i].milliCPU < grades[j].milliCPU
+		}
+		return grades[i].memory < grades[j].memory
+	})
+
+	model := make([]fedcorev1a1.ResourceModelGrade, 0, len(grades))
+	for _, g := range grades {
+		model = append(model, fedcorev1a1.ResourceModelGrade{
+			Available: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewMilliQuantity(g.milliCPU, resource.DecimalSI),
+				corev1.ResourceMemory: *resource.NewQuantity(g.memory, resource.BinarySI),
+			},
+			Nodes: nodesPerGrade[g],
+		})
+	}
+	return model
+}
+
+// getGradeBoundary rounds the value down to the nearest grade boundary. The boundaries are 0 and unit multiplied by
+// powers of two and their 1.5 multiples (1, 1.5, 2, 3, 4, 6, 8, ...), so a value is underestimated by at most a
+// third.
+func getGradeBoundary(value, unit int64) int64 {
+	if value < unit {
+		return 0
+	}
+
+	boundary := unit
+	for boundary <= value/2 {
+		boundary *= 2
+	}
+	if value-boundary >= boundary/2 {
+		return boundary + boundary/2
+	}
+	return boundary
+}
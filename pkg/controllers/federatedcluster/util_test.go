@@ -22,6 +22,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func Test_aggregateResources(t *testing.T) {
@@ -281,3 +282,94 @@ func Test_aggregateResources(t *testing.T) {
 		})
 	}
 }
+
+func Test_getResourceModel(t *testing.T) {
+	makeNode := func(name, cpu, memory string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				},
+			},
+		}
+	}
+	makePod := func(nodeName, cpu, memory string) *corev1.Pod {
+		return &corev1.Pod{
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+				Containers: []corev1.Container{
+					{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse(cpu),
+								corev1.ResourceMemory: resource.MustParse(memory),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	unschedulableNode := makeNode("node4", "32", "64Gi")
+	unschedulableNode.Spec.Unschedulable = true
+	nodes := []*corev1.Node{
+		makeNode("node1", "8", "16Gi"),
+		makeNode("node2", "8", "16Gi"),
+		makeNode("node3", "4", "8Gi"),
+		unschedulableNode,
+	}
+	pods := []*corev1.Pod{
+		makePod("node1", "1", "1Gi"),
+		makePod("node2", "2500m", "10Gi"),
+		makePod("node3", "4", "8Gi"),
+		makePod("", "1", "1Gi"),
+	}
+
+	expectedGrades := []struct {
+		cpu    string
+		memory string
+		nodes  int64
+	}{
+		{cpu: "0", memory: "0", nodes: 1},
+		{cpu: "4.8", memory: "6Gi", nodes: 1},
+		{cpu: "6.4", memory: "12Gi", nodes: 1},
+	}
+
+	model := getResourceModel(nodes, pods)
+	if len(model) != len(expectedGrades) {
+		t.Fatalf("expected %d grades, got %s", len(expectedGrades), spew.Sdump(model))
+	}
+	for i, expected := range expectedGrades {
+		grade := model[i]
+		if !grade.Available.Cpu().Equal(resource.MustParse(expected.cpu)) ||
+			!grade.Available.Memory().Equal(resource.MustParse(expected.memory)) ||
+			grade.Nodes != expected.nodes {
+			t.Fatalf("grade %d differs from expected %v: %s", i, expected, spew.Sdump(grade))
+		}
+	}
+}
+
+func Test_getGradeBoundary(t *testing.T) {
+	testCases := []struct {
+		value    int64
+		expected int64
+	}{
+		{value: -1, expected: 0},
+		{value: 99, expected: 0},
+		{value: 100, expected: 100},
+		{value: 149, expected: 100},
+		{value: 150, expected: 150},
+		{value: 399, expected: 300},
+		{value: 400, expected: 400},
+		{value: 32000, expected: 25600},
+	}
+
+	for _, tc := range testCases {
+		if actual := getGradeBoundary(tc.value, 100); actual != tc.expected {
+			t.Errorf("expected grade boundary of %d to be %d, got %d", tc.value, tc.expected, actual)
+		}
+	}
+}
@@ -258,14 +258,14 @@ func (c *Controller) reconcileLeader(
 	handles *typeHandles,
 	qualifiedName common.QualifiedName,
 ) (status worker.Result) {
-	c.metrics.Rate(fmt.Sprintf("follower-controller-%s.throughput", handles.name), 1)
+	c.metrics.Rate("follower-controller.throughput", 1, stats.Tag{Name: "type", Value: handles.name})
 	key := qualifiedName.String()
 	logger := c.logger.WithValues("origin", "reconcileLeader", "type", handles.name, "key", key)
 	startTime := time.Now()
 
 	logger.V(3).Info("Starting reconcileLeader")
 	defer func() {
-		c.metrics.Duration(fmt.Sprintf("follower-controller-%s.latency", handles.name), startTime)
+		c.metrics.Duration("follower-controller.latency", startTime, stats.Tag{Name: "type", Value: handles.name})
 		logger.WithValues("duration", time.Since(startTime), "status", status.String()).V(3).Info("Finished reconcileLeader")
 	}()
 
@@ -427,7 +427,7 @@ func (c *Controller) reconcileFollower(
 	handles *typeHandles,
 	qualifiedName common.QualifiedName,
 ) (status worker.Result) {
-	c.metrics.Rate(fmt.Sprintf("follower-controller-%s.throughput", handles.name), 1)
+	c.metrics.Rate("follower-controller.throughput", 1, stats.Tag{Name: "type", Value: handles.name})
 	key := qualifiedName.String()
 	logger := c.logger.WithValues("origin", "reconcileFollower", "type", handles.name, "key", key)
 	ctx := klog.NewContext(context.TODO(), logger)
@@ -435,7 +435,7 @@ func (c *Controller) reconcileFollower(
 
 	logger.V(3).Info("Starting reconcileFollower")
 	defer func() {
-		c.metrics.Duration(fmt.Sprintf("follower-controller-%s.latency", handles.name), startTime)
+		c.metrics.Duration("follower-controller.latency", startTime, stats.Tag{Name: "type", Value: handles.name})
 		logger.WithValues("duration", time.Since(startTime), "status", status.String()).V(3).Info("Finished reconcileFollower")
 	}()
 
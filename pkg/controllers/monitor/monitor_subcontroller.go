@@ -122,7 +122,7 @@ func newMonitorSubController(
 	}
 
 	m.worker = worker.NewReconcileWorker(m.reconcile, worker.WorkerTiming{}, controllerConfig.WorkerCount,
-		controllerConfig.Metrics, delayingdeliver.NewMetricTags("monitor-subcontroller", m.kind))
+		controllerConfig.Metrics, delayingdeliver.NewFTCMetricTags("monitor-subcontroller", m.kind, typeConfig.Name))
 
 	m.federatedStore, m.federatedController = util.NewResourceInformer(m.federatedClient,
 		controllerConfig.TargetNamespace, m.worker.EnqueueObject, controllerConfig.Metrics)
@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package override
+
+import (
+	"fmt"
+	"strings"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+// image is a container image reference split into its components, e.g.
+// "docker.io/library/nginx:1.25@sha256:..." is split into the registry "docker.io", the repository "library/nginx",
+// the tag "1.25" and the digest "sha256:...".
+type image struct {
+	registry   string
+	repository string
+	tag        string
+	digest     string
+}
+
+func parseImage(s string) *image {
+	img := &image{}
+
+	if i := strings.Index(s, "@"); i >= 0 {
+		s, img.digest = s[:i], s[i+1:]
+	}
+
+	// A colon after the last slash separates the tag, otherwise it belongs to the registry's port.
+	if i := strings.LastIndex(s, ":"); i >= 0 && i > strings.LastIndex(s, "/") {
+		s, img.tag = s[:i], s[i+1:]
+	}
+
+	// Similar to docker, the first component is only treated as a registry if it looks like a hostname.
+	if i := strings.Index(s, "/"); i >= 0 {
+		if first := s[:i]; strings.ContainsAny(first, ".:") || first == "localhost" {
+			img.registry, s = first, s[i+1:]
+		}
+	}
+	img.repository = s
+
+	return img
+}
+
+func (img *image) String() string {
+	var sb strings.Builder
+	if len(img.registry) > 0 {
+		sb.WriteString(img.registry)
+		sb.WriteString("/")
+	}
+	sb.WriteString(img.repository)
+	if len(img.tag) > 0 {
+		sb.WriteString(":")
+		sb.WriteString(img.tag)
+	}
+	if len(img.digest) > 0 {
+		sb.WriteString("@")
+		sb.WriteString(img.digest)
+	}
+	return sb.String()
+}
+
+func (img *image) apply(operation *fedcorev1a1.ImageOperation) error {
+	var component *string
+	switch operation.ImageComponent {
+	case ImageComponentRegistry:
+		component = &img.registry
+	case ImageComponentRepository:
+		component = &img.repository
+	case ImageComponentTag:
+		component = &img.tag
+	case ImageComponentDigest:
+		component = &img.digest
+	default:
+		return fmt.Errorf("unsupported image component %q", operation.ImageComponent)
+	}
+
+	switch operation.Operator {
+	case OperatorAddIfAbsent:
+		if len(*component) == 0 {
+			*component = operation.Value
+		}
+	case OperatorOverwrite, "":
+		*component = operation.Value
+	case OperatorDelete:
+		*component = ""
+	default:
+		return fmt.Errorf("unsupported image operator %q", operation.Operator)
+	}
+
+	return nil
+}
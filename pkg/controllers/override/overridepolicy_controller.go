@@ -137,7 +137,7 @@ func newController(
 		worker.WorkerTiming{},
 		controllerConfig.WorkerCount,
 		controllerConfig.Metrics,
-		delayingdeliver.NewMetricTags(c.name, federatedApiResource.Kind),
+		delayingdeliver.NewFTCMetricTags(c.name, federatedApiResource.Kind, typeConfig.Name),
 	)
 	enqueueObj := c.worker.EnqueueObject
 	c.federatedStore, c.federatedController = util.NewResourceInformer(
@@ -201,15 +201,18 @@ func newController(
 			*/
 			AddFunc:    nil,
 			DeleteFunc: nil,
-			// We only care about label change, since that is the only cluster change
-			// that can affect overrider computation.
+			// We only care about changes to labels, annotations and taints, since those are the only
+			// cluster changes that can affect overrider computation, either through cluster selection
+			// or through templated override values.
 			// Currently MatchFields only matches /metadata/name.
 			// If we extend MatchFields to match new fields, we may need to revise UpdateFunc
 			// to expand the trigger conditions.
 			UpdateFunc: func(oldObj, newObj interface{}) {
 				oldCluster := oldObj.(*fedcorev1a1.FederatedCluster)
 				newCluster := newObj.(*fedcorev1a1.FederatedCluster)
-				if !equality.Semantic.DeepEqual(oldCluster.Labels, newCluster.Labels) {
+				if !equality.Semantic.DeepEqual(oldCluster.Labels, newCluster.Labels) ||
+					!equality.Semantic.DeepEqual(oldCluster.Annotations, newCluster.Annotations) ||
+					!equality.Semantic.DeepEqual(oldCluster.Spec.Taints, newCluster.Spec.Taints) {
 					c.reconcileOnClusterChange(newCluster)
 				}
 			},
@@ -255,11 +258,11 @@ func (c *Controller) reconcile(qualifiedName common.QualifiedName) worker.Result
 	kind := c.typeConfig.GetFederatedType().Kind
 	key := qualifiedName.String()
 
-	c.metrics.Rate(fmt.Sprintf("%v.throughput", c.name), 1)
+	c.metrics.Rate("overridepolicy-controller.throughput", 1, stats.Tag{Name: "ftc", Value: c.typeConfig.Name})
 	klog.V(4).Infof("%s starting to reconcile %s %v", c.name, kind, key)
 	startTime := time.Now()
 	defer func() {
-		c.metrics.Duration(fmt.Sprintf("%s.latency", c.name), startTime)
+		c.metrics.Duration("overridepolicy-controller.latency", startTime, stats.Tag{Name: "ftc", Value: c.typeConfig.Name})
 		klog.V(4).Infof("%s finished reconciling %s %v (duration: %v)", c.name, kind, key, time.Since(startTime))
 	}()
 
@@ -306,10 +309,16 @@ func (c *Controller) reconcile(qualifiedName common.QualifiedName) worker.Result
 		return worker.StatusError
 	}
 
+	target, err := newOverrideTarget(fedObject, c.typeConfig)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to get override target for %s %q: %w", kind, key, err))
+		return worker.StatusErrorNoRetry
+	}
+
 	var overrides util.OverridesMap
 	// Apply overrides from each policy in order
 	for _, policy := range policies {
-		newOverrides, err := parseOverrides(policy, placedClusters)
+		newOverrides, err := parseOverrides(policy, placedClusters, target, overrides)
 		if err != nil {
 			c.eventRecorder.Eventf(
 				fedObject,
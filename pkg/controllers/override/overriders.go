@@ -0,0 +1,455 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package override
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+const (
+	OperatorAddIfAbsent = "addIfAbsent"
+	OperatorOverwrite   = "overwrite"
+	OperatorDelete      = "delete"
+	OperatorAppend      = "append"
+
+	ImageComponentRegistry   = "Registry"
+	ImageComponentRepository = "Repository"
+	ImageComponentTag        = "Tag"
+	ImageComponentDigest     = "Digest"
+)
+
+// overrideTarget is the object that overriders are applied to. Unlike JSON patch overriders, which are passed on
+// verbatim, the other overriders are converted to JSON patches by comparing against the target.
+type overrideTarget struct {
+	// template is the template of the federated object.
+	template *unstructured.Unstructured
+	// podSpecPath is the path to the pod spec in the template, or nil if the type does not define one.
+	podSpecPath []string
+}
+
+func newOverrideTarget(
+	fedObject *unstructured.Unstructured,
+	typeConfig *fedcorev1a1.FederatedTypeConfig,
+) (*overrideTarget, error) {
+	templateContent, exists, err := unstructured.NestedMap(fedObject.Object, common.TemplatePath...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("template not found")
+	}
+
+	target := &overrideTarget{template: &unstructured.Unstructured{Object: templateContent}}
+	if podSpecPath := typeConfig.Spec.PathDefinition.PodSpec; len(podSpecPath) > 0 {
+		target.podSpecPath = strings.Split(podSpecPath, ".")
+	}
+	return target, nil
+}
+
+// render returns a copy of the template with the given overrides applied.
+func (t *overrideTarget) render(overrides fedtypesv1a1.OverridePatches) (*unstructured.Unstructured, error) {
+	obj := t.template.DeepCopy()
+	if len(overrides) == 0 {
+		return obj, nil
+	}
+
+	// ApplyJsonPatch defaults the op of the patches it is given, so we pass it a copy.
+	if err := util.ApplyJsonPatch(obj, append(fedtypesv1a1.OverridePatches{}, overrides...)); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func hasNonJsonPatchOverriders(overriders *fedcorev1a1.Overriders) bool {
+	return len(overriders.Image) > 0 ||
+		len(overriders.Command) > 0 ||
+		len(overriders.Args) > 0 ||
+		len(overriders.Env) > 0 ||
+		len(overriders.Labels) > 0 ||
+		len(overriders.Annotations) > 0
+}
+
+// nonJsonPatchOverridersToOverridePatches converts all overriders except JSON patch overriders to patches against obj.
+// obj is updated with the patches as they are generated, so that each overrider observes the effects of the
+// previous ones.
+func nonJsonPatchOverridersToOverridePatches(
+	overriders *fedcorev1a1.Overriders,
+	obj *unstructured.Unstructured,
+	podSpecPath []string,
+) (fedtypesv1a1.OverridePatches, error) {
+	patches := make(fedtypesv1a1.OverridePatches, 0)
+
+	if podSpecPath != nil {
+		containers, err := getContainers(obj, podSpecPath)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range overriders.Image {
+			newPatches, err := applyImageOverrider(&overriders.Image[i], containers)
+			if err != nil {
+				return nil, err
+			}
+			patches = append(patches, newPatches...)
+		}
+
+		for i := range overriders.Command {
+			newPatches, err := applyEntrypointOverrider(&overriders.Command[i], "command", containers)
+			if err != nil {
+				return nil, err
+			}
+			patches = append(patches, newPatches...)
+		}
+
+		for i := range overriders.Args {
+			newPatches, err := applyEntrypointOverrider(&overriders.Args[i], "args", containers)
+			if err != nil {
+				return nil, err
+			}
+			patches = append(patches, newPatches...)
+		}
+
+		for i := range overriders.Env {
+			newPatches, err := applyEnvOverrider(&overriders.Env[i], containers)
+			if err != nil {
+				return nil, err
+			}
+			patches = append(patches, newPatches...)
+		}
+	}
+
+	for i := range overriders.Labels {
+		newPatches, err := applyStringMapOverrider(&overriders.Labels[i], obj, "metadata", "labels")
+		if err != nil {
+			return nil, err
+		}
+		patches = append(patches, newPatches...)
+	}
+
+	for i := range overriders.Annotations {
+		newPatches, err := applyStringMapOverrider(&overriders.Annotations[i], obj, "metadata", "annotations")
+		if err != nil {
+			return nil, err
+		}
+		patches = append(patches, newPatches...)
+	}
+
+	return patches, nil
+}
+
+// containerRef references a container in the pod spec of an unstructured object.
+type containerRef struct {
+	name string
+	// path is the path to the container in the object.
+	path []string
+	// content is the container itself, modifications to it are reflected in the object.
+	content map[string]interface{}
+}
+
+func getContainers(obj *unstructured.Unstructured, podSpecPath []string) ([]containerRef, error) {
+	containers := []containerRef{}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		path := append(append([]string{}, podSpecPath...), field)
+		value, exists, err := unstructured.NestedFieldNoCopy(obj.Object, path...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", strings.Join(path, "."), err)
+		}
+		if !exists || value == nil {
+			continue
+		}
+
+		slice, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is of type %T, expected []interface{}", strings.Join(path, "."), value)
+		}
+
+		for i, item := range slice {
+			content, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s[%d] is of type %T, expected map[string]interface{}", strings.Join(path, "."), i, item)
+			}
+			name, _ := content["name"].(string)
+			containers = append(containers, containerRef{
+				name:    name,
+				path:    append(append([]string{}, path...), strconv.Itoa(i)),
+				content: content,
+			})
+		}
+	}
+
+	return containers, nil
+}
+
+func applyImageOverrider(
+	overrider *fedcorev1a1.ImageOverrider,
+	containers []containerRef,
+) (fedtypesv1a1.OverridePatches, error) {
+	patches := make(fedtypesv1a1.OverridePatches, 0)
+
+	for _, container := range containers {
+		if len(overrider.ContainerNames) > 0 && !containsString(overrider.ContainerNames, container.name) {
+			continue
+		}
+
+		oldImage, _ := container.content["image"].(string)
+		image := parseImage(oldImage)
+		for _, operation := range overrider.Operations {
+			if err := image.apply(&operation); err != nil {
+				return nil, fmt.Errorf("failed to override image of container %q: %w", container.name, err)
+			}
+		}
+		if len(image.repository) == 0 {
+			return nil, fmt.Errorf("failed to override image of container %q: repository must not be empty", container.name)
+		}
+
+		newImage := image.String()
+		if newImage == oldImage {
+			continue
+		}
+		container.content["image"] = newImage
+		patches = append(patches, fedtypesv1a1.OverridePatch{
+			Op:    "add",
+			Path:  toJsonPointer(append(container.path, "image")),
+			Value: newImage,
+		})
+	}
+
+	return patches, nil
+}
+
+func applyEntrypointOverrider(
+	overrider *fedcorev1a1.EntrypointOverrider,
+	field string,
+	containers []containerRef,
+) (fedtypesv1a1.OverridePatches, error) {
+	patches := make(fedtypesv1a1.OverridePatches, 0)
+
+	for _, container := range containers {
+		if container.name != overrider.ContainerName {
+			continue
+		}
+
+		oldValue, _ := container.content[field].([]interface{})
+		var newValue []interface{}
+		switch overrider.Operator {
+		case OperatorAppend:
+			newValue = append(newValue, oldValue...)
+		case OperatorOverwrite, "":
+		default:
+			return nil, fmt.Errorf("unsupported %s operator %q", field, overrider.Operator)
+		}
+		for _, value := range overrider.Value {
+			newValue = append(newValue, value)
+		}
+
+		newPatches, err := setContainerField(container, field, newValue)
+		if err != nil {
+			return nil, err
+		}
+		patches = append(patches, newPatches...)
+	}
+
+	return patches, nil
+}
+
+func applyEnvOverrider(
+	overrider *fedcorev1a1.EnvOverrider,
+	containers []containerRef,
+) (fedtypesv1a1.OverridePatches, error) {
+	patches := make(fedtypesv1a1.OverridePatches, 0)
+
+	for _, container := range containers {
+		if container.name != overrider.ContainerName {
+			continue
+		}
+
+		oldEnv, _ := container.content["env"].([]interface{})
+		newEnv := make([]interface{}, 0, len(oldEnv)+len(overrider.Value))
+		newEnv = append(newEnv, oldEnv...)
+
+		for i := range overrider.Value {
+			envVar := &overrider.Value[i]
+			index := indexOfEnvVar(newEnv, envVar.Name)
+
+			switch overrider.Operator {
+			case OperatorAddIfAbsent:
+				if index < 0 {
+					value, err := envVarToUnstructured(envVar)
+					if err != nil {
+						return nil, err
+					}
+					newEnv = append(newEnv, value)
+				}
+			case OperatorOverwrite, "":
+				value, err := envVarToUnstructured(envVar)
+				if err != nil {
+					return nil, err
+				}
+				if index < 0 {
+					newEnv = append(newEnv, value)
+				} else {
+					newEnv[index] = value
+				}
+			case OperatorDelete:
+				if index >= 0 {
+					newEnv = append(newEnv[:index], newEnv[index+1:]...)
+				}
+			default:
+				return nil, fmt.Errorf("unsupported env operator %q", overrider.Operator)
+			}
+		}
+
+		newPatches, err := setContainerField(container, "env", newEnv)
+		if err != nil {
+			return nil, err
+		}
+		patches = append(patches, newPatches...)
+	}
+
+	return patches, nil
+}
+
+func applyStringMapOverrider(
+	overrider *fedcorev1a1.StringMapOverrider,
+	obj *unstructured.Unstructured,
+	path ...string,
+) (fedtypesv1a1.OverridePatches, error) {
+	oldMap, _, err := unstructured.NestedStringMap(obj.Object, path...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", strings.Join(path, "."), err)
+	}
+
+	newMap := make(map[string]interface{}, len(oldMap)+len(overrider.Value))
+	for key, value := range oldMap {
+		newMap[key] = value
+	}
+
+	for key, value := range overrider.Value {
+		switch overrider.Operator {
+		case OperatorAddIfAbsent:
+			if _, exists := newMap[key]; !exists {
+				newMap[key] = value
+			}
+		case OperatorOverwrite, "":
+			newMap[key] = value
+		case OperatorDelete:
+			delete(newMap, key)
+		default:
+			return nil, fmt.Errorf("unsupported %s operator %q", path[len(path)-1], overrider.Operator)
+		}
+	}
+
+	if len(newMap) == len(oldMap) {
+		unchanged := true
+		for key, value := range newMap {
+			if oldValue, exists := oldMap[key]; !exists || oldValue != value {
+				unchanged = false
+				break
+			}
+		}
+		if unchanged {
+			return nil, nil
+		}
+	}
+
+	if err := unstructured.SetNestedMap(obj.Object, newMap, path...); err != nil {
+		return nil, err
+	}
+	return fedtypesv1a1.OverridePatches{{
+		Op:    "add",
+		Path:  toJsonPointer(path),
+		Value: newMap,
+	}}, nil
+}
+
+// setContainerField sets the field of the container to value and returns the patch that does the same, or no patch
+// if the value is unchanged.
+func setContainerField(
+	container containerRef,
+	field string,
+	value []interface{},
+) (fedtypesv1a1.OverridePatches, error) {
+	// Round-trip through JSON so that the value is comparable to the ones read back from the federated object.
+	normalizedValue, err := util.InterfaceToUnstructured(value)
+	if err != nil {
+		return nil, err
+	}
+	if oldValue, exists := container.content[field]; exists {
+		normalizedOldValue, err := util.InterfaceToUnstructured(oldValue)
+		if err != nil {
+			return nil, err
+		}
+		if reflect.DeepEqual(normalizedOldValue, normalizedValue) {
+			return nil, nil
+		}
+	}
+
+	container.content[field] = normalizedValue
+	return fedtypesv1a1.OverridePatches{{
+		Op:    "add",
+		Path:  toJsonPointer(append(container.path, field)),
+		Value: normalizedValue,
+	}}, nil
+}
+
+func envVarToUnstructured(envVar *corev1.EnvVar) (map[string]interface{}, error) {
+	value, err := util.InterfaceToUnstructured(envVar)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert env var %q: %w", envVar.Name, err)
+	}
+	return value.(map[string]interface{}), nil
+}
+
+func indexOfEnvVar(env []interface{}, name string) int {
+	for i, envVar := range env {
+		if envVarMap, ok := envVar.(map[string]interface{}); ok && envVarMap["name"] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// toJsonPointer converts the path to a JSON pointer (RFC 6901).
+func toJsonPointer(path []string) string {
+	var sb strings.Builder
+	for _, segment := range path {
+		sb.WriteString("/")
+		sb.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(segment))
+	}
+	return sb.String()
+}
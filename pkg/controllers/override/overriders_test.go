@@ -0,0 +1,354 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package override
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+func TestParseImage(t *testing.T) {
+	testCases := map[string]image{
+		"nginx":                         {repository: "nginx"},
+		"nginx:1.25":                    {repository: "nginx", tag: "1.25"},
+		"library/nginx:1.25":            {repository: "library/nginx", tag: "1.25"},
+		"docker.io/library/nginx":       {registry: "docker.io", repository: "library/nginx"},
+		"localhost:5000/nginx:1.25":     {registry: "localhost:5000", repository: "nginx", tag: "1.25"},
+		"localhost/nginx@sha256:abc":    {registry: "localhost", repository: "nginx", digest: "sha256:abc"},
+		"gcr.io/a/b:v1@sha256:abc":      {registry: "gcr.io", repository: "a/b", tag: "v1", digest: "sha256:abc"},
+		"registry:5000/nginx@sha256:ab": {registry: "registry:5000", repository: "nginx", digest: "sha256:ab"},
+	}
+
+	for s, expected := range testCases {
+		t.Run(s, func(t *testing.T) {
+			actual := parseImage(s)
+			assert.Equal(t, expected, *actual)
+			assert.Equal(t, s, actual.String())
+		})
+	}
+}
+
+func newOverridersTestTarget() *overrideTarget {
+	template := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name": "test",
+			"labels": map[string]interface{}{
+				"app": "test",
+			},
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"initContainers": []interface{}{
+						map[string]interface{}{
+							"name":  "init",
+							"image": "busybox",
+						},
+					},
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":    "server",
+							"image":   "docker.io/library/nginx:1.25",
+							"command": []interface{}{"nginx"},
+							"env": []interface{}{
+								map[string]interface{}{"name": "LOG_LEVEL", "value": "info"},
+								map[string]interface{}{"name": "REGION", "value": "default"},
+							},
+						},
+						map[string]interface{}{
+							"name":  "sidecar",
+							"image": "envoy:v1",
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	return &overrideTarget{
+		template:    template,
+		podSpecPath: []string{"spec", "template", "spec"},
+	}
+}
+
+func TestParseNonJsonPatchOverriders(t *testing.T) {
+	clusters := []*fedcorev1a1.FederatedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+	}
+
+	testCases := map[string]struct {
+		overriders           fedcorev1a1.Overriders
+		noPodSpecPath        bool
+		base                 util.OverridesMap
+		expectedOverridesMap util.OverridesMap
+		isErrorExpected      bool
+	}{
+		"image overriders applied to all containers": {
+			overriders: fedcorev1a1.Overriders{
+				Image: []fedcorev1a1.ImageOverrider{
+					{
+						Operations: []fedcorev1a1.ImageOperation{
+							{ImageComponent: ImageComponentRegistry, Value: "mirror.example.com"},
+						},
+					},
+				},
+			},
+			expectedOverridesMap: util.OverridesMap{
+				"cluster1": fedtypesv1a1.OverridePatches{
+					{Op: "add", Path: "/spec/template/spec/initContainers/0/image", Value: "mirror.example.com/busybox"},
+					{
+						Op:    "add",
+						Path:  "/spec/template/spec/containers/0/image",
+						Value: "mirror.example.com/library/nginx:1.25",
+					},
+					{Op: "add", Path: "/spec/template/spec/containers/1/image", Value: "mirror.example.com/envoy:v1"},
+				},
+			},
+		},
+		"image overriders applied in order to selected containers": {
+			overriders: fedcorev1a1.Overriders{
+				Image: []fedcorev1a1.ImageOverrider{
+					{
+						ContainerNames: []string{"server"},
+						Operations: []fedcorev1a1.ImageOperation{
+							{ImageComponent: ImageComponentTag, Value: "1.26"},
+							{ImageComponent: ImageComponentDigest, Operator: OperatorAddIfAbsent, Value: "sha256:abc"},
+						},
+					},
+					{
+						ContainerNames: []string{"server"},
+						Operations: []fedcorev1a1.ImageOperation{
+							{ImageComponent: ImageComponentRegistry, Operator: OperatorDelete},
+						},
+					},
+				},
+			},
+			expectedOverridesMap: util.OverridesMap{
+				"cluster1": fedtypesv1a1.OverridePatches{
+					{
+						Op:    "add",
+						Path:  "/spec/template/spec/containers/0/image",
+						Value: "docker.io/library/nginx:1.26@sha256:abc",
+					},
+					{Op: "add", Path: "/spec/template/spec/containers/0/image", Value: "library/nginx:1.26@sha256:abc"},
+				},
+			},
+		},
+		"deleting image repository should return error": {
+			overriders: fedcorev1a1.Overriders{
+				Image: []fedcorev1a1.ImageOverrider{
+					{
+						Operations: []fedcorev1a1.ImageOperation{
+							{ImageComponent: ImageComponentRepository, Operator: OperatorDelete},
+						},
+					},
+				},
+			},
+			isErrorExpected: true,
+		},
+		"command and args overriders": {
+			overriders: fedcorev1a1.Overriders{
+				Command: []fedcorev1a1.EntrypointOverrider{
+					{ContainerName: "server", Operator: OperatorAppend, Value: []string{"-g", "daemon off;"}},
+					{ContainerName: "non-existent", Value: []string{"sleep"}},
+				},
+				Args: []fedcorev1a1.EntrypointOverrider{
+					{ContainerName: "sidecar", Value: []string{"--log-level", "debug"}},
+				},
+			},
+			expectedOverridesMap: util.OverridesMap{
+				"cluster1": fedtypesv1a1.OverridePatches{
+					{
+						Op:    "add",
+						Path:  "/spec/template/spec/containers/0/command",
+						Value: []interface{}{"nginx", "-g", "daemon off;"},
+					},
+					{
+						Op:    "add",
+						Path:  "/spec/template/spec/containers/1/args",
+						Value: []interface{}{"--log-level", "debug"},
+					},
+				},
+			},
+		},
+		"env overriders": {
+			overriders: fedcorev1a1.Overriders{
+				Env: []fedcorev1a1.EnvOverrider{
+					{
+						ContainerName: "server",
+						Value: []corev1.EnvVar{
+							{Name: "REGION", Value: "us-east-1"},
+							{Name: "ZONE", Value: "us-east-1a"},
+						},
+					},
+					{
+						ContainerName: "server",
+						Operator:      OperatorAddIfAbsent,
+						Value:         []corev1.EnvVar{{Name: "ZONE", Value: "ignored"}},
+					},
+					{
+						ContainerName: "server",
+						Operator:      OperatorDelete,
+						Value:         []corev1.EnvVar{{Name: "LOG_LEVEL"}},
+					},
+				},
+			},
+			expectedOverridesMap: util.OverridesMap{
+				"cluster1": fedtypesv1a1.OverridePatches{
+					{
+						Op:   "add",
+						Path: "/spec/template/spec/containers/0/env",
+						Value: []interface{}{
+							map[string]interface{}{"name": "LOG_LEVEL", "value": "info"},
+							map[string]interface{}{"name": "REGION", "value": "us-east-1"},
+							map[string]interface{}{"name": "ZONE", "value": "us-east-1a"},
+						},
+					},
+					{
+						Op:   "add",
+						Path: "/spec/template/spec/containers/0/env",
+						Value: []interface{}{
+							map[string]interface{}{"name": "REGION", "value": "us-east-1"},
+							map[string]interface{}{"name": "ZONE", "value": "us-east-1a"},
+						},
+					},
+				},
+			},
+		},
+		"label and annotation overriders": {
+			overriders: fedcorev1a1.Overriders{
+				Labels: []fedcorev1a1.StringMapOverrider{
+					{Operator: OperatorAddIfAbsent, Value: map[string]string{"app": "ignored", "tier": "frontend"}},
+					{Operator: OperatorDelete, Value: map[string]string{"non-existent": ""}},
+				},
+				Annotations: []fedcorev1a1.StringMapOverrider{
+					{Value: map[string]string{"kubeadmiral.io/region": "us-east-1"}},
+				},
+			},
+			expectedOverridesMap: util.OverridesMap{
+				"cluster1": fedtypesv1a1.OverridePatches{
+					{
+						Op:    "add",
+						Path:  "/metadata/labels",
+						Value: map[string]interface{}{"app": "test", "tier": "frontend"},
+					},
+					{
+						Op:    "add",
+						Path:  "/metadata/annotations",
+						Value: map[string]interface{}{"kubeadmiral.io/region": "us-east-1"},
+					},
+				},
+			},
+		},
+		"pod spec overriders ignored without pod spec path": {
+			overriders: fedcorev1a1.Overriders{
+				Image: []fedcorev1a1.ImageOverrider{
+					{
+						Operations: []fedcorev1a1.ImageOperation{
+							{ImageComponent: ImageComponentTag, Value: "latest"},
+						},
+					},
+				},
+				Labels: []fedcorev1a1.StringMapOverrider{
+					{Value: map[string]string{"app": "override"}},
+				},
+			},
+			noPodSpecPath: true,
+			expectedOverridesMap: util.OverridesMap{
+				"cluster1": fedtypesv1a1.OverridePatches{
+					{Op: "add", Path: "/metadata/labels", Value: map[string]interface{}{"app": "override"}},
+				},
+			},
+		},
+		"overriders computed on top of base overrides and json patches": {
+			overriders: fedcorev1a1.Overriders{
+				Image: []fedcorev1a1.ImageOverrider{
+					{
+						ContainerNames: []string{"sidecar"},
+						Operations: []fedcorev1a1.ImageOperation{
+							{ImageComponent: ImageComponentTag, Operator: OperatorAddIfAbsent, Value: "latest"},
+						},
+					},
+				},
+			},
+			base: util.OverridesMap{
+				"cluster1": fedtypesv1a1.OverridePatches{
+					{Op: "replace", Path: "/spec/template/spec/containers/1/image", Value: "envoy"},
+				},
+			},
+			expectedOverridesMap: util.OverridesMap{
+				"cluster1": fedtypesv1a1.OverridePatches{
+					{Op: "add", Path: "/spec/template/spec/containers/1/image", Value: "envoy:latest"},
+				},
+			},
+		},
+		"unchanged values produce no patches": {
+			overriders: fedcorev1a1.Overriders{
+				Command: []fedcorev1a1.EntrypointOverrider{
+					{ContainerName: "server", Value: []string{"nginx"}},
+				},
+				Labels: []fedcorev1a1.StringMapOverrider{
+					{Value: map[string]string{"app": "test"}},
+				},
+			},
+			expectedOverridesMap: util.OverridesMap{},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			target := newOverridersTestTarget()
+			if testCase.noPodSpecPath {
+				target.podSpecPath = nil
+			}
+			templateBefore := target.template.DeepCopy()
+
+			policy := &fedcorev1a1.OverridePolicy{
+				Spec: fedcorev1a1.GenericOverridePolicySpec{
+					OverrideRules: []fedcorev1a1.OverrideRule{
+						{Overriders: &testCase.overriders},
+					},
+				},
+			}
+
+			overrides, err := parseOverrides(policy, clusters, target, testCase.base)
+			if (err != nil) != testCase.isErrorExpected {
+				t.Fatalf("err = %v, but testCase.isErrorExpected = %v", err, testCase.isErrorExpected)
+			}
+
+			assert.Equal(t, testCase.expectedOverridesMap, overrides)
+			assert.Equal(t, templateBefore, target.template, "the template should not be modified")
+
+			if err == nil {
+				// the patches should be applicable to the template
+				obj := target.template.DeepCopy()
+				patches := append(testCase.base["cluster1"], overrides["cluster1"]...)
+				assert.NoError(t, util.ApplyJsonPatch(obj, patches))
+			}
+		})
+	}
+}
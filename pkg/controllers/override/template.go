@@ -0,0 +1,186 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package override
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+// templateFuncs are the functions available to override value templates. Only pure string functions are provided so
+// that templates cannot reach anything except the data they are given.
+var templateFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"replace":    strings.ReplaceAll,
+	"trimPrefix": strings.TrimPrefix,
+	"trimSuffix": strings.TrimSuffix,
+	"default": func(defaultValue, value string) string {
+		if len(value) == 0 {
+			return defaultValue
+		}
+		return value
+	},
+}
+
+// templateData is the data that override value templates are rendered with.
+type templateData struct {
+	Cluster templateCluster
+}
+
+type templateCluster struct {
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+	// Taints maps the keys of the cluster's taints to their values.
+	Taints map[string]string
+}
+
+func newTemplateData(cluster *fedcorev1a1.FederatedCluster) *templateData {
+	data := &templateData{
+		Cluster: templateCluster{
+			Name:        cluster.Name,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+			Taints:      map[string]string{},
+		},
+	}
+	for key, value := range cluster.Labels {
+		data.Cluster.Labels[key] = value
+	}
+	for key, value := range cluster.Annotations {
+		data.Cluster.Annotations[key] = value
+	}
+	for _, taint := range cluster.Spec.Taints {
+		data.Cluster.Taints[taint.Key] = taint.Value
+	}
+	return data
+}
+
+func renderTemplate(text string, data *templateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New("value").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %q: %w", text, err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render template %q: %w", text, err)
+	}
+	return sb.String(), nil
+}
+
+// renderOverriders returns a copy of the overriders with their string values rendered as templates for the cluster.
+func renderOverriders(
+	overriders *fedcorev1a1.Overriders,
+	cluster *fedcorev1a1.FederatedCluster,
+) (*fedcorev1a1.Overriders, error) {
+	data := newTemplateData(cluster)
+	rendered := overriders.DeepCopy()
+
+	var err error
+	render := func(s *string) {
+		if err == nil {
+			*s, err = renderTemplate(*s, data)
+		}
+	}
+
+	for i := range rendered.Image {
+		for j := range rendered.Image[i].Operations {
+			render(&rendered.Image[i].Operations[j].Value)
+		}
+	}
+	for _, entrypointOverriders := range [][]fedcorev1a1.EntrypointOverrider{rendered.Command, rendered.Args} {
+		for i := range entrypointOverriders {
+			for j := range entrypointOverriders[i].Value {
+				render(&entrypointOverriders[i].Value[j])
+			}
+		}
+	}
+	for i := range rendered.Env {
+		for j := range rendered.Env[i].Value {
+			render(&rendered.Env[i].Value[j].Value)
+		}
+	}
+	for _, stringMapOverriders := range [][]fedcorev1a1.StringMapOverrider{rendered.Labels, rendered.Annotations} {
+		for i := range stringMapOverriders {
+			for key, value := range stringMapOverriders[i].Value {
+				render(&value)
+				stringMapOverriders[i].Value[key] = value
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rendered.JsonPatch {
+		raw := rendered.JsonPatch[i].Value.Raw
+		if len(raw) == 0 {
+			continue
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal jsonpatch overrider value %q: %w", raw, err)
+		}
+		if value, err = renderJSONValue(value, data); err != nil {
+			return nil, err
+		}
+		if rendered.JsonPatch[i].Value.Raw, err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return rendered, nil
+}
+
+// renderJSONValue renders the strings in a value unmarshalled from JSON as templates. Map keys are left untouched.
+func renderJSONValue(value interface{}, data *templateData) (interface{}, error) {
+	switch value := value.(type) {
+	case string:
+		return renderTemplate(value, data)
+	case []interface{}:
+		for i := range value {
+			rendered, err := renderJSONValue(value[i], data)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = rendered
+		}
+		return value, nil
+	case map[string]interface{}:
+		for key := range value {
+			rendered, err := renderJSONValue(value[key], data)
+			if err != nil {
+				return nil, err
+			}
+			value[key] = rendered
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package override
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+func TestParseTemplatedOverrides(t *testing.T) {
+	clusters := []*fedcorev1a1.FederatedCluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "cluster1",
+				Labels: map[string]string{"example.io/region": "US-East"},
+			},
+			Spec: fedcorev1a1.FederatedClusterSpec{
+				Taints: []corev1.Taint{{Key: "example.io/zone", Value: "a", Effect: corev1.TaintEffectNoSchedule}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster2",
+				Annotations: map[string]string{"example.io/domain": "example.com"},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		rule                 fedcorev1a1.OverrideRule
+		expectedOverridesMap util.OverridesMap
+		isErrorExpected      bool
+	}{
+		"templated values are rendered per cluster": {
+			rule: fedcorev1a1.OverrideRule{
+				TemplateValues: true,
+				Overriders: &fedcorev1a1.Overriders{
+					Env: []fedcorev1a1.EnvOverrider{
+						{
+							ContainerName: "server",
+							Value: []corev1.EnvVar{
+								{
+									Name:  "CLUSTER_REGION",
+									Value: `{{ index .Cluster.Labels "example.io/region" | lower | default "global" }}`,
+								},
+							},
+						},
+					},
+					JsonPatch: []fedcorev1a1.JsonPatchOverrider{
+						{
+							Path: "/spec/rules/0/host",
+							Value: apiextensionsv1.JSON{
+								Raw: []byte(
+									`"{{ .Cluster.Name }}{{ index .Cluster.Taints \"example.io/zone\" }}.` +
+										`{{ index .Cluster.Annotations \"example.io/domain\" | default \"local\" }}"`,
+								),
+							},
+						},
+					},
+				},
+			},
+			expectedOverridesMap: util.OverridesMap{
+				"cluster1": fedtypesv1a1.OverridePatches{
+					{
+						Op:   "add",
+						Path: "/spec/template/spec/containers/0/env",
+						Value: []interface{}{
+							map[string]interface{}{"name": "LOG_LEVEL", "value": "info"},
+							map[string]interface{}{"name": "REGION", "value": "default"},
+							map[string]interface{}{"name": "CLUSTER_REGION", "value": "us-east"},
+						},
+					},
+					{Path: "/spec/rules/0/host", Value: "cluster1a.local"},
+				},
+				"cluster2": fedtypesv1a1.OverridePatches{
+					{
+						Op:   "add",
+						Path: "/spec/template/spec/containers/0/env",
+						Value: []interface{}{
+							map[string]interface{}{"name": "LOG_LEVEL", "value": "info"},
+							map[string]interface{}{"name": "REGION", "value": "default"},
+							map[string]interface{}{"name": "CLUSTER_REGION", "value": "global"},
+						},
+					},
+					{Path: "/spec/rules/0/host", Value: "cluster2.example.com"},
+				},
+			},
+		},
+		"values are not rendered unless enabled": {
+			rule: fedcorev1a1.OverrideRule{
+				Overriders: &fedcorev1a1.Overriders{
+					Labels: []fedcorev1a1.StringMapOverrider{
+						{Value: map[string]string{"cluster": "{{ .Cluster.Name }}"}},
+					},
+				},
+			},
+			expectedOverridesMap: util.OverridesMap{
+				"cluster1": fedtypesv1a1.OverridePatches{
+					{
+						Op:    "add",
+						Path:  "/metadata/labels",
+						Value: map[string]interface{}{"app": "test", "cluster": "{{ .Cluster.Name }}"},
+					},
+				},
+				"cluster2": fedtypesv1a1.OverridePatches{
+					{
+						Op:    "add",
+						Path:  "/metadata/labels",
+						Value: map[string]interface{}{"app": "test", "cluster": "{{ .Cluster.Name }}"},
+					},
+				},
+			},
+		},
+		"unknown fields should return error": {
+			rule: fedcorev1a1.OverrideRule{
+				TemplateValues: true,
+				Overriders: &fedcorev1a1.Overriders{
+					Labels: []fedcorev1a1.StringMapOverrider{
+						{Value: map[string]string{"cluster": "{{ .Cluster.Secret }}"}},
+					},
+				},
+			},
+			isErrorExpected: true,
+		},
+		"invalid template should return error": {
+			rule: fedcorev1a1.OverrideRule{
+				TemplateValues: true,
+				Overriders: &fedcorev1a1.Overriders{
+					Image: []fedcorev1a1.ImageOverrider{
+						{
+							Operations: []fedcorev1a1.ImageOperation{
+								{ImageComponent: ImageComponentRegistry, Value: "{{ .Cluster.Name "},
+							},
+						},
+					},
+				},
+			},
+			isErrorExpected: true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			policy := &fedcorev1a1.ClusterOverridePolicy{
+				Spec: fedcorev1a1.GenericOverridePolicySpec{
+					OverrideRules: []fedcorev1a1.OverrideRule{testCase.rule},
+				},
+			}
+			policyBefore := policy.DeepCopy()
+
+			overrides, err := parseOverrides(policy, clusters, newOverridersTestTarget(), nil)
+			if (err != nil) != testCase.isErrorExpected {
+				t.Fatalf("err = %v, but testCase.isErrorExpected = %v", err, testCase.isErrorExpected)
+			}
+
+			assert.Equal(t, testCase.expectedOverridesMap, overrides)
+			assert.Equal(t, policyBefore, policy, "the policy should not be modified")
+		})
+	}
+}
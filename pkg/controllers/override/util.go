@@ -96,9 +96,18 @@ func lookForMatchedPolicies(
 	return policies, false, nil
 }
 
+/*
+parseOverrides converts the override rules of the policy to patches for each of the clusters.
+
+JSON patch overriders are passed on verbatim, while the other overriders are converted to patches against the
+target's template, with the overrides in base (from previously applied policies) and the preceding overriders
+of the policy applied.
+*/
 func parseOverrides(
 	policy fedcorev1a1.GenericOverridePolicy,
 	clusters []*fedcorev1a1.FederatedCluster,
+	target *overrideTarget,
+	base util.OverridesMap,
 ) (util.OverridesMap, error) {
 	overridesMap := make(util.OverridesMap)
 
@@ -118,11 +127,49 @@ func parseOverrides(
 				)
 			}
 
-			if !matched {
+			if !matched || rule.Overriders == nil {
 				continue
 			}
 
-			for _, overrider := range rule.Overriders.JsonPatch {
+			overriders := rule.Overriders
+			if rule.TemplateValues {
+				if overriders, err = renderOverriders(overriders, cluster); err != nil {
+					return nil, fmt.Errorf(
+						"failed to render policy %q's overrideRules[%v] for cluster %q: %w",
+						policy.GetName(),
+						i,
+						cluster.Name,
+						err,
+					)
+				}
+			}
+
+			if hasNonJsonPatchOverriders(overriders) {
+				obj, err := target.render(append(append(fedtypesv1a1.OverridePatches{}, base[cluster.Name]...), patches...))
+				if err != nil {
+					return nil, fmt.Errorf(
+						"failed to apply overrides preceding policy %q's overrideRules[%v] for cluster %q: %w",
+						policy.GetName(),
+						i,
+						cluster.Name,
+						err,
+					)
+				}
+
+				newPatches, err := nonJsonPatchOverridersToOverridePatches(overriders, obj, target.podSpecPath)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"failed to parse policy %q's overrideRules[%v] for cluster %q: %w",
+						policy.GetName(),
+						i,
+						cluster.Name,
+						err,
+					)
+				}
+				patches = append(patches, newPatches...)
+			}
+
+			for _, overrider := range overriders.JsonPatch {
 				patch, err := policyJsonPatchOverriderToOverridePatch(&overrider)
 				if err != nil {
 					return nil, err
@@ -631,7 +631,8 @@ func TestParseOverrides(t *testing.T) {
 
 	for testName, testCase := range testCases {
 		t.Run(testName, func(t *testing.T) {
-			overrides, err := parseOverrides(testCase.policy, testCase.clusters)
+			target := &overrideTarget{template: &unstructured.Unstructured{Object: map[string]interface{}{}}}
+			overrides, err := parseOverrides(testCase.policy, testCase.clusters, target, nil)
 			if (err != nil) != testCase.isErrorExpected {
 				t.Fatalf("err = %v, but testCase.isErrorExpected = %v", err, testCase.isErrorExpected)
 			}
@@ -107,7 +107,7 @@ func newController(controllerConfig *util.ControllerConfig,
 		worker.WorkerTiming{},
 		1, // currently only one worker is meaningful due to the global mutex
 		controllerConfig.Metrics,
-		delayingdeliver.NewMetricTags("policyrc-controller-count-worker", c.typeConfig.GetFederatedType().Kind),
+		delayingdeliver.NewFTCMetricTags("policyrc-controller-count-worker", c.typeConfig.GetFederatedType().Kind, c.typeConfig.Name),
 	)
 
 	c.persistPpWorker = worker.NewReconcileWorker(
@@ -117,7 +117,7 @@ func newController(controllerConfig *util.ControllerConfig,
 		worker.WorkerTiming{},
 		controllerConfig.WorkerCount,
 		controllerConfig.Metrics,
-		delayingdeliver.NewMetricTags("policyrc-controller-persist-worker", c.typeConfig.GetFederatedType().Kind),
+		delayingdeliver.NewFTCMetricTags("policyrc-controller-persist-worker", c.typeConfig.GetFederatedType().Kind, c.typeConfig.Name),
 	)
 	c.persistOpWorker = worker.NewReconcileWorker(
 		func(qualifiedName common.QualifiedName) worker.Result {
@@ -126,7 +126,7 @@ func newController(controllerConfig *util.ControllerConfig,
 		worker.WorkerTiming{},
 		controllerConfig.WorkerCount,
 		controllerConfig.Metrics,
-		delayingdeliver.NewMetricTags("policyrc-controller-persist-worker", c.typeConfig.GetFederatedType().Kind),
+		delayingdeliver.NewFTCMetricTags("policyrc-controller-persist-worker", c.typeConfig.GetFederatedType().Kind, c.typeConfig.Name),
 	)
 
 	targetNamespace := controllerConfig.TargetNamespace
@@ -231,11 +231,11 @@ func (c *Controller) HasSynced() bool {
 func (c *Controller) reconcileCount(qualifiedName common.QualifiedName) (status worker.Result) {
 	logger := c.logger.WithValues("object", qualifiedName.String())
 
-	c.metrics.Rate("policyrc-count-controller.throughput", 1)
+	c.metrics.Rate("policyrc-count-controller.throughput", 1, stats.Tag{Name: "ftc", Value: c.typeConfig.Name})
 	logger.V(3).Info("Policyrc count controller starting to reconcile")
 	startTime := time.Now()
 	defer func() {
-		c.metrics.Duration("policyrc-count-controller.latency", startTime)
+		c.metrics.Duration("policyrc-count-controller.latency", startTime, stats.Tag{Name: "ftc", Value: c.typeConfig.Name})
 		logger.V(3).WithValues("duration", time.Since(startTime), "status", status.String()).
 			Info("Policyrc count controller finished reconciling")
 	}()
@@ -284,12 +284,13 @@ func (c *Controller) reconcilePersist(
 	counter *Counter,
 ) worker.Result {
 	logger := c.logger.WithValues("object", qualifiedName.String())
+	metricTags := []stats.Tag{{Name: "ftc", Value: c.typeConfig.Name}, {Name: "policy", Value: metricName}}
 
-	c.metrics.Rate(fmt.Sprintf("policyrc-persist-%s-controller.throughput", metricName), 1)
+	c.metrics.Rate("policyrc-persist-controller.throughput", 1, metricTags...)
 	logger.V(3).Info("Policyrc persist controller starting to reconcile")
 	startTime := time.Now()
 	defer func() {
-		c.metrics.Duration(fmt.Sprintf("policyrc-persist-%s-controller.latency", metricName), startTime)
+		c.metrics.Duration("policyrc-persist-controller.latency", startTime, metricTags...)
 		logger.V(3).WithValues("duration", time.Since(startTime)).Info("Policyrc persist controller finished reconciling")
 	}()
 
@@ -17,6 +17,8 @@ limitations under the License.
 package scheduler
 
 import (
+	"time"
+
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 )
@@ -41,6 +43,7 @@ const (
 	ClusterSelectorAnnotations   = common.DefaultPrefix + "clusterSelector"
 	AffinityAnnotations          = common.DefaultPrefix + "affinity"
 	MaxClustersAnnotations       = common.DefaultPrefix + "maxClusters"
+	PriorityAnnotations          = common.DefaultPrefix + "priority"
 
 	DefaultSchedulingMode = fedcorev1a1.SchedulingModeDuplicate
 
@@ -48,6 +51,32 @@ const (
 	EventReasonInvalidFollowsObject      = "InvalidFollowsObject"
 	EventReasonWebhookConfigurationError = "WebhookConfigurationError"
 	EventReasonWebhookRegistered         = "WebhookRegistered"
+	EventReasonPreemptReplicas           = "PreemptReplicas"
 
 	SchedulingTriggerHashAnnotation = common.DefaultPrefix + "scheduling-trigger-hash"
+
+	// SchedulingExplanationAnnotation contains the JSON-encoded core.ScheduleExplanation of the last scheduling cycle.
+	SchedulingExplanationAnnotation = common.DefaultPrefix + "scheduling-explanation"
+	// maxExplainedClusters is the maximum number of clusters whose details are recorded in the scheduling explanation.
+	maxExplainedClusters = 20
+	// maxSchedulingExplanationBytes bounds the size of the scheduling explanation annotation, well below the 256KiB
+	// limit of the total size of annotations.
+	maxSchedulingExplanationBytes = 32 * 1024
+
+	// PreemptionVictimsAnnotation contains the JSON-encoded []core.Preemption of the replicas preempted by the object
+	// that are still in effect.
+	PreemptionVictimsAnnotation = common.DefaultPrefix + "preemption-victims"
+	// PreemptedReplicasAnnotation contains the JSON-encoded []core.PreemptedReplicas of the object's replicas that
+	// were preempted by objects with a higher priority.
+	PreemptedReplicasAnnotation = common.DefaultPrefix + "preempted-replicas"
+	// DrainedReplicasAnnotation contains the JSON-encoded []DrainedReplicas of the object's replicas in the clusters
+	// that are being drained.
+	DrainedReplicasAnnotation = common.DefaultPrefix + "drained-replicas"
+
+	// replicaEstimationTimeout bounds the total time spent calling the replica estimators of member clusters for an
+	// object. Clusters whose estimators do not respond in time are scheduled without estimated capacity.
+	replicaEstimationTimeout = 5 * time.Second
+
+	// placedClusterIndex indexes federated objects by the clusters they are placed in.
+	placedClusterIndex = "placedCluster"
 )
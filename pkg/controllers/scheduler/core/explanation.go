@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sort"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+)
+
+// ScheduleExplanation records the decisions made in each phase of a scheduling cycle, so that users can find out why
+// an object was or was not placed in a cluster.
+type ScheduleExplanation struct {
+	// StickyCluster is true if scheduling was skipped because the object sticks to its current clusters.
+	StickyCluster bool `json:"stickyCluster,omitempty"`
+	// Filter contains the verdict of the filter plugins for each cluster.
+	Filter []ClusterFilterVerdict `json:"filter,omitempty"`
+	// Scores contains the scores of each feasible cluster.
+	Scores []ClusterScoreDetails `json:"scores,omitempty"`
+	// SelectedClusters contains the clusters chosen by the select plugins.
+	SelectedClusters []string `json:"selectedClusters,omitempty"`
+	// Replicas contains the replica plan computed by the replicas plugins.
+	Replicas map[string]int64 `json:"replicas,omitempty"`
+	// Preemptions contains the replicas of objects with a lower priority preempted to make room for the replicas.
+	Preemptions []Preemption `json:"preemptions,omitempty"`
+	// PreemptedReplicas contains the replicas of the object that were preempted by objects with a higher priority.
+	PreemptedReplicas []PreemptedReplicas `json:"preemptedReplicas,omitempty"`
+
+	// FilterRejections contains the number of clusters rejected by each filter plugin. It is only set in truncated
+	// explanations, where Filter does not contain the verdicts of all clusters.
+	FilterRejections map[string]int `json:"filterRejections,omitempty"`
+	// Truncated is true if the per-cluster details were truncated to bound the size of the explanation.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ClusterFilterVerdict is the result of running the filter plugins against a cluster.
+type ClusterFilterVerdict struct {
+	Cluster  string `json:"cluster"`
+	Feasible bool   `json:"feasible"`
+	// FailedPlugin is the name of the filter plugin that rejected the cluster.
+	FailedPlugin string `json:"failedPlugin,omitempty"`
+	// Reasons explains why the cluster was rejected.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// ClusterScoreDetails contains the total score of a cluster and the score given by each score plugin.
+type ClusterScoreDetails struct {
+	Cluster      string           `json:"cluster"`
+	Total        int64            `json:"total"`
+	PluginScores map[string]int64 `json:"pluginScores,omitempty"`
+}
+
+func (e *ScheduleExplanation) recordFilterResult(cluster *fedcorev1a1.FederatedCluster, result *framework.Result) {
+	if e == nil {
+		return
+	}
+
+	verdict := ClusterFilterVerdict{
+		Cluster:  cluster.Name,
+		Feasible: result.IsSuccess(),
+	}
+	if !verdict.Feasible {
+		verdict.FailedPlugin = result.FailedPlugin()
+		verdict.Reasons = result.Reasons()
+	}
+	e.Filter = append(e.Filter, verdict)
+}
+
+func (e *ScheduleExplanation) recordScores(
+	pluginScores framework.PluginToClusterScore,
+	clusterScores framework.ClusterScoreList,
+) {
+	if e == nil {
+		return
+	}
+
+	e.Scores = make([]ClusterScoreDetails, len(clusterScores))
+	for i, clusterScore := range clusterScores {
+		details := ClusterScoreDetails{
+			Cluster:      clusterScore.Cluster.Name,
+			Total:        clusterScore.Score,
+			PluginScores: make(map[string]int64, len(pluginScores)),
+		}
+		for plugin, scores := range pluginScores {
+			details.PluginScores[plugin] = scores[i].Score
+		}
+		e.Scores[i] = details
+	}
+
+	sort.SliceStable(e.Scores, func(i, j int) bool {
+		return e.Scores[i].Total > e.Scores[j].Total
+	})
+}
+
+func (e *ScheduleExplanation) recordSelectedClusters(clusters []*fedcorev1a1.FederatedCluster) {
+	if e == nil {
+		return
+	}
+
+	e.SelectedClusters = make([]string, len(clusters))
+	for i, cluster := range clusters {
+		e.SelectedClusters[i] = cluster.Name
+	}
+	sort.Strings(e.SelectedClusters)
+}
+
+func (e *ScheduleExplanation) recordReplicas(clusterReplicasList framework.ClusterReplicasList) {
+	if e == nil {
+		return
+	}
+
+	e.Replicas = make(map[string]int64, len(clusterReplicasList))
+	for _, clusterReplicas := range clusterReplicasList {
+		e.Replicas[clusterReplicas.Cluster.Name] = clusterReplicas.Replicas
+	}
+}
+
+func (e *ScheduleExplanation) recordPreemptions(preemptions []Preemption) {
+	if e == nil {
+		return
+	}
+
+	e.Preemptions = preemptions
+}
+
+// Truncate returns a copy of the explanation whose per-cluster details are limited to maxClusters entries, so that its
+// size does not grow with the number of clusters. Only the verdicts of rejected clusters and the top scores are kept,
+// and the number of rejections of each filter plugin is recorded instead. Truncate returns e itself if it is small
+// enough.
+func (e *ScheduleExplanation) Truncate(maxClusters int) *ScheduleExplanation {
+	if e == nil {
+		return nil
+	}
+	if len(e.Filter) <= maxClusters && len(e.Scores) <= maxClusters && len(e.SelectedClusters) <= maxClusters &&
+		len(e.Preemptions) <= maxClusters && len(e.PreemptedReplicas) <= maxClusters {
+		return e
+	}
+
+	truncated := &ScheduleExplanation{
+		StickyCluster:     e.StickyCluster,
+		Scores:            e.Scores,
+		SelectedClusters:  e.SelectedClusters,
+		Preemptions:       e.Preemptions,
+		PreemptedReplicas: e.PreemptedReplicas,
+		FilterRejections:  map[string]int{},
+		Truncated:         true,
+	}
+
+	for _, verdict := range e.Filter {
+		if verdict.Feasible {
+			continue
+		}
+		truncated.FilterRejections[verdict.FailedPlugin]++
+		if len(truncated.Filter) < maxClusters {
+			truncated.Filter = append(truncated.Filter, verdict)
+		}
+	}
+	if len(truncated.FilterRejections) == 0 {
+		truncated.FilterRejections = nil
+	}
+
+	// Scores are sorted in descending order, so the top clusters are kept.
+	if len(truncated.Scores) > maxClusters {
+		truncated.Scores = truncated.Scores[:maxClusters]
+	}
+	if len(truncated.SelectedClusters) > maxClusters {
+		truncated.SelectedClusters = truncated.SelectedClusters[:maxClusters]
+	}
+	if len(truncated.Preemptions) > maxClusters {
+		truncated.Preemptions = truncated.Preemptions[:maxClusters]
+	}
+	if len(truncated.PreemptedReplicas) > maxClusters {
+		truncated.PreemptedReplicas = truncated.PreemptedReplicas[:maxClusters]
+	}
+
+	if e.Replicas != nil {
+		truncated.Replicas = make(map[string]int64, len(truncated.SelectedClusters))
+		for _, cluster := range truncated.SelectedClusters {
+			if replicas, ok := e.Replicas[cluster]; ok {
+				truncated.Replicas[cluster] = replicas
+			}
+		}
+	}
+
+	return truncated
+}
@@ -50,6 +50,11 @@ type ScheduleResult struct {
 	// The key is the name of the cluster and the value is the recommended number of replicas for it.
 	// If the value is nil, it means that there is no recommended number of replicas for the cluster (used in Duplicate scheduling mode).
 	SuggestedClusters map[string]*int64
+	// Explanation records the decisions that led to the suggested clusters.
+	Explanation *ScheduleExplanation
+	// Preemptions contains the replicas of units with a lower priority that should be preempted to make room for
+	// the suggested replicas.
+	Preemptions []Preemption
 }
 
 func (result ScheduleResult) ClusterSet() map[string]struct{} {
@@ -96,14 +101,16 @@ func (g *genericScheduler) Schedule(
 	clusters []*fedcorev1a1.FederatedCluster,
 ) (result ScheduleResult, err error) {
 	logger := klog.FromContext(ctx)
+	result.Explanation = &ScheduleExplanation{}
 
 	// we do not reschedule if sticky cluster is enabled
 	if schedulingUnit.StickyCluster && len(schedulingUnit.CurrentClusters) > 0 {
 		result.SuggestedClusters = schedulingUnit.CurrentClusters
+		result.Explanation.StickyCluster = true
 		return result, nil
 	}
 
-	feasibleClusters, err := g.findClustersThatFitWorkload(ctx, fwk, schedulingUnit, clusters)
+	feasibleClusters, err := g.findClustersThatFitWorkload(ctx, fwk, schedulingUnit, clusters, result.Explanation)
 	if err != nil {
 		return result, fmt.Errorf("failed to findClustersThatFitWorkload: %w", err)
 	}
@@ -113,7 +120,7 @@ func (g *genericScheduler) Schedule(
 		return result, nil
 	}
 
-	clusterScores, err := g.scoreClusters(ctx, fwk, schedulingUnit, feasibleClusters)
+	clusterScores, err := g.scoreClusters(ctx, fwk, schedulingUnit, feasibleClusters, result.Explanation)
 	if err != nil {
 		return result, fmt.Errorf("failed to scoreClusters: %w", err)
 	}
@@ -125,6 +132,7 @@ func (g *genericScheduler) Schedule(
 		return result, fmt.Errorf("failed to selectClusters: %w", err)
 	}
 	logger.V(2).Info("Clusters selected", "clusters", spew.Sprint(selectedClusters))
+	result.Explanation.recordSelectedClusters(selectedClusters)
 
 	// we skip replica scheduling if mode is Duplicate
 	if schedulingUnit.SchedulingMode == fedcorev1a1.SchedulingModeDuplicate {
@@ -142,10 +150,17 @@ func (g *genericScheduler) Schedule(
 	}
 	logger.V(2).
 		Info("Replicas assigned", "result", spew.Sprint(clusterReplicaList))
+	result.Explanation.recordReplicas(clusterReplicaList)
 	result.SuggestedClusters = make(map[string]*int64, len(clusterReplicaList))
 	for _, clusterReplica := range clusterReplicaList {
 		result.SuggestedClusters[clusterReplica.Cluster.Name] = pointer.Int64(clusterReplica.Replicas)
 	}
+
+	result.Preemptions = g.preempt(ctx, schedulingUnit, clusterReplicaList)
+	if len(result.Preemptions) > 0 {
+		logger.V(2).Info("Replicas preempted", "preemptions", spew.Sprint(result.Preemptions))
+	}
+	result.Explanation.recordPreemptions(result.Preemptions)
 	return result, nil
 }
 
@@ -154,12 +169,15 @@ func (g *genericScheduler) findClustersThatFitWorkload(
 	fwk framework.Framework,
 	schedulingUnit framework.SchedulingUnit,
 	clusters []*fedcorev1a1.FederatedCluster,
+	explanation *ScheduleExplanation,
 ) ([]*fedcorev1a1.FederatedCluster, error) {
 	logger := klog.FromContext(ctx)
 
 	ret := make([]*fedcorev1a1.FederatedCluster, 0)
 	for _, cluster := range clusters {
-		if result := fwk.RunFilterPlugins(ctx, &schedulingUnit, cluster); !result.IsSuccess() {
+		result := fwk.RunFilterPlugins(ctx, &schedulingUnit, cluster)
+		explanation.recordFilterResult(cluster, result)
+		if !result.IsSuccess() {
 			logger.V(2).Info("Cluster doesn't fit", "name", cluster.Name, "reason", result.AsError())
 		} else {
 			ret = append(ret, cluster)
@@ -173,6 +191,7 @@ func (g *genericScheduler) scoreClusters(
 	fwk framework.Framework,
 	schedulingUnit framework.SchedulingUnit,
 	clusters []*fedcorev1a1.FederatedCluster,
+	explanation *ScheduleExplanation,
 ) (framework.ClusterScoreList, error) {
 	ret := make(framework.ClusterScoreList, len(clusters))
 	scores, result := fwk.RunScorePlugins(ctx, &schedulingUnit, clusters)
@@ -188,6 +207,7 @@ func (g *genericScheduler) scoreClusters(
 			ret[i].Score += scores[j][i].Score
 		}
 	}
+	explanation.recordScores(scores, ret)
 	return ret, nil
 }
 
@@ -194,3 +194,128 @@ func TestSchedulingWithStickyCluster(t *testing.T) {
 		}
 	})
 }
+
+type rejectClusterPlugin struct {
+	rejected string
+}
+
+func (r *rejectClusterPlugin) Name() string {
+	return "RejectCluster"
+}
+
+func (r *rejectClusterPlugin) Filter(
+	ctx context.Context,
+	su *framework.SchedulingUnit,
+	cluster *fedcorev1a1.FederatedCluster,
+) *framework.Result {
+	if cluster.Name == r.rejected {
+		return framework.NewResult(framework.Unschedulable, "cluster is rejected")
+	}
+	return framework.NewResult(framework.Success)
+}
+
+type nameLengthScorePlugin struct{}
+
+func (n *nameLengthScorePlugin) Name() string {
+	return "NameLength"
+}
+
+func (n *nameLengthScorePlugin) Score(
+	ctx context.Context,
+	su *framework.SchedulingUnit,
+	cluster *fedcorev1a1.FederatedCluster,
+) (int64, *framework.Result) {
+	return int64(len(cluster.Name)), framework.NewResult(framework.Success)
+}
+
+func (n *nameLengthScorePlugin) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+func TestSchedulingExplanation(t *testing.T) {
+	clusters := []*fedcorev1a1.FederatedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster10"}},
+	}
+
+	registry := runtime.Registry{
+		"NaiveReplicas": newNaiveReplicas,
+		"RejectCluster": func(_ framework.Handle) (framework.Plugin, error) {
+			return &rejectClusterPlugin{rejected: "cluster2"}, nil
+		},
+		"NameLength": func(_ framework.Handle) (framework.Plugin, error) {
+			return &nameLengthScorePlugin{}, nil
+		},
+	}
+	fwk, err := runtime.NewFramework(registry, nil, &fedcore.EnabledPlugins{
+		FilterPlugins:   []string{"RejectCluster"},
+		ScorePlugins:    []string{"NameLength"},
+		ReplicasPlugins: []string{"NaiveReplicas"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error when creating framework: %v", err)
+	}
+
+	schedulingUnit := framework.SchedulingUnit{
+		DesiredReplicas: pointer.Int64(10),
+		SchedulingMode:  fedcorev1a1.SchedulingModeDivide,
+	}
+	result, err := NewSchedulerAlgorithm().Schedule(context.TODO(), fwk, schedulingUnit, clusters)
+	if err != nil {
+		t.Fatalf("unexpected error when scheduling: %v", err)
+	}
+
+	expected := &ScheduleExplanation{
+		Filter: []ClusterFilterVerdict{
+			{Cluster: "cluster1", Feasible: true},
+			{Cluster: "cluster2", Feasible: false, FailedPlugin: "RejectCluster", Reasons: []string{"cluster is rejected"}},
+			{Cluster: "cluster10", Feasible: true},
+		},
+		Scores: []ClusterScoreDetails{
+			{Cluster: "cluster10", Total: 9, PluginScores: map[string]int64{"NameLength": 9}},
+			{Cluster: "cluster1", Total: 8, PluginScores: map[string]int64{"NameLength": 8}},
+		},
+		SelectedClusters: []string{"cluster1", "cluster10"},
+		Replicas:         map[string]int64{"cluster1": 1, "cluster10": 1},
+	}
+	if !reflect.DeepEqual(result.Explanation, expected) {
+		t.Errorf("unexpected scheduling explanation, want %+v but got %+v", expected, result.Explanation)
+	}
+}
+
+func TestTruncateScheduleExplanation(t *testing.T) {
+	explanation := &ScheduleExplanation{
+		Filter: []ClusterFilterVerdict{
+			{Cluster: "cluster1", Feasible: true},
+			{Cluster: "cluster2", Feasible: false, FailedPlugin: "TaintToleration", Reasons: []string{"untolerated taint"}},
+			{Cluster: "cluster3", Feasible: false, FailedPlugin: "TaintToleration", Reasons: []string{"untolerated taint"}},
+			{Cluster: "cluster4", Feasible: false, FailedPlugin: "APIResources", Reasons: []string{"no resource"}},
+			{Cluster: "cluster5", Feasible: true},
+		},
+		Scores: []ClusterScoreDetails{
+			{Cluster: "cluster5", Total: 9},
+			{Cluster: "cluster1", Total: 8},
+		},
+		SelectedClusters: []string{"cluster1", "cluster5"},
+		Replicas:         map[string]int64{"cluster1": 1, "cluster5": 1},
+	}
+
+	if truncated := explanation.Truncate(5); truncated != explanation {
+		t.Errorf("expected small explanation to be kept, but got %+v", truncated)
+	}
+
+	expected := &ScheduleExplanation{
+		Filter: []ClusterFilterVerdict{
+			{Cluster: "cluster2", Feasible: false, FailedPlugin: "TaintToleration", Reasons: []string{"untolerated taint"}},
+		},
+		Scores:           []ClusterScoreDetails{{Cluster: "cluster5", Total: 9}},
+		SelectedClusters: []string{"cluster1"},
+		Replicas:         map[string]int64{"cluster1": 1},
+		FilterRejections: map[string]int{"TaintToleration": 2, "APIResources": 1},
+		Truncated:        true,
+	}
+	if truncated := explanation.Truncate(1); !reflect.DeepEqual(truncated, expected) {
+		t.Errorf("unexpected truncated explanation, want %+v but got %+v", expected, truncated)
+	}
+}
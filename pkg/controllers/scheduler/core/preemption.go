@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+)
+
+// Preemption describes the replicas of a unit with a lower priority that are preempted in a cluster.
+type Preemption struct {
+	Cluster string `json:"cluster"`
+	// Victim is the key of the unit whose replicas are preempted.
+	Victim         string `json:"victim"`
+	VictimPriority int32  `json:"victimPriority"`
+	// Replicas is the number of preempted replicas.
+	Replicas int64 `json:"replicas"`
+	// RemainingReplicas is the number of replicas the victim may keep in the cluster.
+	RemainingReplicas int64 `json:"remainingReplicas"`
+}
+
+// PreemptedReplicas describes the replicas of a unit in a cluster that were preempted by a unit with a higher priority.
+type PreemptedReplicas struct {
+	Cluster string `json:"cluster"`
+	// Preemptor is the key of the unit that preempted the replicas.
+	Preemptor string `json:"preemptor"`
+	// Replicas is the number of preempted replicas.
+	Replicas int64 `json:"replicas"`
+	// MaxReplicas is the number of replicas the unit may keep in the cluster while the preemption is in effect.
+	MaxReplicas int64 `json:"maxReplicas"`
+}
+
+// preempt finds the replicas of units with a lower priority to preempt in the clusters where the replicas assigned to
+// the scheduling unit exceed the estimated capacity. Victims are chosen in ascending order of priority, and the number
+// of replicas preempted from a victim is derived from the resource requests of both units.
+func (g *genericScheduler) preempt(
+	ctx context.Context,
+	schedulingUnit framework.SchedulingUnit,
+	clusterReplicasList framework.ClusterReplicasList,
+) []Preemption {
+	if schedulingUnit.Preemption == nil || len(schedulingUnit.Preemption.Candidates) == 0 {
+		return nil
+	}
+
+	logger := klog.FromContext(ctx)
+	capacity := GetEstimatedCapacity(&schedulingUnit)
+
+	clusterReplicasList = append(framework.ClusterReplicasList(nil), clusterReplicasList...)
+	sort.Slice(clusterReplicasList, func(i, j int) bool {
+		return clusterReplicasList[i].Cluster.Name < clusterReplicasList[j].Cluster.Name
+	})
+
+	candidates := append([]framework.PreemptionCandidate(nil), schedulingUnit.Preemption.Candidates...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Priority != candidates[j].Priority {
+			return candidates[i].Priority < candidates[j].Priority
+		}
+		return candidates[i].Key() < candidates[j].Key()
+	})
+
+	var preemptions []Preemption
+	for _, clusterReplicas := range clusterReplicasList {
+		cluster := clusterReplicas.Cluster.Name
+		clusterCapacity, exists := capacity[cluster]
+		if !exists || clusterReplicas.Replicas <= clusterCapacity {
+			continue
+		}
+		if _, pending := schedulingUnit.Preemption.PendingClusters[cluster]; pending {
+			logger.V(2).Info("Skip preemption in cluster with pending preemptions", "cluster", cluster)
+			continue
+		}
+
+		shortage := clusterReplicas.Replicas - clusterCapacity
+		for _, candidate := range candidates {
+			if shortage <= 0 {
+				break
+			}
+			if candidate.Priority >= schedulingUnit.Priority || candidate.Replicas[cluster] <= 0 {
+				continue
+			}
+
+			ratio, ok := getReplicaRatio(&schedulingUnit.Preemption.ResourceRequest, &candidate.ResourceRequest)
+			if !ok {
+				continue
+			}
+
+			replicas := int64(math.Ceil(float64(shortage) * ratio))
+			if replicas > candidate.Replicas[cluster] {
+				replicas = candidate.Replicas[cluster]
+				shortage -= int64(math.Floor(float64(replicas) / ratio))
+			} else {
+				shortage = 0
+			}
+
+			preemptions = append(preemptions, Preemption{
+				Cluster:           cluster,
+				Victim:            candidate.Key(),
+				VictimPriority:    candidate.Priority,
+				Replicas:          replicas,
+				RemainingReplicas: candidate.Replicas[cluster] - replicas,
+			})
+		}
+
+		if shortage > 0 {
+			logger.V(2).Info("Insufficient replicas to preempt", "cluster", cluster, "shortage", shortage)
+		}
+	}
+
+	return preemptions
+}
+
+// GetEstimatedCapacity returns the lowest estimated capacity of each cluster known to the scheduling unit.
+func GetEstimatedCapacity(schedulingUnit *framework.SchedulingUnit) map[string]int64 {
+	capacity := make(map[string]int64, len(schedulingUnit.EstimatedCapacity))
+	if autoMigration := schedulingUnit.AutoMigration; autoMigration != nil && autoMigration.Info != nil {
+		for cluster, ec := range autoMigration.Info.EstimatedCapacity {
+			if ec >= 0 {
+				capacity[cluster] = ec
+			}
+		}
+	}
+	for cluster, ec := range schedulingUnit.EstimatedCapacity {
+		if existing, exists := capacity[cluster]; ec >= 0 && (!exists || ec < existing) {
+			capacity[cluster] = ec
+		}
+	}
+	return capacity
+}
+
+// getReplicaRatio returns the number of victim replicas that must be preempted to make room for a single replica of
+// the preemptor. It returns false if preempting the victim does not release a resource requested by the preemptor.
+func getReplicaRatio(preemptor, victim *framework.Resource) (float64, bool) {
+	ratio := 0.0
+	for _, requests := range [][2]int64{
+		{preemptor.MilliCPU, victim.MilliCPU},
+		{preemptor.Memory, victim.Memory},
+	} {
+		if requests[0] <= 0 {
+			continue
+		}
+		if requests[1] <= 0 {
+			return 0, false
+		}
+		if r := float64(requests[0]) / float64(requests[1]); r > ratio {
+			ratio = r
+		}
+	}
+
+	if ratio == 0 {
+		// neither unit requests resources, so we preempt replicas one for one
+		return 1, true
+	}
+	return ratio, true
+}
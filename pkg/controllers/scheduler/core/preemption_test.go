@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+)
+
+func TestPreempt(t *testing.T) {
+	cluster1 := &fedcorev1a1.FederatedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}
+	cluster2 := &fedcorev1a1.FederatedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster2"}}
+	replicasList := framework.ClusterReplicasList{
+		{Cluster: cluster1, Replicas: 5},
+		{Cluster: cluster2, Replicas: 3},
+	}
+
+	smallRequest := framework.Resource{MilliCPU: 500, Memory: 1 << 30}
+	largeRequest := framework.Resource{MilliCPU: 1000, Memory: 1 << 30}
+
+	testCases := map[string]struct {
+		estimatedCapacity map[string]int64
+		preemption        *framework.PreemptionSpec
+		expected          []Preemption
+	}{
+		"no preemption without candidates": {
+			estimatedCapacity: map[string]int64{"cluster1": 2},
+			preemption:        &framework.PreemptionSpec{ResourceRequest: smallRequest},
+			expected:          nil,
+		},
+		"no preemption if capacity is sufficient": {
+			estimatedCapacity: map[string]int64{"cluster1": 5, "cluster2": 3},
+			preemption: &framework.PreemptionSpec{
+				ResourceRequest: smallRequest,
+				Candidates: []framework.PreemptionCandidate{
+					{Namespace: "default", Name: "batch", ResourceRequest: smallRequest, Replicas: map[string]int64{"cluster1": 4}},
+				},
+			},
+			expected: nil,
+		},
+		"lowest priority victims are preempted first": {
+			estimatedCapacity: map[string]int64{"cluster1": 2},
+			preemption: &framework.PreemptionSpec{
+				ResourceRequest: smallRequest,
+				Candidates: []framework.PreemptionCandidate{
+					{
+						Namespace:       "default",
+						Name:            "low",
+						Priority:        -1,
+						ResourceRequest: smallRequest,
+						Replicas:        map[string]int64{"cluster1": 2},
+					},
+					{
+						Namespace:       "default",
+						Name:            "lower",
+						Priority:        -2,
+						ResourceRequest: smallRequest,
+						Replicas:        map[string]int64{"cluster1": 2, "cluster2": 5},
+					},
+				},
+			},
+			expected: []Preemption{
+				{Cluster: "cluster1", Victim: "default/lower", VictimPriority: -2, Replicas: 2, RemainingReplicas: 0},
+				{Cluster: "cluster1", Victim: "default/low", VictimPriority: -1, Replicas: 1, RemainingReplicas: 1},
+			},
+		},
+		"victim replicas are derived from resource requests": {
+			estimatedCapacity: map[string]int64{"cluster2": 0},
+			preemption: &framework.PreemptionSpec{
+				ResourceRequest: largeRequest,
+				Candidates: []framework.PreemptionCandidate{
+					{
+						Namespace:       "default",
+						Name:            "batch",
+						Priority:        -1,
+						ResourceRequest: smallRequest,
+						Replicas:        map[string]int64{"cluster2": 10},
+					},
+				},
+			},
+			expected: []Preemption{
+				{Cluster: "cluster2", Victim: "default/batch", VictimPriority: -1, Replicas: 6, RemainingReplicas: 4},
+			},
+		},
+		"victims that release no requested resource are not preempted": {
+			estimatedCapacity: map[string]int64{"cluster1": 4},
+			preemption: &framework.PreemptionSpec{
+				ResourceRequest: smallRequest,
+				Candidates: []framework.PreemptionCandidate{
+					{
+						Namespace: "default",
+						Name:      "batch",
+						Priority:  -1,
+						Replicas:  map[string]int64{"cluster1": 4},
+					},
+				},
+			},
+			expected: nil,
+		},
+		"clusters with pending preemptions are skipped": {
+			estimatedCapacity: map[string]int64{"cluster1": 4},
+			preemption: &framework.PreemptionSpec{
+				ResourceRequest: smallRequest,
+				Candidates: []framework.PreemptionCandidate{
+					{
+						Namespace:       "default",
+						Name:            "batch",
+						Priority:        -1,
+						ResourceRequest: smallRequest,
+						Replicas:        map[string]int64{"cluster1": 4},
+					},
+				},
+				PendingClusters: map[string]struct{}{"cluster1": {}},
+			},
+			expected: nil,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			su := framework.SchedulingUnit{
+				Namespace:         "default",
+				Name:              "critical",
+				EstimatedCapacity: tc.estimatedCapacity,
+				Preemption:        tc.preemption,
+			}
+
+			g := &genericScheduler{}
+			preemptions := g.preempt(context.Background(), su, replicasList)
+			assert.Equal(t, tc.expected, preemptions)
+		})
+	}
+}
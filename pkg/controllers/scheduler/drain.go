@@ -0,0 +1,340 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/core"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	annotationutil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/annotation"
+)
+
+const defaultDrainStepInterval = time.Minute
+
+// DrainedReplicas is the maximum number of replicas an object in Divide mode may keep in a draining cluster during a
+// step of the drain.
+type DrainedReplicas struct {
+	Cluster     string `json:"cluster"`
+	Step        int64  `json:"step"`
+	MaxReplicas int64  `json:"maxReplicas"`
+}
+
+// clusterDrain describes the drain of a cluster that has started.
+type clusterDrain struct {
+	startTime                time.Time
+	stepInterval             time.Duration
+	maxUnavailablePercentage int64
+}
+
+func (d *clusterDrain) step(now time.Time) int64 {
+	if now.Before(d.startTime) {
+		return 0
+	}
+	return int64(now.Sub(d.startTime) / d.stepInterval)
+}
+
+func (d *clusterDrain) nextStepTime(now time.Time) time.Time {
+	return d.startTime.Add(time.Duration(d.step(now)+1) * d.stepInterval)
+}
+
+// getClusterDrain returns the drain of the cluster, or nil if the cluster is not drained or the federated cluster
+// controller has not started the drain yet.
+func getClusterDrain(cluster *fedcorev1a1.FederatedCluster) *clusterDrain {
+	if cluster.Spec.Drain == nil || cluster.Status.Drain == nil {
+		return nil
+	}
+
+	drain := &clusterDrain{
+		startTime:                cluster.Status.Drain.StartTime.Time,
+		stepInterval:             cluster.Spec.Drain.StepInterval.Duration,
+		maxUnavailablePercentage: 100,
+	}
+	if drain.stepInterval <= 0 {
+		drain.stepInterval = defaultDrainStepInterval
+	}
+	if percentage := cluster.Spec.Drain.MaxUnavailablePercentage; percentage != nil && *percentage > 0 && *percentage < 100 {
+		drain.maxUnavailablePercentage = int64(*percentage)
+	}
+	return drain
+}
+
+// getClusterDrainSteps returns the current drain step of each draining cluster the object is placed in, and the
+// time of the next step, at which the object should be rescheduled.
+func getClusterDrainSteps(
+	currentClusters map[string]*int64,
+	clusters []*fedcorev1a1.FederatedCluster,
+	now time.Time,
+) ([]keyValue[string, int64], *time.Time) {
+	steps := make(map[string]int64)
+	var nextStepTime *time.Time
+
+	for _, cluster := range clusters {
+		if _, placed := currentClusters[cluster.Name]; !placed {
+			continue
+		}
+		drain := getClusterDrain(cluster)
+		if drain == nil {
+			continue
+		}
+
+		steps[cluster.Name] = drain.step(now)
+		if t := drain.nextStepTime(now); nextStepTime == nil || t.Before(*nextStepTime) {
+			nextStepTime = &t
+		}
+	}
+
+	if len(steps) == 0 {
+		return nil, nil
+	}
+	return sortMap(steps), nextStepTime
+}
+
+// getDrainedReplicas returns the replicas the object in Divide mode may keep in each draining cluster during the
+// current step of the drain. The limit of a step is kept when the object is rescheduled within the same step, so that
+// at most the max unavailable percentage of its replicas are moved in each step. The limit of the previous step is
+// also kept until destinationsAvailable is true, i.e. the replicas moved to other clusters are available.
+func getDrainedReplicas(
+	fedObject *unstructured.Unstructured,
+	su *framework.SchedulingUnit,
+	clusters []*fedcorev1a1.FederatedCluster,
+	destinationsAvailable bool,
+	now time.Time,
+) ([]DrainedReplicas, error) {
+	if su.SchedulingMode != fedcorev1a1.SchedulingModeDivide {
+		return nil, nil
+	}
+
+	previous := make(map[string]DrainedReplicas)
+	if value, exists := fedObject.GetAnnotations()[DrainedReplicasAnnotation]; exists {
+		var records []DrainedReplicas
+		if err := json.Unmarshal([]byte(value), &records); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal drained replicas: %w", err)
+		}
+		for _, record := range records {
+			previous[record.Cluster] = record
+		}
+	}
+
+	var records []DrainedReplicas
+	for _, cluster := range clusters {
+		drain := getClusterDrain(cluster)
+		replicas := su.CurrentClusters[cluster.Name]
+		if drain == nil || replicas == nil || *replicas <= 0 {
+			continue
+		}
+
+		step := drain.step(now)
+		if record, exists := previous[cluster.Name]; exists && (record.Step == step || !destinationsAvailable) {
+			records = append(records, record)
+			continue
+		}
+
+		unavailable := (*replicas*drain.maxUnavailablePercentage + 99) / 100
+		records = append(records, DrainedReplicas{
+			Cluster:     cluster.Name,
+			Step:        step,
+			MaxReplicas: *replicas - unavailable,
+		})
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Cluster < records[j].Cluster
+	})
+	return records, nil
+}
+
+// applyClusterDrain caps the replicas of the unit in the draining clusters according to the drained replicas, and
+// returns the clusters that the unit may be scheduled to. Draining clusters are excluded once the unit may not keep
+// any replicas in them.
+func applyClusterDrain(
+	su *framework.SchedulingUnit,
+	clusters []*fedcorev1a1.FederatedCluster,
+	records []DrainedReplicas,
+) []*fedcorev1a1.FederatedCluster {
+	drainedReplicas := make(map[string]int64, len(records))
+	for _, record := range records {
+		drainedReplicas[record.Cluster] = record.MaxReplicas
+	}
+
+	maxReplicas := make(map[string]int64, len(su.MaxReplicas)+len(records))
+	for cluster, replicas := range su.MaxReplicas {
+		maxReplicas[cluster] = replicas
+	}
+	minReplicas := make(map[string]int64, len(su.MinReplicas))
+	for cluster, replicas := range su.MinReplicas {
+		minReplicas[cluster] = replicas
+	}
+
+	ret := make([]*fedcorev1a1.FederatedCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		if getClusterDrain(cluster) == nil {
+			ret = append(ret, cluster)
+			continue
+		}
+
+		replicas, exists := drainedReplicas[cluster.Name]
+		if !exists || replicas <= 0 {
+			continue
+		}
+
+		if existing, exists := maxReplicas[cluster.Name]; !exists || replicas < existing {
+			maxReplicas[cluster.Name] = replicas
+		}
+		if existing, exists := minReplicas[cluster.Name]; exists && existing > replicas {
+			minReplicas[cluster.Name] = replicas
+		}
+		ret = append(ret, cluster)
+	}
+
+	if len(records) > 0 {
+		su.MaxReplicas = maxReplicas
+		su.MinReplicas = minReplicas
+	}
+	return ret
+}
+
+// setDrainedReplicas records the drained replicas of the clusters the object still has replicas in.
+func setDrainedReplicas(fedObject *unstructured.Unstructured, records []DrainedReplicas, result core.ScheduleResult) error {
+	var ret []DrainedReplicas
+	for _, record := range records {
+		if replicas := result.SuggestedClusters[record.Cluster]; replicas != nil && *replicas > 0 {
+			ret = append(ret, record)
+		}
+	}
+
+	if len(ret) == 0 {
+		_, err := annotationutil.RemoveAnnotation(fedObject, DrainedReplicasAnnotation)
+		return err
+	}
+
+	recordsBytes, err := json.Marshal(ret)
+	if err != nil {
+		return fmt.Errorf("failed to marshal drained replicas: %w", err)
+	}
+	_, err = annotationutil.AddAnnotation(fedObject, DrainedReplicasAnnotation, string(recordsBytes))
+	return err
+}
+
+// drainAvailabilityCollected returns whether the status controller collects the replicas and available replicas of
+// the objects of the type, which are required to tell whether the replicas moved by a drain are available.
+func drainAvailabilityCollected(typeConfig *fedcorev1a1.FederatedTypeConfig) bool {
+	pathDefinition := typeConfig.Spec.PathDefinition
+	if typeConfig.Spec.StatusCollection == nil || typeConfig.GetStatusType() == nil ||
+		len(pathDefinition.ReplicasSpec) == 0 || len(pathDefinition.AvailableReplicasStatus) == 0 {
+		return false
+	}
+
+	isCollected := func(path string) bool {
+		for _, field := range typeConfig.Spec.StatusCollection.Fields {
+			if field == path || strings.HasPrefix(path, field+".") {
+				return true
+			}
+		}
+		return false
+	}
+	return isCollected(pathDefinition.ReplicasSpec) && isCollected(pathDefinition.AvailableReplicasStatus)
+}
+
+// drainDestinationsAvailable returns whether the replicas of the object in the clusters that are not drained have been
+// propagated and are available according to the collected status of the object. It returns true if the status is not
+// collected for the type, in which case the drain advances on the step interval alone.
+func (s *Scheduler) drainDestinationsAvailable(
+	fedObject *unstructured.Unstructured,
+	currentClusters map[string]*int64,
+	clusters []*fedcorev1a1.FederatedCluster,
+) bool {
+	if s.federatedStatusLister == nil {
+		return true
+	}
+
+	var (
+		obj pkgruntime.Object
+		err error
+	)
+	if s.typeConfig.GetNamespaced() {
+		obj, err = s.federatedStatusLister.ByNamespace(fedObject.GetNamespace()).Get(fedObject.GetName())
+	} else {
+		obj, err = s.federatedStatusLister.Get(fedObject.GetName())
+	}
+	if err != nil {
+		return false
+	}
+
+	status := &util.FederatedResource{}
+	if err := pkgruntime.DefaultUnstructuredConverter.FromUnstructured(
+		obj.(*unstructured.Unstructured).Object,
+		status,
+	); err != nil {
+		return false
+	}
+	clusterStatuses := make(map[string]util.ResourceClusterStatus, len(status.ClusterStatus))
+	for _, clusterStatus := range status.ClusterStatus {
+		clusterStatuses[clusterStatus.ClusterName] = clusterStatus
+	}
+
+	replicasPath := strings.Split(s.typeConfig.Spec.PathDefinition.ReplicasSpec, ".")
+	availablePath := strings.Split(s.typeConfig.Spec.PathDefinition.AvailableReplicasStatus, ".")
+	for _, cluster := range clusters {
+		desired, placed := currentClusters[cluster.Name]
+		if !placed || desired == nil || getClusterDrain(cluster) != nil {
+			continue
+		}
+
+		clusterStatus, exists := clusterStatuses[cluster.Name]
+		if !exists || len(clusterStatus.Error) > 0 {
+			return false
+		}
+		// The replicas must match the latest scheduling result, otherwise the status predates its propagation.
+		replicas, found, err := unstructured.NestedInt64(clusterStatus.CollectedFields, replicasPath...)
+		if err != nil || !found || replicas != *desired {
+			return false
+		}
+		available, found, err := unstructured.NestedInt64(clusterStatus.CollectedFields, availablePath...)
+		if err != nil || !found || available < *desired {
+			return false
+		}
+	}
+
+	return true
+}
+
+// enqueueDrainingFederatedObject enqueues the federated object of the status if it is being drained from a cluster.
+func (s *Scheduler) enqueueDrainingFederatedObject(obj interface{}) {
+	status, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	fedObject, err := s.federatedObjectFromStore(common.NewQualifiedName(status))
+	if err != nil {
+		return
+	}
+	if _, exists := fedObject.GetAnnotations()[DrainedReplicasAnnotation]; exists {
+		s.worker.Enqueue(common.NewQualifiedName(fedObject))
+	}
+}
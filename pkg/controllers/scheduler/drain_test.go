@@ -0,0 +1,214 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/core"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+)
+
+func TestClusterDrain(t *testing.T) {
+	now := time.Now()
+	drainingCluster := &fedcorev1a1.FederatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "draining"},
+		Spec: fedcorev1a1.FederatedClusterSpec{
+			Drain: &fedcorev1a1.ClusterDrain{
+				MaxUnavailablePercentage: pointer.Int32(30),
+				StepInterval:             metav1.Duration{Duration: time.Minute},
+			},
+		},
+		Status: fedcorev1a1.FederatedClusterStatus{
+			Drain: &fedcorev1a1.ClusterDrainStatus{
+				Phase:     fedcorev1a1.ClusterDraining,
+				StartTime: metav1.NewTime(now.Add(-90 * time.Second)),
+			},
+		},
+	}
+	cordonedCluster := &fedcorev1a1.FederatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cordoned"},
+		Spec:       fedcorev1a1.FederatedClusterSpec{Unschedulable: true},
+	}
+	clusters := []*fedcorev1a1.FederatedCluster{drainingCluster, cordonedCluster}
+
+	newSU := func(mode fedcorev1a1.SchedulingMode, replicas int64) *framework.SchedulingUnit {
+		return &framework.SchedulingUnit{
+			SchedulingMode: mode,
+			CurrentClusters: map[string]*int64{
+				"draining": pointer.Int64(replicas),
+				"cordoned": pointer.Int64(replicas),
+			},
+			MinReplicas: map[string]int64{"draining": 10},
+		}
+	}
+
+	t.Run("steps are reported for draining clusters the object is placed in", func(t *testing.T) {
+		steps, nextStepTime := getClusterDrainSteps(newSU(fedcorev1a1.SchedulingModeDivide, 10).CurrentClusters, clusters, now)
+		assert.Equal(t, []keyValue[string, int64]{{Key: "draining", Value: 1}}, steps)
+		assert.Equal(t, now.Add(30*time.Second), *nextStepTime)
+
+		steps, nextStepTime = getClusterDrainSteps(map[string]*int64{"cordoned": nil}, clusters, now)
+		assert.Nil(t, steps)
+		assert.Nil(t, nextStepTime)
+	})
+
+	t.Run("replicas are moved in steps in Divide mode", func(t *testing.T) {
+		su := newSU(fedcorev1a1.SchedulingModeDivide, 10)
+		records, err := getDrainedReplicas(&unstructured.Unstructured{}, su, clusters, true, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []DrainedReplicas{{Cluster: "draining", Step: 1, MaxReplicas: 7}}, records)
+
+		assert.Equal(t, clusters, applyClusterDrain(su, clusters, records))
+		assert.Equal(t, map[string]int64{"draining": 7}, su.MaxReplicas)
+		assert.Equal(t, map[string]int64{"draining": 7}, su.MinReplicas)
+	})
+
+	t.Run("the limit of a step is kept when rescheduled within the step", func(t *testing.T) {
+		fedObject := &unstructured.Unstructured{}
+		fedObject.SetAnnotations(map[string]string{
+			DrainedReplicasAnnotation: `[{"cluster":"draining","step":1,"maxReplicas":7}]`,
+		})
+		records, err := getDrainedReplicas(fedObject, newSU(fedcorev1a1.SchedulingModeDivide, 7), clusters, true, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []DrainedReplicas{{Cluster: "draining", Step: 1, MaxReplicas: 7}}, records)
+
+		fedObject.SetAnnotations(map[string]string{
+			DrainedReplicasAnnotation: `[{"cluster":"draining","step":0,"maxReplicas":7}]`,
+		})
+		records, err = getDrainedReplicas(fedObject, newSU(fedcorev1a1.SchedulingModeDivide, 7), clusters, true, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []DrainedReplicas{{Cluster: "draining", Step: 1, MaxReplicas: 4}}, records)
+	})
+
+	t.Run("the limit of the previous step is kept until the moved replicas are available", func(t *testing.T) {
+		fedObject := &unstructured.Unstructured{}
+		fedObject.SetAnnotations(map[string]string{
+			DrainedReplicasAnnotation: `[{"cluster":"draining","step":0,"maxReplicas":7}]`,
+		})
+		records, err := getDrainedReplicas(fedObject, newSU(fedcorev1a1.SchedulingModeDivide, 7), clusters, false, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []DrainedReplicas{{Cluster: "draining", Step: 0, MaxReplicas: 7}}, records)
+	})
+
+	t.Run("the cluster is excluded once no replicas are left", func(t *testing.T) {
+		su := newSU(fedcorev1a1.SchedulingModeDivide, 1)
+		records, err := getDrainedReplicas(&unstructured.Unstructured{}, su, clusters, true, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []DrainedReplicas{{Cluster: "draining", Step: 1, MaxReplicas: 0}}, records)
+		assert.Equal(t, []*fedcorev1a1.FederatedCluster{cordonedCluster}, applyClusterDrain(su, clusters, records))
+
+		fedObject := &unstructured.Unstructured{}
+		assert.NoError(t, setDrainedReplicas(fedObject, records, core.ScheduleResult{}))
+		assert.NotContains(t, fedObject.GetAnnotations(), DrainedReplicasAnnotation)
+	})
+
+	t.Run("the cluster is excluded at once in Duplicate mode", func(t *testing.T) {
+		su := newSU(fedcorev1a1.SchedulingModeDuplicate, 10)
+		records, err := getDrainedReplicas(&unstructured.Unstructured{}, su, clusters, true, now)
+		assert.NoError(t, err)
+		assert.Empty(t, records)
+		assert.Equal(t, []*fedcorev1a1.FederatedCluster{cordonedCluster}, applyClusterDrain(su, clusters, records))
+	})
+}
+
+func TestDrainDestinationsAvailable(t *testing.T) {
+	typeConfig := &fedcorev1a1.FederatedTypeConfig{
+		Spec: fedcorev1a1.FederatedTypeConfigSpec{
+			TargetType: fedcorev1a1.APIResource{Kind: "Deployment", Scope: "Namespaced"},
+			StatusType: &fedcorev1a1.APIResource{Kind: "FederatedDeploymentStatus", Scope: "Namespaced"},
+			PathDefinition: fedcorev1a1.PathDefinition{
+				ReplicasSpec:            "spec.replicas",
+				AvailableReplicasStatus: "status.availableReplicas",
+			},
+			StatusCollection: &fedcorev1a1.StatusCollection{Fields: []string{"spec.replicas", "status"}},
+		},
+	}
+	assert.True(t, drainAvailabilityCollected(typeConfig))
+
+	drainingCluster := &fedcorev1a1.FederatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "draining"},
+		Spec:       fedcorev1a1.FederatedClusterSpec{Drain: &fedcorev1a1.ClusterDrain{}},
+		Status: fedcorev1a1.FederatedClusterStatus{
+			Drain: &fedcorev1a1.ClusterDrainStatus{Phase: fedcorev1a1.ClusterDraining},
+		},
+	}
+	destination := &fedcorev1a1.FederatedCluster{ObjectMeta: metav1.ObjectMeta{Name: "destination"}}
+	clusters := []*fedcorev1a1.FederatedCluster{drainingCluster, destination}
+	currentClusters := map[string]*int64{"draining": pointer.Int64(4), "destination": pointer.Int64(6)}
+
+	fedObject := &unstructured.Unstructured{}
+	fedObject.SetNamespace("default")
+	fedObject.SetName("test")
+
+	newStatus := func(replicas, available int64) *unstructured.Unstructured {
+		status := &unstructured.Unstructured{Object: map[string]interface{}{
+			"clusterStatus": []interface{}{
+				map[string]interface{}{
+					"clusterName": "draining",
+					"collectedFields": map[string]interface{}{
+						"spec":   map[string]interface{}{"replicas": int64(8)},
+						"status": map[string]interface{}{"availableReplicas": int64(4)},
+					},
+				},
+				map[string]interface{}{
+					"clusterName": "destination",
+					"collectedFields": map[string]interface{}{
+						"spec":   map[string]interface{}{"replicas": replicas},
+						"status": map[string]interface{}{"availableReplicas": available},
+					},
+				},
+			},
+		}}
+		status.SetNamespace("default")
+		status.SetName("test")
+		return status
+	}
+
+	testCases := []struct {
+		name     string
+		status   *unstructured.Unstructured
+		expected bool
+	}{
+		{name: "status not collected yet", status: nil, expected: false},
+		{name: "replicas not propagated yet", status: newStatus(4, 4), expected: false},
+		{name: "replicas not available yet", status: newStatus(6, 5), expected: false},
+		{name: "replicas available", status: newStatus(6, 6), expected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tc.status != nil {
+				assert.NoError(t, indexer.Add(tc.status))
+			}
+			s := &Scheduler{typeConfig: typeConfig, federatedStatusLister: cache.NewGenericLister(indexer, schema.GroupResource{})}
+			assert.Equal(t, tc.expected, s.drainDestinationsAvailable(fedObject, currentClusters, clusters))
+		})
+	}
+
+	s := &Scheduler{typeConfig: typeConfig}
+	assert.True(t, s.drainDestinationsAvailable(fedObject, currentClusters, clusters))
+}
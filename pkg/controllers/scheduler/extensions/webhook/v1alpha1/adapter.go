@@ -88,5 +88,7 @@ func ConvertSchedulingUnit(su *framework.SchedulingUnit) *schedwebhookv1a1.Sched
 		Tolerations:                su.Tolerations,
 		MaxClusters:                su.MaxClusters,
 		Placements:                 placements,
+		TopologySpread:             su.TopologySpread,
+		Priority:                   su.Priority,
 	}
 }
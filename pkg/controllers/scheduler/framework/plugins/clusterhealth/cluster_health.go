@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterhealth
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/names"
+)
+
+// ClusterHealth filters out clusters whose health checks fail. Clusters without health checks, or whose health is
+// unknown, are not filtered.
+type ClusterHealth struct{}
+
+func NewClusterHealth(_ framework.Handle) (framework.Plugin, error) {
+	return &ClusterHealth{}, nil
+}
+
+func (pl *ClusterHealth) Name() string {
+	return names.ClusterHealth
+}
+
+func (pl *ClusterHealth) Filter(
+	ctx context.Context,
+	su *framework.SchedulingUnit,
+	cluster *fedcorev1a1.FederatedCluster,
+) *framework.Result {
+	err := framework.PreCheck(ctx, su, cluster)
+	if err != nil {
+		return framework.NewResult(framework.Error, err.Error())
+	}
+
+	var reasons []string
+	for _, conditionType := range fedcorev1a1.ClusterHealthConditionTypes {
+		for _, condition := range cluster.Status.Conditions {
+			if condition.Type == conditionType && condition.Status == corev1.ConditionFalse {
+				reasons = append(reasons, fmt.Sprintf("cluster condition %s is False: %s", condition.Type, condition.Reason))
+			}
+		}
+	}
+
+	if len(reasons) > 0 {
+		return framework.NewResult(framework.Unschedulable, reasons...)
+	}
+	return framework.NewResult(framework.Success)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterhealth
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+)
+
+func clusterWithConditions(conditions ...fedcorev1a1.ClusterCondition) *fedcorev1a1.FederatedCluster {
+	return &fedcorev1a1.FederatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1"},
+		Status:     fedcorev1a1.FederatedClusterStatus{Conditions: conditions},
+	}
+}
+
+func TestClusterHealthFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		cluster    *fedcorev1a1.FederatedCluster
+		wantResult *framework.Result
+	}{
+		{
+			name:       "cluster without health checks",
+			cluster:    clusterWithConditions(),
+			wantResult: framework.NewResult(framework.Success),
+		},
+		{
+			name: "healthy cluster",
+			cluster: clusterWithConditions(
+				fedcorev1a1.ClusterCondition{Type: fedcorev1a1.ClusterNodesHealthy, Status: corev1.ConditionTrue},
+				fedcorev1a1.ClusterCondition{Type: fedcorev1a1.ClusterCanaryHealthy, Status: corev1.ConditionUnknown},
+			),
+			wantResult: framework.NewResult(framework.Success),
+		},
+		{
+			name: "unhealthy cluster",
+			cluster: clusterWithConditions(
+				fedcorev1a1.ClusterCondition{
+					Type:   fedcorev1a1.ClusterNodesHealthy,
+					Status: corev1.ConditionFalse,
+					Reason: "InsufficientReadyNodes",
+				},
+				fedcorev1a1.ClusterCondition{
+					Type:   fedcorev1a1.ClusterHTTPProbesHealthy,
+					Status: corev1.ConditionFalse,
+					Reason: "HTTPProbesFailed",
+				},
+			),
+			wantResult: framework.NewResult(
+				framework.Unschedulable,
+				"cluster condition NodesHealthy is False: InsufficientReadyNodes",
+				"cluster condition HTTPProbesHealthy is False: HTTPProbesFailed",
+			),
+		},
+	}
+
+	p, _ := NewClusterHealth(nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotResult := p.(framework.FilterPlugin).Filter(context.TODO(), &framework.SchedulingUnit{}, test.cluster)
+			if !reflect.DeepEqual(gotResult, test.wantResult) {
+				t.Errorf("result does not match: %v, want: %v", gotResult, test.wantResult)
+			}
+		})
+	}
+}
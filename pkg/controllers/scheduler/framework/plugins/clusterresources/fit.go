@@ -67,6 +67,11 @@ func (pl *ClusterResourcesFit) Filter(
 		return framework.NewResult(framework.Unschedulable, failureReasons...)
 	}
 
+	// The aggregated resources may be fragmented across nodes, so check that at least one replica fits on a single node.
+	if replicas, ok := framework.EstimateReplicasByResourceModel(cluster, &su.ResourceRequest); ok && replicas == 0 {
+		return framework.NewResult(framework.Unschedulable, "Insufficient cpu or memory on any single node")
+	}
+
 	return framework.NewResult(framework.Success)
 }
 
@@ -65,6 +65,20 @@ func makeSchedulingUnitWithScalarResource(suName string, scalarA int64) *framewo
 	}
 }
 
+func makeClusterWithResourceModel(clusterName string, nodes, nodeMilliCPU, nodeMemory int64) *fedcorev1a1.FederatedCluster {
+	cluster := makeCluster(clusterName, nodes*nodeMilliCPU, nodes*nodeMemory, nodes*nodeMilliCPU, nodes*nodeMemory)
+	cluster.Status.Resources.ResourceModel = []fedcorev1a1.ResourceModelGrade{
+		{
+			Available: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewMilliQuantity(nodeMilliCPU, resource.DecimalSI),
+				corev1.ResourceMemory: *resource.NewQuantity(nodeMemory, resource.BinarySI),
+			},
+			Nodes: nodes,
+		},
+	}
+	return cluster
+}
+
 func getErrReason(rn corev1.ResourceName) string {
 	return fmt.Sprintf("Insufficient %v", rn)
 }
@@ -136,6 +150,18 @@ func TestEnoughRequests(t *testing.T) {
 			name:       "scalar predicate resources fails",
 			wantResult: framework.NewResult(framework.Success),
 		},
+		{
+			su:         makeSchedulingUnit("su", 500, 1024),
+			cluster:    makeClusterWithResourceModel("cluster", 64, 1000, 4096),
+			name:       "resource model predicate resources success",
+			wantResult: framework.NewResult(framework.Success),
+		},
+		{
+			su:         makeSchedulingUnit("su", 32000, 1024),
+			cluster:    makeClusterWithResourceModel("cluster", 64, 1000, 4096),
+			name:       "resource model predicate resources fails on fragmented cluster",
+			wantResult: framework.NewResult(framework.Unschedulable, "Insufficient cpu or memory on any single node"),
+		},
 	}
 
 	p, _ := NewClusterResourcesFit(nil)
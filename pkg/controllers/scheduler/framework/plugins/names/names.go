@@ -27,4 +27,6 @@ const (
 	ClusterResourcesMostAllocated      = "ClusterResourcesMostAllocated"
 	MaxCluster                         = "MaxCluster"
 	ClusterCapacityWeight              = "ClusterCapacityWeight"
+	TopologySpread                     = "TopologySpread"
+	ClusterHealth                      = "ClusterHealth"
 )
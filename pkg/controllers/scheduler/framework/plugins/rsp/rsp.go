@@ -85,7 +85,11 @@ func (pl *ClusterCapacityWeight) ReplicaScheduling(
 			)
 		}
 
-		schedulingWeights, err = AvailableToPercentage(clusterAvailables, weightLimit)
+		if estimatedReplicas, ok := estimateReplicasByResourceModel(su, clusters); ok {
+			schedulingWeights = EstimatedReplicasToPercentage(estimatedReplicas, weightLimit)
+		} else {
+			schedulingWeights, err = AvailableToPercentage(clusterAvailables, weightLimit)
+		}
 		if err != nil {
 			return clusterReplicasList, framework.NewResult(
 				framework.Error,
@@ -138,6 +142,12 @@ func (pl *ClusterCapacityWeight) ReplicaScheduling(
 		}
 	}
 
+	for cluster, ec := range su.EstimatedCapacity {
+		if existing, exists := estimatedCapacity[cluster]; ec >= 0 && (!exists || ec < existing) {
+			estimatedCapacity[cluster] = ec
+		}
+	}
+
 	scheduleResult, overflow, err := planner.Plan(
 		&planner.ReplicaSchedulingPreference{
 			Clusters: clusterPreferences,
@@ -232,9 +242,7 @@ func AvailableToPercentage(
 		return
 	}
 
-	tmpMemberWeights := make(map[string]int64)
-	sumTmpWeight := int64(0)
-
+	cpuValues := make(map[string]float64, len(clusterAvailables))
 	for member, resources := range clusterAvailables {
 		cpu, ok := resources[corev1.ResourceCPU]
 		if !ok {
@@ -246,8 +254,44 @@ func AvailableToPercentage(
 		if cpuValue < 0.0 {
 			cpuValue = 0.0
 		}
+		cpuValues[member] = cpuValue
+	}
+
+	return valuesToPercentage(cpuValues, sumAvailable, weightLimit), nil
+}
+
+// EstimatedReplicasToPercentage converts the numbers of replicas estimated to fit in each cluster to weights.
+func EstimatedReplicasToPercentage(
+	estimatedReplicas map[string]int64,
+	weightLimit map[string]int64,
+) map[string]int64 {
+	sumReplicas := 0.0
+	values := make(map[string]float64, len(estimatedReplicas))
+	for member, replicas := range estimatedReplicas {
+		values[member] = float64(replicas)
+		sumReplicas += float64(replicas)
+	}
+
+	if sumReplicas == 0 {
+		clusterWeights := make(map[string]int64, len(estimatedReplicas))
+		for member := range estimatedReplicas {
+			clusterWeights[member] = int64(math.Round(sumWeight / float64(len(estimatedReplicas))))
+		}
+		return clusterWeights
+	}
+
+	return valuesToPercentage(values, sumReplicas, weightLimit)
+}
+
+// valuesToPercentage converts the non-negative values of each cluster to weights proportional to them, subject to the
+// weight limits.
+func valuesToPercentage(values map[string]float64, sumValue float64, weightLimit map[string]int64) map[string]int64 {
+	clusterWeights := make(map[string]int64)
+	tmpMemberWeights := make(map[string]int64)
+	sumTmpWeight := int64(0)
 
-		weight := int64(math.Round(cpuValue / sumAvailable * sumWeight))
+	for member, value := range values {
+		weight := int64(math.Round(value / sumValue * sumWeight))
 		if weight > weightLimit[member] {
 			weight = weightLimit[member]
 		}
@@ -268,7 +312,24 @@ func AvailableToPercentage(
 		otherSumWeight += weight
 	}
 	clusterWeights[maxCluster] += int64(sumWeight) - otherSumWeight
-	return
+	return clusterWeights
+}
+
+// estimateReplicasByResourceModel estimates the number of replicas that fit in each cluster. It returns false unless
+// the estimate is available for every cluster.
+func estimateReplicasByResourceModel(
+	su *framework.SchedulingUnit,
+	clusters []*fedcorev1a1.FederatedCluster,
+) (map[string]int64, bool) {
+	estimatedReplicas := make(map[string]int64, len(clusters))
+	for _, cluster := range clusters {
+		replicas, ok := framework.EstimateReplicasByResourceModel(cluster, &su.ResourceRequest)
+		if !ok {
+			return nil, false
+		}
+		estimatedReplicas[cluster.Name] = replicas
+	}
+	return estimatedReplicas, true
 }
 
 // QueryClusterResource aggregate cluster resources, accept available and allocatable.
@@ -532,3 +532,66 @@ func TestMaxReplicas(t *testing.T) {
 		})
 	}
 }
+
+func makeClusterWithResourceModel(name string, nodes, nodeCPU int) *fedcorev1a1.FederatedCluster {
+	cluster := makeClusterWithCPU(name, nodes*nodeCPU, nodes*nodeCPU)
+	cluster.Status.Resources.ResourceModel = []fedcorev1a1.ResourceModelGrade{
+		{
+			Available: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse(strconv.Itoa(nodeCPU)),
+			},
+			Nodes: int64(nodes),
+		},
+	}
+	return cluster
+}
+
+func TestResourceModel(t *testing.T) {
+	tests := []struct {
+		name             string
+		schedulingUnit   framework.SchedulingUnit
+		clusters         []*fedcorev1a1.FederatedCluster
+		expectedReplicas map[string]int64
+	}{
+		{
+			name: "replicas are divided by the number of replicas that fit",
+			schedulingUnit: framework.SchedulingUnit{
+				DesiredReplicas: pointer.Int64(4),
+				SchedulingMode:  fedcorev1a1.SchedulingModeDivide,
+				ResourceRequest: framework.Resource{MilliCPU: 32000},
+			},
+			clusters: []*fedcorev1a1.FederatedCluster{
+				makeClusterWithResourceModel("cluster1", 64, 1),
+				makeClusterWithResourceModel("cluster2", 2, 32),
+			},
+			expectedReplicas: map[string]int64{"cluster2": 4},
+		},
+		{
+			name: "available resources are used without resource models for all clusters",
+			schedulingUnit: framework.SchedulingUnit{
+				DesiredReplicas: pointer.Int64(4),
+				SchedulingMode:  fedcorev1a1.SchedulingModeDivide,
+				ResourceRequest: framework.Resource{MilliCPU: 32000},
+			},
+			clusters: []*fedcorev1a1.FederatedCluster{
+				makeClusterWithCPU("cluster1", 64, 64),
+				makeClusterWithResourceModel("cluster2", 2, 32),
+			},
+			expectedReplicas: map[string]int64{"cluster1": 2, "cluster2": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rspPlugin := &ClusterCapacityWeight{}
+
+			replicasList, res := rspPlugin.ReplicaScheduling(context.Background(), &tt.schedulingUnit, tt.clusters)
+			assert.Equal(t, framework.NewResult(framework.Success), res)
+
+			replicas := map[string]int64{}
+			for _, clusterReplicas := range replicasList {
+				replicas[clusterReplicas.Cluster.Name] = clusterReplicas.Replicas
+			}
+			assert.Equal(t, tt.expectedReplicas, replicas)
+		})
+	}
+}
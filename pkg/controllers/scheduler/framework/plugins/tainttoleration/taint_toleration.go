@@ -23,6 +23,7 @@ package tainttoleration
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -77,7 +78,8 @@ func (pl *TaintToleration) Filter(
 		return t.Effect == corev1.TaintEffectNoSchedule || t.Effect == corev1.TaintEffectNoExecute
 	}
 
-	taint, isUntolerated := framework.FindMatchingUntoleratedTaint(taints, tolerations, filterPredicate)
+	// NoExecute taints tolerated for a limited time evict the object once the toleration expires
+	taint, isUntolerated := framework.FindMatchingUntoleratedTaintAt(taints, tolerations, filterPredicate, time.Now())
 	if !isUntolerated {
 		return framework.NewResult(framework.Success)
 	}
@@ -24,9 +24,11 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
@@ -360,6 +362,14 @@ func TestTaintTolerationScore(t *testing.T) {
 }
 
 func TestTaintTolerationFilter(t *testing.T) {
+	recentlyAdded := metav1.NewTime(time.Now().Add(-time.Minute))
+	addedLongAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+	scheduledSU := func(tolerations []corev1.Toleration) *framework.SchedulingUnit {
+		su := suWithTolerations("su1", tolerations)
+		su.CurrentClusters = map[string]*int64{"clusterA": nil}
+		return su
+	}
+
 	tests := []struct {
 		name       string
 		su         *framework.SchedulingUnit
@@ -465,6 +475,51 @@ func TestTaintTolerationFilter(t *testing.T) {
 			),
 			wantResult: framework.NewResult(framework.Success),
 		},
+		{
+			name: "The schedulingUnit is scheduled onto the cluster and ignores its NoSchedule taint",
+			su:   scheduledSU([]corev1.Toleration{}),
+			cluster: clusterWithTaints(
+				"clusterA",
+				[]corev1.Taint{{Key: "dedicated", Value: "user1", Effect: "NoSchedule"}},
+			),
+			wantResult: framework.NewResult(framework.Success),
+		},
+		{
+			name: "The schedulingUnit is scheduled onto the cluster and tolerates its NoExecute taint " +
+				"until the toleration seconds have passed",
+			su: scheduledSU([]corev1.Toleration{
+				{Key: "unreachable", Operator: "Exists", Effect: "NoExecute", TolerationSeconds: pointer.Int64(300)},
+			}),
+			cluster: clusterWithTaints(
+				"clusterA",
+				[]corev1.Taint{{Key: "unreachable", Effect: "NoExecute", TimeAdded: &recentlyAdded}},
+			),
+			wantResult: framework.NewResult(framework.Success),
+		},
+		{
+			name: "The schedulingUnit is scheduled onto the cluster and no longer tolerates its NoExecute taint " +
+				"after the toleration seconds have passed",
+			su: scheduledSU([]corev1.Toleration{
+				{Key: "unreachable", Operator: "Exists", Effect: "NoExecute", TolerationSeconds: pointer.Int64(300)},
+			}),
+			cluster: clusterWithTaints(
+				"clusterA",
+				[]corev1.Taint{{Key: "unreachable", Effect: "NoExecute", TimeAdded: &addedLongAgo}},
+			),
+			wantResult: framework.NewResult(framework.Unschedulable,
+				"cluster(s) had taint {unreachable: }, that the schedulingUnit didn't tolerate"),
+		},
+		{
+			name: "The schedulingUnit tolerates the NoExecute taint indefinitely without toleration seconds",
+			su: scheduledSU([]corev1.Toleration{
+				{Key: "unreachable", Operator: "Exists", Effect: "NoExecute"},
+			}),
+			cluster: clusterWithTaints(
+				"clusterA",
+				[]corev1.Taint{{Key: "unreachable", Effect: "NoExecute", TimeAdded: &addedLongAgo}},
+			),
+			wantResult: framework.NewResult(framework.Success),
+		},
 	}
 
 	p, _ := NewTaintToleration(nil)
@@ -0,0 +1,257 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologyspread
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/maxcluster"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/names"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/rsp"
+)
+
+// TopologySpread spreads scheduling units with a topology spread constraint across the topology domains of clusters.
+// It selects clusters from each domain in turn, and in Divide mode splits the replicas between the domains before
+// dividing each domain's replicas between its clusters by cluster capacity.
+type TopologySpread struct {
+	replicasPlugin framework.ReplicasPlugin
+}
+
+var (
+	_ framework.SelectPlugin   = &TopologySpread{}
+	_ framework.ReplicasPlugin = &TopologySpread{}
+)
+
+func NewTopologySpread(handle framework.Handle) (framework.Plugin, error) {
+	replicasPlugin, err := rsp.NewClusterCapacityWeight(handle)
+	if err != nil {
+		return nil, err
+	}
+	return &TopologySpread{replicasPlugin: replicasPlugin.(framework.ReplicasPlugin)}, nil
+}
+
+func (pl *TopologySpread) Name() string {
+	return names.TopologySpread
+}
+
+func (pl *TopologySpread) SelectClusters(
+	ctx context.Context,
+	su *framework.SchedulingUnit,
+	clusterScoreList framework.ClusterScoreList,
+) ([]*fedcorev1a1.FederatedCluster, *framework.Result) {
+	constraint := su.TopologySpread
+	if constraint == nil {
+		return nil, framework.NewResult(framework.Skip)
+	}
+
+	clusters := make([]*fedcorev1a1.FederatedCluster, 0)
+	if su.MaxClusters != nil && *su.MaxClusters < 0 {
+		return clusters, framework.NewResult(framework.Unschedulable, maxcluster.MaxClusterErrReason)
+	}
+
+	clusterScores := make(framework.ClusterScoreList, 0, len(clusterScoreList))
+	for _, clusterScore := range clusterScoreList {
+		if _, exists := clusterScore.Cluster.Labels[constraint.TopologyKey]; exists {
+			clusterScores = append(clusterScores, clusterScore)
+		}
+	}
+	sort.SliceStable(clusterScores, func(i, j int) bool {
+		return clusterScores[i].Score > clusterScores[j].Score
+	})
+
+	// Domains are ordered by the score of their best cluster.
+	domains, clustersByDomain := groupClustersByDomain(constraint.TopologyKey, clusterScores)
+
+	length := len(clusterScores)
+	if su.MaxClusters != nil && int(*su.MaxClusters) < length {
+		length = int(*su.MaxClusters)
+	}
+
+	selectedDomains := len(domains)
+	if length < selectedDomains {
+		selectedDomains = length
+	}
+	if int64(selectedDomains) < constraint.MinDomains {
+		return clusters, framework.NewResult(
+			framework.Unschedulable,
+			fmt.Sprintf(
+				"%d topology domains of %q can be selected, but at least %d are required",
+				selectedDomains, constraint.TopologyKey, constraint.MinDomains,
+			),
+		)
+	}
+
+	// Select the best remaining cluster of each domain in turn, so that the clusters are spread evenly across domains.
+	for round := 0; len(clusters) < length; round++ {
+		for _, domain := range domains {
+			if len(clusters) == length {
+				break
+			}
+			if domainClusters := clustersByDomain[domain]; round < len(domainClusters) {
+				clusters = append(clusters, domainClusters[round])
+			}
+		}
+	}
+
+	return clusters, framework.NewResult(framework.Success)
+}
+
+func (pl *TopologySpread) ReplicaScheduling(
+	ctx context.Context,
+	su *framework.SchedulingUnit,
+	clusters []*fedcorev1a1.FederatedCluster,
+) (framework.ClusterReplicasList, *framework.Result) {
+	constraint := su.TopologySpread
+	if constraint == nil || su.SchedulingMode != fedcorev1a1.SchedulingModeDivide {
+		return nil, framework.NewResult(framework.Skip)
+	}
+
+	clusterScores := make(framework.ClusterScoreList, 0, len(clusters))
+	for _, cluster := range clusters {
+		if _, exists := cluster.Labels[constraint.TopologyKey]; exists {
+			clusterScores = append(clusterScores, framework.ClusterScore{Cluster: cluster})
+		}
+	}
+	domains, clustersByDomain := groupClustersByDomain(constraint.TopologyKey, clusterScores)
+	sort.Strings(domains)
+
+	totalReplicas := int64(0)
+	if su.DesiredReplicas != nil {
+		totalReplicas = *su.DesiredReplicas
+	}
+	domainReplicas := getDomainReplicas(su, constraint, domains, clustersByDomain, totalReplicas)
+
+	clusterReplicasList := make(framework.ClusterReplicasList, 0, len(clusters))
+	for _, domain := range domains {
+		replicas := domainReplicas[domain]
+		if replicas == 0 {
+			continue
+		}
+
+		domainClusters := clustersByDomain[domain]
+		domainSU := *su
+		domainSU.DesiredReplicas = &replicas
+		domainSU.CurrentClusters = make(map[string]*int64, len(domainClusters))
+		for _, cluster := range domainClusters {
+			if currentReplicas, exists := su.CurrentClusters[cluster.Name]; exists {
+				domainSU.CurrentClusters[cluster.Name] = currentReplicas
+			}
+		}
+
+		domainClusterReplicasList, result := pl.replicasPlugin.ReplicaScheduling(ctx, &domainSU, domainClusters)
+		if !result.IsSuccess() {
+			return nil, result
+		}
+		clusterReplicasList = append(clusterReplicasList, domainClusterReplicasList...)
+	}
+
+	return clusterReplicasList, framework.NewResult(framework.Success)
+}
+
+// groupClustersByDomain groups the clusters by the value of their topology key label. The domains are returned in the
+// order of their first cluster.
+func groupClustersByDomain(
+	topologyKey string,
+	clusterScores framework.ClusterScoreList,
+) ([]string, map[string][]*fedcorev1a1.FederatedCluster) {
+	domains := []string{}
+	clustersByDomain := map[string][]*fedcorev1a1.FederatedCluster{}
+	for _, clusterScore := range clusterScores {
+		domain := clusterScore.Cluster.Labels[topologyKey]
+		if _, exists := clustersByDomain[domain]; !exists {
+			domains = append(domains, domain)
+		}
+		clustersByDomain[domain] = append(clustersByDomain[domain], clusterScore.Cluster)
+	}
+	return domains, clustersByDomain
+}
+
+// getDomainReplicas divides the replicas between the domains. If disruption should be avoided, the current
+// distribution is kept as long as its skew does not exceed the constraint's MaxSkew. Otherwise, the replicas are
+// divided evenly.
+func getDomainReplicas(
+	su *framework.SchedulingUnit,
+	constraint *fedcorev1a1.TopologySpreadConstraint,
+	domains []string,
+	clustersByDomain map[string][]*fedcorev1a1.FederatedCluster,
+	totalReplicas int64,
+) map[string]int64 {
+	currentReplicas := make(map[string]int64, len(domains))
+	currentTotalReplicas := int64(0)
+	for _, domain := range domains {
+		for _, cluster := range clustersByDomain[domain] {
+			replicas, exists := su.CurrentClusters[cluster.Name]
+			if !exists {
+				continue
+			}
+			// A nil value means the cluster has the full replicas of the template.
+			if replicas == nil {
+				replicas = &totalReplicas
+			}
+			currentReplicas[domain] += *replicas
+			currentTotalReplicas += *replicas
+		}
+	}
+
+	maxSkew := constraint.MaxSkew
+	if maxSkew < 1 {
+		maxSkew = 1
+	}
+	if su.AvoidDisruption && currentTotalReplicas == totalReplicas && getSkew(domains, currentReplicas) <= maxSkew {
+		return currentReplicas
+	}
+
+	domainReplicas := make(map[string]int64, len(domains))
+	if len(domains) == 0 {
+		return domainReplicas
+	}
+
+	// The remainder goes to the domains with the most current replicas to minimize disruption.
+	orderedDomains := append([]string{}, domains...)
+	sort.SliceStable(orderedDomains, func(i, j int) bool {
+		return currentReplicas[orderedDomains[i]] > currentReplicas[orderedDomains[j]]
+	})
+	base, remainder := totalReplicas/int64(len(domains)), totalReplicas%int64(len(domains))
+	for i, domain := range orderedDomains {
+		domainReplicas[domain] = base
+		if int64(i) < remainder {
+			domainReplicas[domain]++
+		}
+	}
+	return domainReplicas
+}
+
+func getSkew(domains []string, replicas map[string]int64) int64 {
+	if len(domains) == 0 {
+		return 0
+	}
+
+	min, max := replicas[domains[0]], replicas[domains[0]]
+	for _, domain := range domains[1:] {
+		if replicas[domain] < min {
+			min = replicas[domain]
+		}
+		if replicas[domain] > max {
+			max = replicas[domain]
+		}
+	}
+	return max - min
+}
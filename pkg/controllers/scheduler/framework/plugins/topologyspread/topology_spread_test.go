@@ -0,0 +1,249 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologyspread
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+)
+
+const regionLabel = "topology.kubernetes.io/region"
+
+func makeCluster(clusterName, region string) *fedcorev1a1.FederatedCluster {
+	cluster := &fedcorev1a1.FederatedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+	}
+	if region != "" {
+		cluster.Labels = map[string]string{regionLabel: region}
+	}
+	return cluster
+}
+
+func newTopologySpread(t *testing.T) *TopologySpread {
+	pl, err := NewTopologySpread(nil)
+	if err != nil {
+		t.Fatalf("failed to create plugin: %v", err)
+	}
+	return pl.(*TopologySpread)
+}
+
+func getClusterNames(clusters []*fedcorev1a1.FederatedCluster) []string {
+	names := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		names = append(names, cluster.Name)
+	}
+	return names
+}
+
+func TestTopologySpreadSelectClusters(t *testing.T) {
+	clusterScoreList := framework.ClusterScoreList{
+		{Cluster: makeCluster("us-1", "us"), Score: 100},
+		{Cluster: makeCluster("us-2", "us"), Score: 90},
+		{Cluster: makeCluster("us-3", "us"), Score: 80},
+		{Cluster: makeCluster("eu-1", "eu"), Score: 70},
+		{Cluster: makeCluster("eu-2", "eu"), Score: 60},
+		{Cluster: makeCluster("ap-1", "ap"), Score: 50},
+		{Cluster: makeCluster("none", ""), Score: 200},
+	}
+
+	tests := []struct {
+		name             string
+		su               *framework.SchedulingUnit
+		expectedClusters []string
+		expectedResult   *framework.Result
+	}{
+		{
+			name:           "no constraint is skipped",
+			su:             &framework.SchedulingUnit{},
+			expectedResult: framework.NewResult(framework.Skip),
+		},
+		{
+			name: "clusters are selected from each domain in turn",
+			su: &framework.SchedulingUnit{
+				MaxClusters:    pointer.Int64(4),
+				TopologySpread: &fedcorev1a1.TopologySpreadConstraint{TopologyKey: regionLabel},
+			},
+			expectedClusters: []string{"us-1", "eu-1", "ap-1", "us-2"},
+			expectedResult:   framework.NewResult(framework.Success),
+		},
+		{
+			name: "all labeled clusters are selected without max clusters",
+			su: &framework.SchedulingUnit{
+				TopologySpread: &fedcorev1a1.TopologySpreadConstraint{TopologyKey: regionLabel},
+			},
+			expectedClusters: []string{"us-1", "eu-1", "ap-1", "us-2", "eu-2", "us-3"},
+			expectedResult:   framework.NewResult(framework.Success),
+		},
+		{
+			name: "min domains is satisfied",
+			su: &framework.SchedulingUnit{
+				MaxClusters:    pointer.Int64(3),
+				TopologySpread: &fedcorev1a1.TopologySpreadConstraint{TopologyKey: regionLabel, MinDomains: 3},
+			},
+			expectedClusters: []string{"us-1", "eu-1", "ap-1"},
+			expectedResult:   framework.NewResult(framework.Success),
+		},
+		{
+			name: "min domains is not satisfied because of max clusters",
+			su: &framework.SchedulingUnit{
+				MaxClusters:    pointer.Int64(2),
+				TopologySpread: &fedcorev1a1.TopologySpreadConstraint{TopologyKey: regionLabel, MinDomains: 3},
+			},
+			expectedClusters: []string{},
+			expectedResult: framework.NewResult(
+				framework.Unschedulable,
+				`2 topology domains of "topology.kubernetes.io/region" can be selected, but at least 3 are required`,
+			),
+		},
+		{
+			name: "min domains is not satisfied",
+			su: &framework.SchedulingUnit{
+				TopologySpread: &fedcorev1a1.TopologySpreadConstraint{TopologyKey: regionLabel, MinDomains: 4},
+			},
+			expectedClusters: []string{},
+			expectedResult: framework.NewResult(
+				framework.Unschedulable,
+				`3 topology domains of "topology.kubernetes.io/region" can be selected, but at least 4 are required`,
+			),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pl := newTopologySpread(t)
+			clusters, result := pl.SelectClusters(context.Background(), test.su, clusterScoreList)
+			assert.Equal(t, test.expectedResult, result)
+			if test.expectedClusters != nil {
+				assert.Equal(t, test.expectedClusters, getClusterNames(clusters))
+			}
+		})
+	}
+}
+
+func TestTopologySpreadReplicaScheduling(t *testing.T) {
+	clusters := []*fedcorev1a1.FederatedCluster{
+		makeCluster("us-1", "us"),
+		makeCluster("us-2", "us"),
+		makeCluster("eu-1", "eu"),
+		makeCluster("ap-1", "ap"),
+		makeCluster("none", ""),
+	}
+
+	tests := []struct {
+		name             string
+		su               *framework.SchedulingUnit
+		expectedReplicas map[string]int64
+		expectedResult   *framework.Result
+	}{
+		{
+			name: "no constraint is skipped",
+			su: &framework.SchedulingUnit{
+				SchedulingMode:  fedcorev1a1.SchedulingModeDivide,
+				DesiredReplicas: pointer.Int64(12),
+			},
+			expectedResult: framework.NewResult(framework.Skip),
+		},
+		{
+			name: "duplicate mode is skipped",
+			su: &framework.SchedulingUnit{
+				SchedulingMode: fedcorev1a1.SchedulingModeDuplicate,
+				TopologySpread: &fedcorev1a1.TopologySpreadConstraint{TopologyKey: regionLabel, MaxSkew: 1},
+			},
+			expectedResult: framework.NewResult(framework.Skip),
+		},
+		{
+			name: "replicas are divided evenly between domains",
+			su: &framework.SchedulingUnit{
+				SchedulingMode:  fedcorev1a1.SchedulingModeDivide,
+				DesiredReplicas: pointer.Int64(12),
+				TopologySpread:  &fedcorev1a1.TopologySpreadConstraint{TopologyKey: regionLabel, MaxSkew: 1},
+			},
+			expectedReplicas: map[string]int64{"us-1": 2, "us-2": 2, "eu-1": 4, "ap-1": 4},
+			expectedResult:   framework.NewResult(framework.Success),
+		},
+		{
+			name: "remainder goes to the domains with the most current replicas",
+			su: &framework.SchedulingUnit{
+				SchedulingMode:  fedcorev1a1.SchedulingModeDivide,
+				DesiredReplicas: pointer.Int64(4),
+				CurrentClusters: map[string]*int64{"eu-1": pointer.Int64(3)},
+				TopologySpread:  &fedcorev1a1.TopologySpreadConstraint{TopologyKey: regionLabel, MaxSkew: 1},
+			},
+			expectedReplicas: map[string]int64{"us-2": 1, "eu-1": 2, "ap-1": 1},
+			expectedResult:   framework.NewResult(framework.Success),
+		},
+		{
+			name: "current distribution within max skew is kept when avoiding disruption",
+			su: &framework.SchedulingUnit{
+				SchedulingMode:  fedcorev1a1.SchedulingModeDivide,
+				DesiredReplicas: pointer.Int64(12),
+				AvoidDisruption: true,
+				CurrentClusters: map[string]*int64{
+					"us-1": pointer.Int64(5),
+					"eu-1": pointer.Int64(4),
+					"ap-1": pointer.Int64(3),
+				},
+				TopologySpread: &fedcorev1a1.TopologySpreadConstraint{TopologyKey: regionLabel, MaxSkew: 2},
+			},
+			expectedReplicas: map[string]int64{"us-1": 5, "eu-1": 4, "ap-1": 3},
+			expectedResult:   framework.NewResult(framework.Success),
+		},
+		{
+			name: "current distribution exceeding max skew is rebalanced",
+			su: &framework.SchedulingUnit{
+				SchedulingMode:  fedcorev1a1.SchedulingModeDivide,
+				DesiredReplicas: pointer.Int64(12),
+				AvoidDisruption: true,
+				CurrentClusters: map[string]*int64{
+					"us-1": pointer.Int64(8),
+					"eu-1": pointer.Int64(4),
+				},
+				TopologySpread: &fedcorev1a1.TopologySpreadConstraint{TopologyKey: regionLabel, MaxSkew: 1},
+			},
+			expectedReplicas: map[string]int64{"us-1": 4, "eu-1": 4, "ap-1": 4},
+			expectedResult:   framework.NewResult(framework.Success),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pl := newTopologySpread(t)
+			clusterReplicasList, result := pl.ReplicaScheduling(context.Background(), test.su, clusters)
+			assert.Equal(t, test.expectedResult, result)
+			if test.expectedReplicas == nil {
+				return
+			}
+
+			replicas := map[string]int64{}
+			for _, clusterReplicas := range clusterReplicasList {
+				if clusterReplicas.Replicas > 0 {
+					replicas[clusterReplicas.Cluster.Name] = clusterReplicas.Replicas
+				}
+			}
+			assert.Equal(t, test.expectedReplicas, replicas)
+		})
+	}
+}
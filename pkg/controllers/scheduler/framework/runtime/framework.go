@@ -119,7 +119,7 @@ func (f *frameworkImpl) RunFilterPlugins(
 	for _, pl := range f.filterPlugins {
 		pluginResult := f.runFilterPlugin(ctx, pl, schedulingUnit, cluster)
 		if !pluginResult.IsSuccess() {
-			return pluginResult
+			return pluginResult.WithFailedPlugin(pl.Name())
 		}
 	}
 	return framework.NewResult(framework.Success)
@@ -185,14 +185,11 @@ func (f *frameworkImpl) RunSelectClustersPlugin(
 	schedulingUnit *framework.SchedulingUnit,
 	clusterScores framework.ClusterScoreList,
 ) (clusters []*fedcorev1a1.FederatedCluster, result *framework.Result) {
-	if len(f.selectPlugins) == 0 {
-		for _, clusterScore := range clusterScores {
-			clusters = append(clusters, clusterScore.Cluster)
-		}
-		result = framework.NewResult(framework.Success)
-	}
 	for _, plugin := range f.selectPlugins {
 		clusters, result = plugin.SelectClusters(ctx, schedulingUnit, clusterScores)
+		if result.IsSkip() {
+			continue
+		}
 		if !result.IsSuccess() {
 			msg := fmt.Sprintf(
 				"plugin %q failed to select clusters for schedulingUnit %s: %v",
@@ -205,7 +202,13 @@ func (f *frameworkImpl) RunSelectClustersPlugin(
 		}
 		return clusters, result
 	}
-	return clusters, result
+
+	// Select all clusters if no plugin applies to the scheduling unit.
+	clusters = nil
+	for _, clusterScore := range clusterScores {
+		clusters = append(clusters, clusterScore.Cluster)
+	}
+	return clusters, framework.NewResult(framework.Success)
 }
 
 func (f *frameworkImpl) RunReplicasPlugin(
@@ -225,14 +228,11 @@ func (f *frameworkImpl) RunReplicasPlugin(
 			"unnecessary to schedule due to replicas less or equal zero",
 		)
 	}
-	if len(f.replicasPlugins) == 0 {
-		return clusterReplicasList, framework.NewResult(
-			framework.Success,
-			"no replicas plugin registered in the framework",
-		)
-	}
 	for _, plugin := range f.replicasPlugins {
 		clusterReplicasList, result = plugin.ReplicaScheduling(ctx, schedulingUnit, clusters)
+		if result.IsSkip() {
+			continue
+		}
 		if !result.IsSuccess() {
 			msg := fmt.Sprintf(
 				"plugin %q failed to replica scheduling for schedulingUnit %s: %v",
@@ -245,5 +245,8 @@ func (f *frameworkImpl) RunReplicasPlugin(
 		}
 		return clusterReplicasList, result
 	}
-	return clusterReplicasList, result
+	return nil, framework.NewResult(
+		framework.Success,
+		"no applicable replicas plugin registered in the framework",
+	)
 }
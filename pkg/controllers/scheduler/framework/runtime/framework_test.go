@@ -161,7 +161,7 @@ func TestRunFilterPlugins(t *testing.T) {
 				"a": getNaiveFilterPluginFactory(false),
 			},
 			&fedcore.EnabledPlugins{FilterPlugins: []string{"a"}},
-			framework.NewResult(framework.Error),
+			framework.NewResult(framework.Error).WithFailedPlugin("NaiveFilterPlugin"),
 		},
 		{
 			"multiple filter plugins, all succeed",
@@ -181,7 +181,7 @@ func TestRunFilterPlugins(t *testing.T) {
 				"c": getNaiveFilterPluginFactory(true),
 			},
 			&fedcore.EnabledPlugins{FilterPlugins: []string{"a", "b", "c"}},
-			framework.NewResult(framework.Error),
+			framework.NewResult(framework.Error).WithFailedPlugin("NaiveFilterPlugin"),
 		},
 		{
 			"multiple filter plugins, none succeed",
@@ -191,7 +191,7 @@ func TestRunFilterPlugins(t *testing.T) {
 				"c": getNaiveFilterPluginFactory(false),
 			},
 			&fedcore.EnabledPlugins{FilterPlugins: []string{"a", "b", "c"}},
-			framework.NewResult(framework.Error),
+			framework.NewResult(framework.Error).WithFailedPlugin("NaiveFilterPlugin"),
 		},
 	}
 
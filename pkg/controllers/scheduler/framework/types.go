@@ -66,6 +66,45 @@ type SchedulingUnit struct {
 	MinReplicas     map[string]int64
 	MaxReplicas     map[string]int64
 	Weights         map[string]int64
+	TopologySpread  *fedcorev1a1.TopologySpreadConstraint
+
+	// EstimatedCapacity is the number of replicas each cluster can hold according to its replica estimator.
+	EstimatedCapacity map[string]int64
+
+	// Priority is the scheduling priority of the unit.
+	Priority int32
+	// Preemption contains the information required to preempt the replicas of units with a lower priority. It is nil
+	// if the unit may not preempt other units.
+	Preemption *PreemptionSpec
+}
+
+// PreemptionSpec contains the information required to preempt the replicas of units with a lower priority.
+type PreemptionSpec struct {
+	// ResourceRequest is the resource request of a single replica of the unit.
+	ResourceRequest Resource
+	// Candidates are the units with a lower priority whose replicas may be preempted.
+	Candidates []PreemptionCandidate
+	// PendingClusters are the clusters where replicas preempted by the unit have not been released yet. No more
+	// replicas are preempted in these clusters until the pending preemptions complete.
+	PendingClusters map[string]struct{}
+}
+
+// PreemptionCandidate is a unit with a lower priority whose replicas may be preempted.
+type PreemptionCandidate struct {
+	Namespace string
+	Name      string
+	Priority  int32
+	// ResourceRequest is the resource request of a single replica of the candidate.
+	ResourceRequest Resource
+	// Replicas is the number of replicas of the candidate in each cluster.
+	Replicas map[string]int64
+}
+
+func (c *PreemptionCandidate) Key() string {
+	if len(c.Namespace) > 0 {
+		return c.Namespace + "/" + c.Name
+	}
+	return c.Name
 }
 
 type AutoMigrationSpec struct {
@@ -150,6 +189,8 @@ type Result struct {
 	code    Code
 	reasons []string
 	err     error
+	// failedPlugin is the name of the plugin that returned the result when it is not a success.
+	failedPlugin string
 }
 
 // Code is the Status code/type which is returned from plugins.
@@ -165,6 +206,9 @@ const (
 	Unschedulable
 	// Error is used for internal plugin errors, unexpected input, etc.
 	Error
+	// Skip is used when a select or replicas plugin does not apply to the scheduling unit.
+	// The next plugin at the same extension point will be run instead.
+	Skip
 )
 
 // NewResult makes a result out of the given arguments and returns its pointer.
@@ -184,6 +228,11 @@ func (s *Result) IsSuccess() bool {
 	return s == nil || s.code == Success
 }
 
+// IsSkip returns true if and only if "Result" is not nil and Code is "Skip".
+func (s *Result) IsSkip() bool {
+	return s != nil && s.code == Skip
+}
+
 // Message returns a concatenated message on reasons of the Status.
 func (s *Result) Message() string {
 	if s == nil {
@@ -192,6 +241,31 @@ func (s *Result) Message() string {
 	return strings.Join(s.reasons, ", ")
 }
 
+// Reasons returns the reasons of the Result.
+func (s *Result) Reasons() []string {
+	if s == nil {
+		return nil
+	}
+	return s.reasons
+}
+
+// FailedPlugin returns the name of the plugin that failed, if it is known.
+func (s *Result) FailedPlugin() string {
+	if s == nil {
+		return ""
+	}
+	return s.failedPlugin
+}
+
+// WithFailedPlugin sets the name of the plugin that returned the Result and returns the Result itself.
+func (s *Result) WithFailedPlugin(plugin string) *Result {
+	if s.IsSuccess() {
+		return s
+	}
+	s.failedPlugin = plugin
+	return s
+}
+
 // AsError returns nil if the Result is a success; otherwise returns an "error" object
 // with a concatenated message on reasons of the Result.
 func (s *Result) AsError() error {
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -345,6 +346,35 @@ func GetNonzeroRequestForResource(resource corev1.ResourceName, requests *corev1
 	return 0
 }
 
+// EstimateReplicasByResourceModel estimates how many replicas with the given resource request fit in the cluster
+// according to the resource model in its status. It returns false if the cluster does not publish a resource model
+// or the request has neither cpu nor memory.
+func EstimateReplicasByResourceModel(cluster *fedcorev1a1.FederatedCluster, request *Resource) (int64, bool) {
+	model := cluster.Status.Resources.ResourceModel
+	if len(model) == 0 || (request.MilliCPU <= 0 && request.Memory <= 0) {
+		return 0, false
+	}
+
+	replicas := int64(0)
+	for _, grade := range model {
+		replicasPerNode := int64(math.MaxInt64)
+		if request.MilliCPU > 0 {
+			if cpuReplicas := grade.Available.Cpu().MilliValue() / request.MilliCPU; cpuReplicas < replicasPerNode {
+				replicasPerNode = cpuReplicas
+			}
+		}
+		if request.Memory > 0 {
+			if memoryReplicas := grade.Available.Memory().Value() / request.Memory; memoryReplicas < replicasPerNode {
+				replicasPerNode = memoryReplicas
+			}
+		}
+		if replicasPerNode > 0 {
+			replicas += replicasPerNode * grade.Nodes
+		}
+	}
+	return replicas, true
+}
+
 func PreCheck(ctx context.Context, su *SchedulingUnit, cluster *fedcorev1a1.FederatedCluster) error {
 	if su == nil {
 		return fmt.Errorf("invalid scheduling unit")
@@ -412,6 +442,37 @@ func TolerationsTolerateTaint(tolerations []corev1.Toleration, taint *corev1.Tai
 	return false
 }
 
+// GetTaintTolerationExpiry returns whether the taint is tolerated by any of the tolerations, and the time at which it
+// stops being tolerated. The expiry is nil if the taint is tolerated indefinitely. Only NoExecute taints that record the
+// time they were added can expire, after the longest TolerationSeconds of the matching tolerations.
+func GetTaintTolerationExpiry(tolerations []corev1.Toleration, taint *corev1.Taint) (*time.Time, bool) {
+	var expiry *time.Time
+	tolerated := false
+	for i := range tolerations {
+		if !tolerations[i].ToleratesTaint(taint) {
+			continue
+		}
+		tolerated = true
+
+		seconds := tolerations[i].TolerationSeconds
+		if taint.Effect != corev1.TaintEffectNoExecute || seconds == nil || taint.TimeAdded == nil {
+			return nil, true
+		}
+		tolerationExpiry := taint.TimeAdded.Add(time.Duration(*seconds) * time.Second)
+		if expiry == nil || tolerationExpiry.After(*expiry) {
+			expiry = &tolerationExpiry
+		}
+	}
+	return expiry, tolerated
+}
+
+// TolerationsTolerateTaintAt checks if taint is tolerated by any of the tolerations at the given time, taking the
+// TolerationSeconds of the tolerations into account.
+func TolerationsTolerateTaintAt(tolerations []corev1.Toleration, taint *corev1.Taint, now time.Time) bool {
+	expiry, tolerated := GetTaintTolerationExpiry(tolerations, taint)
+	return tolerated && (expiry == nil || now.Before(*expiry))
+}
+
 type taintsFilterFunc func(*corev1.Taint) bool
 
 // FindMatchingUntoleratedTaint checks if the given tolerations tolerates
@@ -433,6 +494,24 @@ func FindMatchingUntoleratedTaint(
 	return corev1.Taint{}, false
 }
 
+// FindMatchingUntoleratedTaintAt is like FindMatchingUntoleratedTaint, except that tolerations of NoExecute taints
+// with TolerationSeconds no longer tolerate the taints once the seconds have passed since the taints were added.
+func FindMatchingUntoleratedTaintAt(
+	taints []corev1.Taint,
+	tolerations []corev1.Toleration,
+	inclusionFilter taintsFilterFunc,
+	now time.Time,
+) (corev1.Taint, bool) {
+	filteredTaints := getFilteredTaints(taints, inclusionFilter)
+	for _, taint := range filteredTaints {
+		taint := taint
+		if !TolerationsTolerateTaintAt(tolerations, &taint, now) {
+			return taint, true
+		}
+	}
+	return corev1.Taint{}, false
+}
+
 // getFilteredTaints returns a list of taints satisfying the filter predicate
 func getFilteredTaints(taints []corev1.Taint, inclusionFilter taintsFilterFunc) []corev1.Taint {
 	if inclusionFilter == nil {
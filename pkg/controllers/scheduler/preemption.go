@@ -0,0 +1,430 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/core"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	annotationutil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/annotation"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/replicaestimator"
+)
+
+/*
+Preemption allows a federated object with a higher priority to take capacity held by federated objects with a lower
+priority and the same federated type.
+
+When the replicas assigned to a preemptor in a cluster exceed the cluster's estimated capacity, the generic scheduler
+chooses victims with a lower priority in that cluster. The preemptor records the victims in its
+PreemptionVictimsAnnotation, and each victim records the preemption in its PreemptedReplicasAnnotation. The victim's
+replicas in the cluster are capped for as long as the preemptor still records the preemption, which it does until it
+no longer has replicas in the cluster. Changes to the preemptor's records re-enqueue its victims.
+*/
+
+// preemptionSpecForFedObject returns the information required by the scheduling unit to preempt the replicas of
+// federated objects with a lower priority, or nil if it may not preempt.
+func (s *Scheduler) preemptionSpecForFedObject(
+	ctx context.Context,
+	fedObject *unstructured.Unstructured,
+	policy fedcorev1a1.GenericPropagationPolicy,
+	su *framework.SchedulingUnit,
+) *framework.PreemptionSpec {
+	if su.SchedulingMode != fedcorev1a1.SchedulingModeDivide ||
+		getPreemptionPolicyFromPolicy(policy) == fedcorev1a1.PreemptNever {
+		return nil
+	}
+	// Preemption only takes place in clusters that may be unable to hold the replicas assigned to them. Without
+	// estimated capacity we are unable to tell whether the clusters lack capacity.
+	shortClusters := clustersShortOfCapacity(su)
+	if len(shortClusters) == 0 {
+		return nil
+	}
+
+	keyedLogger := klog.FromContext(ctx)
+
+	resourceRequest, err := s.getReplicaResourceRequest(fedObject)
+	if err != nil {
+		keyedLogger.Error(err, "Failed to get resource request for preemption")
+		return nil
+	}
+
+	// Only objects placed in the clusters short of capacity can be preempted.
+	fedObjects := make(map[types.UID]*unstructured.Unstructured)
+	for cluster := range shortClusters {
+		objs, err := s.federatedObjectIndexer.ByIndex(placedClusterIndex, cluster)
+		if err != nil {
+			keyedLogger.Error(err, "Failed to list federated objects for preemption", "cluster", cluster)
+			return nil
+		}
+		for _, obj := range objs {
+			candidate := obj.(*unstructured.Unstructured)
+			fedObjects[candidate.GetUID()] = candidate
+		}
+	}
+
+	spec := &framework.PreemptionSpec{
+		ResourceRequest: *resourceRequest,
+		PendingClusters: map[string]struct{}{},
+	}
+	preemptorKey := common.NewQualifiedName(fedObject).String()
+	for _, candidate := range fedObjects {
+		if candidate.GetUID() == fedObject.GetUID() || candidate.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		policyKey, found := MatchedPolicyKey(candidate, s.typeConfig.GetNamespaced())
+		if !found {
+			continue
+		}
+		candidatePolicy, err := s.policyFromStore(policyKey)
+		if err != nil {
+			continue
+		}
+
+		priority := getPriorityFromPolicy(candidatePolicy)
+		if priorityOverride, exists := getPriorityFromObject(candidate); exists {
+			priority = priorityOverride
+		}
+		if priority >= su.Priority {
+			continue
+		}
+
+		currentReplicas, err := getCurrentReplicasFromObject(s.typeConfig, candidate)
+		if err != nil {
+			continue
+		}
+		replicas := make(map[string]int64, len(currentReplicas))
+		for cluster, r := range currentReplicas {
+			if _, short := shortClusters[cluster]; short && r != nil && *r > 0 {
+				replicas[cluster] = *r
+			}
+		}
+		// replicas that were already preempted cannot be preempted again
+		for _, preempted := range s.getPreemptedReplicas(candidate) {
+			if r, exists := replicas[preempted.Cluster]; exists && r > preempted.MaxReplicas {
+				replicas[preempted.Cluster] = preempted.MaxReplicas
+				if preempted.Preemptor == preemptorKey {
+					spec.PendingClusters[preempted.Cluster] = struct{}{}
+				}
+			}
+		}
+		if len(replicas) == 0 {
+			continue
+		}
+
+		candidateRequest, err := s.getReplicaResourceRequest(candidate)
+		if err != nil {
+			continue
+		}
+
+		spec.Candidates = append(spec.Candidates, framework.PreemptionCandidate{
+			Namespace:       candidate.GetNamespace(),
+			Name:            candidate.GetName(),
+			Priority:        priority,
+			ResourceRequest: *candidateRequest,
+			Replicas:        replicas,
+		})
+	}
+
+	// Sort candidates for a deterministic scheduling explanation, the generic scheduler sorts them by priority.
+	sort.Slice(spec.Candidates, func(i, j int) bool {
+		return spec.Candidates[i].Key() < spec.Candidates[j].Key()
+	})
+
+	return spec
+}
+
+// clustersShortOfCapacity returns the clusters whose estimated capacity is lower than the desired replicas of the
+// scheduling unit. Clusters that can hold all the desired replicas never need preemption.
+func clustersShortOfCapacity(su *framework.SchedulingUnit) map[string]struct{} {
+	ret := map[string]struct{}{}
+	for cluster, capacity := range core.GetEstimatedCapacity(su) {
+		if su.DesiredReplicas == nil || capacity < *su.DesiredReplicas {
+			ret[cluster] = struct{}{}
+		}
+	}
+	return ret
+}
+
+// placedClustersIndexFunc indexes federated objects by the clusters they are placed in by the global scheduler.
+func placedClustersIndexFunc(obj interface{}) ([]string, error) {
+	fedObject, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	placementObj, err := util.UnmarshalGenericPlacements(fedObject)
+	if err != nil {
+		return nil, nil
+	}
+	placement := placementObj.Spec.GetPlacementOrNil(PrefixedGlobalSchedulerName)
+	if placement == nil {
+		return nil, nil
+	}
+
+	clusterNames := placement.ClusterNames()
+	clusters := make([]string, 0, len(clusterNames))
+	for cluster := range clusterNames {
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+func (s *Scheduler) getReplicaResourceRequest(fedObject *unstructured.Unstructured) (*framework.Resource, error) {
+	podSpec, err := replicaestimator.GetPodSpec(fedObject, s.typeConfig.Spec.PathDefinition.PodSpec)
+	if err != nil {
+		return nil, err
+	}
+	return framework.NewResource(util.GetPodResourceRequests(podSpec)), nil
+}
+
+// getPreemptedReplicas returns the preemptions of the federated object's replicas that are still in effect.
+func (s *Scheduler) getPreemptedReplicas(fedObject *unstructured.Unstructured) []core.PreemptedReplicas {
+	value, exists := fedObject.GetAnnotations()[PreemptedReplicasAnnotation]
+	if !exists {
+		return nil
+	}
+
+	var records []core.PreemptedReplicas
+	if err := json.Unmarshal([]byte(value), &records); err != nil {
+		s.logger.Error(err, "Failed to unmarshal preempted replicas", "object", common.NewQualifiedName(fedObject).String())
+		return nil
+	}
+
+	victim := common.NewQualifiedName(fedObject).String()
+	ret := make([]core.PreemptedReplicas, 0, len(records))
+	for _, record := range records {
+		preemptor, err := s.federatedObjectFromStore(common.NewQualifiedFromString(record.Preemptor))
+		if err != nil || preemptor.GetDeletionTimestamp() != nil {
+			continue
+		}
+		for _, preemption := range getPreemptionVictims(preemptor) {
+			if preemption.Cluster == record.Cluster && preemption.Victim == victim {
+				ret = append(ret, record)
+				break
+			}
+		}
+	}
+
+	if len(ret) == 0 {
+		return nil
+	}
+	return ret
+}
+
+// getPreemptionVictims returns the preemptions recorded by the preemptor.
+func getPreemptionVictims(fedObject *unstructured.Unstructured) []core.Preemption {
+	value, exists := fedObject.GetAnnotations()[PreemptionVictimsAnnotation]
+	if !exists {
+		return nil
+	}
+
+	var preemptions []core.Preemption
+	if err := json.Unmarshal([]byte(value), &preemptions); err != nil {
+		return nil
+	}
+	return preemptions
+}
+
+// applyPreemptedReplicas caps the replicas of the scheduling unit in the clusters where they were preempted.
+func applyPreemptedReplicas(su *framework.SchedulingUnit, records []core.PreemptedReplicas) {
+	if len(records) == 0 {
+		return
+	}
+
+	maxReplicas := make(map[string]int64, len(su.MaxReplicas)+len(records))
+	for cluster, replicas := range su.MaxReplicas {
+		maxReplicas[cluster] = replicas
+	}
+	for _, record := range records {
+		if existing, exists := maxReplicas[record.Cluster]; !exists || record.MaxReplicas < existing {
+			maxReplicas[record.Cluster] = record.MaxReplicas
+		}
+	}
+	su.MaxReplicas = maxReplicas
+}
+
+// setPreemptionVictims records the preemptions made by the federated object. Previous preemptions remain in effect as
+// long as the object still has replicas in their clusters.
+func setPreemptionVictims(fedObject *unstructured.Unstructured, result core.ScheduleResult) error {
+	type victimKey struct{ cluster, victim string }
+
+	preemptions := make(map[victimKey]core.Preemption)
+	for _, preemption := range getPreemptionVictims(fedObject) {
+		if replicas := result.SuggestedClusters[preemption.Cluster]; replicas != nil && *replicas > 0 {
+			preemptions[victimKey{preemption.Cluster, preemption.Victim}] = preemption
+		}
+	}
+	for _, preemption := range result.Preemptions {
+		preemptions[victimKey{preemption.Cluster, preemption.Victim}] = preemption
+	}
+
+	if len(preemptions) == 0 {
+		_, err := annotationutil.RemoveAnnotation(fedObject, PreemptionVictimsAnnotation)
+		return err
+	}
+
+	sorted := make([]core.Preemption, 0, len(preemptions))
+	for _, preemption := range preemptions {
+		sorted = append(sorted, preemption)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Cluster != sorted[j].Cluster {
+			return sorted[i].Cluster < sorted[j].Cluster
+		}
+		return sorted[i].Victim < sorted[j].Victim
+	})
+
+	preemptionsBytes, err := json.Marshal(sorted)
+	if err != nil {
+		return fmt.Errorf("failed to marshal preemption victims: %w", err)
+	}
+	_, err = annotationutil.AddAnnotation(fedObject, PreemptionVictimsAnnotation, string(preemptionsBytes))
+	return err
+}
+
+// setPreemptedReplicas records the preemptions of the federated object's replicas, or removes the annotation if there
+// are none.
+func setPreemptedReplicas(fedObject *unstructured.Unstructured, records []core.PreemptedReplicas) error {
+	if len(records) == 0 {
+		_, err := annotationutil.RemoveAnnotation(fedObject, PreemptedReplicasAnnotation)
+		return err
+	}
+
+	recordsBytes, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal preempted replicas: %w", err)
+	}
+	_, err = annotationutil.AddAnnotation(fedObject, PreemptedReplicasAnnotation, string(recordsBytes))
+	return err
+}
+
+// preemptVictims records the preemptions on the victims, which will cap their replicas in the preempted clusters when
+// they are rescheduled.
+func (s *Scheduler) preemptVictims(ctx context.Context, preemptor *unstructured.Unstructured, preemptions []core.Preemption) {
+	keyedLogger := klog.FromContext(ctx)
+	preemptorKey := common.NewQualifiedName(preemptor).String()
+
+	for _, preemption := range preemptions {
+		preemption := preemption
+		victimName := common.NewQualifiedFromString(preemption.Victim)
+
+		var victim *unstructured.Unstructured
+		err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			var err error
+			victim, err = s.federatedObjectClient.Namespace(victimName.Namespace).Get(ctx, victimName.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			var records []core.PreemptedReplicas
+			if value, exists := victim.GetAnnotations()[PreemptedReplicasAnnotation]; exists {
+				if err := json.Unmarshal([]byte(value), &records); err != nil {
+					records = nil
+				}
+			}
+
+			updated := make([]core.PreemptedReplicas, 0, len(records)+1)
+			for _, record := range records {
+				if record.Cluster != preemption.Cluster || record.Preemptor != preemptorKey {
+					updated = append(updated, record)
+				}
+			}
+			updated = append(updated, core.PreemptedReplicas{
+				Cluster:     preemption.Cluster,
+				Preemptor:   preemptorKey,
+				Replicas:    preemption.Replicas,
+				MaxReplicas: preemption.RemainingReplicas,
+			})
+			if err := setPreemptedReplicas(victim, updated); err != nil {
+				return err
+			}
+
+			victim, err = s.federatedObjectClient.Namespace(victimName.Namespace).Update(ctx, victim, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil {
+			// the victim will keep its replicas until the preemptor is rescheduled
+			keyedLogger.Error(err, "Failed to preempt replicas", "victim", preemption.Victim, "cluster", preemption.Cluster)
+			s.eventRecorder.Eventf(
+				preemptor,
+				corev1.EventTypeWarning,
+				EventReasonPreemptReplicas,
+				"failed to preempt %d replicas of %s in cluster %s: %v",
+				preemption.Replicas,
+				preemption.Victim,
+				preemption.Cluster,
+				err,
+			)
+			continue
+		}
+
+		s.eventRecorder.Eventf(
+			preemptor,
+			corev1.EventTypeNormal,
+			EventReasonPreemptReplicas,
+			"preempted %d replicas of %s in cluster %s",
+			preemption.Replicas,
+			preemption.Victim,
+			preemption.Cluster,
+		)
+		s.eventRecorder.Eventf(
+			victim,
+			corev1.EventTypeWarning,
+			EventReasonPreemptReplicas,
+			"%d replicas in cluster %s preempted by %s with priority higher than %d",
+			preemption.Replicas,
+			preemption.Cluster,
+			preemptorKey,
+			preemption.VictimPriority,
+		)
+	}
+}
+
+// enqueuePreemptionVictims enqueues the victims of the preemptions recorded by the federated object.
+func (s *Scheduler) enqueuePreemptionVictims(obj interface{}) {
+	if deleted, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		// This object might be stale but ok for our current usage.
+		obj = deleted.Obj
+		if obj == nil {
+			return
+		}
+	}
+
+	fedObject, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	for _, preemption := range getPreemptionVictims(fedObject) {
+		s.worker.Enqueue(common.NewQualifiedFromString(preemption.Victim))
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	fedcorev1a1listers "github.com/kubewharf/kubeadmiral/pkg/client/listers/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+func TestPreemptionSpecOnlyConsidersObjectsInClustersShortOfCapacity(t *testing.T) {
+	policy := &fedcorev1a1.PropagationPolicy{}
+	policy.SetNamespace("default")
+	policy.SetName("policy")
+	policyIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, policyIndexer.Add(policy))
+
+	newFedObject := func(name string, replicas map[string]int64) *unstructured.Unstructured {
+		fedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		fedObject.SetNamespace("default")
+		fedObject.SetName(name)
+		fedObject.SetUID(types.UID(name))
+		fedObject.SetLabels(map[string]string{PropagationPolicyNameLabel: "policy"})
+		require.NoError(t, unstructured.SetNestedMap(fedObject.Object, map[string]interface{}{
+			"containers": []interface{}{map[string]interface{}{
+				"name":      "server",
+				"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "1"}},
+			}},
+		}, "spec", "template", "spec", "template", "spec"))
+
+		clusters := map[string]struct{}{}
+		overrides := util.OverridesMap{}
+		for cluster, r := range replicas {
+			clusters[cluster] = struct{}{}
+			overrides[cluster] = fedtypesv1a1.OverridePatches{{Path: "/spec/replicas", Value: r}}
+		}
+		_, err := util.SetPlacementClusterNames(fedObject, PrefixedGlobalSchedulerName, clusters)
+		require.NoError(t, err)
+		require.NoError(t, util.SetOverrides(fedObject, PrefixedGlobalSchedulerName, overrides))
+		return fedObject
+	}
+
+	preemptor := newFedObject("preemptor", nil)
+	fedObjectIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{placedClusterIndex: placedClustersIndexFunc})
+	for _, obj := range []*unstructured.Unstructured{
+		preemptor,
+		newFedObject("in-short-cluster", map[string]int64{"short": 3, "roomy": 2}),
+		newFedObject("in-roomy-cluster", map[string]int64{"roomy": 5}),
+	} {
+		require.NoError(t, fedObjectIndexer.Add(obj))
+	}
+
+	scheduler := getSimulationScheduler()
+	scheduler.typeConfig.Spec.PathDefinition.PodSpec = "spec.template.spec"
+	scheduler.typeConfig.Spec.PathDefinition.ReplicasSpec = "spec.replicas"
+	scheduler.propagationPolicyLister = fedcorev1a1listers.NewPropagationPolicyLister(policyIndexer)
+	scheduler.federatedObjectIndexer = fedObjectIndexer
+
+	su := &framework.SchedulingUnit{
+		SchedulingMode:    fedcorev1a1.SchedulingModeDivide,
+		DesiredReplicas:   pointer.Int64(6),
+		Priority:          10,
+		EstimatedCapacity: map[string]int64{"short": 2, "roomy": 10},
+	}
+	spec := scheduler.preemptionSpecForFedObject(context.TODO(), preemptor, policy, su)
+	require.NotNil(t, spec)
+	require.Len(t, spec.Candidates, 1)
+	assert.Equal(t, "default/in-short-cluster", spec.Candidates[0].Key())
+	assert.Equal(t, map[string]int64{"short": 3}, spec.Candidates[0].Replicas)
+
+	su.EstimatedCapacity = map[string]int64{"short": 6, "roomy": 10}
+	assert.Nil(t, scheduler.preemptionSpecForFedObject(context.TODO(), preemptor, policy, su))
+}
@@ -26,12 +26,14 @@ import (
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/apiresources"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/clusteraffinity"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/clusterhealth"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/clusterresources"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/maxcluster"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/names"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/placement"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/rsp"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/tainttoleration"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/topologyspread"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/runtime"
 )
 
@@ -47,6 +49,8 @@ var inTreeRegistry = runtime.Registry{
 	names.ClusterResourcesMostAllocated:      clusterresources.NewClusterResourcesMostAllocated,
 	names.MaxCluster:                         maxcluster.NewMaxCluster,
 	names.ClusterCapacityWeight:              rsp.NewClusterCapacityWeight,
+	names.TopologySpread:                     topologyspread.NewTopologySpread,
+	names.ClusterHealth:                      clusterhealth.NewClusterHealth,
 }
 
 func applyProfile(base *fedcore.EnabledPlugins, profile *fedcorev1a1.SchedulingProfile) {
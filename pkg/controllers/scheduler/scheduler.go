@@ -20,6 +20,7 @@ package scheduler
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sync"
@@ -38,7 +39,6 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
-	"k8s.io/utils/pointer"
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
@@ -46,11 +46,13 @@ import (
 	fedcorev1a1listers "github.com/kubewharf/kubeadmiral/pkg/client/listers/core/v1alpha1"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/core"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 	annotationutil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/annotation"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/eventsink"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/pendingcontrollers"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/replicaestimator"
 	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
 	"github.com/kubewharf/kubeadmiral/pkg/stats"
@@ -68,9 +70,13 @@ type Scheduler struct {
 	fedClient     fedclient.Interface
 	dynamicClient dynamicclient.Interface
 
-	federatedObjectClient dynamicclient.NamespaceableResourceInterface
-	federatedObjectLister cache.GenericLister
-	federatedObjectSynced cache.InformerSynced
+	federatedObjectClient  dynamicclient.NamespaceableResourceInterface
+	federatedObjectLister  cache.GenericLister
+	federatedObjectIndexer cache.Indexer
+	federatedObjectSynced  cache.InformerSynced
+
+	federatedStatusLister cache.GenericLister
+	federatedStatusSynced cache.InformerSynced
 
 	propagationPolicyLister        fedcorev1a1listers.PropagationPolicyLister
 	clusterPropagationPolicyLister fedcorev1a1listers.ClusterPropagationPolicyLister
@@ -86,6 +92,8 @@ type Scheduler struct {
 	webhookConfigurationSynced cache.InformerSynced
 	webhookPlugins             sync.Map
 
+	replicaEstimator replicaestimator.Estimator
+
 	worker        worker.ReconcileWorker
 	eventRecorder record.EventRecorder
 
@@ -106,6 +114,7 @@ func NewScheduler(
 	fedClient fedclient.Interface,
 	dynamicClient dynamicclient.Interface,
 	federatedObjectInformer informers.GenericInformer,
+	federatedStatusInformer informers.GenericInformer,
 	propagationPolicyInformer fedcorev1a1informers.PropagationPolicyInformer,
 	clusterPropagationPolicyInformer fedcorev1a1informers.ClusterPropagationPolicyInformer,
 	clusterInformer fedcorev1a1informers.FederatedClusterInformer,
@@ -123,6 +132,8 @@ func NewScheduler(
 		dynamicClient: dynamicClient,
 		metrics:       metrics,
 		logger:        logger.WithValues("controller", GlobalSchedulerName, "ftc", typeConfig.Name),
+
+		replicaEstimator: replicaestimator.NewHTTPEstimator(replicaestimator.DefaultResultTTL),
 	}
 
 	s.worker = worker.NewReconcileWorker(
@@ -130,7 +141,7 @@ func NewScheduler(
 		worker.WorkerTiming{},
 		workerCount,
 		metrics,
-		delayingdeliver.NewMetricTags("scheduler-worker", s.typeConfig.GetFederatedType().Kind),
+		delayingdeliver.NewFTCMetricTags("scheduler-worker", s.typeConfig.GetFederatedType().Kind, s.typeConfig.Name),
 	)
 	s.eventRecorder = eventsink.NewDefederatingRecorderMux(kubeClient, s.name, 6)
 
@@ -139,7 +150,40 @@ func NewScheduler(
 
 	s.federatedObjectLister = federatedObjectInformer.Lister()
 	s.federatedObjectSynced = federatedObjectInformer.Informer().HasSynced
+	// The informer is shared and may have been indexed by a previous scheduler of a recreated FTC.
+	if _, exists := federatedObjectInformer.Informer().GetIndexer().GetIndexers()[placedClusterIndex]; !exists {
+		if err := federatedObjectInformer.Informer().AddIndexers(cache.Indexers{
+			placedClusterIndex: placedClustersIndexFunc,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to add placed cluster index: %w", err)
+		}
+	}
+	s.federatedObjectIndexer = federatedObjectInformer.Informer().GetIndexer()
+
+	// The collected status of the object's replicas is used to hold the drain of clusters until the replicas moved to
+	// other clusters are available.
+	if federatedStatusInformer != nil && drainAvailabilityCollected(typeConfig) {
+		s.federatedStatusLister = federatedStatusInformer.Lister()
+		s.federatedStatusSynced = federatedStatusInformer.Informer().HasSynced
+		federatedStatusInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: s.enqueueDrainingFederatedObject,
+			UpdateFunc: func(_, newObj interface{}) {
+				s.enqueueDrainingFederatedObject(newObj)
+			},
+		})
+	}
 	federatedObjectInformer.Informer().AddEventHandler(util.NewTriggerOnAllChanges(s.worker.EnqueueObject))
+	federatedObjectInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldUntyped, newUntyped interface{}) {
+			oldObj, newObj := oldUntyped.(*unstructured.Unstructured), newUntyped.(*unstructured.Unstructured)
+			if oldObj.GetAnnotations()[PreemptionVictimsAnnotation] != newObj.GetAnnotations()[PreemptionVictimsAnnotation] ||
+				oldObj.GetDeletionTimestamp().IsZero() != newObj.GetDeletionTimestamp().IsZero() {
+				s.enqueuePreemptionVictims(oldObj)
+				s.enqueuePreemptionVictims(newObj)
+			}
+		},
+		DeleteFunc: s.enqueuePreemptionVictims,
+	})
 
 	// only required if namespaced
 	if s.typeConfig.GetNamespaced() {
@@ -164,13 +208,16 @@ func NewScheduler(
 					return
 				}
 			}
+			s.replicaEstimator.Forget(obj.(*fedcorev1a1.FederatedCluster).Name)
 			s.enqueueFederatedObjectsForCluster(obj.(pkgruntime.Object))
 		},
 		UpdateFunc: func(oldUntyped, newUntyped interface{}) {
 			oldCluster, newCluster := oldUntyped.(*fedcorev1a1.FederatedCluster), newUntyped.(*fedcorev1a1.FederatedCluster)
 			if !equality.Semantic.DeepEqual(oldCluster.Labels, newCluster.Labels) ||
 				!equality.Semantic.DeepEqual(oldCluster.Spec.Taints, newCluster.Spec.Taints) ||
-				!equality.Semantic.DeepEqual(oldCluster.Status.APIResourceTypes, newCluster.Status.APIResourceTypes) {
+				!equality.Semantic.DeepEqual(oldCluster.Status.APIResourceTypes, newCluster.Status.APIResourceTypes) ||
+				!equality.Semantic.DeepEqual(getUnhealthyConditions(oldCluster), getUnhealthyConditions(newCluster)) ||
+				!equality.Semantic.DeepEqual(getClusterDrain(oldCluster), getClusterDrain(newCluster)) {
 				s.enqueueFederatedObjectsForCluster(newCluster)
 			}
 		},
@@ -221,6 +268,9 @@ func (s *Scheduler) HasSynced() bool {
 	if s.typeConfig.GetNamespaced() {
 		cachesSynced = append(cachesSynced, s.propagationPolicySynced)
 	}
+	if s.federatedStatusSynced != nil {
+		cachesSynced = append(cachesSynced, s.federatedStatusSynced)
+	}
 
 	for _, synced := range cachesSynced {
 		if !synced() {
@@ -244,14 +294,14 @@ func (s *Scheduler) Run(ctx context.Context) {
 }
 
 func (s *Scheduler) reconcile(qualifiedName common.QualifiedName) (status worker.Result) {
-	_ = s.metrics.Rate("scheduler.throughput", 1)
+	_ = s.metrics.Rate("scheduler.throughput", 1, stats.Tag{Name: "ftc", Value: s.typeConfig.Name})
 	keyedLogger := s.logger.WithValues("origin", "reconcile", "object", qualifiedName.String())
 	ctx := klog.NewContext(context.TODO(), keyedLogger)
 	startTime := time.Now()
 
 	keyedLogger.V(3).Info("Start reconcile")
 	defer func() {
-		s.metrics.Duration(fmt.Sprintf("%s.latency", s.name), startTime)
+		s.metrics.Duration("scheduler.latency", startTime, stats.Tag{Name: "ftc", Value: s.typeConfig.Name})
 		keyedLogger.V(3).WithValues("duration", time.Since(startTime), "status", status.String()).Info("Finished reconcile")
 	}()
 
@@ -290,7 +340,7 @@ func (s *Scheduler) reconcile(qualifiedName common.QualifiedName) (status worker
 
 	auxInfo := &auxiliarySchedulingInformation{
 		enableFollowerScheduling: false,
-		unschedulableThreshold:   nil,
+		autoMigrationTrigger:     nil,
 	}
 	if policy != nil {
 		spec := policy.GetSpec()
@@ -299,9 +349,11 @@ func (s *Scheduler) reconcile(qualifiedName common.QualifiedName) (status worker
 		keyedLogger = keyedLogger.WithValues("enableFollowerScheduling", auxInfo.enableFollowerScheduling)
 
 		if autoMigration := spec.AutoMigration; autoMigration != nil {
-			auxInfo.unschedulableThreshold = pointer.Duration(autoMigration.Trigger.PodUnschedulableDuration.Duration)
-			keyedLogger = keyedLogger.WithValues("unschedulableThreshold", auxInfo.unschedulableThreshold.String())
+			auxInfo.autoMigrationTrigger = &autoMigration.Trigger
+			keyedLogger = keyedLogger.WithValues("autoMigrationTrigger", auxInfo.autoMigrationTrigger)
 		}
+
+		auxInfo.rolloutStrategy = spec.RolloutStrategy
 	}
 
 	ctx = klog.NewContext(ctx, keyedLogger)
@@ -397,6 +449,17 @@ func (s *Scheduler) prepareToSchedule(
 		return nil, nil, nil, &worker.StatusError
 	}
 
+	// reschedule the object once a toleration of a NoExecute taint expires, so that it is evicted from the cluster
+	if _, nextExpiry := getTaintTolerationExpiries(getTolerations(fedObject, policy), clusters, time.Now()); nextExpiry != nil {
+		s.worker.EnqueueWithDelay(common.NewQualifiedName(fedObject), time.Until(*nextExpiry))
+	}
+	// reschedule the object at the next step of the drain of the clusters it is placed in
+	if currentClusters, err := getCurrentReplicasFromObject(s.typeConfig, fedObject); err == nil {
+		if _, nextStepTime := getClusterDrainSteps(currentClusters, clusters, time.Now()); nextStepTime != nil {
+			s.worker.EnqueueWithDelay(common.NewQualifiedName(fedObject), time.Until(*nextStepTime))
+		}
+	}
+
 	triggersChanged, err := annotationutil.AddAnnotation(fedObject, SchedulingTriggerHashAnnotation, triggerHash)
 	if err != nil {
 		keyedLogger.Error(err, "Failed to update scheduling trigger hash")
@@ -489,6 +552,24 @@ func (s *Scheduler) schedule(
 		return nil, &worker.StatusError
 	}
 
+	schedulingUnit.EstimatedCapacity = s.estimateCapacity(ctx, fedObject, schedulingUnit, clusters)
+	preemptedReplicas := s.getPreemptedReplicas(fedObject)
+	applyPreemptedReplicas(schedulingUnit, preemptedReplicas)
+	schedulingUnit.Preemption = s.preemptionSpecForFedObject(ctx, fedObject, policy, schedulingUnit)
+
+	drainedReplicas, err := getDrainedReplicas(
+		fedObject,
+		schedulingUnit,
+		clusters,
+		s.drainDestinationsAvailable(fedObject, schedulingUnit.CurrentClusters, clusters),
+		time.Now(),
+	)
+	if err != nil {
+		// the drain then starts over from the current step
+		keyedLogger.Error(err, "Failed to get drained replicas")
+	}
+	clusters = applyClusterDrain(schedulingUnit, clusters, drainedReplicas)
+
 	framework, err := s.createFramework(schedulingProfile, s.buildFrameworkHandle())
 	if err != nil {
 		keyedLogger.Error(err, "Failed to construct scheduling profile")
@@ -516,10 +597,88 @@ func (s *Scheduler) schedule(
 		)
 		return nil, &worker.StatusError
 	}
+	if result.Explanation != nil {
+		result.Explanation.PreemptedReplicas = preemptedReplicas
+	}
+	if err := setDrainedReplicas(fedObject, drainedReplicas, result); err != nil {
+		keyedLogger.Error(err, "Failed to set drained replicas")
+		return nil, &worker.StatusError
+	}
 
 	return &result, nil
 }
 
+// estimateCapacity returns the number of replicas each cluster with a replica estimator can hold, which is the sum of
+// its current replicas and the additional replicas its estimator can schedule.
+func (s *Scheduler) estimateCapacity(
+	ctx context.Context,
+	fedObject *unstructured.Unstructured,
+	su *framework.SchedulingUnit,
+	clusters []*fedcorev1a1.FederatedCluster,
+) map[string]int64 {
+	if su.SchedulingMode != fedcorev1a1.SchedulingModeDivide {
+		return nil
+	}
+
+	clustersWithEstimator := make([]*fedcorev1a1.FederatedCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		if cluster.Spec.ReplicaEstimator != nil {
+			clustersWithEstimator = append(clustersWithEstimator, cluster)
+		}
+	}
+	if len(clustersWithEstimator) == 0 {
+		return nil
+	}
+
+	keyedLogger := klog.FromContext(ctx)
+	podSpec, err := replicaestimator.GetPodSpec(fedObject, s.typeConfig.Spec.PathDefinition.PodSpec)
+	if err != nil {
+		keyedLogger.Error(err, "Failed to get pod spec for replica estimation")
+		return nil
+	}
+
+	// Bound the time spent on estimation so that slow estimators cannot stall the scheduling of the object.
+	ctx, cancel := context.WithTimeout(ctx, replicaEstimationTimeout)
+	defer cancel()
+
+	var (
+		lock              sync.Mutex
+		wg                sync.WaitGroup
+		estimatedCapacity = make(map[string]int64, len(clustersWithEstimator))
+	)
+	for _, cluster := range clustersWithEstimator {
+		cluster := cluster
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			request := replicaestimator.NewRequest(cluster.Name, podSpec)
+			maxReplicas, err := s.replicaEstimator.MaxAvailableReplicas(ctx, cluster, request)
+			if err != nil {
+				keyedLogger.Error(err, "Failed to estimate replicas", "cluster", cluster.Name)
+				return
+			}
+
+			currentReplicas := int64(0)
+			if replicas, exists := su.CurrentClusters[cluster.Name]; exists {
+				if replicas != nil {
+					currentReplicas = *replicas
+				} else if su.DesiredReplicas != nil {
+					currentReplicas = *su.DesiredReplicas
+				}
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			estimatedCapacity[cluster.Name] = currentReplicas + maxReplicas
+		}()
+	}
+	wg.Wait()
+
+	keyedLogger.V(3).Info("Estimated capacity with replica estimators", "estimatedCapacity", estimatedCapacity)
+	return estimatedCapacity
+}
+
 func (s *Scheduler) persistSchedulingResult(
 	ctx context.Context,
 	fedObject *unstructured.Unstructured,
@@ -552,6 +711,18 @@ func (s *Scheduler) persistSchedulingResult(
 		)
 		return worker.StatusError
 	}
+	if err := setSchedulingExplanation(fedObject, result.Explanation); err != nil {
+		// the explanation is informational only and should not block scheduling
+		keyedLogger.Error(err, "Failed to set scheduling explanation")
+	}
+	if err := setPreemptionVictims(fedObject, result); err != nil {
+		keyedLogger.Error(err, "Failed to set preemption victims")
+		return worker.StatusError
+	}
+	if err := setPreemptedReplicas(fedObject, s.getPreemptedReplicas(fedObject)); err != nil {
+		keyedLogger.Error(err, "Failed to set preempted replicas")
+		return worker.StatusError
+	}
 
 	// We always update the federated object because the fact that scheduling even occurred minimally implies that the
 	// scheduling trigger hash must have changed.
@@ -576,6 +747,7 @@ func (s *Scheduler) persistSchedulingResult(
 	}
 
 	keyedLogger.V(1).Info("Updated federated object")
+	s.preemptVictims(ctx, fedObject, result.Preemptions)
 	s.eventRecorder.Eventf(
 		fedObject,
 		corev1.EventTypeNormal,
@@ -624,7 +796,8 @@ func (s *Scheduler) updatePendingControllers(fedObject *unstructured.Unstructure
 
 type auxiliarySchedulingInformation struct {
 	enableFollowerScheduling bool
-	unschedulableThreshold   *time.Duration
+	autoMigrationTrigger     *fedcorev1a1.AutoMigrationTrigger
+	rolloutStrategy          *fedcorev1a1.RolloutStrategy
 }
 
 // applySchedulingResult updates the federated object with the scheduling result and the enableFollowerScheduling annotation, it returns a
@@ -673,15 +846,42 @@ func (s *Scheduler) applySchedulingResult(
 		annotationsModified = true
 	}
 
-	if auxInfo.unschedulableThreshold == nil {
-		if _, ok := annotations[common.PodUnschedulableThresholdAnnotation]; ok {
-			delete(annotations, common.PodUnschedulableThresholdAnnotation)
+	// PodUnschedulableThresholdAnnotation has been superseded by AutoMigrationTriggerAnnotation.
+	if _, ok := annotations[common.PodUnschedulableThresholdAnnotation]; ok {
+		delete(annotations, common.PodUnschedulableThresholdAnnotation)
+		annotationsModified = true
+	}
+
+	if auxInfo.autoMigrationTrigger == nil {
+		if _, ok := annotations[common.AutoMigrationTriggerAnnotation]; ok {
+			delete(annotations, common.AutoMigrationTriggerAnnotation)
 			annotationsModified = true
 		}
 	} else {
-		unschedulableThresholdAnnotationValue := auxInfo.unschedulableThreshold.String()
-		if annotations[common.PodUnschedulableThresholdAnnotation] != unschedulableThresholdAnnotationValue {
-			annotations[common.PodUnschedulableThresholdAnnotation] = unschedulableThresholdAnnotationValue
+		autoMigrationTriggerBytes, err := json.Marshal(auxInfo.autoMigrationTrigger)
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal auto migration trigger: %w", err)
+		}
+		autoMigrationTriggerAnnotationValue := string(autoMigrationTriggerBytes)
+		if annotations[common.AutoMigrationTriggerAnnotation] != autoMigrationTriggerAnnotationValue {
+			annotations[common.AutoMigrationTriggerAnnotation] = autoMigrationTriggerAnnotationValue
+			annotationsModified = true
+		}
+	}
+
+	if auxInfo.rolloutStrategy == nil {
+		if _, ok := annotations[common.RolloutStrategyAnnotation]; ok {
+			delete(annotations, common.RolloutStrategyAnnotation)
+			annotationsModified = true
+		}
+	} else {
+		rolloutStrategyBytes, err := json.Marshal(auxInfo.rolloutStrategy)
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal rollout strategy: %w", err)
+		}
+		rolloutStrategyAnnotationValue := string(rolloutStrategyBytes)
+		if annotations[common.RolloutStrategyAnnotation] != rolloutStrategyAnnotationValue {
+			annotations[common.RolloutStrategyAnnotation] = rolloutStrategyAnnotationValue
 			annotationsModified = true
 		}
 	}
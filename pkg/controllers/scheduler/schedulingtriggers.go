@@ -22,6 +22,7 @@ import (
 	"hash/fnv"
 	"sort"
 	"strconv"
+	"time"
 
 	"golang.org/x/exp/constraints"
 	corev1 "k8s.io/api/core/v1"
@@ -32,6 +33,7 @@ import (
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/core"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 	utilunstructured "github.com/kubewharf/kubeadmiral/pkg/controllers/util/unstructured"
@@ -50,6 +52,7 @@ Federated object changes:
 2. object scheduling annotation updates
 3. object replica count change
 4. object resource request change
+5. preemption of the object's replicas
 
 Propagation policy changes:
 1. policy creation
@@ -60,6 +63,9 @@ Cluster changes:
 2. cluster labels change
 3. cluster taints change
 4. cluster apiresource changes
+5. cluster health condition changes
+6. expiry of the object's tolerations of cluster NoExecute taints
+7. cluster drain steps and the availability of the replicas in the clusters that are not drained
 
 Simply checking for these triggers in the event handlers is insufficient. This is because when the controller restarts, all objects will be
 "created" again, causing mass rescheduling for all objects. Thus, we hash the scheduling triggers and write it into the federated object's
@@ -92,6 +98,8 @@ type schedulingTriggers struct {
 
 	AutoMigrationInfo *string `json:"autoMigrationInfo,omitempty"`
 
+	PreemptedReplicas []core.PreemptedReplicas `json:"preemptedReplicas,omitempty"`
+
 	PolicyName       string `json:"policyName"`
 	PolicyGeneration int64  `json:"policyGeneration"`
 
@@ -101,6 +109,15 @@ type schedulingTriggers struct {
 	ClusterTaints []keyValue[string, []corev1.Taint] `json:"clusterTaints"`
 	// a map from each cluster to its apiresources
 	ClusterAPIResourceTypes []keyValue[string, []fedcorev1a1.APIResource] `json:"clusterAPIResourceTypes"`
+	// a map from each unhealthy cluster to its failed health conditions
+	ClusterUnhealthyConditions []keyValue[string, []fedcorev1a1.ClusterConditionType] `json:"clusterUnhealthyConditions,omitempty"`
+	// a map from each cluster to the keys of its NoExecute taints whose tolerations have expired
+	ExpiredTaintTolerations []keyValue[string, []string] `json:"expiredTaintTolerations,omitempty"`
+	// a map from each draining cluster the object is placed in to the current step of its drain
+	ClusterDrainSteps []keyValue[string, int64] `json:"clusterDrainSteps,omitempty"`
+	// whether the replicas of the object in the clusters that are not drained are available, which allows the drain
+	// to advance to its current step
+	DrainDestinationsAvailable *bool `json:"drainDestinationsAvailable,omitempty"`
 }
 
 func (s *Scheduler) computeSchedulingTriggerHash(
@@ -117,6 +134,7 @@ func (s *Scheduler) computeSchedulingTriggerHash(
 		return "", fmt.Errorf("failed to get object replica count: %w", err)
 	}
 	trigger.ResourceRequest = getResourceRequest(fedObject)
+	trigger.PreemptedReplicas = s.getPreemptedReplicas(fedObject)
 
 	if policy != nil {
 		trigger.PolicyName = policy.GetName()
@@ -132,6 +150,18 @@ func (s *Scheduler) computeSchedulingTriggerHash(
 	trigger.ClusterLabels = getClusterLabels(clusters)
 	trigger.ClusterTaints = getClusterTaints(clusters)
 	trigger.ClusterAPIResourceTypes = getClusterAPIResourceTypes(clusters)
+	trigger.ClusterUnhealthyConditions = getClusterUnhealthyConditions(clusters)
+	trigger.ExpiredTaintTolerations, _ = getTaintTolerationExpiries(getTolerations(fedObject, policy), clusters, time.Now())
+
+	currentClusters, err := getCurrentReplicasFromObject(s.typeConfig, fedObject)
+	if err != nil {
+		return "", fmt.Errorf("failed to get current clusters: %w", err)
+	}
+	trigger.ClusterDrainSteps, _ = getClusterDrainSteps(currentClusters, clusters, time.Now())
+	if len(trigger.ClusterDrainSteps) > 0 {
+		available := s.drainDestinationsAvailable(fedObject, currentClusters, clusters)
+		trigger.DrainDestinationsAvailable = &available
+	}
 
 	triggerBytes, err := json.Marshal(trigger)
 	if err != nil {
@@ -155,6 +185,7 @@ var knownSchedulingAnnotations = sets.New(
 	ClusterSelectorAnnotations,
 	AffinityAnnotations,
 	MaxClustersAnnotations,
+	PriorityAnnotations,
 	FollowsObjectAnnotation,
 )
 
@@ -261,6 +292,87 @@ func getClusterAPIResourceTypes(clusters []*fedcorev1a1.FederatedCluster) []keyV
 	return sortMap(ret)
 }
 
+func getClusterUnhealthyConditions(
+	clusters []*fedcorev1a1.FederatedCluster,
+) []keyValue[string, []fedcorev1a1.ClusterConditionType] {
+	ret := make(map[string][]fedcorev1a1.ClusterConditionType)
+	for _, cluster := range clusters {
+		if conditions := getUnhealthyConditions(cluster); len(conditions) > 0 {
+			ret[cluster.Name] = conditions
+		}
+	}
+	if len(ret) == 0 {
+		return nil
+	}
+	return sortMap(ret)
+}
+
+// getUnhealthyConditions returns the health conditions of the cluster that are False, in a deterministic order.
+func getUnhealthyConditions(cluster *fedcorev1a1.FederatedCluster) []fedcorev1a1.ClusterConditionType {
+	var ret []fedcorev1a1.ClusterConditionType
+	for _, conditionType := range fedcorev1a1.ClusterHealthConditionTypes {
+		for _, condition := range cluster.Status.Conditions {
+			if condition.Type == conditionType && condition.Status == corev1.ConditionFalse {
+				ret = append(ret, conditionType)
+			}
+		}
+	}
+	return ret
+}
+
+// getTolerations returns the tolerations of the federated object, which may be overridden by the object's annotations.
+func getTolerations(
+	fedObject *unstructured.Unstructured,
+	policy fedcorev1a1.GenericPropagationPolicy,
+) []corev1.Toleration {
+	var tolerations []corev1.Toleration
+	if policy != nil {
+		tolerations = getTolerationsFromPolicy(policy)
+	}
+	if tolerationsOverride, exists := getTolerationsFromObject(fedObject); exists {
+		tolerations = tolerationsOverride
+	}
+	return tolerations
+}
+
+// getTaintTolerationExpiries returns the keys of the NoExecute taints of each cluster that are no longer tolerated
+// because their tolerations have expired, and the next time at which a toleration will expire.
+func getTaintTolerationExpiries(
+	tolerations []corev1.Toleration,
+	clusters []*fedcorev1a1.FederatedCluster,
+	now time.Time,
+) ([]keyValue[string, []string], *time.Time) {
+	expired := make(map[string][]string)
+	var nextExpiry *time.Time
+
+	for _, cluster := range clusters {
+		for i := range cluster.Spec.Taints {
+			taint := &cluster.Spec.Taints[i]
+			if taint.Effect != corev1.TaintEffectNoExecute {
+				continue
+			}
+
+			expiry, tolerated := framework.GetTaintTolerationExpiry(tolerations, taint)
+			if !tolerated || expiry == nil {
+				continue
+			}
+			if !now.Before(*expiry) {
+				expired[cluster.Name] = append(expired[cluster.Name], taint.Key)
+			} else if nextExpiry == nil || expiry.Before(*nextExpiry) {
+				nextExpiry = expiry
+			}
+		}
+	}
+
+	if len(expired) == 0 {
+		return nil, nextExpiry
+	}
+	for _, keys := range expired {
+		sort.Strings(keys)
+	}
+	return sortMap(expired), nextExpiry
+}
+
 // enqueueFederatedObjectsForPolicy enqueues federated objects which match the policy
 func (s *Scheduler) enqueueFederatedObjectsForPolicy(policy pkgruntime.Object) {
 	policyAccessor, ok := policy.(fedcorev1a1.GenericPropagationPolicy)
@@ -153,12 +153,20 @@ func schedulingUnitForFedObject(
 		schedulingUnit.Tolerations = tolerationsOverride
 	}
 
+	schedulingUnit.TopologySpread = policy.GetSpec().TopologySpread
+
 	schedulingUnit.MaxClusters = getMaxClustersFromPolicy(policy)
 	maxClustersOverride, exists := getMaxClustersFromObject(fedObject)
 	if exists {
 		schedulingUnit.MaxClusters = maxClustersOverride
 	}
 
+	schedulingUnit.Priority = getPriorityFromPolicy(policy)
+	priorityOverride, exists := getPriorityFromObject(fedObject)
+	if exists {
+		schedulingUnit.Priority = priorityOverride
+	}
+
 	return schedulingUnit, nil
 }
 
@@ -447,6 +455,45 @@ func getMaxClustersFromObject(object *unstructured.Unstructured) (*int64, bool)
 	return &result, true
 }
 
+func getPriorityFromPolicy(policy fedcorev1a1.GenericPropagationPolicy) int32 {
+	if priority := policy.GetSpec().Priority; priority != nil {
+		return *priority
+	}
+	return 0
+}
+
+func getPriorityFromObject(object *unstructured.Unstructured) (int32, bool) {
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		return 0, false
+	}
+
+	annotation, exists := annotations[PriorityAnnotations]
+	if !exists {
+		return 0, false
+	}
+
+	priority, err := strconv.ParseInt(annotation, 10, 32)
+	if err != nil {
+		klog.Errorf(
+			"Failed to unmarshal priority annotation (%s) on fed object %s with err %s",
+			PriorityAnnotations,
+			object.GetName(),
+			err,
+		)
+		return 0, false
+	}
+
+	return int32(priority), true
+}
+
+func getPreemptionPolicyFromPolicy(policy fedcorev1a1.GenericPropagationPolicy) fedcorev1a1.PreemptionPolicy {
+	if preemptionPolicy := policy.GetSpec().PreemptionPolicy; preemptionPolicy != nil {
+		return *preemptionPolicy
+	}
+	return fedcorev1a1.PreemptLowerPriority
+}
+
 func getWeightsFromPolicy(policy fedcorev1a1.GenericPropagationPolicy) map[string]int64 {
 	if policy.GetSpec().Placements == nil {
 		return nil
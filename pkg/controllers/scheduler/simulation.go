@@ -0,0 +1,225 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/core"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+// SimulationPathPrefix is the path under which the SimulationHandler serves simulation requests. Requests should be
+// sent to <SimulationPathPrefix><ftc name>.
+const SimulationPathPrefix = "/scheduler/simulate/"
+
+// maxSimulationRequestBytes limits the size of a simulation request body.
+const maxSimulationRequestBytes = 10 << 20
+
+// SimulationRequest describes a what-if scheduling request.
+type SimulationRequest struct {
+	// FederatedObject is the federated object to schedule.
+	FederatedObject *unstructured.Unstructured `json:"federatedObject"`
+	// PropagationPolicy is the policy to schedule the object with. If neither PropagationPolicy nor
+	// ClusterPropagationPolicy is set, the policy currently matched by the object is used.
+	PropagationPolicy *fedcorev1a1.PropagationPolicy `json:"propagationPolicy,omitempty"`
+	// ClusterPropagationPolicy is the cluster-scoped policy to schedule the object with.
+	ClusterPropagationPolicy *fedcorev1a1.ClusterPropagationPolicy `json:"clusterPropagationPolicy,omitempty"`
+	// Clusters is a hypothetical set of clusters to schedule the object to. If nil, the clusters currently joined to
+	// the federation are used. The replica estimators of hypothetical clusters are never called.
+	Clusters []*fedcorev1a1.FederatedCluster `json:"clusters,omitempty"`
+}
+
+// SimulationResult is the result of a what-if scheduling request.
+type SimulationResult struct {
+	// SuggestedClusters is the placement and replica distribution the object would be scheduled with, see
+	// core.ScheduleResult.
+	SuggestedClusters map[string]*int64 `json:"suggestedClusters"`
+	// Explanation records the decisions that led to the suggested clusters.
+	Explanation *core.ScheduleExplanation `json:"explanation,omitempty"`
+}
+
+// Simulate runs the given request through the scheduling framework and returns the resulting placement without
+// persisting anything. Unlike the scheduler, Simulate does not preempt replicas of other objects, and ignores cluster
+// drains and auto migration, so the result may differ from the placement the object would actually get.
+func (s *Scheduler) Simulate(ctx context.Context, request *SimulationRequest) (*SimulationResult, error) {
+	if request.FederatedObject == nil {
+		return nil, errors.New("federated object must be specified")
+	}
+	if request.PropagationPolicy != nil && request.ClusterPropagationPolicy != nil {
+		return nil, errors.New("at most one of propagation policy and cluster propagation policy may be specified")
+	}
+
+	fedObject := request.FederatedObject.DeepCopy()
+
+	var policy fedcorev1a1.GenericPropagationPolicy
+	switch {
+	case request.PropagationPolicy != nil:
+		if !s.typeConfig.GetNamespaced() {
+			return nil, errors.New("propagation policy cannot be used for cluster-scoped objects")
+		}
+		policy = request.PropagationPolicy
+	case request.ClusterPropagationPolicy != nil:
+		policy = request.ClusterPropagationPolicy
+	default:
+		policyKey, hasSchedulingPolicy := MatchedPolicyKey(fedObject, s.typeConfig.GetNamespaced())
+		if !hasSchedulingPolicy {
+			return nil, errors.New("no propagation policy specified or matched by the object")
+		}
+		var err error
+		if policy, err = s.policyFromStore(policyKey); err != nil {
+			return nil, fmt.Errorf("failed to get policy %s: %w", policyKey.String(), err)
+		}
+	}
+
+	var schedulingProfile *fedcorev1a1.SchedulingProfile
+	if profileName := policy.GetSpec().SchedulingProfile; len(profileName) > 0 {
+		var err error
+		if schedulingProfile, err = s.schedulingProfileLister.Get(profileName); err != nil {
+			return nil, fmt.Errorf("failed to get scheduling profile %s: %w", profileName, err)
+		}
+	}
+
+	clusters := request.Clusters
+	hypotheticalClusters := clusters != nil
+	if !hypotheticalClusters {
+		allClusters, err := s.clusterLister.List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get clusters from store: %w", err)
+		}
+		for _, cluster := range allClusters {
+			if util.IsClusterJoined(&cluster.Status) {
+				clusters = append(clusters, cluster)
+			}
+		}
+	}
+
+	schedulingUnit, err := schedulingUnitForFedObject(s.typeConfig, fedObject, policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduling unit: %w", err)
+	}
+	// The replica estimator URLs of hypothetical clusters are supplied by the caller and must not be requested.
+	if !hypotheticalClusters {
+		schedulingUnit.EstimatedCapacity = s.estimateCapacity(ctx, fedObject, schedulingUnit, clusters)
+	}
+
+	framework, err := s.createFramework(schedulingProfile, s.buildFrameworkHandle())
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct scheduling profile: %w", err)
+	}
+
+	result, err := s.algorithm.Schedule(ctx, framework, *schedulingUnit, clusters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute scheduling result: %w", err)
+	}
+
+	return &SimulationResult{
+		SuggestedClusters: result.SuggestedClusters,
+		Explanation:       result.Explanation,
+	}, nil
+}
+
+// SimulationHandler serves what-if scheduling requests over HTTP for the schedulers registered with it. It does not
+// authenticate requests by itself and must only be served behind authentication and authorization, since requests
+// may read the clusters and policies of the federation.
+type SimulationHandler struct {
+	lock       sync.RWMutex
+	schedulers map[string]*Scheduler
+}
+
+func NewSimulationHandler() *SimulationHandler {
+	return &SimulationHandler{
+		schedulers: map[string]*Scheduler{},
+	}
+}
+
+// Register makes the scheduler available for simulation requests for the given FTC.
+func (h *SimulationHandler) Register(ftcName string, scheduler *Scheduler) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.schedulers[ftcName] = scheduler
+}
+
+// Unregister removes the scheduler of the given FTC.
+func (h *SimulationHandler) Unregister(ftcName string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	delete(h.schedulers, ftcName)
+}
+
+func (h *SimulationHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		http.Error(writer, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ftcName := strings.TrimPrefix(request.URL.Path, SimulationPathPrefix)
+	h.lock.RLock()
+	scheduler, exists := h.schedulers[ftcName]
+	h.lock.RUnlock()
+	if !exists {
+		http.Error(writer, fmt.Sprintf("no scheduler found for FederatedTypeConfig %q", ftcName), http.StatusNotFound)
+		return
+	}
+
+	simulationRequest := &SimulationRequest{}
+	decoder := json.NewDecoder(http.MaxBytesReader(writer, request.Body, maxSimulationRequestBytes))
+	if err := decoder.Decode(simulationRequest); err != nil {
+		http.Error(writer, fmt.Sprintf("failed to decode simulation request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	logger := scheduler.logger.WithValues("origin", "simulate")
+	if simulationRequest.FederatedObject != nil {
+		logger = logger.WithValues("object", common.NewQualifiedName(simulationRequest.FederatedObject).String())
+	}
+	ctx := klog.NewContext(request.Context(), logger)
+
+	logger.V(2).Info("Simulating scheduling")
+	result, err := scheduler.Simulate(ctx, simulationRequest)
+	if err != nil {
+		logger.Error(err, "Failed to simulate scheduling")
+		status := http.StatusBadRequest
+		if apierrors.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(writer, err.Error(), status)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(writer).Encode(result); err != nil {
+		logger.Error(err, "Failed to write simulation result")
+	}
+}
+
+var _ http.Handler = &SimulationHandler{}
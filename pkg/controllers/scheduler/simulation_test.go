@@ -0,0 +1,190 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	estimatorv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/replicaestimator/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/core"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/names"
+)
+
+func getSimulationCluster(name string, taints ...corev1.Taint) *fedcorev1a1.FederatedCluster {
+	cluster := getCluster(name)
+	cluster.Spec.Taints = taints
+	cluster.Status.APIResourceTypes = []fedcorev1a1.APIResource{
+		{Group: "apps", Version: "v1", Kind: "Deployment", PluralName: "deployments", Scope: "Namespaced"},
+	}
+	return cluster
+}
+
+func getSimulationRequest() *SimulationRequest {
+	fedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	fedObject.SetAPIVersion("types.kubeadmiral.io/v1alpha1")
+	fedObject.SetKind("FederatedDeployment")
+	fedObject.SetNamespace("default")
+	fedObject.SetName("test")
+	_ = unstructured.SetNestedMap(fedObject.Object, map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"namespace": "default",
+			"name":      "test",
+		},
+	}, common.TemplatePath...)
+
+	return &SimulationRequest{
+		FederatedObject: fedObject,
+		PropagationPolicy: &fedcorev1a1.PropagationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "policy"},
+			Spec: fedcorev1a1.PropagationPolicySpec{
+				SchedulingMode: fedcorev1a1.SchedulingModeDuplicate,
+			},
+		},
+		Clusters: []*fedcorev1a1.FederatedCluster{
+			getSimulationCluster("cluster-1", corev1.Taint{Key: "maintenance", Effect: corev1.TaintEffectNoSchedule}),
+			getSimulationCluster("cluster-2"),
+		},
+	}
+}
+
+func getSimulationScheduler() *Scheduler {
+	return &Scheduler{
+		typeConfig: &fedcorev1a1.FederatedTypeConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "deployments.apps"},
+			Spec: fedcorev1a1.FederatedTypeConfigSpec{
+				TargetType: fedcorev1a1.APIResource{
+					Group:      "apps",
+					Version:    "v1",
+					Kind:       "Deployment",
+					PluralName: "deployments",
+					Scope:      "Namespaced",
+				},
+			},
+		},
+		algorithm: core.NewSchedulerAlgorithm(),
+		logger:    klog.Background(),
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	result, err := getSimulationScheduler().Simulate(context.TODO(), getSimulationRequest())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(result.SuggestedClusters).To(gomega.Equal(map[string]*int64{"cluster-2": nil}))
+	g.Expect(result.Explanation.Filter).To(gomega.ContainElement(core.ClusterFilterVerdict{
+		Cluster:      "cluster-1",
+		Feasible:     false,
+		FailedPlugin: names.TaintToleration,
+		Reasons:      []string{"cluster(s) had taint {maintenance: }, that the schedulingUnit didn't tolerate"},
+	}))
+}
+
+type recordingEstimator struct {
+	lock     sync.Mutex
+	clusters []string
+}
+
+func (e *recordingEstimator) MaxAvailableReplicas(
+	_ context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+	_ *estimatorv1a1.MaxAvailableReplicasRequest,
+) (int64, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.clusters = append(e.clusters, cluster.Name)
+	return 0, nil
+}
+
+func (e *recordingEstimator) Forget(string) {}
+
+func TestSimulateDoesNotCallEstimatorsOfHypotheticalClusters(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	request := getSimulationRequest()
+	request.PropagationPolicy.Spec.SchedulingMode = fedcorev1a1.SchedulingModeDivide
+	g.Expect(unstructured.SetNestedField(request.FederatedObject.Object, map[string]interface{}{
+		"containers": []interface{}{map[string]interface{}{"name": "server", "image": "nginx"}},
+	}, "spec", "template", "spec", "template", "spec")).To(gomega.Succeed())
+	g.Expect(unstructured.SetNestedField(request.FederatedObject.Object, int64(2), "spec", "template", "spec", "replicas")).
+		To(gomega.Succeed())
+	for _, cluster := range request.Clusters {
+		cluster.Spec.ReplicaEstimator = &fedcorev1a1.ReplicaEstimatorConfig{URL: "http://169.254.169.254/"}
+	}
+
+	estimator := &recordingEstimator{}
+	scheduler := getSimulationScheduler()
+	scheduler.typeConfig.Spec.PathDefinition.PodSpec = "spec.template.spec"
+	scheduler.typeConfig.Spec.PathDefinition.ReplicasSpec = "spec.replicas"
+	scheduler.replicaEstimator = estimator
+
+	_, err := scheduler.Simulate(context.TODO(), request)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(estimator.clusters).To(gomega.BeEmpty())
+}
+
+func TestSimulateWithoutPolicy(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	request := getSimulationRequest()
+	request.PropagationPolicy = nil
+	_, err := getSimulationScheduler().Simulate(context.TODO(), request)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestSimulationHandler(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	handler := NewSimulationHandler()
+	handler.Register("deployments.apps", getSimulationScheduler())
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	body, err := json.Marshal(getSimulationRequest())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	resp, err := http.Post(server.URL+SimulationPathPrefix+"deployments.apps", "application/json", bytes.NewReader(body))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer resp.Body.Close()
+	g.Expect(resp.StatusCode).To(gomega.Equal(http.StatusOK))
+
+	result := &SimulationResult{}
+	g.Expect(json.NewDecoder(resp.Body).Decode(result)).To(gomega.Succeed())
+	g.Expect(result.SuggestedClusters).To(gomega.Equal(map[string]*int64{"cluster-2": nil}))
+
+	handler.Unregister("deployments.apps")
+	notFoundResp, err := http.Post(server.URL+SimulationPathPrefix+"deployments.apps", "application/json", bytes.NewReader(body))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer notFoundResp.Body.Close()
+	g.Expect(notFoundResp.StatusCode).To(gomega.Equal(http.StatusNotFound))
+}
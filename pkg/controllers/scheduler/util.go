@@ -17,6 +17,8 @@ limitations under the License.
 package scheduler
 
 import (
+	"encoding/json"
+	"fmt"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -26,7 +28,9 @@ import (
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/core"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	annotationutil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/annotation"
 	utilunstructured "github.com/kubewharf/kubeadmiral/pkg/controllers/util/unstructured"
 )
 
@@ -183,3 +187,27 @@ func OverrideUpdateNeeded(
 
 	return checkLen != resultLen
 }
+
+// setSchedulingExplanation records the explanation of the scheduling result in the federated object's annotations, or
+// removes the annotation if there is no explanation. The explanation is truncated so that the size of the annotation
+// does not grow with the number of clusters.
+func setSchedulingExplanation(fedObject *unstructured.Unstructured, explanation *core.ScheduleExplanation) error {
+	if explanation == nil {
+		_, err := annotationutil.RemoveAnnotation(fedObject, SchedulingExplanationAnnotation)
+		return err
+	}
+
+	explanationBytes, err := json.Marshal(explanation.Truncate(maxExplainedClusters))
+	if err != nil {
+		return fmt.Errorf("failed to marshal scheduling explanation: %w", err)
+	}
+	if len(explanationBytes) > maxSchedulingExplanationBytes {
+		// The per-cluster details are too long, e.g. because of long rejection reasons, so only the summary is kept.
+		if explanationBytes, err = json.Marshal(explanation.Truncate(0)); err != nil {
+			return fmt.Errorf("failed to marshal scheduling explanation: %w", err)
+		}
+	}
+
+	_, err = annotationutil.AddAnnotation(fedObject, SchedulingExplanationAnnotation, string(explanationBytes))
+	return err
+}
@@ -22,16 +22,15 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
-	utilnet "k8s.io/apimachinery/pkg/util/net"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/client-go/rest"
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	schedwebhookv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/schedulerwebhook/v1alpha1"
 	pluginv1a1 "github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/extensions/webhook/v1alpha1"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/runtime"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 )
 
 // SchedulerSupportedPayloadVersions is the list of payload versions supported by the scheduler.
@@ -66,7 +65,7 @@ func (s *Scheduler) cacheWebhookPlugin(config *fedcorev1a1.SchedulerPluginWebhoo
 		return
 	}
 
-	transport, err := makeTransport(&config.Spec)
+	transport, err := util.NewWebhookTransport(config.Spec.TLSConfig)
 	if err != nil {
 		logger.Error(err, "Failed to create webhook transport")
 		s.eventRecorder.Eventf(
@@ -112,22 +111,6 @@ func (s *Scheduler) cacheWebhookPlugin(config *fedcorev1a1.SchedulerPluginWebhoo
 	)
 }
 
-func makeTransport(config *fedcorev1a1.SchedulerPluginWebhookConfigurationSpec) (http.RoundTripper, error) {
-	var restConfig rest.Config
-	if config.TLSConfig != nil {
-		restConfig.TLSClientConfig.Insecure = config.TLSConfig.Insecure
-		restConfig.TLSClientConfig.ServerName = config.TLSConfig.ServerName
-		restConfig.TLSClientConfig.CertData = config.TLSConfig.CertData
-		restConfig.TLSClientConfig.KeyData = config.TLSConfig.KeyData
-		restConfig.TLSClientConfig.CAData = config.TLSConfig.CAData
-	}
-	tlsConfig, err := rest.TLSConfigFor(&restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("error creating TLS config: %w", err)
-	}
-	return utilnet.SetTransportDefaults(&http.Transport{TLSClientConfig: tlsConfig}), nil
-}
-
 func (s *Scheduler) webhookPluginRegistry() (runtime.Registry, error) {
 	registry := runtime.Registry{}
 
@@ -287,6 +287,7 @@ func doTest(t *testing.T, clientTLS *fedcorev1a1.WebhookTLSConfig, serverTLS *tl
 		ktesting.NewLogger(t, ktesting.NewConfig(ktesting.Verbosity(3))),
 		typeConfig, kubeClient, fedClient, dynamicClient,
 		dynInformerFactory.ForResource(gvr),
+		nil,
 		fedInformerFactory.Core().V1alpha1().PropagationPolicies(),
 		fedInformerFactory.Core().V1alpha1().ClusterPropagationPolicies(),
 		fedInformerFactory.Core().V1alpha1().FederatedClusters(),
@@ -38,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -45,7 +46,10 @@ import (
 	"k8s.io/klog/v2"
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
 	genericclient "github.com/kubewharf/kubeadmiral/pkg/client/generic"
+	fedinformers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions"
+	fedcorev1a1listers "github.com/kubewharf/kubeadmiral/pkg/client/listers/core/v1alpha1"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/annotation"
@@ -86,6 +90,10 @@ type StatusController struct {
 	// Informer for the status of the federated type
 	statusController cache.Controller
 
+	// Informer for the PulledObjects of clusters in Pull mode, whose agents report the collected fields
+	pulledObjectInformer cache.SharedIndexInformer
+	pulledObjectLister   fedcorev1a1listers.PulledObjectLister
+
 	worker worker.ReconcileWorker
 
 	clusterAvailableDelay         time.Duration
@@ -140,6 +148,10 @@ func newStatusController(
 		return nil, err
 	}
 	client := genericclient.NewForConfigOrDieWithUserAgent(controllerConfig.KubeConfig, userAgent)
+	fedClient, err := fedclient.NewForConfig(configCopy)
+	if err != nil {
+		return nil, err
+	}
 
 	federatedTypeClient, err := util.NewResourceClient(controllerConfig.KubeConfig, &federatedAPIResource)
 	if err != nil {
@@ -174,7 +186,7 @@ func newStatusController(
 		worker.WorkerTiming{},
 		controllerConfig.WorkerCount,
 		controllerConfig.Metrics,
-		delayingdeliver.NewMetricTags("status-worker", typeConfig.GetTargetType().Kind),
+		delayingdeliver.NewFTCMetricTags("status-worker", typeConfig.GetTargetType().Kind, typeConfig.Name),
 	)
 
 	// Build deliverer for triggering cluster reconciliations.
@@ -197,6 +209,24 @@ func newStatusController(
 		enqueueObj,
 		controllerConfig.Metrics,
 	)
+	pulledObjectInformer := fedinformers.NewSharedInformerFactoryWithOptions(
+		fedClient,
+		util.NoResyncPeriod,
+		fedinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = util.PulledObjectTypeSelector(typeConfig.Name).String()
+		}),
+	).Core().V1alpha1().PulledObjects()
+	s.pulledObjectInformer = pulledObjectInformer.Informer()
+	s.pulledObjectInformer.AddEventHandler(util.NewTriggerOnAllChanges(func(obj pkgruntime.Object) {
+		qualifiedName, err := util.PulledObjectTargetName(obj.(*fedcorev1a1.PulledObject))
+		if err != nil {
+			logger.Error(err, "Failed to get target of pulled object", "pulled-object", common.NewQualifiedName(obj))
+			return
+		}
+		s.worker.EnqueueWithDelay(qualifiedName, s.memberObjectEnqueueDelay)
+	}))
+	s.pulledObjectLister = pulledObjectInformer.Lister()
+
 	logger.Info("Creating new FederatedInformer")
 
 	targetAPIResource := typeConfig.GetTargetType()
@@ -240,9 +270,10 @@ func (s *StatusController) minimizeLatency() {
 // Run runs the status controller
 func (s *StatusController) Run(stopChan <-chan struct{}) {
 	go s.clusterDeliverer.RunMetricLoop(stopChan, 30*time.Second, s.metrics,
-		delayingdeliver.NewMetricTags("status-clusterDeliverer", s.typeConfig.GetTargetType().Kind))
+		delayingdeliver.NewFTCMetricTags("status-clusterDeliverer", s.typeConfig.GetTargetType().Kind, s.typeConfig.Name))
 	go s.federatedController.Run(stopChan)
 	go s.statusController.Run(stopChan)
+	go s.pulledObjectInformer.Run(stopChan)
 	s.informer.Start()
 	s.clusterDeliverer.StartWithHandler(func(_ *delayingdeliver.DelayingDelivererItem) {
 		s.reconcileOnClusterChange()
@@ -277,6 +308,10 @@ func (s *StatusController) HasSynced() bool {
 		s.logger.V(3).Info("Status not synced")
 		return false
 	}
+	if !s.pulledObjectInformer.HasSynced() {
+		s.logger.V(3).Info("PulledObject list not synced")
+		return false
+	}
 	return true
 }
 
@@ -296,11 +331,11 @@ func (s *StatusController) reconcile(qualifiedName common.QualifiedName) (reconc
 	keyedLogger := s.logger.WithValues("object", key)
 	ctx := klog.NewContext(context.TODO(), keyedLogger)
 
-	s.metrics.Rate("status.throughput", 1)
+	s.metrics.Rate("status.throughput", 1, stats.Tag{Name: "ftc", Value: s.typeConfig.Name})
 	keyedLogger.V(3).Info("Starting reconcile")
 	startTime := time.Now()
 	defer func() {
-		s.metrics.Duration("status.latency", startTime)
+		s.metrics.Duration("status.latency", startTime, stats.Tag{Name: "ftc", Value: s.typeConfig.Name})
 		keyedLogger.WithValues("duration", time.Since(startTime), "status", reconcileStatus.String()).
 			V(3).Info("Finished reconcile")
 	}()
@@ -327,7 +362,13 @@ func (s *StatusController) reconcile(qualifiedName common.QualifiedName) (reconc
 		return worker.Result{RequeueAfter: &s.clusterAvailableDelay}
 	}
 
-	clusterStatus := s.clusterStatuses(ctx, fedObject, clusterNames, qualifiedName)
+	pullClusterNames, err := s.pullClusterNames()
+	if err != nil {
+		keyedLogger.Error(err, "Failed to get cluster list")
+		return worker.Result{RequeueAfter: &s.clusterAvailableDelay}
+	}
+
+	clusterStatus := s.clusterStatuses(ctx, fedObject, clusterNames, pullClusterNames, qualifiedName)
 
 	existingStatus, err := s.objFromCache(s.statusStore, key)
 	if err != nil {
@@ -487,11 +528,51 @@ func (s *StatusController) clusterNames() ([]string, error) {
 	return clusterNames, nil
 }
 
+// pullClusterNames returns the names of the ready clusters in Pull mode, which are not watched by the federated
+// informer.
+func (s *StatusController) pullClusterNames() ([]string, error) {
+	clusters, err := s.informer.GetJoinedClusters()
+	if err != nil {
+		return nil, err
+	}
+	clusterNames := []string{}
+	for _, cluster := range clusters {
+		if cluster.IsPullMode() && util.IsClusterReady(&cluster.Status) {
+			clusterNames = append(clusterNames, cluster.Name)
+		}
+	}
+
+	return clusterNames, nil
+}
+
+// pulledObjectFields returns the fields collected by the agent of a cluster in Pull mode from the object in its cluster.
+func (s *StatusController) pulledObjectFields(
+	clusterName string,
+	qualifiedName common.QualifiedName,
+) (*unstructured.Unstructured, bool, error) {
+	pulledObject, err := s.pulledObjectLister.PulledObjects(util.PullNamespace(clusterName)).
+		Get(util.PulledObjectName(s.typeConfig.Name, qualifiedName))
+	if apierrors.IsNotFound(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if pulledObject.Status.CollectedFields == nil {
+		return nil, false, nil
+	}
+	collectedFields := map[string]interface{}{}
+	if err := json.Unmarshal(pulledObject.Status.CollectedFields.Raw, &collectedFields); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal collected fields: %w", err)
+	}
+	return &unstructured.Unstructured{Object: collectedFields}, true, nil
+}
+
 // clusterStatuses returns the resource status in member cluster.
 func (s *StatusController) clusterStatuses(
 	ctx context.Context,
 	fedObject *unstructured.Unstructured,
-	clusterNames []string,
+	clusterNames, pullClusterNames []string,
 	qualifiedName common.QualifiedName,
 ) []util.ResourceClusterStatus {
 	clusterStatus := []util.ResourceClusterStatus{}
@@ -499,16 +580,25 @@ func (s *StatusController) clusterStatuses(
 	// collect errors during status collection and record them as event
 	errList := []string{}
 
-	for _, clusterName := range clusterNames {
+	isPullCluster := sets.NewString(pullClusterNames...)
+	for _, clusterName := range append(append([]string{}, clusterNames...), pullClusterNames...) {
 		resourceClusterStatus := util.ResourceClusterStatus{ClusterName: clusterName}
 
-		clusterObj, exist, err := util.GetClusterObject(
-			ctx,
-			s.informer,
-			clusterName,
-			qualifiedName,
-			s.typeConfig.GetTargetType(),
-		)
+		var clusterObj *unstructured.Unstructured
+		var exist bool
+		var err error
+		if isPullCluster.Has(clusterName) {
+			// The agent collects the fields from the object in its cluster.
+			clusterObj, exist, err = s.pulledObjectFields(clusterName, qualifiedName)
+		} else {
+			clusterObj, exist, err = util.GetClusterObject(
+				ctx,
+				s.informer,
+				clusterName,
+				qualifiedName,
+				s.typeConfig.GetTargetType(),
+			)
+		}
 		if err != nil {
 			keyedLogger.WithValues("cluster-name", clusterName).Error(err, "Failed to get object from cluster")
 			errMsg := fmt.Sprintf("Failed to get object from cluster, error info: %s", err.Error())
@@ -521,39 +611,16 @@ func (s *StatusController) clusterStatuses(
 			continue
 		}
 
-		collectedFields := map[string]interface{}{}
-		failedFields := []string{}
-
+		var fields []string
 		if s.typeConfig.Spec.StatusCollection != nil {
-			for _, field := range s.typeConfig.Spec.StatusCollection.Fields {
-				fieldVal, found, err := unstructured.NestedFieldCopy(
-					clusterObj.Object,
-					strings.Split(field, ".")...)
-				if err != nil || !found {
-					keyedLogger.WithValues("status-field", field, "cluster-name", clusterName).
-						Error(err, "Failed to get status field value")
-					if err != nil {
-						failedFields = append(failedFields, fmt.Sprintf("%s: %s", field, err.Error()))
-					} else {
-						failedFields = append(failedFields, fmt.Sprintf("%s: not found", field))
-					}
-					continue
-				}
-
-				err = unstructured.SetNestedField(collectedFields, fieldVal, strings.Split(field, ".")...)
-				if err != nil {
-					keyedLogger.WithValues("status-field", field, "cluster-name", clusterName).
-						Error(err, "Failed to set status field value")
-					continue
-				}
-			}
+			fields = s.typeConfig.Spec.StatusCollection.Fields
 		}
+		collectedFields, failedFields := util.CollectStatusFields(clusterObj.Object, fields)
 
 		resourceClusterStatus.CollectedFields = collectedFields
 		if len(failedFields) > 0 {
-			sort.Slice(failedFields, func(i, j int) bool {
-				return failedFields[i] < failedFields[j]
-			})
+			keyedLogger.WithValues("cluster-name", clusterName, "failed-fields", failedFields).
+				Error(nil, "Failed to get status field values")
 			resourceClusterStatus.Error = fmt.Sprintf("Failed to get those fields: %s", strings.Join(failedFields, ", "))
 			errList = append(errList, fmt.Sprintf("cluster-name: %s, error-info: %s", clusterName, resourceClusterStatus.Error))
 		}
@@ -117,6 +117,12 @@ func newStatusAggregator(controllerConfig *util.ControllerConfig,
 		return nil, errors.Errorf("Object federation is not supported for %q", federatedAPIResource.Kind)
 	}
 	plugin := plugins.GetPlugin(sourceAPIResource)
+	if plugin == nil && typeConfig.Spec.StatusAggregationWebhook != nil {
+		var err error
+		if plugin, err = plugins.NewWebhookPluginForConfig(typeConfig.Spec.StatusAggregationWebhook); err != nil {
+			return nil, errors.Wrapf(err, "failed to create statuses aggregation webhook plugin for %q", sourceAPIResource.Kind)
+		}
+	}
 	if plugin == nil {
 		return nil, errors.Errorf("statuses aggregation plugin is not found for %q", sourceAPIResource.Kind)
 	}
@@ -157,7 +163,7 @@ func newStatusAggregator(controllerConfig *util.ControllerConfig,
 		worker.WorkerTiming{},
 		controllerConfig.WorkerCount,
 		controllerConfig.Metrics,
-		delayingdeliver.NewMetricTags("statusaggregator-worker", typeConfig.GetTargetType().Kind),
+		delayingdeliver.NewFTCMetricTags("statusaggregator-worker", typeConfig.GetTargetType().Kind, typeConfig.Name),
 	)
 	enqueueObj := a.worker.EnqueueObject
 	targetNamespace := controllerConfig.TargetNamespace
@@ -210,7 +216,7 @@ func (a *StatusAggregator) Run(stopChan <-chan struct{}) {
 		a.reconcileOnClusterChange()
 	})
 	go a.clusterDeliverer.RunMetricLoop(stopChan, 30*time.Second, a.metrics,
-		delayingdeliver.NewMetricTags("schedulingpreference-clusterDeliverer", a.typeConfig.GetTargetType().Kind))
+		delayingdeliver.NewFTCMetricTags("schedulingpreference-clusterDeliverer", a.typeConfig.GetTargetType().Kind, a.typeConfig.Name))
 	if !cache.WaitForNamedCacheSync(a.name, stopChan, a.HasSynced) {
 		return
 	}
@@ -252,11 +258,11 @@ func (a *StatusAggregator) reconcile(qualifiedName common.QualifiedName) (status
 	logger := a.logger.WithValues("object", key)
 	ctx := klog.NewContext(context.TODO(), logger)
 
-	a.metrics.Rate("status-aggregator.throughput", 1)
+	a.metrics.Rate("status-aggregator.throughput", 1, stats.Tag{Name: "ftc", Value: a.typeConfig.Name})
 	logger.V(3).Info("Starting to reconcile")
 	startTime := time.Now()
 	defer func() {
-		a.metrics.Duration("status-aggregator.latency", startTime)
+		a.metrics.Duration("status-aggregator.latency", startTime, stats.Tag{Name: "ftc", Value: a.typeConfig.Name})
 		logger.V(3).WithValues("duration", time.Since(startTime), "status", status.String()).
 			Info("Finished reconciling")
 	}()
@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+)
+
+type DaemonSetPlugin struct{}
+
+func NewDaemonSetPlugin() *DaemonSetPlugin {
+	return &DaemonSetPlugin{}
+}
+
+func (receiver *DaemonSetPlugin) AggregateStatuses(
+	ctx context.Context,
+	sourceObject, fedObject *unstructured.Unstructured,
+	clusterObjs map[string]interface{},
+	clusterObjsUpToDate bool,
+) (*unstructured.Unstructured, bool, error) {
+	logger := klog.FromContext(ctx).WithValues("status-aggregator-plugin", "daemonsets")
+
+	needUpdateObservedGeneration := clusterObjsUpToDate
+	if !clusterObjsUpToDate {
+		logger.V(3).Info("Cluster objects are not up to date")
+	}
+
+	clusterSyncedGenerations, err := getClusterSyncedGenerations(fedObject)
+	if err != nil {
+		return nil, false, err
+	}
+
+	aggregatedStatus := &appsv1.DaemonSetStatus{}
+
+	for clusterName, clusterObj := range clusterObjs {
+		logger := logger.WithValues("cluster-name", clusterName)
+
+		daemonSetStatus := &appsv1.DaemonSetStatus{}
+		found, err := getClusterObjectStatus(clusterObj, daemonSetStatus)
+		if err != nil {
+			logger.Error(err, "Failed to get status of cluster object")
+			return nil, false, err
+		}
+		if !found {
+			needUpdateObservedGeneration = false
+			continue
+		}
+
+		// If the cluster's controller has not observed the latest synced generation, its status will be out-of-date.
+		if gen, exist := clusterSyncedGenerations[clusterName]; !exist || gen != daemonSetStatus.ObservedGeneration {
+			needUpdateObservedGeneration = false
+		}
+
+		aggregatedStatus.CurrentNumberScheduled += daemonSetStatus.CurrentNumberScheduled
+		aggregatedStatus.NumberMisscheduled += daemonSetStatus.NumberMisscheduled
+		aggregatedStatus.DesiredNumberScheduled += daemonSetStatus.DesiredNumberScheduled
+		aggregatedStatus.NumberReady += daemonSetStatus.NumberReady
+		aggregatedStatus.UpdatedNumberScheduled += daemonSetStatus.UpdatedNumberScheduled
+		aggregatedStatus.NumberAvailable += daemonSetStatus.NumberAvailable
+		aggregatedStatus.NumberUnavailable += daemonSetStatus.NumberUnavailable
+	}
+
+	if aggregatedStatus.ObservedGeneration, err = getObservedGeneration(
+		sourceObject,
+		needUpdateObservedGeneration,
+	); err != nil {
+		return nil, false, err
+	}
+
+	needUpdate, err := setSourceObjectStatus(sourceObject, aggregatedStatus)
+	if err != nil {
+		logger.Error(err, "Failed to update status of source object")
+		return nil, false, err
+	}
+
+	return sourceObject, needUpdate, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+)
+
+func TestDaemonSetPlugin(t *testing.T) {
+	ctx := klog.NewContext(context.Background(), klog.Background())
+
+	sourceObject := toUnstructured(t, &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Generation: 3},
+	})
+	clusterObjs := map[string]interface{}{
+		"c1": toUnstructured(t, &appsv1.DaemonSet{
+			Status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     1,
+				CurrentNumberScheduled: 3,
+				DesiredNumberScheduled: 3,
+				NumberReady:            2,
+				UpdatedNumberScheduled: 3,
+				NumberAvailable:        2,
+				NumberUnavailable:      1,
+			},
+		}),
+		"c2": toUnstructured(t, &appsv1.DaemonSet{
+			Status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     4,
+				CurrentNumberScheduled: 5,
+				NumberMisscheduled:     1,
+				DesiredNumberScheduled: 5,
+				NumberReady:            5,
+				UpdatedNumberScheduled: 4,
+				NumberAvailable:        5,
+			},
+		}),
+		// cluster objects without status are skipped
+		"c3": &unstructured.Unstructured{Object: map[string]interface{}{}},
+	}
+	fedObject := newFedObjectWithClusterGenerations(map[string]int64{"c1": 1, "c2": 4})
+
+	got, needUpdate, err := NewDaemonSetPlugin().AggregateStatuses(ctx, sourceObject, fedObject, clusterObjs, true)
+	assert.NoError(t, err)
+	assert.True(t, needUpdate)
+
+	gotDaemonSet := &appsv1.DaemonSet{}
+	assert.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(got.Object, gotDaemonSet))
+	assert.Equal(t, appsv1.DaemonSetStatus{
+		// c3 has no status yet, so the latest generation has not been observed
+		ObservedGeneration:     0,
+		CurrentNumberScheduled: 8,
+		NumberMisscheduled:     1,
+		DesiredNumberScheduled: 8,
+		NumberReady:            7,
+		UpdatedNumberScheduled: 7,
+		NumberAvailable:        7,
+		NumberUnavailable:      1,
+	}, gotDaemonSet.Status)
+
+	delete(clusterObjs, "c3")
+	got, needUpdate, err = NewDaemonSetPlugin().AggregateStatuses(ctx, got, fedObject, clusterObjs, true)
+	assert.NoError(t, err)
+	assert.True(t, needUpdate)
+	observedGeneration, _, _ := unstructured.NestedInt64(got.Object, "status", "observedGeneration")
+	assert.Equal(t, int64(3), observedGeneration)
+}
@@ -143,6 +143,7 @@ func (receiver *DeploymentPlugin) AggregateStatuses(
 	if err != nil {
 		return nil, false, err
 	}
+	retainRolledBackCondition(oldStatus, newStatus)
 
 	// update status of source object if needed
 	if !reflect.DeepEqual(newStatus, oldStatus) {
@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+)
+
+// IngressPlugin aggregates the load balancer ingress entries of ingresses in all clusters.
+type IngressPlugin struct{}
+
+func NewIngressPlugin() *IngressPlugin {
+	return &IngressPlugin{}
+}
+
+func (receiver *IngressPlugin) AggregateStatuses(
+	ctx context.Context,
+	sourceObject, fedObject *unstructured.Unstructured,
+	clusterObjs map[string]interface{},
+	clusterObjsUpToDate bool,
+) (*unstructured.Unstructured, bool, error) {
+	logger := klog.FromContext(ctx).WithValues("status-aggregator-plugin", "ingresses")
+
+	var ingresses []networkingv1.IngressLoadBalancerIngress
+	for clusterName, clusterObj := range clusterObjs {
+		logger := logger.WithValues("cluster-name", clusterName)
+
+		ingressStatus := &networkingv1.IngressStatus{}
+		found, err := getClusterObjectStatus(clusterObj, ingressStatus)
+		if err != nil {
+			logger.Error(err, "Failed to get status of cluster object")
+			return nil, false, err
+		}
+		if !found {
+			continue
+		}
+
+		ingresses = append(ingresses, ingressStatus.LoadBalancer.Ingress...)
+	}
+
+	aggregatedStatus := &networkingv1.IngressStatus{
+		LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+			Ingress: mergeLoadBalancerIngresses(ingresses, func(ingress networkingv1.IngressLoadBalancerIngress) (string, string) {
+				return ingress.IP, ingress.Hostname
+			}),
+		},
+	}
+
+	needUpdate, err := setSourceObjectStatus(sourceObject, aggregatedStatus)
+	if err != nil {
+		logger.Error(err, "Failed to update status of source object")
+		return nil, false, err
+	}
+
+	return sourceObject, needUpdate, nil
+}
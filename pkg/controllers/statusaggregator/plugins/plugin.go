@@ -22,6 +22,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -40,10 +41,13 @@ type Plugin interface {
 }
 
 var pluginsMap = map[schema.GroupVersionKind]Plugin{
-	appsv1.SchemeGroupVersion.WithKind(common.DeploymentKind):  NewDeploymentPlugin(),
-	appsv1.SchemeGroupVersion.WithKind(common.StatefulSetKind): NewSingleClusterPlugin(),
-	batchv1.SchemeGroupVersion.WithKind(common.JobKind):        NewJobPlugin(),
-	corev1.SchemeGroupVersion.WithKind(common.PodKind):         NewPodPlugin(),
+	appsv1.SchemeGroupVersion.WithKind(common.DeploymentKind):    NewDeploymentPlugin(),
+	appsv1.SchemeGroupVersion.WithKind(common.StatefulSetKind):   NewStatefulSetPlugin(),
+	appsv1.SchemeGroupVersion.WithKind(common.DaemonSetKind):     NewDaemonSetPlugin(),
+	batchv1.SchemeGroupVersion.WithKind(common.JobKind):          NewJobPlugin(),
+	corev1.SchemeGroupVersion.WithKind(common.PodKind):           NewPodPlugin(),
+	corev1.SchemeGroupVersion.WithKind(common.ServiceKind):       NewServicePlugin(),
+	networkingv1.SchemeGroupVersion.WithKind(common.IngressKind): NewIngressPlugin(),
 }
 
 func GetPlugin(apiResource *metav1.APIResource) Plugin {
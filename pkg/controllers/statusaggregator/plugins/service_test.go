@@ -0,0 +1,102 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+)
+
+func TestServicePlugin(t *testing.T) {
+	ctx := klog.NewContext(context.Background(), klog.Background())
+
+	sourceObject := toUnstructured(t, &corev1.Service{})
+	clusterObjs := map[string]interface{}{
+		"c1": toUnstructured(t, &corev1.Service{
+			Status: corev1.ServiceStatus{
+				LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.2"}, {Hostname: "lb.example.com"}},
+				},
+			},
+		}),
+		"c2": toUnstructured(t, &corev1.Service{
+			Status: corev1.ServiceStatus{
+				LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}, {Hostname: "lb.example.com"}},
+				},
+			},
+		}),
+		"c3": toUnstructured(t, &corev1.Service{}),
+	}
+
+	got, needUpdate, err := NewServicePlugin().AggregateStatuses(ctx, sourceObject, nil, clusterObjs, true)
+	assert.NoError(t, err)
+	assert.True(t, needUpdate)
+
+	gotService := &corev1.Service{}
+	assert.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(got.Object, gotService))
+	assert.Equal(t, []corev1.LoadBalancerIngress{
+		{Hostname: "lb.example.com"},
+		{IP: "10.0.0.1"},
+		{IP: "10.0.0.2"},
+	}, gotService.Status.LoadBalancer.Ingress)
+
+	_, needUpdate, err = NewServicePlugin().AggregateStatuses(ctx, got, nil, clusterObjs, true)
+	assert.NoError(t, err)
+	assert.False(t, needUpdate)
+}
+
+func TestIngressPlugin(t *testing.T) {
+	ctx := klog.NewContext(context.Background(), klog.Background())
+
+	sourceObject := toUnstructured(t, &networkingv1.Ingress{})
+	clusterObjs := map[string]interface{}{
+		"c1": toUnstructured(t, &networkingv1.Ingress{
+			Status: networkingv1.IngressStatus{
+				LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+					Ingress: []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.2"}},
+				},
+			},
+		}),
+		"c2": toUnstructured(t, &networkingv1.Ingress{
+			Status: networkingv1.IngressStatus{
+				LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+					Ingress: []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}},
+				},
+			},
+		}),
+		"c3": &unstructured.Unstructured{Object: map[string]interface{}{}},
+	}
+
+	got, needUpdate, err := NewIngressPlugin().AggregateStatuses(ctx, sourceObject, nil, clusterObjs, true)
+	assert.NoError(t, err)
+	assert.True(t, needUpdate)
+
+	gotIngress := &networkingv1.Ingress{}
+	assert.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(got.Object, gotIngress))
+	assert.Equal(t, []networkingv1.IngressLoadBalancerIngress{
+		{IP: "10.0.0.1"},
+		{IP: "10.0.0.2"},
+	}, gotIngress.Status.LoadBalancer.Ingress)
+}
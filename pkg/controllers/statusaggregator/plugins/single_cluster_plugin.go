@@ -27,7 +27,7 @@ import (
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 )
 
-// NewSingleClusterPlugin aggregates status for resources that are only dispatched to a single cluster, such as Job, StatefulSet.
+// NewSingleClusterPlugin aggregates status for resources that are only dispatched to a single cluster.
 func NewSingleClusterPlugin() *SingleClusterPlugin {
 	return &SingleClusterPlugin{}
 }
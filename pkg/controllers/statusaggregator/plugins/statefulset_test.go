@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+func toUnstructured(t *testing.T, obj interface{}) *unstructured.Unstructured {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	return &unstructured.Unstructured{Object: content}
+}
+
+func newFedObjectWithClusterGenerations(clusterGenerations map[string]int64) *unstructured.Unstructured {
+	clusters := []interface{}{}
+	for cluster, generation := range clusterGenerations {
+		clusters = append(clusters, map[string]interface{}{"name": cluster, "generation": generation})
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"clusters": clusters},
+	}}
+}
+
+func TestStatefulSetPlugin(t *testing.T) {
+	sourceObject := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Generation: 2},
+		Status:     appsv1.StatefulSetStatus{ObservedGeneration: 1},
+	}
+	clusterObject := func(observedGeneration int64, revision string, replicas int32) *unstructured.Unstructured {
+		return toUnstructured(t, &appsv1.StatefulSet{
+			Status: appsv1.StatefulSetStatus{
+				ObservedGeneration: observedGeneration,
+				Replicas:           replicas,
+				ReadyReplicas:      replicas,
+				CurrentReplicas:    replicas,
+				UpdatedReplicas:    replicas,
+				AvailableReplicas:  replicas - 1,
+				CurrentRevision:    revision,
+				UpdateRevision:     revision,
+			},
+		})
+	}
+
+	tests := []struct {
+		name                string
+		clusterObjs         map[string]interface{}
+		clusterObjsUpToDate bool
+		expectedStatus      appsv1.StatefulSetStatus
+	}{
+		{
+			name: "consistent revisions, observed latest generation",
+			clusterObjs: map[string]interface{}{
+				"c1": clusterObject(5, "test-abc", 2),
+				"c2": clusterObject(7, "test-abc", 3),
+			},
+			clusterObjsUpToDate: true,
+			expectedStatus: appsv1.StatefulSetStatus{
+				ObservedGeneration: 2,
+				Replicas:           5,
+				ReadyReplicas:      5,
+				CurrentReplicas:    5,
+				UpdatedReplicas:    5,
+				AvailableReplicas:  3,
+				CurrentRevision:    "test-abc",
+				UpdateRevision:     "test-abc",
+			},
+		},
+		{
+			name: "inconsistent revisions, stale cluster status",
+			clusterObjs: map[string]interface{}{
+				"c1": clusterObject(5, "test-abc", 2),
+				"c2": clusterObject(6, "test-def", 3),
+			},
+			clusterObjsUpToDate: true,
+			expectedStatus: appsv1.StatefulSetStatus{
+				ObservedGeneration: 1,
+				Replicas:           5,
+				ReadyReplicas:      5,
+				CurrentReplicas:    5,
+				UpdatedReplicas:    5,
+				AvailableReplicas:  3,
+			},
+		},
+		{
+			name: "cluster objects not up to date",
+			clusterObjs: map[string]interface{}{
+				"c1": clusterObject(5, "test-abc", 2),
+			},
+			clusterObjsUpToDate: false,
+			expectedStatus: appsv1.StatefulSetStatus{
+				ObservedGeneration: 1,
+				Replicas:           2,
+				ReadyReplicas:      2,
+				CurrentReplicas:    2,
+				UpdatedReplicas:    2,
+				AvailableReplicas:  1,
+				CurrentRevision:    "test-abc",
+				UpdateRevision:     "test-abc",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := klog.NewContext(context.Background(), klog.Background())
+			fedObject := newFedObjectWithClusterGenerations(map[string]int64{"c1": 5, "c2": 7})
+
+			got, needUpdate, err := NewStatefulSetPlugin().AggregateStatuses(
+				ctx,
+				toUnstructured(t, sourceObject),
+				fedObject,
+				tt.clusterObjs,
+				tt.clusterObjsUpToDate,
+			)
+			assert.NoError(t, err)
+			assert.True(t, needUpdate)
+
+			gotStatefulSet := &appsv1.StatefulSet{}
+			assert.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(got.Object, gotStatefulSet))
+			assert.Equal(t, tt.expectedStatus, gotStatefulSet.Status)
+
+			_, needUpdate, err = NewStatefulSetPlugin().AggregateStatuses(
+				ctx,
+				got,
+				fedObject,
+				tt.clusterObjs,
+				tt.clusterObjsUpToDate,
+			)
+			assert.NoError(t, err)
+			assert.False(t, needUpdate)
+		})
+	}
+}
+
+func TestStatefulSetPluginRetainsRolledBackCondition(t *testing.T) {
+	rolledBackCondition := appsv1.StatefulSetCondition{
+		Type:    common.RolledBackConditionType,
+		Status:  corev1.ConditionTrue,
+		Reason:  "ProgressDeadlineExceeded",
+		Message: "Rolled back from revision test-def to revision test-abc",
+	}
+	sourceObject := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Generation: 2},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 1,
+			Conditions:         []appsv1.StatefulSetCondition{rolledBackCondition},
+		},
+	}
+	clusterObjs := map[string]interface{}{
+		"c1": toUnstructured(t, &appsv1.StatefulSet{Status: appsv1.StatefulSetStatus{ObservedGeneration: 5, Replicas: 2}}),
+	}
+
+	got, needUpdate, err := NewStatefulSetPlugin().AggregateStatuses(
+		klog.NewContext(context.Background(), klog.Background()),
+		toUnstructured(t, sourceObject),
+		newFedObjectWithClusterGenerations(map[string]int64{"c1": 5}),
+		clusterObjs,
+		true,
+	)
+	assert.NoError(t, err)
+	assert.True(t, needUpdate)
+
+	gotStatefulSet := &appsv1.StatefulSet{}
+	assert.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(got.Object, gotStatefulSet))
+	assert.Equal(t, int32(2), gotStatefulSet.Status.Replicas)
+	assert.Equal(t, []appsv1.StatefulSetCondition{rolledBackCondition}, gotStatefulSet.Status.Conditions)
+}
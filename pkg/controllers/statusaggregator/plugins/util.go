@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+// getClusterSyncedGenerations returns the generations of the cluster objects last synced by the sync controller.
+func getClusterSyncedGenerations(fedObject *unstructured.Unstructured) (map[string]int64, error) {
+	resource := &fedtypesv1a1.GenericObjectWithStatus{}
+	if err := util.UnstructuredToInterface(fedObject, resource); err != nil {
+		return nil, fmt.Errorf("failed to unmarshall to generic resource: %w", err)
+	}
+
+	clusterSyncedGenerations := make(map[string]int64)
+	if resource.Status != nil {
+		for _, cluster := range resource.Status.Clusters {
+			clusterSyncedGenerations[cluster.Name] = cluster.Generation
+		}
+	}
+	return clusterSyncedGenerations, nil
+}
+
+// getObservedGeneration returns the observed generation for the aggregated status of the source object.
+// We only update the source object's observed generation after it has been federated and synced,
+// and we have aggregated the statuses of the latest cluster objects.
+// It is only at this point where we can "successfully observe" the latest generation of the source object.
+func getObservedGeneration(sourceObject *unstructured.Unstructured, needUpdateObservedGeneration bool) (int64, error) {
+	if needUpdateObservedGeneration {
+		return sourceObject.GetGeneration(), nil
+	}
+
+	observedGeneration, _, err := unstructured.NestedInt64(sourceObject.Object, common.StatusField, "observedGeneration")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get observed generation of source object: %w", err)
+	}
+	return observedGeneration, nil
+}
+
+// getClusterObjectStatus converts the status of the cluster object to status. It returns false if the cluster object
+// does not have a status yet.
+func getClusterObjectStatus(clusterObj interface{}, status interface{}) (bool, error) {
+	utd := clusterObj.(*unstructured.Unstructured)
+	content, found, err := unstructured.NestedMap(utd.Object, common.StatusField)
+	if err != nil {
+		return false, fmt.Errorf("failed to get status of cluster object: %w", err)
+	}
+	if !found || content == nil {
+		return false, nil
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, status); err != nil {
+		return false, fmt.Errorf("failed to convert the status of cluster object: %w", err)
+	}
+	return true, nil
+}
+
+// setSourceObjectStatus sets the status of the source object and returns whether it was changed.
+func setSourceObjectStatus(sourceObject *unstructured.Unstructured, status interface{}) (bool, error) {
+	newStatus, err := runtime.DefaultUnstructuredConverter.ToUnstructured(status)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert aggregated status to unstructured: %w", err)
+	}
+
+	oldStatus, _, err := unstructured.NestedMap(sourceObject.Object, common.StatusField)
+	if err != nil {
+		return false, fmt.Errorf("failed to get old status of source object: %w", err)
+	}
+	retainRolledBackCondition(oldStatus, newStatus)
+
+	if reflect.DeepEqual(newStatus, oldStatus) {
+		return false, nil
+	}
+
+	if err := unstructured.SetNestedMap(sourceObject.Object, newStatus, common.StatusField); err != nil {
+		return false, fmt.Errorf("failed to set the new status on source object: %w", err)
+	}
+	return true, nil
+}
+
+// retainRolledBackCondition copies the RolledBack condition set by the sync controller from the old status to the
+// aggregated status, since it is not reported by the clusters.
+func retainRolledBackCondition(oldStatus, newStatus map[string]interface{}) {
+	oldConditions, _, _ := unstructured.NestedSlice(oldStatus, common.ConditionsField)
+	for _, condition := range oldConditions {
+		if condition, ok := condition.(map[string]interface{}); ok && condition["type"] == common.RolledBackConditionType {
+			newConditions, _, _ := unstructured.NestedSlice(newStatus, common.ConditionsField)
+			newConditions = append(newConditions, condition)
+			_ = unstructured.SetNestedSlice(newStatus, newConditions, common.ConditionsField)
+			return
+		}
+	}
+}
+
+// mergeLoadBalancerIngresses merges the load balancer ingress entries of all clusters, removing duplicates and
+// sorting them by IP and hostname for a stable result.
+func mergeLoadBalancerIngresses[T any](entries []T, ipAndHostname func(T) (string, string)) []T {
+	merged := make([]T, 0, len(entries))
+	for _, entry := range entries {
+		duplicate := false
+		for _, existing := range merged {
+			if reflect.DeepEqual(entry, existing) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			merged = append(merged, entry)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		ipI, hostnameI := ipAndHostname(merged[i])
+		ipJ, hostnameJ := ipAndHostname(merged[j])
+		if ipI != ipJ {
+			return ipI < ipJ
+		}
+		return hostnameI < hostnameJ
+	})
+
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
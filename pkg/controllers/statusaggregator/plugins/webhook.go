@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	aggwebhookv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/statusaggregatorwebhook/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+// WebhookSupportedPayloadVersions is the list of payload versions supported by the webhook plugin.
+var WebhookSupportedPayloadVersions = sets.New(
+	aggwebhookv1a1.PayloadVersion,
+)
+
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WebhookPlugin delegates status aggregation to a webhook.
+type WebhookPlugin struct {
+	url    string
+	client HTTPClient
+}
+
+func NewWebhookPlugin(url string, client HTTPClient) *WebhookPlugin {
+	return &WebhookPlugin{
+		url:    url,
+		client: client,
+	}
+}
+
+// NewWebhookPluginForConfig returns the webhook plugin for the webhook config of a FederatedTypeConfig.
+func NewWebhookPluginForConfig(config *fedcorev1a1.StatusAggregationWebhookConfig) (Plugin, error) {
+	// Find the most preferred payload version that is supported by the plugin.
+	var payloadVersion string
+	for _, version := range config.PayloadVersions {
+		if WebhookSupportedPayloadVersions.Has(version) {
+			payloadVersion = version
+			break
+		}
+	}
+	if len(payloadVersion) == 0 {
+		return nil, fmt.Errorf(
+			"no supported payload version found, webhook supports %v, status aggregator supports %v",
+			config.PayloadVersions, WebhookSupportedPayloadVersions.UnsortedList(),
+		)
+	}
+
+	transport, err := util.NewWebhookTransport(config.TLSConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook transport: %w", err)
+	}
+
+	timeout := config.HTTPTimeout.Duration
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}
+
+	return NewWebhookPlugin(config.URL, client), nil
+}
+
+func (p *WebhookPlugin) AggregateStatuses(
+	ctx context.Context,
+	sourceObject, fedObject *unstructured.Unstructured,
+	clusterObjs map[string]interface{},
+	clusterObjsUpToDate bool,
+) (*unstructured.Unstructured, bool, error) {
+	logger := klog.FromContext(ctx).WithValues("status-aggregator-plugin", "webhook", "url", p.url)
+	ctx = klog.NewContext(ctx, logger)
+
+	clusterSyncedGenerations, err := getClusterSyncedGenerations(fedObject)
+	if err != nil {
+		return nil, false, err
+	}
+
+	req := aggwebhookv1a1.AggregateStatusesRequest{
+		SourceObject:           sourceObject.Object,
+		ClusterObjectStatuses:  make([]aggwebhookv1a1.ClusterObjectStatus, 0, len(clusterObjs)),
+		ClusterObjectsUpToDate: clusterObjsUpToDate,
+	}
+	for clusterName, clusterObj := range clusterObjs {
+		status, _, err := unstructured.NestedMap(clusterObj.(*unstructured.Unstructured).Object, common.StatusField)
+		if err != nil {
+			logger.Error(err, "Failed to get status of cluster object", "cluster-name", clusterName)
+			return nil, false, fmt.Errorf("failed to get status of cluster object: %w", err)
+		}
+		req.ClusterObjectStatuses = append(req.ClusterObjectStatuses, aggwebhookv1a1.ClusterObjectStatus{
+			ClusterName:      clusterName,
+			SyncedGeneration: clusterSyncedGenerations[clusterName],
+			Status:           status,
+		})
+	}
+	sort.Slice(req.ClusterObjectStatuses, func(i, j int) bool {
+		return req.ClusterObjectStatuses[i].ClusterName < req.ClusterObjectStatuses[j].ClusterName
+	})
+
+	resp := aggwebhookv1a1.AggregateStatusesResponse{}
+	if err := p.doRequest(ctx, &req, &resp); err != nil {
+		return nil, false, err
+	}
+	if len(resp.Error) > 0 {
+		return nil, false, fmt.Errorf("webhook returned error: %s", resp.Error)
+	}
+	if len(resp.Status) == 0 {
+		return sourceObject, false, nil
+	}
+
+	// Unmarshal with the apimachinery json package so that numbers are decoded as int64 where possible, as they are
+	// in the source object.
+	var status map[string]interface{}
+	if err := utiljson.Unmarshal(resp.Status, &status); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal aggregated status: %w", err)
+	}
+	if status == nil {
+		return sourceObject, false, nil
+	}
+
+	needUpdate, err := setSourceObjectStatus(sourceObject, &status)
+	if err != nil {
+		logger.Error(err, "Failed to update status of source object")
+		return nil, false, err
+	}
+
+	return sourceObject, needUpdate, nil
+}
+
+func (p *WebhookPlugin) doRequest(ctx context.Context, body any, response any) error {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("User-Agent", "kubeadmiral-status-aggregator")
+
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Sending request to webhook")
+	start := time.Now()
+
+	httpResp, err := p.client.Do(req)
+	logger = logger.WithValues("duration", time.Since(start))
+	if err != nil {
+		logger.Error(err, "Webhook request failed")
+		return fmt.Errorf("request failed: %w", err)
+	}
+	logger = logger.WithValues("status", httpResp.StatusCode)
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(httpResp.Body)
+		if err != nil {
+			logger.Error(err, "Received non-200 response from webhook and failed to read body")
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		logger.Error(nil, "Received non-200 response from webhook", "body", string(body))
+		return fmt.Errorf("unexpected status code: %d, body: %s", httpResp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(httpResp.Body).Decode(response); err != nil {
+		logger.Error(err, "Failed to decode response from webhook")
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	aggwebhookv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/statusaggregatorwebhook/v1alpha1"
+)
+
+func TestWebhookPlugin(t *testing.T) {
+	newSourceObject := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.io/v1",
+			"kind":       "Topic",
+			"metadata":   map[string]interface{}{"name": "test", "namespace": "default", "generation": int64(2)},
+		}}
+	}
+	clusterObject := func(partitions int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"partitions": partitions},
+		}}
+	}
+	clusterObjs := map[string]interface{}{
+		"c2": clusterObject(3),
+		"c1": clusterObject(2),
+		"c3": &unstructured.Unstructured{Object: map[string]interface{}{}},
+	}
+	fedObject := newFedObjectWithClusterGenerations(map[string]int64{"c1": 5, "c2": 7})
+
+	tests := []struct {
+		name           string
+		response       string
+		statusCode     int
+		expectedStatus map[string]interface{}
+		expectedUpdate bool
+		expectedErr    bool
+	}{
+		{
+			name:           "status is aggregated by webhook",
+			response:       `{"status":{"partitions":5,"ready":true}}`,
+			statusCode:     http.StatusOK,
+			expectedStatus: map[string]interface{}{"partitions": int64(5), "ready": true},
+			expectedUpdate: true,
+		},
+		{
+			name:       "empty status leaves source object unchanged",
+			response:   `{}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:        "webhook error",
+			response:    `{"error":"failed"}`,
+			statusCode:  http.StatusOK,
+			expectedErr: true,
+		},
+		{
+			name:        "unexpected status code",
+			response:    `internal error`,
+			statusCode:  http.StatusInternalServerError,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req aggwebhookv1a1.AggregateStatusesRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			plugin, err := NewWebhookPluginForConfig(&fedcorev1a1.StatusAggregationWebhookConfig{
+				PayloadVersions: []string{"v1beta1", aggwebhookv1a1.PayloadVersion},
+				URL:             server.URL,
+			})
+			assert.NoError(t, err)
+
+			ctx := klog.NewContext(context.Background(), klog.Background())
+			got, needUpdate, err := plugin.AggregateStatuses(ctx, newSourceObject(), fedObject, clusterObjs, true)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedUpdate, needUpdate)
+
+			assert.Equal(t, []aggwebhookv1a1.ClusterObjectStatus{
+				{ClusterName: "c1", SyncedGeneration: 5, Status: map[string]interface{}{"partitions": float64(2)}},
+				{ClusterName: "c2", SyncedGeneration: 7, Status: map[string]interface{}{"partitions": float64(3)}},
+				{ClusterName: "c3"},
+			}, req.ClusterObjectStatuses)
+			assert.True(t, req.ClusterObjectsUpToDate)
+			assert.Equal(t, "test", req.SourceObject["metadata"].(map[string]interface{})["name"])
+
+			status, _, err := unstructured.NestedMap(got.Object, "status")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, status)
+
+			if tt.expectedUpdate {
+				_, needUpdate, err = plugin.AggregateStatuses(ctx, got, fedObject, clusterObjs, true)
+				assert.NoError(t, err)
+				assert.False(t, needUpdate)
+			}
+		})
+	}
+}
+
+func TestNewWebhookPluginForConfig(t *testing.T) {
+	_, err := NewWebhookPluginForConfig(&fedcorev1a1.StatusAggregationWebhookConfig{
+		PayloadVersions: []string{"v1beta1"},
+		URL:             "http://localhost",
+	})
+	assert.Error(t, err)
+}
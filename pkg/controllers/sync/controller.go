@@ -33,6 +33,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -47,7 +48,10 @@ import (
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
 	genericclient "github.com/kubewharf/kubeadmiral/pkg/client/generic"
+	fedinformers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions"
+	fedcorev1a1listers "github.com/kubewharf/kubeadmiral/pkg/client/listers/core/v1alpha1"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/sync/dispatch"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/sync/status"
@@ -62,6 +66,7 @@ import (
 	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/sourcefeedback"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+	"github.com/kubewharf/kubeadmiral/pkg/rolloutaudit"
 	"github.com/kubewharf/kubeadmiral/pkg/stats"
 )
 
@@ -116,6 +121,12 @@ type SyncController struct {
 
 	hostClusterClient genericclient.Client
 
+	fedClient fedclient.Interface
+
+	// Informer for the PulledObjects of clusters in Pull mode
+	pulledObjectInformer cache.SharedIndexInformer
+	pulledObjectLister   fedcorev1a1listers.PulledObjectLister
+
 	controllerHistory history.Interface
 
 	controllerRevisionStore cache.Store
@@ -128,7 +139,9 @@ type SyncController struct {
 
 	cascadingDeleteFinalizer string
 
-	metrics stats.Metrics
+	metrics          stats.Metrics
+	rolloutAuditSink rolloutaudit.Sink
+	driftCorrections *dispatch.DriftCorrectionTracker
 
 	logger klog.Logger
 }
@@ -196,6 +209,8 @@ func newSyncController(
 		controllerRevisionStore:       controllerRevisionStore,
 		controllerRevisionController:  controllerRevisionController,
 		metrics:                       controllerConfig.Metrics,
+		rolloutAuditSink:              controllerConfig.RolloutAuditSink,
+		driftCorrections:              dispatch.NewDriftCorrectionTracker(),
 		logger:                        logger,
 	}
 
@@ -219,11 +234,11 @@ func newSyncController(
 		worker.WorkerTiming{},
 		controllerConfig.WorkerCount,
 		controllerConfig.Metrics,
-		deliverutil.NewMetricTags("sync-worker", typeConfig.GetTargetType().Kind),
+		deliverutil.NewFTCMetricTags("sync-worker", typeConfig.GetTargetType().Kind, typeConfig.Name),
 	)
 
 	s.clusterWorker = worker.NewReconcileWorker(s.reconcileCluster, worker.WorkerTiming{}, 1, controllerConfig.Metrics,
-		deliverutil.NewMetricTags("sync-cluster-worker", typeConfig.GetTargetType().Kind))
+		deliverutil.NewFTCMetricTags("sync-cluster-worker", typeConfig.GetTargetType().Kind, typeConfig.Name))
 
 	// Build deliverer for triggering cluster reconciliations.
 	s.clusterDeliverer = deliverutil.NewDelayingDeliverer()
@@ -264,6 +279,21 @@ func newSyncController(
 		return nil, err
 	}
 
+	s.fedClient, err = fedclient.NewForConfig(configCopy)
+	if err != nil {
+		return nil, err
+	}
+	pulledObjectInformer := fedinformers.NewSharedInformerFactoryWithOptions(
+		s.fedClient,
+		util.NoResyncPeriod,
+		fedinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = util.PulledObjectTypeSelector(typeConfig.Name).String()
+		}),
+	).Core().V1alpha1().PulledObjects()
+	s.pulledObjectInformer = pulledObjectInformer.Informer()
+	s.pulledObjectInformer.AddEventHandler(util.NewTriggerOnAllChanges(s.enqueuePulledObject))
+	s.pulledObjectLister = pulledObjectInformer.Lister()
+
 	if typeConfig.GetRevisionHistoryEnabled() {
 		s.controllerHistory = history.NewHistory(kubeClient, controllerRevisionStore)
 		s.revListerSynced = controllerRevisionController.HasSynced
@@ -285,12 +315,13 @@ func (s *SyncController) minimizeLatency() {
 
 func (s *SyncController) Run(stopChan <-chan struct{}) {
 	s.fedAccessor.Run(stopChan)
+	go s.pulledObjectInformer.Run(stopChan)
 	s.informer.Start()
 	s.clusterDeliverer.StartWithHandler(func(_ *deliverutil.DelayingDelivererItem) {
 		s.reconcileOnClusterChange()
 	})
 	go s.clusterDeliverer.RunMetricLoop(stopChan, 30*time.Second, s.metrics,
-		deliverutil.NewMetricTags("sync-clusterDeliverer", s.typeConfig.GetTargetType().Kind))
+		deliverutil.NewFTCMetricTags("sync-clusterDeliverer", s.typeConfig.GetTargetType().Kind, s.typeConfig.Name))
 
 	if !cache.WaitForNamedCacheSync(s.name, stopChan, s.HasSynced) {
 		return
@@ -320,6 +351,11 @@ func (s *SyncController) HasSynced() bool {
 		return false
 	}
 
+	if !s.pulledObjectInformer.HasSynced() {
+		s.logger.V(3).Info("PulledObject list not synced")
+		return false
+	}
+
 	if s.typeConfig.GetRevisionHistoryEnabled() && !s.revListerSynced() {
 		s.logger.V(3).Info("ControllerRevision list not synced")
 		return false
@@ -358,17 +394,25 @@ func (s *SyncController) reconcile(qualifiedName common.QualifiedName) (status w
 			return worker.StatusError
 		}
 
+		// Objects in clusters in Pull mode are orphaned by their agents.
+		if recheck, err := s.ensurePulledObjectsRemoved(ctx, qualifiedName, util.OrphanManagedResourcesAll); err != nil {
+			keyedLogger.Error(err, "Failed to remove pulled objects")
+			return worker.StatusError
+		} else if recheck {
+			return worker.Result{RequeueAfter: &s.ensureDeletionRecheckDelay}
+		}
+
 		return worker.StatusAllOK
 	}
 	if fedResource == nil {
 		return worker.StatusAllOK
 	}
 
-	s.metrics.Rate("sync.throughput", 1)
+	s.metrics.Rate("sync.throughput", 1, stats.Tag{Name: "ftc", Value: s.typeConfig.Name})
 	keyedLogger.V(3).Info("Starting to reconcile")
 	startTime := time.Now()
 	defer func() {
-		s.metrics.Duration("sync.latency", startTime)
+		s.metrics.Duration("sync.latency", startTime, stats.Tag{Name: "ftc", Value: s.typeConfig.Name})
 		keyedLogger.WithValues("duration", time.Since(startTime), "status", status).V(3).Info("Finished reconciling")
 	}()
 
@@ -416,6 +460,21 @@ func (s *SyncController) reconcile(qualifiedName common.QualifiedName) (status w
 		return worker.StatusError
 	}
 
+	// The federated object will be updated with the template of the source object after a rollback, so there is no
+	// need to sync the current template.
+	rollbackRequested, err := s.handleRollbackRequest(ctx, fedResource)
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			return worker.StatusConflict
+		}
+		keyedLogger.Error(err, "Failed to handle rollback request")
+		fedResource.RecordError(EventReasonRollbackFailed, errors.Wrap(err, "Failed to handle rollback request"))
+		return worker.StatusError
+	}
+	if rollbackRequested {
+		return worker.StatusAllOK
+	}
+
 	return s.syncToClusters(ctx, fedResource, collisionCount)
 }
 
@@ -445,15 +504,28 @@ func (s *SyncController) syncToClusters(ctx context.Context, fedResource Federat
 	keyedLogger.WithValues("clusters", strings.Join(selectedClusterNames.List(), ",")).
 		V(2).Info("Ensuring target object in clusters")
 
+	stagedRollout, err := s.planStagedRollout(ctx, fedResource, clusters, selectedClusterNames)
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			return worker.StatusConflict
+		}
+		keyedLogger.Error(err, "Failed to plan staged rollout")
+		fedResource.RecordError("PlanStagedRolloutError", errors.Wrap(err, "Failed to plan staged rollout"))
+		return worker.StatusError
+	}
+
 	skipAdoptingPreexistingResources := !util.ShouldAdoptPreexistingResources(fedResource.Object())
 	dispatcher := dispatch.NewManagedDispatcher(
 		s.informer.GetClientForCluster,
 		fedResource,
 		skipAdoptingPreexistingResources,
 		s.metrics,
+		s.rolloutAuditSink,
+		s.driftCorrections,
 	)
 
 	shouldRecheckAfterDispatch := false
+	pullOk := true
 	for _, cluster := range clusters {
 		clusterName := cluster.Name
 		isSelectedCluster := selectedClusterNames.Has(clusterName)
@@ -470,6 +542,21 @@ func (s *SyncController) syncToClusters(ctx context.Context, fedResource Federat
 			continue
 		}
 
+		if cluster.IsPullMode() {
+			ok, recheck := s.syncToPullCluster(
+				ctx,
+				dispatcher,
+				fedResource,
+				cluster,
+				shouldBeDeleted,
+				isCascadingDeletionTriggered,
+				stagedRollout != nil && stagedRollout.heldClusters.Has(clusterName),
+			)
+			pullOk = pullOk && ok
+			shouldRecheckAfterDispatch = shouldRecheckAfterDispatch || recheck
+			continue
+		}
+
 		clusterObj, _, err := util.GetClusterObject(
 			ctx,
 			s.informer,
@@ -535,14 +622,18 @@ func (s *SyncController) syncToClusters(ctx context.Context, fedResource Federat
 			)
 			continue
 		}
-		if clusterObj == nil {
+		switch {
+		case clusterObj == nil:
 			dispatcher.Create(ctx, clusterName)
-		} else {
+		case stagedRollout != nil && stagedRollout.heldClusters.Has(clusterName):
+			dispatcher.UpdateAndHoldTemplate(ctx, clusterName, clusterObj)
+		default:
 			dispatcher.Update(ctx, clusterName, clusterObj)
 		}
 	}
 
 	dispatchOk, timeoutErr := dispatcher.Wait()
+	dispatchOk = dispatchOk && pullOk
 	if !dispatchOk {
 		keyedLogger.Error(nil, "Failed to sync target object to cluster")
 	}
@@ -573,11 +664,15 @@ func (s *SyncController) syncToClusters(ctx context.Context, fedResource Federat
 	}
 
 	collectedStatus := dispatcher.CollectedStatus()
+	reason := fedtypesv1a1.AggregateSuccess
+	if stagedRollout != nil {
+		reason = stagedRollout.reason
+	}
 	if reconcileStatus := s.setFederatedStatus(
 		ctx,
 		fedResource,
 		collisionCount,
-		fedtypesv1a1.AggregateSuccess,
+		reason,
 		&collectedStatus,
 	); reconcileStatus != worker.StatusAllOK {
 		return reconcileStatus
@@ -590,6 +685,9 @@ func (s *SyncController) syncToClusters(ctx context.Context, fedResource Federat
 	if shouldRecheckAfterDispatch {
 		return worker.Result{RequeueAfter: &s.recheckAfterDispatchDelay}
 	}
+	if stagedRollout != nil && stagedRollout.recheckAfter != nil {
+		return worker.Result{RequeueAfter: stagedRollout.recheckAfter}
+	}
 
 	return worker.StatusAllOK
 }
@@ -738,9 +836,19 @@ func (s *SyncController) ensureDeletion(ctx context.Context, fedResource Federat
 	}
 
 	if util.GetOrphaningBehavior(obj) == util.OrphanManagedResourcesAll {
+		// Objects in clusters in Pull mode are orphaned by their agents.
+		recheck, err := s.ensurePulledObjectsRemoved(ctx, fedResource.TargetName(), util.OrphanManagedResourcesAll)
+		if err != nil {
+			keyedLogger.Error(err, "Failed to remove pulled objects")
+			return worker.StatusError
+		}
+		if recheck {
+			return worker.Result{RequeueAfter: &s.ensureDeletionRecheckDelay}
+		}
+
 		keyedLogger.WithValues("orphaning-behavior", util.OrphanManagedResourcesAll).
 			V(2).Info("Removing the finalizer")
-		err := s.deleteHistory(fedResource)
+		err = s.deleteHistory(fedResource)
 		if err != nil {
 			keyedLogger.Error(err, "Failed to delete history for federated object")
 			return worker.StatusError
@@ -847,6 +955,12 @@ func (s *SyncController) deleteFromClusters(ctx context.Context, fedResource Fed
 	qualifiedName := fedResource.TargetName()
 	keyedLogger := klog.FromContext(ctx)
 
+	// Objects in clusters in Pull mode are removed by their agents once their PulledObjects are deleted.
+	pullRecheck, err := s.ensurePulledObjectsRemoved(ctx, qualifiedName, util.GetOrphaningBehavior(fedResource.Object()))
+	if err != nil {
+		return false, err
+	}
+
 	remainingClusters := []string{}
 	ok, err := s.handleDeletionInClusters(
 		ctx,
@@ -868,6 +982,10 @@ func (s *SyncController) deleteFromClusters(ctx context.Context, fedResource Fed
 			V(2).Info("Waiting for resources managed by this federated object to be removed from some clusters")
 		return true, nil
 	}
+	if pullRecheck {
+		keyedLogger.V(2).Info("Waiting for pulled objects of this federated object to be removed")
+		return true, nil
+	}
 	err = s.ensureRemovedOrUnmanaged(ctx, fedResource)
 	if err != nil {
 		return false, errors.Wrapf(err, "failed to verify that managed resources no longer exist in any cluster")
@@ -898,6 +1016,10 @@ func (s *SyncController) ensureRemovedOrUnmanaged(ctx context.Context, fedResour
 	)
 	unreadyClusters := []string{}
 	for _, cluster := range clusters {
+		if cluster.IsPullMode() {
+			// objects in clusters in pull mode are removed by their agents
+			continue
+		}
 		if !util.IsClusterReady(&cluster.Status) {
 			unreadyClusters = append(unreadyClusters, cluster.Name)
 			continue
@@ -937,6 +1059,10 @@ func (s *SyncController) handleDeletionInClusters(
 	for _, cluster := range clusters {
 		clusterName := cluster.Name
 
+		if cluster.IsPullMode() {
+			// objects in clusters in pull mode are removed by their agents
+			continue
+		}
 		if !util.IsClusterReady(&cluster.Status) {
 			unreadyClusters = append(unreadyClusters, clusterName)
 			continue
@@ -1121,8 +1247,29 @@ func (s *SyncController) reconcileCluster(qualifiedName common.QualifiedName) wo
 		return worker.StatusAllOK
 	}
 
-	if !util.IsClusterJoined(&cluster.Status) || !util.IsCascadingDeleteEnabled(cluster) {
-		// cascading-delete is not required, remove cascading-delete finalizer immediately
+	if cluster.IsPullMode() && util.IsClusterJoined(&cluster.Status) && util.IsClusterReady(&cluster.Status) {
+		// the objects in a cluster in pull mode are removed or orphaned by its agent once their pulled objects are
+		// deleted, wait for the pulled objects to be deleted
+		pulledObjects, err := s.pulledObjectLister.PulledObjects(util.PullNamespace(cluster.Name)).List(labels.Everything())
+		if err != nil {
+			logger.Error(err, "Failed to list pulled objects")
+			return worker.StatusError
+		}
+		if len(pulledObjects) > 0 {
+			s.eventRecorder.Eventf(
+				cluster,
+				corev1.EventTypeNormal,
+				EventReasonWaitForCascadingDelete,
+				"waiting for removal of pulled objects of %s",
+				s.typeConfig.GetTargetType().Name,
+			)
+			return worker.Result{RequeueAfter: &s.cascadingDeletionRecheckDelay}
+		}
+	}
+
+	if !util.IsClusterJoined(&cluster.Status) || !util.IsCascadingDeleteEnabled(cluster) || cluster.IsPullMode() {
+		// cascading-delete is not required, or the pulled objects of a cluster in pull mode are removed,
+		// remove cascading-delete finalizer immediately
 		err := s.removeClusterFinalizer(ctx, cluster)
 		if err != nil {
 			if apierrors.IsConflict(err) {
@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+// maxDriftedFields limits the number of drifted fields reported for a cluster to bound the size of the status.
+const maxDriftedFields = 20
+
+var pathKeyEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// listMergeKeys are the fields by which the elements of lists are matched, in order of preference, e.g. the names of
+// containers and environment variables, the mount paths of volume mounts and the ports of container ports.
+var listMergeKeys = []string{"name", "mountPath", "devicePath", "containerPort"}
+
+// driftDetectionMode returns the drift detection mode of the federated object. The mode of the federated type config
+// is used unless it is overridden by a valid value of the drift-detection annotation.
+func driftDetectionMode(
+	typeConfig *fedcorev1a1.FederatedTypeConfig,
+	fedObj *unstructured.Unstructured,
+) fedcorev1a1.DriftDetectionMode {
+	switch mode := fedcorev1a1.DriftDetectionMode(fedObj.GetAnnotations()[common.DriftDetectionAnnotation]); mode {
+	case fedcorev1a1.DriftDetectionDisabled, fedcorev1a1.DriftDetectionReportOnly, fedcorev1a1.DriftDetectionAutoCorrect:
+		return mode
+	default:
+		return typeConfig.GetDriftDetectionMode()
+	}
+}
+
+// detectDrift returns the sorted JSON pointer paths of the fields set in the desired object that have a different
+// value in the cluster object. Fields only set in the cluster object, e.g. defaulted fields, are not drifts. The
+// status and the metadata other than labels and annotations are ignored.
+func detectDrift(desiredObj, clusterObj *unstructured.Unstructured) []string {
+	var paths []string
+	for key, desiredValue := range desiredObj.Object {
+		switch key {
+		case "apiVersion", "kind", common.StatusField:
+		case common.MetadataField:
+			for _, field := range []string{"labels", "annotations"} {
+				desired, _, _ := unstructured.NestedFieldNoCopy(desiredObj.Object, common.MetadataField, field)
+				actual, _, _ := unstructured.NestedFieldNoCopy(clusterObj.Object, common.MetadataField, field)
+				paths = diffValues("/"+common.MetadataField+"/"+field, desired, actual, paths)
+			}
+		default:
+			paths = diffValues("/"+pathKeyEscaper.Replace(key), desiredValue, clusterObj.Object[key], paths)
+		}
+	}
+
+	sort.Strings(paths)
+	if len(paths) > maxDriftedFields {
+		paths = paths[:maxDriftedFields]
+	}
+	return paths
+}
+
+// diffValues appends the paths at which the actual value differs from the desired value to paths. Maps are compared
+// by the keys of the desired map. Lists whose elements have a merge key are compared by the elements of the desired
+// list, so that elements added in the cluster, e.g. injected sidecar containers, are not drifts. Other lists are
+// compared element by element.
+func diffValues(path string, desired, actual interface{}, paths []string) []string {
+	switch desired := desired.(type) {
+	case nil:
+		return paths
+	case map[string]interface{}:
+		actualMap, ok := actual.(map[string]interface{})
+		if !ok && actual != nil {
+			return append(paths, path)
+		}
+		for key, value := range desired {
+			paths = diffValues(path+"/"+pathKeyEscaper.Replace(key), value, actualMap[key], paths)
+		}
+		return paths
+	case []interface{}:
+		actualSlice, ok := actual.([]interface{})
+		if !ok && actual != nil {
+			return append(paths, path)
+		}
+		if mergeKey := listMergeKey(desired); len(mergeKey) > 0 {
+			return diffMergedLists(path, mergeKey, desired, actualSlice, paths)
+		}
+		if len(actualSlice) != len(desired) {
+			return append(paths, path)
+		}
+		for i := range desired {
+			paths = diffValues(fmt.Sprintf("%s/%d", path, i), desired[i], actualSlice[i], paths)
+		}
+		return paths
+	default:
+		if !scalarsEqual(desired, actual) {
+			return append(paths, path)
+		}
+		return paths
+	}
+}
+
+// listMergeKey returns the merge key shared by all elements of the list, or an empty string if the elements do not
+// have a unique value for any merge key.
+func listMergeKey(list []interface{}) string {
+	if len(list) == 0 {
+		return ""
+	}
+
+	for _, key := range listMergeKeys {
+		values := make(map[interface{}]struct{}, len(list))
+		for _, element := range list {
+			value, ok := mergeKeyValue(element, key)
+			if !ok {
+				break
+			}
+			values[value] = struct{}{}
+		}
+		if len(values) == len(list) {
+			return key
+		}
+	}
+	return ""
+}
+
+// diffMergedLists appends the paths at which the elements of the actual list differ from the elements of the desired
+// list with the same merge key to paths. The paths of the elements are their indexes in the actual list. The path of
+// the list is appended if an element of the desired list is missing.
+func diffMergedLists(path, mergeKey string, desired, actual []interface{}, paths []string) []string {
+	actualIndexes := make(map[interface{}]int, len(actual))
+	for i, element := range actual {
+		if value, ok := mergeKeyValue(element, mergeKey); ok {
+			actualIndexes[value] = i
+		}
+	}
+
+	missing := false
+	for _, element := range desired {
+		value, _ := mergeKeyValue(element, mergeKey)
+		i, exists := actualIndexes[value]
+		if !exists {
+			missing = true
+			continue
+		}
+		paths = diffValues(fmt.Sprintf("%s/%d", path, i), element, actual[i], paths)
+	}
+	if missing {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// mergeKeyValue returns the value of the merge key of a list element if it is a string or a number.
+func mergeKeyValue(element interface{}, key string) (interface{}, bool) {
+	elementMap, ok := element.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	switch value := elementMap[key].(type) {
+	case string:
+		return value, true
+	default:
+		if number, ok := toFloat64(value); ok {
+			return number, true
+		}
+		return nil, false
+	}
+}
+
+// scalarsEqual compares scalar values semantically: numbers are compared regardless of their type, quantities by
+// their value, and zero values are equal to unset values since they are omitted by the apiserver.
+func scalarsEqual(desired, actual interface{}) bool {
+	if actual == nil {
+		return reflect.ValueOf(desired).IsZero()
+	}
+
+	if desiredNumber, ok := toFloat64(desired); ok {
+		actualNumber, ok := toFloat64(actual)
+		return ok && desiredNumber == actualNumber
+	}
+
+	if desiredString, ok := desired.(string); ok {
+		actualString, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		if desiredString == actualString {
+			return true
+		}
+		desiredQuantity, err := resource.ParseQuantity(desiredString)
+		if err != nil {
+			return false
+		}
+		actualQuantity, err := resource.ParseQuantity(actualString)
+		return err == nil && desiredQuantity.Cmp(actualQuantity) == 0
+	}
+
+	return reflect.DeepEqual(desired, actual)
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case int64:
+		return float64(value), true
+	case int32:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case float64:
+		return value, true
+	default:
+		return 0, false
+	}
+}
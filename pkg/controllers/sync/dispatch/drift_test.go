@@ -0,0 +1,225 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+func newDeploymentForDrift(replicas int64, image, cpu string, labels map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":   "test",
+			"labels": labels,
+		},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  "server",
+							"image": image,
+							"resources": map[string]interface{}{
+								"requests": map[string]interface{}{"cpu": cpu},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestDetectDrift(t *testing.T) {
+	testCases := map[string]struct {
+		desiredObj *unstructured.Unstructured
+		clusterObj *unstructured.Unstructured
+		expected   []string
+	}{
+		"identical objects have no drift": {
+			desiredObj: newDeploymentForDrift(2, "nginx:1.25", "1", map[string]interface{}{"app": "test"}),
+			clusterObj: newDeploymentForDrift(2, "nginx:1.25", "1", map[string]interface{}{"app": "test"}),
+		},
+		"defaulted fields, status and metadata are not drifts": {
+			desiredObj: newDeploymentForDrift(2, "nginx:1.25", "1000m", map[string]interface{}{"app": "test"}),
+			clusterObj: func() *unstructured.Unstructured {
+				obj := newDeploymentForDrift(2, "nginx:1.25", "1", map[string]interface{}{"app": "test", "extra": "value"})
+				obj.SetResourceVersion("10")
+				obj.SetGeneration(3)
+				_ = unstructured.SetNestedField(obj.Object, int64(600), "spec", "progressDeadlineSeconds")
+				_ = unstructured.SetNestedField(obj.Object, int64(2), "status", "replicas")
+				return obj
+			}(),
+		},
+		"changed fields are reported": {
+			desiredObj: newDeploymentForDrift(2, "nginx:1.25", "1", map[string]interface{}{"app": "test", "example.io/tier": "web"}),
+			clusterObj: newDeploymentForDrift(3, "nginx:1.24", "1", map[string]interface{}{"app": "test", "example.io/tier": "db"}),
+			expected: []string{
+				"/metadata/labels/example.io~1tier",
+				"/spec/replicas",
+				"/spec/template/spec/containers/0/image",
+			},
+		},
+		"removed fields are reported": {
+			desiredObj: newDeploymentForDrift(2, "nginx:1.25", "1", map[string]interface{}{"app": "test"}),
+			clusterObj: func() *unstructured.Unstructured {
+				obj := newDeploymentForDrift(2, "nginx:1.25", "1", nil)
+				unstructured.RemoveNestedField(obj.Object, "spec", "template", "spec", "containers")
+				return obj
+			}(),
+			expected: []string{
+				"/metadata/labels/app",
+				"/spec/template/spec/containers",
+			},
+		},
+		"injected sidecar containers are not drifts": {
+			desiredObj: newDeploymentForDrift(2, "nginx:1.25", "1", nil),
+			clusterObj: func() *unstructured.Unstructured {
+				obj := newDeploymentForDrift(2, "nginx:1.24", "1", nil)
+				containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+				containers = append([]interface{}{map[string]interface{}{"name": "sidecar", "image": "envoy"}}, containers...)
+				_ = unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+				return obj
+			}(),
+			expected: []string{"/spec/template/spec/containers/1/image"},
+		},
+		"missing named elements are reported as a whole": {
+			desiredObj: newDeploymentForDrift(2, "nginx:1.25", "1", nil),
+			clusterObj: func() *unstructured.Unstructured {
+				obj := newDeploymentForDrift(2, "nginx:1.25", "1", nil)
+				containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+				containers[0].(map[string]interface{})["name"] = "proxy"
+				_ = unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+				return obj
+			}(),
+			expected: []string{"/spec/template/spec/containers"},
+		},
+		"unnamed lists of different lengths are reported as a whole": {
+			desiredObj: func() *unstructured.Unstructured {
+				obj := newDeploymentForDrift(2, "nginx:1.25", "1", nil)
+				_ = unstructured.SetNestedStringSlice(obj.Object, []string{"serve"}, "spec", "template", "spec", "args")
+				return obj
+			}(),
+			clusterObj: func() *unstructured.Unstructured {
+				obj := newDeploymentForDrift(2, "nginx:1.25", "1", nil)
+				_ = unstructured.SetNestedStringSlice(obj.Object, []string{"serve", "--debug"}, "spec", "template", "spec", "args")
+				return obj
+			}(),
+			expected: []string{"/spec/template/spec/args"},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			driftedFields := detectDrift(testCase.desiredObj, testCase.clusterObj)
+			if !reflect.DeepEqual(driftedFields, testCase.expected) {
+				t.Errorf("Expected drifted fields %v, got %v", testCase.expected, driftedFields)
+			}
+		})
+	}
+}
+
+func TestDriftDetectionMode(t *testing.T) {
+	reportOnly := fedcorev1a1.DriftDetectionReportOnly
+
+	testCases := map[string]struct {
+		typeConfigMode *fedcorev1a1.DriftDetectionMode
+		annotation     string
+		expected       fedcorev1a1.DriftDetectionMode
+	}{
+		"disabled by default": {
+			expected: fedcorev1a1.DriftDetectionDisabled,
+		},
+		"mode of the type config": {
+			typeConfigMode: &reportOnly,
+			expected:       fedcorev1a1.DriftDetectionReportOnly,
+		},
+		"annotation overrides the type config": {
+			typeConfigMode: &reportOnly,
+			annotation:     string(fedcorev1a1.DriftDetectionAutoCorrect),
+			expected:       fedcorev1a1.DriftDetectionAutoCorrect,
+		},
+		"annotation disables drift detection": {
+			typeConfigMode: &reportOnly,
+			annotation:     string(fedcorev1a1.DriftDetectionDisabled),
+			expected:       fedcorev1a1.DriftDetectionDisabled,
+		},
+		"invalid annotation is ignored": {
+			typeConfigMode: &reportOnly,
+			annotation:     "Enabled",
+			expected:       fedcorev1a1.DriftDetectionReportOnly,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			typeConfig := &fedcorev1a1.FederatedTypeConfig{
+				Spec: fedcorev1a1.FederatedTypeConfigSpec{DriftDetection: testCase.typeConfigMode},
+			}
+			fedObj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if testCase.annotation != "" {
+				fedObj.SetAnnotations(map[string]string{common.DriftDetectionAnnotation: testCase.annotation})
+			}
+
+			if mode := driftDetectionMode(typeConfig, fedObj); mode != testCase.expected {
+				t.Errorf("Expected mode %q, got %q", testCase.expected, mode)
+			}
+		})
+	}
+}
+
+func TestDriftCorrectionTracker(t *testing.T) {
+	now := time.Now()
+	tracker := NewDriftCorrectionTracker()
+	tracker.now = func() time.Time { return now }
+	target := common.QualifiedName{Namespace: "default", Name: "test"}
+
+	for i := 0; i < maxDriftCorrections; i++ {
+		if correct, _ := tracker.startCorrection(target, "cluster1"); !correct {
+			t.Fatalf("Expected correction %d to be allowed", i+1)
+		}
+	}
+	if correct, becamePersistent := tracker.startCorrection(target, "cluster1"); correct || !becamePersistent {
+		t.Errorf("Expected drift to become persistent, got correct=%v, becamePersistent=%v", correct, becamePersistent)
+	}
+	if correct, becamePersistent := tracker.startCorrection(target, "cluster1"); correct || becamePersistent {
+		t.Errorf("Expected drift to stay persistent, got correct=%v, becamePersistent=%v", correct, becamePersistent)
+	}
+	if correct, _ := tracker.startCorrection(target, "cluster2"); !correct {
+		t.Errorf("Expected corrections in other clusters to be allowed")
+	}
+
+	now = now.Add(driftCorrectionPeriod)
+	if correct, _ := tracker.startCorrection(target, "cluster1"); !correct {
+		t.Errorf("Expected correction to be allowed after the correction period")
+	}
+
+	tracker.reset(target, "cluster1")
+	if _, exists := tracker.corrections[driftCorrectionKey{target: target, cluster: "cluster1"}]; exists {
+		t.Errorf("Expected corrections to be forgotten after reset")
+	}
+}
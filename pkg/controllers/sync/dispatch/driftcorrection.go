@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+const (
+	// maxDriftCorrections is the number of corrections of the drift of an object in a cluster within
+	// driftCorrectionPeriod after which the drift is considered persistent and is no longer corrected.
+	maxDriftCorrections = 3
+	// driftCorrectionPeriod is the period after the last correction of the drift of an object in a cluster after which
+	// the corrections are forgotten, so that a persistent drift is corrected again.
+	driftCorrectionPeriod = 30 * time.Minute
+)
+
+// DriftCorrectionTracker tracks the corrections of the drift of objects in clusters across reconciles, so that a drift
+// that is reintroduced after every correction, e.g. by a mutating webhook or defaulting, does not cause an endless
+// update loop.
+type DriftCorrectionTracker struct {
+	lock        sync.Mutex
+	corrections map[driftCorrectionKey]*driftCorrections
+	now         func() time.Time
+}
+
+type driftCorrectionKey struct {
+	target  common.QualifiedName
+	cluster string
+}
+
+type driftCorrections struct {
+	count          int
+	lastCorrection time.Time
+}
+
+func NewDriftCorrectionTracker() *DriftCorrectionTracker {
+	return &DriftCorrectionTracker{
+		corrections: map[driftCorrectionKey]*driftCorrections{},
+		now:         time.Now,
+	}
+}
+
+// startCorrection records a correction of the drift of the target object in the cluster. It returns false if the
+// drift is persistent and should not be corrected, and whether the drift has just become persistent.
+func (t *DriftCorrectionTracker) startCorrection(target common.QualifiedName, cluster string) (bool, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.now()
+	for key, corrections := range t.corrections {
+		if now.Sub(corrections.lastCorrection) >= driftCorrectionPeriod {
+			delete(t.corrections, key)
+		}
+	}
+
+	key := driftCorrectionKey{target: target, cluster: cluster}
+	corrections, exists := t.corrections[key]
+	if !exists {
+		corrections = &driftCorrections{}
+		t.corrections[key] = corrections
+	}
+	if corrections.count >= maxDriftCorrections {
+		corrections.count++
+		return false, corrections.count == maxDriftCorrections+1
+	}
+
+	corrections.count++
+	corrections.lastCorrection = now
+	return true, false
+}
+
+// reset forgets the corrections of the drift of the target object in the cluster once it no longer drifts.
+func (t *DriftCorrectionTracker) reset(target common.QualifiedName, cluster string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.corrections, driftCorrectionKey{target: target, cluster: cluster})
+}
@@ -45,11 +45,10 @@ import (
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/annotation"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/managedlabel"
 	utilunstructured "github.com/kubewharf/kubeadmiral/pkg/controllers/util/unstructured"
+	"github.com/kubewharf/kubeadmiral/pkg/rolloutaudit"
 	"github.com/kubewharf/kubeadmiral/pkg/stats"
 )
 
-const IndexRolloutPlans = "federation_placement_rollout"
-
 // FederatedResourceForDispatch is the subset of the FederatedResource
 // interface required for dispatching operations to managed resources.
 type FederatedResourceForDispatch interface {
@@ -81,6 +80,7 @@ type ManagedDispatcher interface {
 
 	Create(ctx context.Context, clusterName string)
 	Update(ctx context.Context, clusterName string, clusterObj *unstructured.Unstructured)
+	UpdateAndHoldTemplate(ctx context.Context, clusterName string, clusterObj *unstructured.Unstructured)
 	VersionMap() map[string]string
 	CollectedStatus() status.CollectedPropagationStatus
 	RecordClusterError(propStatus fedtypesv1a1.PropagationStatus, clusterName string, err error)
@@ -95,6 +95,7 @@ type managedDispatcherImpl struct {
 	fedResource           FederatedResourceForDispatch
 	versionMap            map[string]string
 	statusMap             status.PropagationStatusMap
+	driftedFieldsMap      map[string][]string
 	skipAdoptingResources bool
 
 	// Track when resource updates are performed to allow indicating
@@ -102,7 +103,9 @@ type managedDispatcherImpl struct {
 	resourcesUpdated bool
 	rolloutPlans     util.RolloutPlans
 
-	metrics stats.Metrics
+	metrics          stats.Metrics
+	rolloutAuditSink rolloutaudit.Sink
+	driftCorrections *DriftCorrectionTracker
 }
 
 func NewManagedDispatcher(
@@ -110,13 +113,18 @@ func NewManagedDispatcher(
 	fedResource FederatedResourceForDispatch,
 	skipAdoptingResources bool,
 	metrics stats.Metrics,
+	rolloutAuditSink rolloutaudit.Sink,
+	driftCorrections *DriftCorrectionTracker,
 ) ManagedDispatcher {
 	d := &managedDispatcherImpl{
 		fedResource:           fedResource,
 		versionMap:            make(map[string]string),
 		statusMap:             make(status.PropagationStatusMap),
+		driftedFieldsMap:      make(map[string][]string),
 		skipAdoptingResources: skipAdoptingResources,
 		metrics:               metrics,
+		rolloutAuditSink:      rolloutAuditSink,
+		driftCorrections:      driftCorrections,
 	}
 	d.dispatcher = newOperationDispatcher(clientAccessor, d)
 	d.unmanagedDispatcher = newUnmanagedDispatcher(d.dispatcher, d, fedResource.TargetGVK(), fedResource.TargetName())
@@ -200,7 +208,7 @@ func (d *managedDispatcherImpl) Dispatch(ctx context.Context, targetGetter targe
 		clusterObjs[clusterName] = clusterObj
 	}
 
-	// skip rollout plan for hpa and daemonset
+	// skip rollout plan for hpa
 	if rolloutPlanEnabled {
 		retain, err := checkRetainReplicas(d.fedResource.Object())
 		if err != nil {
@@ -289,30 +297,48 @@ func (d *managedDispatcherImpl) planRolloutProcess(ctx context.Context, clusterO
 		} else {
 			logger.WithValues("plans", plans, "current-status", planner).V(4).Info("Generating rollout plans")
 		}
-		SendRolloutPlansToES(planner, plans, r.Object(), err)
+		if d.rolloutAuditSink != nil {
+			d.rolloutAuditSink.Send(r.Object(), newRolloutAuditRecord(planner, plans, r.Object(), err))
+		}
 		d.emitRolloutStatus(ctx, clusterObjs, selectedClusterNames, planner)
 	}()
 
-	if gvk != appsv1.SchemeGroupVersion.WithKind(common.DeploymentKind) {
+	if gvk.Group != appsv1.GroupName || !util.IsRolloutPlanSupported(gvk.Kind) {
 		err = errors.Errorf("Unsupported target type for rollout plan: %s", gvk)
 		return nil, err
 	}
-	if replicas, err = r.TotalReplicas(selectedClusterNames); err != nil {
-		return nil, err
+
+	desiredReplicas := make(map[string]int32, len(clusterObjs))
+	for clusterName, clusterObj := range clusterObjs {
+		if toDelete.Has(clusterName) {
+			continue
+		}
+		if gvk.Kind == common.DaemonSetKind {
+			// The replicas of a DaemonSet are determined by the nodes of the cluster, so they are never scaled.
+			if clusterObj != nil {
+				dr, _, _ := unstructured.NestedInt64(clusterObj.Object, common.StatusField, "desiredNumberScheduled")
+				desiredReplicas[clusterName] = int32(dr)
+				replicas += int32(dr)
+			}
+			continue
+		}
+		var dr int32
+		if dr, _, err = r.ReplicasOverrideForCluster(clusterName); err != nil {
+			return nil, err
+		}
+		desiredReplicas[clusterName] = dr
+	}
+	if gvk.Kind != common.DaemonSetKind {
+		if replicas, err = r.TotalReplicas(selectedClusterNames); err != nil {
+			return nil, err
+		}
 	}
+
 	if planner, err = util.NewRolloutPlanner(key, r.TypeConfig(), r.Object(), replicas); err != nil {
 		return nil, err
 	}
 	for clusterName, clusterObj := range clusterObjs {
-		var desiredReplicas int32
-		if !toDelete.Has(clusterName) {
-			var dr int32
-			if dr, _, err = r.ReplicasOverrideForCluster(clusterName); err != nil {
-				return nil, err
-			}
-			desiredReplicas = dr
-		}
-		if err = planner.RegisterTarget(clusterName, clusterObj, desiredReplicas); err != nil {
+		if err = planner.RegisterTarget(clusterName, clusterObj, desiredReplicas[clusterName]); err != nil {
 			err = errors.Wrap(err, "Failed to register target in "+clusterName)
 			return nil, err
 		}
@@ -452,15 +478,19 @@ func (d *managedDispatcherImpl) Update(ctx context.Context, clusterName string,
 			return d.recordOperationError(ctx, fedtypesv1a1.VersionRetrievalFailed, clusterName, op, err)
 		}
 
-		if !util.ObjectNeedsUpdate(obj, clusterObj, version, d.fedResource.TypeConfig()) {
+		driftMode, driftedFields := d.driftedFields(obj, clusterObj)
+		if !util.ObjectNeedsUpdate(obj, clusterObj, version, d.fedResource.TypeConfig()) &&
+			!d.shouldCorrectDrift(clusterName, driftMode, driftedFields) {
 			// Resource is current, we still record version in dispatcher
 			// so that federated status can be set with cluster resource generation
 			d.recordVersion(clusterName, version)
+			d.recordDrift(clusterName, driftStatus(driftMode), driftedFields)
 			return true
 		}
 
 		// Only record an event if the resource is not current
 		d.recordEvent(clusterName, op, "Updating")
+		d.recordDriftCorrection(clusterName, driftedFields)
 
 		keyedLogger.V(1).Info("Updating target object in cluster")
 		err = client.Update(ctx, obj)
@@ -485,6 +515,27 @@ func (d *managedDispatcherImpl) PatchAndKeepTemplate(
 	clusterName string,
 	clusterObj *unstructured.Unstructured,
 	keepRolloutSettings bool,
+) {
+	d.patchAndKeepTemplate(ctx, clusterName, clusterObj, keepRolloutSettings, false)
+}
+
+// UpdateAndHoldTemplate updates the object in the cluster while keeping its current template, so that the cluster
+// stays at its previous revision until it is reached by a staged rollout. The version of the object is not recorded,
+// so that the template is compared again once the cluster is updated with Update.
+func (d *managedDispatcherImpl) UpdateAndHoldTemplate(
+	ctx context.Context,
+	clusterName string,
+	clusterObj *unstructured.Unstructured,
+) {
+	d.patchAndKeepTemplate(ctx, clusterName, clusterObj, false, true)
+}
+
+func (d *managedDispatcherImpl) patchAndKeepTemplate(
+	ctx context.Context,
+	clusterName string,
+	clusterObj *unstructured.Unstructured,
+	keepRolloutSettings bool,
+	holdTemplate bool,
 ) {
 	d.RecordStatus(clusterName, fedtypesv1a1.UpdateTimedOut)
 
@@ -525,31 +576,62 @@ func (d *managedDispatcherImpl) PatchAndKeepTemplate(
 			return d.recordOperationError(ctx, fedtypesv1a1.FieldRetentionFailed, clusterName, op, wrappedErr)
 		}
 
-		if d.fedResource.TargetGVK() == appsv1.SchemeGroupVersion.WithKind(common.DeploymentKind) {
+		targetGVK := d.fedResource.TargetGVK()
+		isRolloutPlanSupported := targetGVK.Group == appsv1.GroupName && util.IsRolloutPlanSupported(targetGVK.Kind)
+		if isRolloutPlanSupported || holdTemplate {
 			if err = retainTemplate(obj, clusterObj, d.fedResource.TypeConfig(), keepRolloutSettings); err != nil {
 				wrappedErr := errors.Wrapf(err, "failed to retain template")
 				return d.recordOperationError(ctx, fedtypesv1a1.FieldRetentionFailed, clusterName, op, wrappedErr)
 			}
+		}
+		if targetGVK == appsv1.SchemeGroupVersion.WithKind(common.DeploymentKind) {
 			if err = setLastReplicasetName(obj, clusterObj); err != nil {
 				wrappedErr := errors.Wrapf(err, "failed to set last replicaset name")
 				return d.recordOperationError(ctx, fedtypesv1a1.SetLastReplicasetNameFailed, clusterName, op, wrappedErr)
 			}
 		}
 
+		if holdTemplate {
+			// The recorded version is not used while the template is held, so the object is compared with its
+			// current version to avoid updating it on every reconcile.
+			driftMode, driftedFields := d.driftedFields(obj, clusterObj)
+			if !util.ObjectNeedsUpdate(obj, clusterObj, util.ObjectVersion(clusterObj), d.fedResource.TypeConfig()) &&
+				!d.shouldCorrectDrift(clusterName, driftMode, driftedFields) {
+				d.RecordStatus(clusterName, fedtypesv1a1.WaitingForRolloutWave)
+				d.recordDrift(clusterName, fedtypesv1a1.WaitingForRolloutWave, driftedFields)
+				return true
+			}
+
+			d.recordEvent(clusterName, op, "Updating")
+			d.recordDriftCorrection(clusterName, driftedFields)
+
+			keyedLogger.V(1).Info("Updating target object in cluster and holding its template")
+			if err = client.Update(ctx, obj); err != nil {
+				return d.recordOperationError(ctx, fedtypesv1a1.UpdateFailed, clusterName, op, err)
+			}
+			d.setResourcesUpdated()
+			d.RecordStatus(clusterName, fedtypesv1a1.WaitingForRolloutWave)
+			return true
+		}
+
 		version, err := d.fedResource.VersionForCluster(clusterName)
 		if err != nil {
 			return d.recordOperationError(ctx, fedtypesv1a1.VersionRetrievalFailed, clusterName, op, err)
 		}
 
-		if !util.ObjectNeedsUpdate(obj, clusterObj, version, d.fedResource.TypeConfig()) {
+		driftMode, driftedFields := d.driftedFields(obj, clusterObj)
+		if !util.ObjectNeedsUpdate(obj, clusterObj, version, d.fedResource.TypeConfig()) &&
+			!d.shouldCorrectDrift(clusterName, driftMode, driftedFields) {
 			// Resource is current, we still record version in dispatcher
 			// so that federated status can be set with cluster resource generation
 			d.recordVersion(clusterName, version)
+			d.recordDrift(clusterName, driftStatus(driftMode), driftedFields)
 			return true
 		}
 
 		// Only record an event if the resource is not current
 		d.recordEvent(clusterName, op, "Updating")
+		d.recordDriftCorrection(clusterName, driftedFields)
 
 		keyedLogger.V(1).Info("Patching and keeping template for target object in cluster")
 		err = client.Update(ctx, obj)
@@ -627,6 +709,87 @@ func (d *managedDispatcherImpl) VersionMap() map[string]string {
 	return versionMap
 }
 
+// driftedFields returns the drift detection mode of the federated object and the fields of the object in the cluster
+// that differ from the desired object. The recorded version only reflects changes of the federated object, so changes
+// made directly to the cluster object are detected by comparing it with the desired object.
+func (d *managedDispatcherImpl) driftedFields(
+	obj, clusterObj *unstructured.Unstructured,
+) (fedcorev1a1.DriftDetectionMode, []string) {
+	driftMode := driftDetectionMode(d.fedResource.TypeConfig(), d.fedResource.Object())
+	if driftMode == fedcorev1a1.DriftDetectionDisabled {
+		return driftMode, nil
+	}
+	return driftMode, detectDrift(obj, clusterObj)
+}
+
+// shouldCorrectDrift returns whether the drifted fields of the object in the cluster should be reverted by updating
+// it. Drift is only corrected in AutoCorrect mode, and is no longer corrected if it is reintroduced after
+// maxDriftCorrections corrections within driftCorrectionPeriod.
+func (d *managedDispatcherImpl) shouldCorrectDrift(
+	clusterName string,
+	driftMode fedcorev1a1.DriftDetectionMode,
+	driftedFields []string,
+) bool {
+	if len(driftedFields) == 0 {
+		d.driftCorrections.reset(d.fedResource.TargetName(), clusterName)
+		return false
+	}
+	if driftMode != fedcorev1a1.DriftDetectionAutoCorrect {
+		return false
+	}
+
+	correct, becamePersistent := d.driftCorrections.startCorrection(d.fedResource.TargetName(), clusterName)
+	if becamePersistent {
+		d.fedResource.RecordEvent(
+			"DriftPersistsInCluster",
+			"Changes to fields %v of %s %q in cluster %q were reintroduced after %d corrections and are not reverted for %v",
+			driftedFields,
+			d.fedResource.TargetKind(),
+			d.unmanagedDispatcher.targetNameForCluster(clusterName),
+			clusterName,
+			maxDriftCorrections,
+			driftCorrectionPeriod,
+		)
+	}
+	return correct
+}
+
+func (d *managedDispatcherImpl) recordDriftCorrection(clusterName string, driftedFields []string) {
+	if len(driftedFields) == 0 {
+		return
+	}
+	d.fedResource.RecordEvent(
+		"CorrectDriftInCluster",
+		"Reverting changes to fields %v of %s %q in cluster %q",
+		driftedFields,
+		d.fedResource.TargetKind(),
+		d.unmanagedDispatcher.targetNameForCluster(clusterName),
+		clusterName,
+	)
+}
+
+func (d *managedDispatcherImpl) recordDrift(
+	clusterName string,
+	propStatus fedtypesv1a1.PropagationStatus,
+	driftedFields []string,
+) {
+	if len(driftedFields) == 0 {
+		return
+	}
+	d.Lock()
+	defer d.Unlock()
+	d.statusMap[clusterName] = propStatus
+	d.driftedFieldsMap[clusterName] = driftedFields
+}
+
+// driftStatus returns the status of an object whose drift is not corrected in the given drift detection mode.
+func driftStatus(driftMode fedcorev1a1.DriftDetectionMode) fedtypesv1a1.PropagationStatus {
+	if driftMode == fedcorev1a1.DriftDetectionAutoCorrect {
+		return fedtypesv1a1.PersistentlyDrifted
+	}
+	return fedtypesv1a1.Drifted
+}
+
 func (d *managedDispatcherImpl) recordVersion(clusterName, version string) {
 	d.Lock()
 	defer d.Unlock()
@@ -646,9 +809,14 @@ func (d *managedDispatcherImpl) CollectedStatus() status.CollectedPropagationSta
 	for key, value := range d.statusMap {
 		statusMap[key] = value
 	}
+	driftedFieldsMap := make(map[string][]string)
+	for key, value := range d.driftedFieldsMap {
+		driftedFieldsMap[key] = value
+	}
 	return status.CollectedPropagationStatus{
 		StatusMap:        statusMap,
 		GenerationMap:    util.ConvertVersionMapToGenerationMap(d.versionMap),
+		DriftedFieldsMap: driftedFieldsMap,
 		ResourcesUpdated: d.resourcesUpdated,
 	}
 }
@@ -657,7 +825,7 @@ func (d *managedDispatcherImpl) rolloutOverrides(clusterName string) fedtypesv1a
 	if d.rolloutPlans == nil {
 		return fedtypesv1a1.OverridePatches{}
 	}
-	return d.rolloutPlans.GetRolloutOverrides(clusterName)
+	return d.rolloutPlans.GetRolloutOverrides(clusterName, d.fedResource.TargetKind())
 }
 
 // emitRolloutStatus temporarily emit status metrics during rollout for observation
@@ -749,52 +917,60 @@ func (d *managedDispatcherImpl) emitRolloutStatus(
 	_ = d.metrics.Store("sync.rollout.available", available, fedTags...)
 }
 
-func SendRolloutPlansToES(
+// newRolloutAuditRecord returns the audit record of the rollout plans computed for the federated object.
+func newRolloutAuditRecord(
 	planner *util.RolloutPlanner,
 	plans util.RolloutPlans,
 	fedResource *unstructured.Unstructured,
 	err error,
-) {
-	data := struct {
-		Date           time.Time
-		Name           string
-		Namespace      string
-		Kind           string
-		Replicas       int32
-		MaxSurge       int32
-		MaxUnavailable int32
-		Revision       string
-		CurrentStatus  string
-		Result         util.RolloutPlans
-		ResultStr      string
-		Error          string
-	}{
-		Date:      time.Now(),
-		Name:      fedResource.GetName(),
-		Namespace: fedResource.GetNamespace(),
-		Kind:      fedResource.GetKind(),
-		Result:    plans,
+) *rolloutaudit.Record {
+	record := &rolloutaudit.Record{
+		Timestamp:  time.Now(),
+		APIVersion: fedResource.GetAPIVersion(),
+		Kind:       fedResource.GetKind(),
+		Namespace:  fedResource.GetNamespace(),
+		Name:       fedResource.GetName(),
+		UID:        fedResource.GetUID(),
+		Generation: fedResource.GetGeneration(),
 	}
 	if err != nil {
-		data.Error = err.Error()
-	} else {
-		if planner != nil {
-			var s []string
-			for _, t := range planner.Targets {
-				s = append(s, t.String())
-			}
-			data.Replicas = planner.Replicas
-			data.MaxSurge = planner.MaxSurge
-			data.MaxUnavailable = planner.MaxUnavailable
-			data.Revision = planner.Revision
-			data.CurrentStatus = strings.Join(s, "\n")
-		}
-		if len(plans) > 0 {
-			var s []string
-			for c, p := range plans {
-				s = append(s, c+":"+p.String())
+		record.Error = err.Error()
+		return record
+	}
+
+	if planner != nil {
+		record.Replicas = planner.Replicas
+		record.MaxSurge = planner.MaxSurge
+		record.MaxUnavailable = planner.MaxUnavailable
+		record.Revision = planner.Revision
+		for _, t := range planner.Targets {
+			record.Targets = append(record.Targets, rolloutaudit.Target{
+				Cluster:                     t.ClusterName,
+				DesiredReplicas:             t.DesiredReplicas,
+				Replicas:                    t.Status.Replicas,
+				ActualReplicas:              t.Status.ActualReplicas,
+				AvailableReplicas:           t.Status.AvailableReplicas,
+				UpdatedReplicas:             t.Status.UpdatedReplicas,
+				UpdatedAvailableReplicas:    t.Status.UpdatedAvailableReplicas,
+				CurrentNewReplicas:          t.Status.CurrentNewReplicas,
+				CurrentNewAvailableReplicas: t.Status.CurrentNewAvailableReplicas,
+				Updated:                     t.Status.Updated,
+				MaxSurge:                    t.Status.MaxSurge,
+				MaxUnavailable:              t.Status.MaxUnavailable,
+			})
+		}
+	}
+	if len(plans) > 0 {
+		record.Plans = make(map[string]rolloutaudit.Plan, len(plans))
+		for cluster, plan := range plans {
+			record.Plans[cluster] = rolloutaudit.Plan{
+				Replicas:          plan.Replicas,
+				MaxSurge:          plan.MaxSurge,
+				MaxUnavailable:    plan.MaxUnavailable,
+				Partition:         plan.Partition,
+				OnlyPatchReplicas: plan.OnlyPatchReplicas,
 			}
-			data.ResultStr = strings.Join(s, "\n")
 		}
 	}
+	return record
 }
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/client/generic"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+type fakeFederatedResource struct {
+	FederatedResourceForDispatch
+	gvk     schema.GroupVersionKind
+	desired *unstructured.Unstructured
+}
+
+func (r *fakeFederatedResource) TargetName() common.QualifiedName {
+	return common.QualifiedName{Namespace: "default", Name: "test"}
+}
+
+func (r *fakeFederatedResource) TargetKind() string { return r.gvk.Kind }
+
+func (r *fakeFederatedResource) TargetGVK() schema.GroupVersionKind { return r.gvk }
+
+func (r *fakeFederatedResource) TypeConfig() *fedcorev1a1.FederatedTypeConfig {
+	return &fedcorev1a1.FederatedTypeConfig{
+		Spec: fedcorev1a1.FederatedTypeConfigSpec{
+			PathDefinition: fedcorev1a1.PathDefinition{ReplicasSpec: "spec.replicas"},
+		},
+	}
+}
+
+func (r *fakeFederatedResource) Object() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+}
+
+func (r *fakeFederatedResource) ObjectForCluster(clusterName string) (*unstructured.Unstructured, error) {
+	return r.desired.DeepCopy(), nil
+}
+
+func (r *fakeFederatedResource) ApplyOverrides(
+	obj *unstructured.Unstructured,
+	clusterName string,
+	otherOverrides fedtypesv1a1.OverridePatches,
+) error {
+	return nil
+}
+
+func (r *fakeFederatedResource) VersionForCluster(clusterName string) (string, error) { return "", nil }
+
+func (r *fakeFederatedResource) RecordError(errorCode string, err error) {}
+
+func (r *fakeFederatedResource) RecordEvent(reason, messageFmt string, args ...interface{}) {}
+
+type fakeUpdateClient struct {
+	generic.Client
+	updated *unstructured.Unstructured
+}
+
+func (c *fakeUpdateClient) Update(ctx context.Context, obj client.Object) error {
+	c.updated = obj.(*unstructured.Unstructured).DeepCopy()
+	return nil
+}
+
+func newWorkloadForDispatch(gvk schema.GroupVersionKind, image string, minReadySeconds int64) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"namespace": "default", "name": "test"},
+		"spec": map[string]interface{}{
+			"minReadySeconds": minReadySeconds,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "app", "image": image}},
+				},
+			},
+		},
+	}}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
+func TestPatchAndKeepTemplate(t *testing.T) {
+	for _, kind := range []string{common.StatefulSetKind, common.DaemonSetKind} {
+		t.Run(kind, func(t *testing.T) {
+			gvk := appsv1.SchemeGroupVersion.WithKind(kind)
+			clusterObj := newWorkloadForDispatch(gvk, "app:v1", 0)
+			fedResource := &fakeFederatedResource{gvk: gvk, desired: newWorkloadForDispatch(gvk, "app:v2", 10)}
+			fakeClient := &fakeUpdateClient{}
+
+			d := NewManagedDispatcher(
+				func(clusterName string) (generic.Client, error) { return fakeClient, nil },
+				fedResource,
+				false,
+				nil,
+				nil,
+				NewDriftCorrectionTracker(),
+			).(*managedDispatcherImpl)
+			d.PatchAndKeepTemplate(context.Background(), "cluster1", clusterObj, false)
+			if ok, err := d.Wait(); !ok || err != nil {
+				t.Fatalf("Expected dispatch to succeed, got %v, %v", ok, err)
+			}
+
+			if fakeClient.updated == nil {
+				t.Fatalf("Expected the object to be updated")
+			}
+			containers, _, _ := unstructured.NestedSlice(fakeClient.updated.Object, "spec", "template", "spec", "containers")
+			if image := containers[0].(map[string]interface{})["image"]; image != "app:v1" {
+				t.Errorf("Expected the template to be held at image app:v1, got %v", image)
+			}
+			minReadySeconds, _, _ := unstructured.NestedInt64(fakeClient.updated.Object, "spec", "minReadySeconds")
+			if minReadySeconds != 10 {
+				t.Errorf("Expected fields outside of the template to be updated, got minReadySeconds %d", minReadySeconds)
+			}
+			if _, exists := fakeClient.updated.GetAnnotations()[common.LastReplicasetName]; exists {
+				t.Errorf("Expected annotation %s to be only set on deployments", common.LastReplicasetName)
+			}
+		})
+	}
+}
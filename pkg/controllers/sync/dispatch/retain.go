@@ -25,6 +25,7 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -96,6 +97,45 @@ func RetainOrMergeClusterFields(
 	return nil
 }
 
+// PrepareObjectForCluster performs the steps the managed dispatcher takes on the desired object before it is created
+// or updated in a cluster: the propagated labels and annotations are recorded and, if the object already exists in the
+// cluster, the fields managed in the cluster are retained. It is used by the agents of clusters in pull mode, which
+// do not have access to the federated object.
+func PrepareObjectForCluster(
+	desiredObj, clusterObj *unstructured.Unstructured,
+	shouldRetainReplicas bool,
+	typeConfig *fedcorev1a1.FederatedTypeConfig,
+) error {
+	recordPropagatedLabelsAndAnnotations(desiredObj)
+	if clusterObj == nil {
+		return nil
+	}
+
+	targetType := typeConfig.GetTargetType()
+	targetGvk := schemautil.APIResourceToGVK(&targetType)
+	if err := RetainOrMergeClusterFields(targetGvk, desiredObj, clusterObj, nil); err != nil {
+		return errors.Wrapf(err, "failed to retain fields")
+	}
+	if shouldRetainReplicas {
+		if err := retainClusterReplicas(desiredObj, clusterObj, typeConfig); err != nil {
+			return errors.Wrapf(err, "failed to retain replicas")
+		}
+	}
+	if targetGvk == appsv1.SchemeGroupVersion.WithKind(common.DeploymentKind) {
+		if err := setLastReplicasetName(desiredObj, clusterObj); err != nil {
+			return errors.Wrapf(err, "failed to set last replicaset name")
+		}
+	}
+	return nil
+}
+
+// HoldTemplate keeps the template and revision of the current object in the desired object, as the managed dispatcher
+// does for clusters whose wave has not been reached in a staged rollout. It is used for clusters in pull mode, whose
+// current object is the template of their PulledObject.
+func HoldTemplate(desiredObj, currentObj *unstructured.Unstructured, typeConfig *fedcorev1a1.FederatedTypeConfig) error {
+	return retainTemplate(desiredObj, currentObj, typeConfig, false)
+}
+
 func recordPropagatedLabelsAndAnnotations(obj *unstructured.Unstructured) {
 	// Record the propagated annotation/label keys, so we can diff it against the cluster object during retention
 	// to determine whether an annotation/label has been deleted from the template.
@@ -542,15 +582,20 @@ func retainReplicas(desiredObj, clusterObj, fedObj *unstructured.Unstructured, t
 		return err
 	}
 	if retain {
-		replicas, err := utilunstructured.GetInt64FromPath(clusterObj, typeConfig.Spec.PathDefinition.ReplicasSpec, nil)
-		if err != nil {
-			return err
-		}
+		return retainClusterReplicas(desiredObj, clusterObj, typeConfig)
+	}
+	return nil
+}
 
-		if replicas != nil {
-			if err := utilunstructured.SetInt64FromPath(desiredObj, typeConfig.Spec.PathDefinition.ReplicasSpec, replicas, nil); err != nil {
-				return err
-			}
+func retainClusterReplicas(desiredObj, clusterObj *unstructured.Unstructured, typeConfig *fedcorev1a1.FederatedTypeConfig) error {
+	replicas, err := utilunstructured.GetInt64FromPath(clusterObj, typeConfig.Spec.PathDefinition.ReplicasSpec, nil)
+	if err != nil {
+		return err
+	}
+
+	if replicas != nil {
+		if err := utilunstructured.SetInt64FromPath(desiredObj, typeConfig.Spec.PathDefinition.ReplicasSpec, replicas, nil); err != nil {
+			return err
 		}
 	}
 	return nil
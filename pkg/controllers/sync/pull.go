@@ -0,0 +1,359 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/sync/dispatch"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	annotationutil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/annotation"
+)
+
+// Objects are propagated to clusters in Pull mode through PulledObjects in the pull namespaces of the clusters. The
+// agent of a cluster applies the template of each PulledObject in its namespace, removes the objects whose
+// PulledObjects are deleted, and reports the result in the status of the PulledObject.
+
+func (s *SyncController) enqueuePulledObject(obj pkgruntime.Object) {
+	pulledObject, ok := obj.(*fedcorev1a1.PulledObject)
+	if !ok {
+		return
+	}
+	targetName, err := util.PulledObjectTargetName(pulledObject)
+	if err != nil {
+		s.logger.Error(err, "Failed to get target of pulled object", "pulled-object", common.NewQualifiedName(obj))
+		return
+	}
+	s.worker.Enqueue(targetName)
+}
+
+// syncToPullCluster ensures the PulledObject of the federated object in a cluster in Pull mode and records the status
+// reported by the agent of the cluster. If holdTemplate is set, the template of an existing PulledObject is kept at its
+// previous revision. It returns whether the sync succeeded and whether a recheck is required.
+func (s *SyncController) syncToPullCluster(
+	ctx context.Context,
+	dispatcher dispatch.ManagedDispatcher,
+	fedResource FederatedResource,
+	cluster *fedcorev1a1.FederatedCluster,
+	shouldBeDeleted, isCascadingDeletionTriggered, holdTemplate bool,
+) (ok bool, recheck bool) {
+	clusterName := cluster.Name
+	pulledObject, err := s.getPulledObject(clusterName, fedResource.TargetName())
+	if err != nil {
+		dispatcher.RecordClusterError(fedtypesv1a1.CachedRetrievalFailed, clusterName, err)
+		return false, false
+	}
+
+	if shouldBeDeleted {
+		if pulledObject == nil {
+			return true, false
+		}
+		// We only respect orphaning behavior during cascading deletion, but not while migrating between clusters. The
+		// objects are orphaned if the cluster is terminating and cascading-delete is disabled, since the pulled objects
+		// are deleted together with the cluster.
+		orphaningBehavior := util.OrphanManagedResourcesNone
+		switch {
+		case isCascadingDeletionTriggered:
+			orphaningBehavior = util.GetOrphaningBehavior(fedResource.Object())
+		case cluster.GetDeletionTimestamp() != nil && !util.IsCascadingDeleteEnabled(cluster):
+			orphaningBehavior = util.OrphanManagedResourcesAll
+		}
+		recheck, err := s.removePulledObject(ctx, pulledObject, orphaningBehavior)
+		if err != nil {
+			dispatcher.RecordClusterError(fedtypesv1a1.DeletionFailed, clusterName, err)
+			return false, false
+		}
+		if recheck {
+			dispatcher.RecordStatus(clusterName, fedtypesv1a1.WaitingForRemoval)
+		}
+		return true, recheck
+	}
+
+	if cluster.GetDeletionTimestamp() != nil {
+		// if the cluster is terminating, we should not sync
+		dispatcher.RecordClusterError(fedtypesv1a1.ClusterTerminating, clusterName, errors.New("Cluster terminating"))
+		return true, false
+	}
+
+	var heldTemplate *unstructured.Unstructured
+	if holdTemplate && pulledObject != nil {
+		if heldTemplate, err = util.PulledObjectTemplate(pulledObject); err != nil {
+			dispatcher.RecordClusterError(fedtypesv1a1.ComputeResourceFailed, clusterName, err)
+			return false, false
+		}
+	}
+	desired, propStatus, err := s.desiredPulledObject(fedResource, clusterName, heldTemplate)
+	if err != nil {
+		dispatcher.RecordClusterError(propStatus, clusterName, err)
+		return false, false
+	}
+
+	client := s.fedClient.CoreV1alpha1().PulledObjects(desired.Namespace)
+	if pulledObject == nil {
+		klog.FromContext(ctx).WithValues("cluster-name", clusterName).V(1).Info("Creating pulled object")
+		if _, err := client.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+			dispatcher.RecordClusterError(fedtypesv1a1.CreationFailed, clusterName, err)
+			return false, false
+		}
+		dispatcher.RecordStatus(clusterName, fedtypesv1a1.WaitingForAgent)
+		return true, false
+	}
+
+	upToDate, err := pulledObjectUpToDate(pulledObject, desired)
+	if err != nil {
+		dispatcher.RecordClusterError(fedtypesv1a1.ComputeResourceFailed, clusterName, err)
+		return false, false
+	}
+	if !upToDate {
+		klog.FromContext(ctx).WithValues("cluster-name", clusterName).V(1).Info("Updating pulled object")
+		pulledObject = pulledObject.DeepCopy()
+		pulledObject.Labels = desired.Labels
+		pulledObject.Spec = desired.Spec
+		if _, err := client.Update(ctx, pulledObject, metav1.UpdateOptions{}); err != nil {
+			dispatcher.RecordClusterError(fedtypesv1a1.UpdateFailed, clusterName, err)
+			return false, false
+		}
+		dispatcher.RecordStatus(clusterName, fedtypesv1a1.WaitingForAgent)
+		return true, false
+	}
+
+	// The agent has not processed the current template yet.
+	if pulledObject.Status.ObservedGeneration != pulledObject.Generation {
+		dispatcher.RecordStatus(clusterName, fedtypesv1a1.WaitingForAgent)
+		return true, false
+	}
+	propStatus = fedtypesv1a1.PropagationStatus(pulledObject.Status.PropagationStatus)
+	if propStatus != fedtypesv1a1.ClusterPropagationOK {
+		dispatcher.RecordClusterError(propStatus, clusterName, errors.Errorf(
+			"Agent of cluster %s failed to apply object: %s", clusterName, pulledObject.Status.Message))
+		return true, false
+	}
+	if heldTemplate != nil {
+		dispatcher.RecordStatus(clusterName, fedtypesv1a1.WaitingForRolloutWave)
+		return true, false
+	}
+	dispatcher.RecordStatus(clusterName, propStatus)
+	return true, false
+}
+
+// ensurePulledObjectsRemoved removes the PulledObjects of the target object from all clusters in Pull mode with the
+// given orphaning behavior. It returns whether the removal has to be rechecked.
+func (s *SyncController) ensurePulledObjectsRemoved(
+	ctx context.Context,
+	targetName common.QualifiedName,
+	orphaningBehavior util.OrphanManagedResourcesBehavior,
+) (bool, error) {
+	clusters, err := s.informer.GetJoinedClusters()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get a list of clusters")
+	}
+
+	recheck := false
+	for _, cluster := range clusters {
+		if !cluster.IsPullMode() {
+			continue
+		}
+		pulledObject, err := s.getPulledObject(cluster.Name, targetName)
+		if err != nil {
+			return false, err
+		}
+		if pulledObject == nil {
+			continue
+		}
+		clusterRecheck, err := s.removePulledObject(ctx, pulledObject, orphaningBehavior)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to remove pulled object for cluster %s", cluster.Name)
+		}
+		recheck = recheck || clusterRecheck
+	}
+	return recheck, nil
+}
+
+// removePulledObject deletes the PulledObject once its agent has observed the given orphaning behavior, which the agent
+// follows when removing the object from the cluster after the PulledObject is deleted. It returns whether the removal
+// has to be rechecked.
+func (s *SyncController) removePulledObject(
+	ctx context.Context,
+	pulledObject *fedcorev1a1.PulledObject,
+	orphaningBehavior util.OrphanManagedResourcesBehavior,
+) (bool, error) {
+	if pulledObject.DeletionTimestamp != nil {
+		return false, nil
+	}
+	logger := klog.FromContext(ctx).WithValues("pulled-object", common.NewQualifiedName(pulledObject).String())
+	client := s.fedClient.CoreV1alpha1().PulledObjects(pulledObject.Namespace)
+
+	template, err := util.PulledObjectTemplate(pulledObject)
+	if err != nil {
+		return false, err
+	}
+	if util.GetOrphaningBehavior(template) != orphaningBehavior {
+		if err := setOrphaningBehavior(template, orphaningBehavior); err != nil {
+			return false, err
+		}
+		pulledObject = pulledObject.DeepCopy()
+		if pulledObject.Spec.Template.Raw, err = template.MarshalJSON(); err != nil {
+			return false, err
+		}
+		logger.V(1).Info("Updating orphaning behavior of pulled object before removal")
+		if _, err := client.Update(ctx, pulledObject, metav1.UpdateOptions{}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if pulledObject.Status.ObservedGeneration != pulledObject.Generation {
+		return true, nil
+	}
+
+	logger.V(1).Info("Deleting pulled object")
+	err = client.Delete(ctx, pulledObject.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &pulledObject.ResourceVersion},
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	return false, nil
+}
+
+func (s *SyncController) getPulledObject(
+	clusterName string,
+	targetName common.QualifiedName,
+) (*fedcorev1a1.PulledObject, error) {
+	pulledObject, err := s.pulledObjectLister.PulledObjects(util.PullNamespace(clusterName)).
+		Get(util.PulledObjectName(s.typeConfig.Name, targetName))
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pulled object from cache")
+	}
+	return pulledObject, nil
+}
+
+// desiredPulledObject returns the PulledObject holding the object desired in the cluster. If heldTemplate is not nil,
+// its template is kept in the desired object. The propagation status to record is returned with any error.
+func (s *SyncController) desiredPulledObject(
+	fedResource FederatedResource,
+	clusterName string,
+	heldTemplate *unstructured.Unstructured,
+) (*fedcorev1a1.PulledObject, fedtypesv1a1.PropagationStatus, error) {
+	obj, err := fedResource.ObjectForCluster(clusterName)
+	if err != nil {
+		return nil, fedtypesv1a1.ComputeResourceFailed, err
+	}
+	if err := fedResource.ApplyOverrides(obj, clusterName, nil); err != nil {
+		return nil, fedtypesv1a1.ApplyOverridesFailed, err
+	}
+	if heldTemplate != nil {
+		if err := dispatch.HoldTemplate(obj, heldTemplate, s.typeConfig); err != nil {
+			return nil, fedtypesv1a1.FieldRetentionFailed, err
+		}
+	}
+	// The orphaning behavior is only recorded in the template when the object is removed, see removePulledObject.
+	template, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, fedtypesv1a1.ComputeResourceFailed, err
+	}
+	retainReplicas, _, err := unstructured.NestedBool(
+		fedResource.Object().Object,
+		common.SpecField,
+		common.RetainReplicasField,
+	)
+	if err != nil {
+		return nil, fedtypesv1a1.ComputeResourceFailed, err
+	}
+
+	return &fedcorev1a1.PulledObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: util.PullNamespace(clusterName),
+			Name:      util.PulledObjectName(s.typeConfig.Name, fedResource.TargetName()),
+			Labels:    util.PulledObjectTypeSelector(s.typeConfig.Name),
+		},
+		Spec: fedcorev1a1.PulledObjectSpec{
+			Template:       apiextensionsv1.JSON{Raw: template},
+			AdoptResources: util.ShouldAdoptPreexistingResources(fedResource.Object()),
+			RetainReplicas: retainReplicas,
+		},
+	}, "", nil
+}
+
+// pulledClusterObject returns the object in the cluster of the PulledObject as reported by its agent: the applied
+// template with the status fields collected from the cluster. It returns nil if the agent has not applied the current
+// template successfully.
+func pulledClusterObject(pulledObject *fedcorev1a1.PulledObject) (*unstructured.Unstructured, error) {
+	if pulledObject.Status.ObservedGeneration != pulledObject.Generation ||
+		pulledObject.Status.PropagationStatus != string(fedtypesv1a1.ClusterPropagationOK) {
+		return nil, nil
+	}
+
+	clusterObj, err := util.PulledObjectTemplate(pulledObject)
+	if err != nil {
+		return nil, err
+	}
+	clusterObj.SetGeneration(pulledObject.Status.ClusterGeneration)
+	unstructured.RemoveNestedField(clusterObj.Object, common.StatusField)
+	if pulledObject.Status.CollectedFields != nil {
+		collectedFields := map[string]interface{}{}
+		if err := utiljson.Unmarshal(pulledObject.Status.CollectedFields.Raw, &collectedFields); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal collected fields")
+		}
+		if status, exists := collectedFields[common.StatusField]; exists {
+			clusterObj.Object[common.StatusField] = status
+		}
+	}
+	return clusterObj, nil
+}
+
+func pulledObjectUpToDate(current, desired *fedcorev1a1.PulledObject) (bool, error) {
+	if !apiequality.Semantic.DeepEqual(current.Labels, desired.Labels) ||
+		current.Spec.AdoptResources != desired.Spec.AdoptResources ||
+		current.Spec.RetainReplicas != desired.Spec.RetainReplicas {
+		return false, nil
+	}
+
+	var currentTemplate, desiredTemplate interface{}
+	if err := json.Unmarshal(current.Spec.Template.Raw, &currentTemplate); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal template")
+	}
+	if err := json.Unmarshal(desired.Spec.Template.Raw, &desiredTemplate); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal template")
+	}
+	return apiequality.Semantic.DeepEqual(currentTemplate, desiredTemplate), nil
+}
+
+func setOrphaningBehavior(obj *unstructured.Unstructured, behavior util.OrphanManagedResourcesBehavior) error {
+	if behavior == util.OrphanManagedResourcesNone {
+		_, err := annotationutil.RemoveAnnotation(obj, util.OrphanManagedResourcesInternalAnnotation)
+		return err
+	}
+	_, err := annotationutil.AddAnnotation(obj, util.OrphanManagedResourcesInternalAnnotation, string(behavior))
+	return err
+}
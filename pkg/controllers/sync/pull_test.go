@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+func newTestPulledObject(template string, retainReplicas bool) *fedcorev1a1.PulledObject {
+	pulledObject := &fedcorev1a1.PulledObject{
+		Spec: fedcorev1a1.PulledObjectSpec{
+			Template:       apiextensionsv1.JSON{Raw: []byte(template)},
+			RetainReplicas: retainReplicas,
+		},
+	}
+	pulledObject.Labels = util.PulledObjectTypeSelector("deployments.apps")
+	return pulledObject
+}
+
+func TestPulledObjectUpToDate(t *testing.T) {
+	current := newTestPulledObject(`{"kind":"Deployment","spec":{"replicas":1,"paused":false}}`, false)
+
+	upToDate, err := pulledObjectUpToDate(
+		current,
+		newTestPulledObject(`{"spec":{"paused":false,"replicas":1},"kind":"Deployment"}`, false),
+	)
+	assert.NoError(t, err)
+	assert.True(t, upToDate, "templates should be compared semantically")
+
+	upToDate, err = pulledObjectUpToDate(
+		current,
+		newTestPulledObject(`{"kind":"Deployment","spec":{"replicas":2,"paused":false}}`, false),
+	)
+	assert.NoError(t, err)
+	assert.False(t, upToDate)
+
+	upToDate, err = pulledObjectUpToDate(
+		current,
+		newTestPulledObject(`{"kind":"Deployment","spec":{"replicas":1,"paused":false}}`, true),
+	)
+	assert.NoError(t, err)
+	assert.False(t, upToDate)
+
+	_, err = pulledObjectUpToDate(current, newTestPulledObject(`{`, false))
+	assert.Error(t, err)
+}
+
+func TestSetOrphaningBehavior(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+	assert.NoError(t, setOrphaningBehavior(obj, util.OrphanManagedResourcesAll))
+	assert.Equal(t, util.OrphanManagedResourcesAll, util.GetOrphaningBehavior(obj))
+
+	assert.NoError(t, setOrphaningBehavior(obj, util.OrphanManagedResourcesNone))
+	assert.Equal(t, util.OrphanManagedResourcesNone, util.GetOrphaningBehavior(obj))
+	assert.NotContains(t, obj.GetAnnotations(), util.OrphanManagedResourcesInternalAnnotation)
+}
+
+func TestPulledClusterObject(t *testing.T) {
+	pulledObject := newTestPulledObject(
+		`{"apiVersion":"apps/v1","kind":"Deployment","spec":{"replicas":2},"status":{"replicas":5}}`,
+		false,
+	)
+	pulledObject.Generation = 2
+	pulledObject.Status = fedcorev1a1.PulledObjectStatus{
+		ObservedGeneration: 1,
+		PropagationStatus:  string(fedtypesv1a1.ClusterPropagationOK),
+		ClusterGeneration:  3,
+		CollectedFields: &apiextensionsv1.JSON{
+			Raw: []byte(`{"spec":{"replicas":1},"status":{"observedGeneration":3,"availableReplicas":2}}`),
+		},
+	}
+
+	clusterObj, err := pulledClusterObject(pulledObject)
+	assert.NoError(t, err)
+	assert.Nil(t, clusterObj, "the agent has not applied the current template")
+
+	pulledObject.Status.ObservedGeneration = 2
+	clusterObj, err = pulledClusterObject(pulledObject)
+	assert.NoError(t, err)
+	if assert.NotNil(t, clusterObj) {
+		assert.Equal(t, int64(3), clusterObj.GetGeneration())
+		assert.Equal(t, map[string]interface{}{"replicas": int64(2)}, clusterObj.Object["spec"])
+		assert.Equal(t, map[string]interface{}{"observedGeneration": int64(3), "availableReplicas": int64(2)},
+			clusterObj.Object["status"])
+	}
+
+	pulledObject.Status.PropagationStatus = string(fedtypesv1a1.UpdateFailed)
+	clusterObj, err = pulledClusterObject(pulledObject)
+	assert.NoError(t, err)
+	assert.Nil(t, clusterObj)
+}
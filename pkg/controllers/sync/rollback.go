@@ -0,0 +1,457 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
+)
+
+const (
+	EventReasonRolledBack     = "RolledBack"
+	EventReasonRollbackFailed = "RollbackFailed"
+
+	RolledBackReasonRequested                     = "RollbackRequested"
+	RolledBackReasonInsufficientAvailableReplicas = "InsufficientAvailableReplicas"
+	RolledBackReasonProgressDeadlineExceeded      = "ProgressDeadlineExceeded"
+
+	defaultRolloutAnalysisDelay = 5 * time.Minute
+)
+
+// rolloutFailure describes why the rollout of a revision has failed.
+type rolloutFailure struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// getUpdatedClusterObjects returns the objects in the clusters of the waves that have been started which have been
+// updated to the revision.
+func (s *SyncController) getUpdatedClusterObjects(
+	ctx context.Context,
+	fedResource FederatedResource,
+	revision string,
+	waves []rolloutWave,
+	currentWave int,
+	pullClusterNames sets.String,
+) map[string]*unstructured.Unstructured {
+	clusterObjs := map[string]*unstructured.Unstructured{}
+	for i := 0; i <= currentWave && i < len(waves); i++ {
+		for clusterName := range waves[i].clusters {
+			clusterObj := s.getRolloutClusterObject(ctx, fedResource, clusterName, pullClusterNames.Has(clusterName))
+			if clusterObj == nil {
+				continue
+			}
+			if clusterObj.GetAnnotations()[common.CurrentRevisionAnnotation] == revision {
+				clusterObjs[clusterName] = clusterObj
+			}
+		}
+	}
+	return clusterObjs
+}
+
+// validateAutoRollback returns an error if auto rollback is configured without any criteria by which a rollout
+// could fail.
+func validateAutoRollback(autoRollback *fedcorev1a1.AutoRollback) error {
+	if autoRollback != nil && autoRollback.MinAvailablePercentage == nil && autoRollback.ProgressDeadline == nil {
+		return fmt.Errorf("auto rollback requires minAvailablePercentage or progressDeadline to be set")
+	}
+	return nil
+}
+
+// analyzeRollout returns the failure of the rollout if its updated clusters do not have enough available replicas or
+// it has not reached all waves within the progress deadline. Otherwise, it returns the time remaining until the
+// rollout has to be analyzed again, if any. The available replicas are only analyzed once the analysis delay has
+// passed since the current wave was started. Rollouts that are complete or of the last good revision are not analyzed.
+func analyzeRollout(
+	autoRollback *fedcorev1a1.AutoRollback,
+	status *stagedRolloutStatus,
+	numWaves int,
+	updatedClusterObjs map[string]*unstructured.Unstructured,
+	now time.Time,
+) (*rolloutFailure, *time.Duration) {
+	if status.Wave >= numWaves || status.Revision == status.LastGoodRevision {
+		return nil, nil
+	}
+
+	var recheckAfter *time.Duration
+	analyzeAvailability := autoRollback.MinAvailablePercentage != nil
+	if analyzeAvailability {
+		analysisDelay := defaultRolloutAnalysisDelay
+		if autoRollback.AnalysisDelay != nil {
+			analysisDelay = autoRollback.AnalysisDelay.Duration
+		}
+		waveStartTime := status.WaveStartTime
+		if waveStartTime == nil {
+			waveStartTime = status.StartTime
+		}
+		if waveStartTime != nil {
+			if remaining := waveStartTime.Add(analysisDelay).Sub(now); remaining > 0 {
+				analyzeAvailability = false
+				recheckAfter = &remaining
+			}
+		}
+	}
+
+	if analyzeAvailability {
+		var desired, available int64
+		clusterNames := make([]string, 0, len(updatedClusterObjs))
+		for clusterName, clusterObj := range updatedClusterObjs {
+			clusterDesired, clusterAvailable, ok := getAvailableReplicas(clusterObj)
+			if !ok {
+				continue
+			}
+			desired += int64(clusterDesired)
+			available += int64(clusterAvailable)
+			clusterNames = append(clusterNames, clusterName)
+		}
+
+		minAvailablePercentage := int64(*autoRollback.MinAvailablePercentage)
+		if desired > 0 && available*100 < desired*minAvailablePercentage {
+			return &rolloutFailure{
+				Reason: RolledBackReasonInsufficientAvailableReplicas,
+				Message: fmt.Sprintf(
+					"%d of %d desired replicas of revision %s are available in clusters %v, below %d%%",
+					available,
+					desired,
+					status.Revision,
+					sets.NewString(clusterNames...).List(),
+					minAvailablePercentage,
+				),
+			}, nil
+		}
+	}
+
+	if autoRollback.ProgressDeadline != nil && status.StartTime != nil {
+		remaining := status.StartTime.Add(autoRollback.ProgressDeadline.Duration).Sub(now)
+		if remaining <= 0 {
+			return &rolloutFailure{
+				Reason: RolledBackReasonProgressDeadlineExceeded,
+				Message: fmt.Sprintf(
+					"revision %s did not reach all clusters within %s, stalled at wave %q",
+					status.Revision,
+					autoRollback.ProgressDeadline.Duration,
+					status.WaveName,
+				),
+			}, nil
+		}
+		if recheckAfter == nil || remaining < *recheckAfter {
+			recheckAfter = &remaining
+		}
+	}
+
+	return nil, recheckAfter
+}
+
+// getAvailableReplicas returns the desired replicas of a workload and its available replicas of the current revision.
+// It returns false if the object is not a workload or its status is out of date.
+func getAvailableReplicas(clusterObj *unstructured.Unstructured) (desired, available int32, ok bool) {
+	observedGeneration, exists, err := unstructured.NestedInt64(clusterObj.Object, common.StatusField, "observedGeneration")
+	if err != nil || !exists || observedGeneration < clusterObj.GetGeneration() {
+		return 0, 0, false
+	}
+
+	switch clusterObj.GroupVersionKind() {
+	case appsv1.SchemeGroupVersion.WithKind(common.DeploymentKind):
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(clusterObj.Object, deployment); err != nil {
+			return 0, 0, false
+		}
+		if available, ok := getNewReplicaSetAvailableReplicas(clusterObj); ok {
+			return getReplicas(deployment.Spec.Replicas), available, true
+		}
+		return getReplicas(deployment.Spec.Replicas), getUpdatedAvailableReplicas(
+			deployment.Status.Replicas,
+			deployment.Status.UpdatedReplicas,
+			deployment.Status.AvailableReplicas,
+		), true
+	case appsv1.SchemeGroupVersion.WithKind(common.StatefulSetKind):
+		statefulSet := &appsv1.StatefulSet{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(clusterObj.Object, statefulSet); err != nil {
+			return 0, 0, false
+		}
+		return getReplicas(statefulSet.Spec.Replicas), getUpdatedAvailableReplicas(
+			statefulSet.Status.Replicas,
+			statefulSet.Status.UpdatedReplicas,
+			statefulSet.Status.AvailableReplicas,
+		), true
+	case appsv1.SchemeGroupVersion.WithKind(common.DaemonSetKind):
+		daemonSet := &appsv1.DaemonSet{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(clusterObj.Object, daemonSet); err != nil {
+			return 0, 0, false
+		}
+		return daemonSet.Status.DesiredNumberScheduled, getUpdatedAvailableReplicas(
+			daemonSet.Status.CurrentNumberScheduled,
+			daemonSet.Status.UpdatedNumberScheduled,
+			daemonSet.Status.NumberAvailable,
+		), true
+	default:
+		return 0, 0, false
+	}
+}
+
+// getNewReplicaSetAvailableReplicas returns the available replicas of the new ReplicaSet of a Deployment from the
+// latest ReplicaSet annotations, if they describe the current generation of the Deployment.
+func getNewReplicaSetAvailableReplicas(deployment *unstructured.Unstructured) (int32, bool) {
+	annotations := deployment.GetAnnotations()
+	observedGeneration, err := strconv.ParseInt(annotations[util.LatestReplicasetObservedGenerationAnnotation], 10, 64)
+	if err != nil || observedGeneration < deployment.GetGeneration() {
+		return 0, false
+	}
+	available, err := strconv.ParseInt(annotations[util.LatestReplicasetAvailableReplicasAnnotation], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(available), true
+}
+
+// getUpdatedAvailableReplicas returns the available replicas of the current revision of a workload, given its total,
+// updated and available replicas of all revisions. Replicas of the previous revisions are assumed to be available,
+// so the result is a lower bound while old replicas remain.
+func getUpdatedAvailableReplicas(replicas, updated, available int32) int32 {
+	updatedAvailable := available - (replicas - updated)
+	if updatedAvailable < 0 {
+		return 0
+	}
+	if updatedAvailable > updated {
+		return updated
+	}
+	return updatedAvailable
+}
+
+// rollbackFailedRollout reverts the template of the source object to the last good revision of a failed rollout. If
+// there is no good revision, the rollout stays paused until the template is changed, and the failure is only reported
+// when it is new.
+func (s *SyncController) rollbackFailedRollout(
+	ctx context.Context,
+	fedResource FederatedResource,
+	status *stagedRolloutStatus,
+	isNewFailure bool,
+) error {
+	toRevision := status.LastGoodRevision
+	if len(toRevision) == 0 {
+		// Fall back to the previous revision if no rollout of the object has completed yet.
+		lastRevision := fedResource.Object().GetAnnotations()[common.LastRevisionAnnotation]
+		toRevision = strings.Split(lastRevision, "|")[0]
+	}
+	if len(toRevision) == 0 || toRevision == status.Revision {
+		if isNewFailure {
+			fedResource.RecordError(EventReasonRollbackFailed, fmt.Errorf(
+				"rollout failed but there is no revision to roll back to, update the template or set annotation %s "+
+					"to resume: %s",
+				common.RollbackToAnnotation,
+				status.Failure.Message,
+			))
+		}
+		return nil
+	}
+
+	revision, err := s.findRevision(fedResource, toRevision)
+	if err != nil {
+		return err
+	}
+
+	sourceObj, err := s.getSourceObject(ctx, fedResource)
+	if err != nil || sourceObj == nil {
+		return err
+	}
+	message := fmt.Sprintf(
+		"Rolled back from revision %s to revision %s: %s",
+		status.Revision,
+		revision.Name,
+		status.Failure.Message,
+	)
+	_, err = s.rollbackSourceObject(ctx, fedResource, sourceObj, revision, status.Failure.Reason, message)
+	return err
+}
+
+// handleRollbackRequest rolls the template of the source object back to the revision requested by the
+// RollbackToAnnotation, and removes the annotation from the source object. It returns whether a rollback was
+// requested.
+func (s *SyncController) handleRollbackRequest(ctx context.Context, fedResource FederatedResource) (bool, error) {
+	toRevision, exists := fedResource.Object().GetAnnotations()[common.RollbackToAnnotation]
+	if !exists {
+		return false, nil
+	}
+
+	sourceObj, err := s.getSourceObject(ctx, fedResource)
+	if err != nil {
+		return false, err
+	}
+	// The annotation is copied from the source object, so the federated object may still have it after the rollback.
+	if sourceObj == nil || sourceObj.GetAnnotations()[common.RollbackToAnnotation] != toRevision {
+		return false, nil
+	}
+
+	annotations := sourceObj.GetAnnotations()
+	delete(annotations, common.RollbackToAnnotation)
+	sourceObj.SetAnnotations(annotations)
+
+	revision, err := s.findRevision(fedResource, toRevision)
+	if err != nil {
+		fedResource.RecordError(EventReasonRollbackFailed, err)
+		return true, s.hostClusterClient.Update(ctx, sourceObj)
+	}
+
+	message := fmt.Sprintf("Rolled back to revision %s as requested by annotation %s", revision.Name, common.RollbackToAnnotation)
+	rolledBack, err := s.rollbackSourceObject(ctx, fedResource, sourceObj, revision, RolledBackReasonRequested, message)
+	if err != nil || rolledBack {
+		return true, err
+	}
+	// The template is already at the revision, but the annotation still has to be removed.
+	return true, s.hostClusterClient.Update(ctx, sourceObj)
+}
+
+// findRevision returns the ControllerRevision of the federated object with the given name or revision number.
+func (s *SyncController) findRevision(fedResource FederatedResource, nameOrNumber string) (*appsv1.ControllerRevision, error) {
+	revisions, err := s.listRevisions(fedResource)
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := strconv.ParseInt(nameOrNumber, 10, 64)
+	isNumber := err == nil
+	for _, revision := range revisions {
+		if revision.Name == nameOrNumber || isNumber && revision.Revision == number {
+			return revision, nil
+		}
+	}
+	return nil, fmt.Errorf("revision %s not found in the history of %s", nameOrNumber, fedResource.FederatedName())
+}
+
+// getSourceObject returns the source object of the federated object, or nil if it does not exist.
+func (s *SyncController) getSourceObject(ctx context.Context, fedResource FederatedResource) (*unstructured.Unstructured, error) {
+	sourceAPIResource := s.typeConfig.GetSourceType()
+	if sourceAPIResource == nil {
+		return nil, nil
+	}
+	sourceObj := &unstructured.Unstructured{}
+	sourceObj.SetGroupVersionKind(schemautil.APIResourceToGVK(sourceAPIResource))
+
+	targetName := fedResource.TargetName()
+	if err := s.hostClusterClient.Get(ctx, sourceObj, targetName.Namespace, targetName.Name); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return sourceObj, nil
+}
+
+// rollbackSourceObject replaces the template of the source object with the template of the revision, and records a
+// RolledBack condition and event. It returns false without updating the source object if its template is already
+// at the revision.
+func (s *SyncController) rollbackSourceObject(
+	ctx context.Context,
+	fedResource FederatedResource,
+	sourceObj *unstructured.Unstructured,
+	revision *appsv1.ControllerRevision,
+	reason, message string,
+) (bool, error) {
+	template, err := templateFromRevision(revision)
+	if err != nil {
+		return false, err
+	}
+
+	oldTemplate, _, err := unstructured.NestedMap(sourceObj.Object, common.SpecField, common.TemplateField)
+	if err != nil {
+		return false, err
+	}
+	if reflect.DeepEqual(oldTemplate, template) {
+		return false, nil
+	}
+
+	if err := unstructured.SetNestedMap(sourceObj.Object, template, common.SpecField, common.TemplateField); err != nil {
+		return false, err
+	}
+	if err := s.hostClusterClient.Update(ctx, sourceObj); err != nil {
+		return false, fmt.Errorf("failed to roll back source object: %w", err)
+	}
+
+	klog.FromContext(ctx).WithValues("revision", revision.Name, "reason", reason).Info("Rolled back source object")
+	fedResource.RecordEvent(EventReasonRolledBack, "%s", message)
+
+	if err := setRolledBackCondition(sourceObj, reason, message, time.Now()); err != nil {
+		return true, err
+	}
+	if err := s.hostClusterClient.UpdateStatus(ctx, sourceObj); err != nil {
+		return true, fmt.Errorf("failed to set %s condition of source object: %w", common.RolledBackConditionType, err)
+	}
+	return true, nil
+}
+
+// templateFromRevision returns the pod template that the patch of the revision reapplies.
+func templateFromRevision(revision *appsv1.ControllerRevision) (map[string]interface{}, error) {
+	patches := []struct {
+		Path  string                 `json:"path"`
+		Value map[string]interface{} `json:"value"`
+	}{}
+	// utiljson converts numbers to int64 where possible, so that the template is comparable with unstructured objects.
+	if err := utiljson.Unmarshal(revision.Data.Raw, &patches); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal revision %s: %w", revision.Name, err)
+	}
+
+	for _, patch := range patches {
+		if patch.Path == "/spec/template/spec/template" && patch.Value != nil {
+			return patch.Value, nil
+		}
+	}
+	return nil, fmt.Errorf("revision %s does not contain a template", revision.Name)
+}
+
+// setRolledBackCondition sets the RolledBack condition on the status of the source object.
+func setRolledBackCondition(sourceObj *unstructured.Unstructured, reason, message string, now time.Time) error {
+	conditions, _, err := unstructured.NestedSlice(sourceObj.Object, common.StatusField, common.ConditionsField)
+	if err != nil {
+		return err
+	}
+
+	condition := map[string]interface{}{
+		"type":               common.RolledBackConditionType,
+		"status":             string(corev1.ConditionTrue),
+		"reason":             reason,
+		"message":            message,
+		"lastTransitionTime": now.UTC().Format(time.RFC3339),
+	}
+	newConditions := make([]interface{}, 0, len(conditions)+1)
+	for _, existing := range conditions {
+		if existing, ok := existing.(map[string]interface{}); ok && existing["type"] == common.RolledBackConditionType {
+			continue
+		}
+		newConditions = append(newConditions, existing)
+	}
+	newConditions = append(newConditions, condition)
+
+	return unstructured.SetNestedSlice(sourceObj.Object, newConditions, common.StatusField, common.ConditionsField)
+}
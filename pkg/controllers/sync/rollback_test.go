@@ -0,0 +1,242 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+func TestAnalyzeRollout(t *testing.T) {
+	now := time.Now()
+	newDeployment := func(replicas, available int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "dp", "generation": int64(1)},
+			"spec":       map[string]interface{}{"replicas": replicas},
+			"status": map[string]interface{}{
+				"observedGeneration": int64(1),
+				"replicas":           replicas,
+				"updatedReplicas":    replicas,
+				"availableReplicas":  available,
+			},
+		}}
+	}
+	autoRollback := &fedcorev1a1.AutoRollback{
+		MinAvailablePercentage: pointer.Int32(50),
+		ProgressDeadline:       &metav1.Duration{Duration: time.Hour},
+	}
+	duration := func(d time.Duration) *time.Duration { return &d }
+
+	testCases := map[string]struct {
+		status                *stagedRolloutStatus
+		updatedClusterObjs    map[string]*unstructured.Unstructured
+		expectedFailureReason string
+		expectedRemaining     *time.Duration
+	}{
+		"healthy rollout waits for the deadline": {
+			status: &stagedRolloutStatus{
+				Revision:      "rev-2",
+				StartTime:     &metav1.Time{Time: now.Add(-10 * time.Minute)},
+				WaveStartTime: &metav1.Time{Time: now.Add(-10 * time.Minute)},
+			},
+			updatedClusterObjs: map[string]*unstructured.Unstructured{
+				"c1": newDeployment(4, 2),
+				"c2": newDeployment(2, 1),
+			},
+			expectedRemaining: duration(50 * time.Minute),
+		},
+		"insufficient available replicas": {
+			status: &stagedRolloutStatus{
+				Revision:      "rev-2",
+				StartTime:     &metav1.Time{Time: now.Add(-10 * time.Minute)},
+				WaveStartTime: &metav1.Time{Time: now.Add(-5 * time.Minute)},
+			},
+			updatedClusterObjs: map[string]*unstructured.Unstructured{
+				"c1": newDeployment(4, 1),
+				"c2": newDeployment(2, 1),
+			},
+			expectedFailureReason: RolledBackReasonInsufficientAvailableReplicas,
+		},
+		"available replicas are not analyzed during the analysis delay": {
+			status: &stagedRolloutStatus{
+				Revision:      "rev-2",
+				StartTime:     &metav1.Time{Time: now.Add(-10 * time.Minute)},
+				WaveStartTime: &metav1.Time{Time: now.Add(-time.Minute)},
+			},
+			updatedClusterObjs: map[string]*unstructured.Unstructured{
+				"c1": newDeployment(4, 0),
+			},
+			expectedRemaining: duration(4 * time.Minute),
+		},
+		"progress deadline exceeded": {
+			status:                &stagedRolloutStatus{Revision: "rev-2", StartTime: &metav1.Time{Time: now.Add(-time.Hour)}},
+			expectedFailureReason: RolledBackReasonProgressDeadlineExceeded,
+		},
+		"completed rollout is not analyzed": {
+			status:             &stagedRolloutStatus{Revision: "rev-2", Wave: 1, StartTime: &metav1.Time{Time: now.Add(-time.Hour)}},
+			updatedClusterObjs: map[string]*unstructured.Unstructured{"c1": newDeployment(4, 0)},
+		},
+		"rollout of the last good revision is not analyzed": {
+			status: &stagedRolloutStatus{
+				Revision:         "rev-2",
+				LastGoodRevision: "rev-2",
+				StartTime:        &metav1.Time{Time: now.Add(-time.Hour)},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			failure, remaining := analyzeRollout(autoRollback, tc.status, 1, tc.updatedClusterObjs, now)
+			if len(tc.expectedFailureReason) == 0 {
+				assert.Nil(t, failure)
+			} else if assert.NotNil(t, failure) {
+				assert.Equal(t, tc.expectedFailureReason, failure.Reason)
+			}
+			assert.Equal(t, tc.expectedRemaining, remaining)
+		})
+	}
+}
+
+func TestValidateAutoRollback(t *testing.T) {
+	assert.NoError(t, validateAutoRollback(nil))
+	assert.NoError(t, validateAutoRollback(&fedcorev1a1.AutoRollback{MinAvailablePercentage: pointer.Int32(50)}))
+	assert.NoError(t, validateAutoRollback(&fedcorev1a1.AutoRollback{ProgressDeadline: &metav1.Duration{Duration: time.Hour}}))
+	assert.Error(t, validateAutoRollback(&fedcorev1a1.AutoRollback{}))
+	assert.Error(t, validateAutoRollback(&fedcorev1a1.AutoRollback{AnalysisDelay: &metav1.Duration{Duration: time.Minute}}))
+}
+
+func TestGetAvailableReplicas(t *testing.T) {
+	statefulSet := &appsv1.StatefulSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: common.StatefulSetKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "sts", Generation: 2},
+		Spec:       appsv1.StatefulSetSpec{Replicas: pointer.Int32(3)},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 2,
+			Replicas:           3,
+			UpdatedReplicas:    2,
+			AvailableReplicas:  2,
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(statefulSet)
+	assert.NoError(t, err)
+
+	desired, available, ok := getAvailableReplicas(&unstructured.Unstructured{Object: obj})
+	assert.True(t, ok)
+	assert.Equal(t, int32(3), desired)
+	assert.Equal(t, int32(1), available, "the replica of the old revision is not counted")
+
+	statefulSet.Status.ObservedGeneration = 1
+	obj, err = runtime.DefaultUnstructuredConverter.ToUnstructured(statefulSet)
+	assert.NoError(t, err)
+	_, _, ok = getAvailableReplicas(&unstructured.Unstructured{Object: obj})
+	assert.False(t, ok, "status is out of date")
+
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: common.DeploymentKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "dp", Generation: 3},
+		Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(4)},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 3,
+			Replicas:           5,
+			UpdatedReplicas:    2,
+			AvailableReplicas:  4,
+		},
+	}
+	obj, err = runtime.DefaultUnstructuredConverter.ToUnstructured(deployment)
+	assert.NoError(t, err)
+	clusterObj := &unstructured.Unstructured{Object: obj}
+
+	desired, available, ok = getAvailableReplicas(clusterObj)
+	assert.True(t, ok)
+	assert.Equal(t, int32(4), desired)
+	assert.Equal(t, int32(1), available, "the replicas of the old revision are assumed to be available")
+
+	clusterObj.SetAnnotations(map[string]string{
+		util.LatestReplicasetObservedGenerationAnnotation: "3",
+		util.LatestReplicasetAvailableReplicasAnnotation:  "2",
+	})
+	_, available, ok = getAvailableReplicas(clusterObj)
+	assert.True(t, ok)
+	assert.Equal(t, int32(2), available, "the available replicas of the new replicaset are used")
+}
+
+func TestTemplateFromRevision(t *testing.T) {
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "dp-5d8f7c"},
+		Data: runtime.RawExtension{Raw: []byte(`[{"op":"replace","path":"/spec/template/spec/template",` +
+			`"value":{"spec":{"containers":[{"name":"server","image":"server:v1","ports":[{"containerPort":80}]}]}}}]`)},
+	}
+
+	template, err := templateFromRevision(revision)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name":  "server",
+					"image": "server:v1",
+					"ports": []interface{}{map[string]interface{}{"containerPort": int64(80)}},
+				},
+			},
+		},
+	}, template)
+
+	revision.Data.Raw = []byte(`[{"op":"replace","path":"/spec/replicas","value":3}]`)
+	_, err = templateFromRevision(revision)
+	assert.Error(t, err)
+}
+
+func TestSetRolledBackCondition(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	sourceObj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "True"},
+				map[string]interface{}{"type": common.RolledBackConditionType, "status": "True", "reason": "old"},
+			},
+		},
+	}}
+
+	assert.NoError(t, setRolledBackCondition(sourceObj, RolledBackReasonRequested, "rolled back", now))
+
+	conditions, _, err := unstructured.NestedSlice(sourceObj.Object, "status", "conditions")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "Available", "status": "True"},
+		map[string]interface{}{
+			"type":               common.RolledBackConditionType,
+			"status":             "True",
+			"reason":             RolledBackReasonRequested,
+			"message":            "rolled back",
+			"lastTransitionTime": "2023-06-01T00:00:00Z",
+		},
+	}, conditions)
+}
@@ -0,0 +1,417 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+const (
+	// StagedRolloutStatusAnnotation contains the JSON-encoded progress of the staged rollout of the current revision.
+	StagedRolloutStatusAnnotation = common.DefaultPrefix + "staged-rollout-status"
+
+	EventReasonRolloutWaveStarted     = "RolloutWaveStarted"
+	EventReasonStagedRolloutCompleted = "StagedRolloutCompleted"
+	EventReasonStagedRolloutDisabled  = "StagedRolloutDisabled"
+	EventReasonAutoRollbackDisabled   = "AutoRollbackDisabled"
+)
+
+// stagedRolloutStatus is the progress of a staged rollout.
+type stagedRolloutStatus struct {
+	// Revision is the revision being rolled out.
+	Revision string `json:"revision"`
+	// Wave is the index of the wave being rolled out. It is equal to the number of waves once the rollout completes.
+	Wave int `json:"wave"`
+	// WaveName is the name of the wave being rolled out.
+	WaveName string `json:"waveName,omitempty"`
+	// WaveStartTime is the time at which the rollout of the current wave was started.
+	WaveStartTime *metav1.Time `json:"waveStartTime,omitempty"`
+	// WaveAvailableTime is the time at which all clusters of the current wave became available.
+	WaveAvailableTime *metav1.Time `json:"waveAvailableTime,omitempty"`
+	// StartTime is the time at which the rollout of the revision was started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// LastGoodRevision is the last revision that was rolled out to all waves.
+	LastGoodRevision string `json:"lastGoodRevision,omitempty"`
+	// Failure is set if the rollout of the revision has failed. A failed rollout does not advance any further. It is
+	// cleared once a new revision is rolled out, either by the rollback or by a change to the template.
+	Failure *rolloutFailure `json:"failure,omitempty"`
+}
+
+type rolloutWave struct {
+	name     string
+	clusters sets.String
+}
+
+// stagedRollout is the result of planning the staged rollout of a federated object.
+type stagedRollout struct {
+	// heldClusters are the clusters in which the template should be kept at its previous revision.
+	heldClusters sets.String
+	// recheckAfter is set if the rollout should be checked again after the bake time of the current wave.
+	recheckAfter *time.Duration
+	// reason is the aggregate reason to report in the status of the federated object while the rollout has not
+	// completed, or AggregateSuccess once the revision is rolled out to all waves.
+	reason fedtypesv1a1.AggregateReason
+}
+
+// planStagedRollout advances the staged rollout of the current revision of the federated object and returns the
+// clusters that have not been reached yet. It returns nil if the object does not have a staged rollout strategy.
+func (s *SyncController) planStagedRollout(
+	ctx context.Context,
+	fedResource FederatedResource,
+	clusters []*fedcorev1a1.FederatedCluster,
+	selectedClusterNames sets.String,
+) (*stagedRollout, error) {
+	obj := fedResource.Object()
+	strategyValue, exists := obj.GetAnnotations()[common.RolloutStrategyAnnotation]
+	if !exists {
+		return nil, nil
+	}
+	strategy := &fedcorev1a1.RolloutStrategy{}
+	if err := json.Unmarshal([]byte(strategyValue), strategy); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rollout strategy: %w", err)
+	}
+
+	revision, exists := obj.GetAnnotations()[common.CurrentRevisionAnnotation]
+	if !exists {
+		fedResource.RecordError(
+			EventReasonStagedRolloutDisabled,
+			fmt.Errorf("staged rollouts and auto rollback require the revision history of %s to be enabled", s.typeConfig.Name),
+		)
+		return nil, nil
+	}
+
+	if err := validateAutoRollback(strategy.AutoRollback); err != nil {
+		fedResource.RecordError(EventReasonAutoRollbackDisabled, err)
+		strategy.AutoRollback = nil
+	}
+
+	oldStatus := &stagedRolloutStatus{}
+	if value, exists := obj.GetAnnotations()[StagedRolloutStatusAnnotation]; exists {
+		if err := json.Unmarshal([]byte(value), oldStatus); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal staged rollout status: %w", err)
+		}
+	}
+
+	waves := computeRolloutWaves(strategy, clusters, selectedClusterNames)
+	pullClusterNames := sets.NewString()
+	for _, cluster := range clusters {
+		if cluster.IsPullMode() {
+			pullClusterNames.Insert(cluster.Name)
+		}
+	}
+	isClusterUpdated := func(clusterName string) bool {
+		clusterObj := s.getRolloutClusterObject(ctx, fedResource, clusterName, pullClusterNames.Has(clusterName))
+		if clusterObj == nil {
+			return false
+		}
+		return clusterObj.GetAnnotations()[common.CurrentRevisionAnnotation] == revision &&
+			isClusterObjectAvailable(clusterObj)
+	}
+
+	var bakeTime time.Duration
+	if strategy.BakeTime != nil {
+		bakeTime = strategy.BakeTime.Duration
+	}
+	now := time.Now()
+	status, recheckAfter := advanceStagedRollout(oldStatus, revision, waves, isClusterUpdated, bakeTime, now)
+
+	if strategy.AutoRollback != nil && status.Failure == nil {
+		updatedClusterObjs := s.getUpdatedClusterObjects(ctx, fedResource, revision, waves, status.Wave, pullClusterNames)
+		var deadline *time.Duration
+		status.Failure, deadline = analyzeRollout(strategy.AutoRollback, status, len(waves), updatedClusterObjs, now)
+		if deadline != nil && (recheckAfter == nil || *deadline < *recheckAfter) {
+			recheckAfter = deadline
+		}
+	}
+
+	if err := s.updateStagedRolloutStatus(ctx, fedResource, status); err != nil {
+		return nil, err
+	}
+
+	if status.Failure != nil {
+		isNewFailure := oldStatus.Revision != status.Revision || oldStatus.Failure == nil
+		if err := s.rollbackFailedRollout(ctx, fedResource, status, isNewFailure); err != nil {
+			return nil, err
+		}
+	}
+
+	if status.Revision == oldStatus.Revision && status.Wave != oldStatus.Wave {
+		if status.Wave < len(waves) {
+			fedResource.RecordEvent(
+				EventReasonRolloutWaveStarted,
+				"Rolling out revision %s to wave %q with clusters %v",
+				revision,
+				status.WaveName,
+				waves[status.Wave].clusters.List(),
+			)
+		} else {
+			fedResource.RecordEvent(EventReasonStagedRolloutCompleted, "Rolled out revision %s to all waves", revision)
+		}
+	}
+
+	rollout := &stagedRollout{heldClusters: sets.NewString(), recheckAfter: recheckAfter}
+	switch {
+	case status.Failure != nil:
+		rollout.reason = fedtypesv1a1.StagedRolloutFailed
+	case status.Wave < len(waves):
+		rollout.reason = fedtypesv1a1.StagedRolloutInProgress
+	}
+	for i := status.Wave + 1; i < len(waves); i++ {
+		rollout.heldClusters = rollout.heldClusters.Union(waves[i].clusters)
+	}
+	klog.FromContext(ctx).WithValues("revision", revision, "wave", status.Wave, "held-clusters", rollout.heldClusters.List()).
+		V(3).Info("Planned staged rollout")
+	return rollout, nil
+}
+
+// getRolloutClusterObject returns the object in the cluster, or nil if it does not exist or cannot be retrieved. The
+// object in a cluster in Pull mode is the template of its PulledObject with the status reported by its agent.
+func (s *SyncController) getRolloutClusterObject(
+	ctx context.Context,
+	fedResource FederatedResource,
+	clusterName string,
+	isPullMode bool,
+) *unstructured.Unstructured {
+	if isPullMode {
+		pulledObject, err := s.getPulledObject(clusterName, fedResource.TargetName())
+		if err != nil || pulledObject == nil {
+			return nil
+		}
+		clusterObj, err := pulledClusterObject(pulledObject)
+		if err != nil {
+			return nil
+		}
+		return clusterObj
+	}
+
+	clusterObj, _, err := util.GetClusterObject(
+		ctx,
+		s.informer,
+		clusterName,
+		fedResource.TargetName(),
+		s.typeConfig.GetTargetType(),
+	)
+	if err != nil {
+		return nil
+	}
+	return clusterObj
+}
+
+func (s *SyncController) updateStagedRolloutStatus(
+	ctx context.Context,
+	fedResource FederatedResource,
+	status *stagedRolloutStatus,
+) error {
+	statusBytes, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal staged rollout status: %w", err)
+	}
+
+	obj := fedResource.Object()
+	if obj.GetAnnotations()[StagedRolloutStatusAnnotation] == string(statusBytes) {
+		return nil
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[StagedRolloutStatusAnnotation] = string(statusBytes)
+	obj.SetAnnotations(annotations)
+
+	return s.hostClusterClient.Update(ctx, obj)
+}
+
+// computeRolloutWaves returns the waves of the selected clusters in order. Clusters that are not selected by any wave
+// of the strategy form the last wave.
+func computeRolloutWaves(
+	strategy *fedcorev1a1.RolloutStrategy,
+	clusters []*fedcorev1a1.FederatedCluster,
+	selectedClusterNames sets.String,
+) []rolloutWave {
+	remaining := sets.NewString()
+	clusterLabels := make(map[string]labels.Set, len(clusters))
+	for _, cluster := range clusters {
+		if selectedClusterNames.Has(cluster.Name) {
+			remaining.Insert(cluster.Name)
+			clusterLabels[cluster.Name] = cluster.Labels
+		}
+	}
+
+	waves := make([]rolloutWave, 0, len(strategy.Waves)+1)
+	for i, wave := range strategy.Waves {
+		name := wave.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("wave-%d", i)
+		}
+
+		clusterNames := remaining.Intersection(sets.NewString(wave.Clusters...))
+		if len(wave.ClusterSelector) > 0 {
+			selector := labels.SelectorFromSet(wave.ClusterSelector)
+			for clusterName := range remaining {
+				if selector.Matches(clusterLabels[clusterName]) {
+					clusterNames.Insert(clusterName)
+				}
+			}
+		}
+
+		if clusterNames.Len() > 0 {
+			waves = append(waves, rolloutWave{name: name, clusters: clusterNames})
+			remaining = remaining.Difference(clusterNames)
+		}
+	}
+	if remaining.Len() > 0 {
+		waves = append(waves, rolloutWave{name: "remaining", clusters: remaining})
+	}
+
+	return waves
+}
+
+// advanceStagedRollout returns the progress of the staged rollout of the revision. The rollout moves on to the next
+// wave once all clusters of the current wave are updated and the bake time has passed since then. If the rollout is
+// waiting for the bake time, the remaining time is returned.
+func advanceStagedRollout(
+	oldStatus *stagedRolloutStatus,
+	revision string,
+	waves []rolloutWave,
+	isClusterUpdated func(clusterName string) bool,
+	bakeTime time.Duration,
+	now time.Time,
+) (*stagedRolloutStatus, *time.Duration) {
+	status := &stagedRolloutStatus{
+		Revision:         revision,
+		StartTime:        &metav1.Time{Time: now},
+		WaveStartTime:    &metav1.Time{Time: now},
+		LastGoodRevision: oldStatus.LastGoodRevision,
+	}
+	if oldStatus.Revision == revision {
+		if oldStatus.Failure != nil {
+			return oldStatus, nil
+		}
+		status.Wave = oldStatus.Wave
+		status.WaveAvailableTime = oldStatus.WaveAvailableTime
+		if oldStatus.StartTime != nil {
+			status.StartTime = oldStatus.StartTime
+		}
+		if oldStatus.WaveStartTime != nil {
+			status.WaveStartTime = oldStatus.WaveStartTime
+		}
+	}
+
+	var recheckAfter *time.Duration
+	for status.Wave < len(waves) {
+		wave := waves[status.Wave]
+
+		updated := true
+		for _, clusterName := range wave.clusters.List() {
+			if !isClusterUpdated(clusterName) {
+				updated = false
+				break
+			}
+		}
+		if !updated {
+			status.WaveAvailableTime = nil
+			break
+		}
+
+		if status.WaveAvailableTime == nil {
+			status.WaveAvailableTime = &metav1.Time{Time: now}
+		}
+		// There is no need to bake the last wave, since no wave follows it.
+		if status.Wave < len(waves)-1 {
+			if remaining := status.WaveAvailableTime.Add(bakeTime).Sub(now); remaining > 0 {
+				recheckAfter = &remaining
+				break
+			}
+		}
+
+		status.Wave++
+		status.WaveStartTime = &metav1.Time{Time: now}
+		status.WaveAvailableTime = nil
+	}
+
+	if status.Wave < len(waves) {
+		status.WaveName = waves[status.Wave].name
+	} else {
+		status.LastGoodRevision = revision
+	}
+	return status, recheckAfter
+}
+
+// isClusterObjectAvailable returns whether the controller in the cluster has observed the latest generation of the
+// object and, for workloads, whether all of its replicas are updated and available.
+func isClusterObjectAvailable(clusterObj *unstructured.Unstructured) bool {
+	observedGeneration, exists, err := unstructured.NestedInt64(clusterObj.Object, common.StatusField, "observedGeneration")
+	if err != nil || exists && observedGeneration < clusterObj.GetGeneration() {
+		return false
+	}
+
+	switch clusterObj.GroupVersionKind() {
+	case appsv1.SchemeGroupVersion.WithKind(common.DeploymentKind):
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(clusterObj.Object, deployment); err != nil {
+			return false
+		}
+		replicas := getReplicas(deployment.Spec.Replicas)
+		return exists &&
+			deployment.Status.UpdatedReplicas == replicas &&
+			deployment.Status.AvailableReplicas == replicas &&
+			deployment.Status.Replicas == replicas
+	case appsv1.SchemeGroupVersion.WithKind(common.StatefulSetKind):
+		statefulSet := &appsv1.StatefulSet{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(clusterObj.Object, statefulSet); err != nil {
+			return false
+		}
+		replicas := getReplicas(statefulSet.Spec.Replicas)
+		return exists &&
+			statefulSet.Status.UpdatedReplicas == replicas &&
+			statefulSet.Status.ReadyReplicas == replicas &&
+			statefulSet.Status.CurrentRevision == statefulSet.Status.UpdateRevision
+	case appsv1.SchemeGroupVersion.WithKind(common.DaemonSetKind):
+		daemonSet := &appsv1.DaemonSet{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(clusterObj.Object, daemonSet); err != nil {
+			return false
+		}
+		return exists &&
+			daemonSet.Status.UpdatedNumberScheduled == daemonSet.Status.DesiredNumberScheduled &&
+			daemonSet.Status.NumberAvailable == daemonSet.Status.DesiredNumberScheduled
+	default:
+		return true
+	}
+}
+
+func getReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
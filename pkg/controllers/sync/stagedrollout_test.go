@@ -0,0 +1,229 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+func newTestCluster(name string, labels map[string]string) *fedcorev1a1.FederatedCluster {
+	return &fedcorev1a1.FederatedCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestComputeRolloutWaves(t *testing.T) {
+	pullCluster := newTestCluster("pull", map[string]string{"canary": "true"})
+	pullCluster.Spec.Mode = fedcorev1a1.ClusterModePull
+	clusters := []*fedcorev1a1.FederatedCluster{
+		newTestCluster("c1", map[string]string{"canary": "true"}),
+		newTestCluster("c2", map[string]string{"region": "east"}),
+		newTestCluster("c3", map[string]string{"region": "east", "canary": "true"}),
+		newTestCluster("c4", nil),
+		newTestCluster("c5", nil),
+		pullCluster,
+	}
+
+	strategy := &fedcorev1a1.RolloutStrategy{
+		Waves: []fedcorev1a1.RolloutWave{
+			{Name: "canary", ClusterSelector: map[string]string{"canary": "true"}},
+			{Clusters: []string{"c3", "c4", "c6"}, ClusterSelector: map[string]string{"region": "east"}},
+			{Name: "empty", Clusters: []string{"c1"}},
+		},
+	}
+
+	waves := computeRolloutWaves(strategy, clusters, sets.NewString("c1", "c2", "c3", "c4", "c5", "pull"))
+	assert.Equal(t, []rolloutWave{
+		{name: "canary", clusters: sets.NewString("c1", "c3", "pull")},
+		{name: "wave-1", clusters: sets.NewString("c2", "c4")},
+		{name: "remaining", clusters: sets.NewString("c5")},
+	}, waves)
+
+	waves = computeRolloutWaves(strategy, clusters, sets.NewString("c2", "c5"))
+	assert.Equal(t, []rolloutWave{
+		{name: "wave-1", clusters: sets.NewString("c2")},
+		{name: "remaining", clusters: sets.NewString("c5")},
+	}, waves)
+}
+
+func TestAdvanceStagedRollout(t *testing.T) {
+	now := time.Now()
+	waves := []rolloutWave{
+		{name: "canary", clusters: sets.NewString("c1")},
+		{name: "remaining", clusters: sets.NewString("c2", "c3")},
+	}
+	duration := func(d time.Duration) *time.Duration { return &d }
+	startTime := &metav1.Time{Time: now.Add(-time.Hour)}
+
+	testCases := map[string]struct {
+		oldStatus            *stagedRolloutStatus
+		updatedClusters      sets.String
+		expectedStatus       *stagedRolloutStatus
+		expectedRecheckAfter *time.Duration
+	}{
+		"new revision starts at the first wave": {
+			oldStatus:       &stagedRolloutStatus{Revision: "rev-1", Wave: 2, StartTime: startTime, LastGoodRevision: "rev-1"},
+			updatedClusters: sets.NewString("c2", "c3"),
+			expectedStatus: &stagedRolloutStatus{
+				Revision:         "rev-2",
+				Wave:             0,
+				WaveName:         "canary",
+				StartTime:        &metav1.Time{Time: now},
+				WaveStartTime:    &metav1.Time{Time: now},
+				LastGoodRevision: "rev-1",
+			},
+		},
+		"wave waits for the bake time once available": {
+			oldStatus:       &stagedRolloutStatus{Revision: "rev-2", Wave: 0, StartTime: startTime, WaveStartTime: startTime},
+			updatedClusters: sets.NewString("c1"),
+			expectedStatus: &stagedRolloutStatus{
+				Revision:          "rev-2",
+				Wave:              0,
+				WaveName:          "canary",
+				WaveStartTime:     startTime,
+				WaveAvailableTime: &metav1.Time{Time: now},
+				StartTime:         startTime,
+			},
+			expectedRecheckAfter: duration(time.Minute),
+		},
+		"next wave starts after the bake time": {
+			oldStatus: &stagedRolloutStatus{
+				Revision:          "rev-2",
+				Wave:              0,
+				WaveAvailableTime: &metav1.Time{Time: now.Add(-time.Minute)},
+				StartTime:         startTime,
+				WaveStartTime:     startTime,
+			},
+			updatedClusters: sets.NewString("c1", "c2"),
+			expectedStatus: &stagedRolloutStatus{
+				Revision:      "rev-2",
+				Wave:          1,
+				WaveName:      "remaining",
+				StartTime:     startTime,
+				WaveStartTime: &metav1.Time{Time: now},
+			},
+		},
+		"bake time is reset if the wave becomes unavailable": {
+			oldStatus: &stagedRolloutStatus{
+				Revision:          "rev-2",
+				Wave:              0,
+				WaveAvailableTime: &metav1.Time{Time: now.Add(-time.Second * 30)},
+				StartTime:         startTime,
+				WaveStartTime:     startTime,
+			},
+			updatedClusters: sets.NewString(),
+			expectedStatus: &stagedRolloutStatus{
+				Revision:      "rev-2",
+				Wave:          0,
+				WaveName:      "canary",
+				StartTime:     startTime,
+				WaveStartTime: startTime,
+			},
+		},
+		"last wave completes without baking": {
+			oldStatus:       &stagedRolloutStatus{Revision: "rev-2", Wave: 1, StartTime: startTime, LastGoodRevision: "rev-1"},
+			updatedClusters: sets.NewString("c1", "c2", "c3"),
+			expectedStatus: &stagedRolloutStatus{
+				Revision:         "rev-2",
+				Wave:             2,
+				StartTime:        startTime,
+				WaveStartTime:    &metav1.Time{Time: now},
+				LastGoodRevision: "rev-2",
+			},
+		},
+		"failed rollout does not advance": {
+			oldStatus: &stagedRolloutStatus{
+				Revision:  "rev-2",
+				Wave:      0,
+				WaveName:  "canary",
+				StartTime: startTime,
+				Failure:   &rolloutFailure{Reason: RolledBackReasonProgressDeadlineExceeded},
+			},
+			updatedClusters: sets.NewString("c1", "c2", "c3"),
+			expectedStatus: &stagedRolloutStatus{
+				Revision:  "rev-2",
+				Wave:      0,
+				WaveName:  "canary",
+				StartTime: startTime,
+				Failure:   &rolloutFailure{Reason: RolledBackReasonProgressDeadlineExceeded},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			status, recheckAfter := advanceStagedRollout(
+				tc.oldStatus,
+				"rev-2",
+				waves,
+				tc.updatedClusters.Has,
+				time.Minute,
+				now,
+			)
+			assert.Equal(t, tc.expectedStatus, status)
+			assert.Equal(t, tc.expectedRecheckAfter, recheckAfter)
+		})
+	}
+}
+
+func TestIsClusterObjectAvailable(t *testing.T) {
+	newDeployment := func(generation, observedGeneration, replicas, updated, available, total int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "dp", "generation": generation},
+			"spec":       map[string]interface{}{"replicas": replicas},
+			"status": map[string]interface{}{
+				"observedGeneration": observedGeneration,
+				"replicas":           total,
+				"updatedReplicas":    updated,
+				"availableReplicas":  available,
+			},
+		}}
+	}
+
+	assert.True(t, isClusterObjectAvailable(newDeployment(2, 2, 3, 3, 3, 3)))
+	assert.False(t, isClusterObjectAvailable(newDeployment(2, 1, 3, 3, 3, 3)), "latest generation is not observed")
+	assert.False(t, isClusterObjectAvailable(newDeployment(2, 2, 3, 2, 3, 3)), "not all replicas are updated")
+	assert.False(t, isClusterObjectAvailable(newDeployment(2, 2, 3, 3, 3, 4)), "old replicas are not scaled down")
+
+	daemonSet := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "DaemonSet",
+		"metadata":   map[string]interface{}{"name": "ds", "generation": int64(1)},
+		"status": map[string]interface{}{
+			"observedGeneration":     int64(1),
+			"desiredNumberScheduled": int64(5),
+			"updatedNumberScheduled": int64(5),
+			"numberAvailable":        int64(4),
+		},
+	}}
+	assert.False(t, isClusterObjectAvailable(daemonSet))
+
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "cm"},
+	}}
+	assert.True(t, isClusterObjectAvailable(configMap))
+}
@@ -40,6 +40,7 @@ type PropagationStatusMap map[string]fedtypesv1a1.PropagationStatus
 type CollectedPropagationStatus struct {
 	StatusMap        PropagationStatusMap
 	GenerationMap    map[string]int64
+	DriftedFieldsMap map[string][]string
 	ResourcesUpdated bool
 }
 
@@ -102,9 +103,14 @@ func update(
 	}
 
 	// Identify whether one or more clusters could not be reconciled
-	// successfully.
-	if reason == fedtypesv1a1.AggregateSuccess {
+	// successfully. Clusters waiting for their rollout wave have not
+	// failed, but the propagation is not complete until they are updated.
+	if reason == fedtypesv1a1.AggregateSuccess || reason == fedtypesv1a1.StagedRolloutInProgress {
 		for _, value := range collectedStatus.StatusMap {
+			if value == fedtypesv1a1.WaitingForRolloutWave {
+				reason = fedtypesv1a1.StagedRolloutInProgress
+				continue
+			}
 			if value != fedtypesv1a1.ClusterPropagationOK {
 				reason = fedtypesv1a1.CheckClusters
 				break
@@ -112,7 +118,12 @@ func update(
 		}
 	}
 
-	clustersChanged := setClusters(s, collectedStatus.StatusMap, collectedStatus.GenerationMap)
+	clustersChanged := setClusters(
+		s,
+		collectedStatus.StatusMap,
+		collectedStatus.GenerationMap,
+		collectedStatus.DriftedFieldsMap,
+	)
 
 	// Indicate that changes were propagated if either status.clusters
 	// was changed or if existing resources were updated (which could
@@ -126,14 +137,15 @@ func update(
 }
 
 // setClusters sets the status.clusters slice from a propagation status
-// map and generation map. Returns a boolean indication of whether the
-// status.clusters was modified.
+// map, generation map and drifted fields map. Returns a boolean indication
+// of whether the status.clusters was modified.
 func setClusters(
 	s *fedtypesv1a1.GenericFederatedStatus,
 	statusMap PropagationStatusMap,
 	generationMap map[string]int64,
+	driftedFieldsMap map[string][]string,
 ) bool {
-	if !clustersDiffers(s, statusMap, generationMap) {
+	if !clustersDiffers(s, statusMap, generationMap, driftedFieldsMap) {
 		return false
 	}
 	s.Clusters = []fedtypesv1a1.GenericClusterStatus{}
@@ -146,20 +158,22 @@ func setClusters(
 	for _, clusterName := range clusterNames {
 		status := statusMap[clusterName]
 		s.Clusters = append(s.Clusters, fedtypesv1a1.GenericClusterStatus{
-			Name:       clusterName,
-			Status:     status,
-			Generation: generationMap[clusterName],
+			Name:          clusterName,
+			Status:        status,
+			Generation:    generationMap[clusterName],
+			DriftedFields: driftedFieldsMap[clusterName],
 		})
 	}
 	return true
 }
 
 // clustersDiffers checks whether `status.clusters` differs from the
-// given status map and generation map.
+// given status map, generation map and drifted fields map.
 func clustersDiffers(
 	s *fedtypesv1a1.GenericFederatedStatus,
 	statusMap PropagationStatusMap,
 	generationMap map[string]int64,
+	driftedFieldsMap map[string][]string,
 ) bool {
 	if len(s.Clusters) != len(statusMap) {
 		return true
@@ -174,6 +188,9 @@ func clustersDiffers(
 		if generationMap[status.Name] != status.Generation {
 			return true
 		}
+		if !reflect.DeepEqual(driftedFieldsMap[status.Name], status.DriftedFields) {
+			return true
+		}
 	}
 	return false
 }
@@ -34,6 +34,7 @@ func TestGenericPropagationStatusUpdateChanged(t *testing.T) {
 		collisionCount   *int32
 		reason           fedtypesv1a1.AggregateReason
 		statusMap        PropagationStatusMap
+		driftedFieldsMap map[string][]string
 		resourcesUpdated bool
 		expectedChanged  bool
 	}{
@@ -49,6 +50,15 @@ func TestGenericPropagationStatusUpdateChanged(t *testing.T) {
 			resourcesUpdated: true,
 			expectedChanged:  true,
 		},
+		"Change in drifted fields indicates changed": {
+			statusMap: PropagationStatusMap{
+				"cluster1": fedtypesv1a1.Drifted,
+			},
+			driftedFieldsMap: map[string][]string{
+				"cluster1": {"/spec/replicas"},
+			},
+			expectedChanged: true,
+		},
 		"Change in clusters indicates changed": {
 			expectedChanged: true,
 		},
@@ -79,6 +89,7 @@ func TestGenericPropagationStatusUpdateChanged(t *testing.T) {
 			}
 			collectedStatus := CollectedPropagationStatus{
 				StatusMap:        tc.statusMap,
+				DriftedFieldsMap: tc.driftedFieldsMap,
 				ResourcesUpdated: tc.resourcesUpdated,
 				GenerationMap:    map[string]int64{"cluster1": tc.generation},
 			}
@@ -89,3 +100,55 @@ func TestGenericPropagationStatusUpdateChanged(t *testing.T) {
 		})
 	}
 }
+
+func TestStagedRolloutPropagationCondition(t *testing.T) {
+	testCases := map[string]struct {
+		reason         fedtypesv1a1.AggregateReason
+		statusMap      PropagationStatusMap
+		expectedReason fedtypesv1a1.AggregateReason
+	}{
+		"Clusters waiting for their wave indicate rollout in progress": {
+			statusMap: PropagationStatusMap{
+				"cluster1": fedtypesv1a1.ClusterPropagationOK,
+				"cluster2": fedtypesv1a1.WaitingForRolloutWave,
+			},
+			expectedReason: fedtypesv1a1.StagedRolloutInProgress,
+		},
+		"Incomplete final wave indicates rollout in progress": {
+			reason: fedtypesv1a1.StagedRolloutInProgress,
+			statusMap: PropagationStatusMap{
+				"cluster1": fedtypesv1a1.ClusterPropagationOK,
+			},
+			expectedReason: fedtypesv1a1.StagedRolloutInProgress,
+		},
+		"Failed clusters take precedence over rollout in progress": {
+			reason: fedtypesv1a1.StagedRolloutInProgress,
+			statusMap: PropagationStatusMap{
+				"cluster1": fedtypesv1a1.UpdateFailed,
+				"cluster2": fedtypesv1a1.WaitingForRolloutWave,
+			},
+			expectedReason: fedtypesv1a1.CheckClusters,
+		},
+		"Failed rollout is reported": {
+			reason: fedtypesv1a1.StagedRolloutFailed,
+			statusMap: PropagationStatusMap{
+				"cluster1": fedtypesv1a1.WaitingForRolloutWave,
+			},
+			expectedReason: fedtypesv1a1.StagedRolloutFailed,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			propStatus := &fedtypesv1a1.GenericFederatedStatus{}
+			update(propStatus, 1, nil, tc.reason, CollectedPropagationStatus{StatusMap: tc.statusMap})
+			if len(propStatus.Conditions) != 1 {
+				t.Fatalf("Expected 1 condition, got %d", len(propStatus.Conditions))
+			}
+			condition := propStatus.Conditions[0]
+			if condition.Reason != tc.expectedReason || condition.Status != corev1.ConditionFalse {
+				t.Fatalf("Expected condition False with reason %q, got %s with reason %q",
+					tc.expectedReason, condition.Status, condition.Reason)
+			}
+		})
+	}
+}
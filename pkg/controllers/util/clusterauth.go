@@ -0,0 +1,448 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	restclient "k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+// OpenID Connect keys
+const (
+	OIDCClientSecretKey = "oidc-client-secret"
+	OIDCRefreshTokenKey = "oidc-refresh-token"
+	OIDCIDTokenKey      = "oidc-id-token"
+	OIDCIssuerCAKey     = "oidc-issuer-certificate-authority-data"
+)
+
+// ClusterAuthConfig configures the use of the alternative credentials configured in the auth of clusters.
+type ClusterAuthConfig struct {
+	// AllowedExecCommands are the commands that the exec plugins of clusters may run. Since the exec plugin is read
+	// from the FederatedCluster and run by the controller manager, exec plugins are rejected unless their command is
+	// listed and their args and env are allowed for the command.
+	AllowedExecCommands []AllowedExecCommand
+	// SecretsClient writes the refresh tokens rotated by OIDC issuers back to the cluster secrets. The rotated refresh
+	// tokens are only kept in memory if it is nil.
+	SecretsClient corev1client.SecretsGetter
+}
+
+// AllowedExecCommand allows the exec plugins of clusters to run a command.
+type AllowedExecCommand struct {
+	// Command is the command, which the command of an exec plugin must match exactly.
+	Command string `json:"command"`
+	// Args are regular expressions which the args of an exec plugin must fully match, one for each arg. Exec plugins
+	// with a different number of args are rejected.
+	Args []string `json:"args,omitempty"`
+	// Env are the names of the environment variables that an exec plugin may set. Exec plugins that set other
+	// environment variables are rejected, since variables such as LD_PRELOAD, BASH_ENV or NODE_OPTIONS make the
+	// command load other code.
+	Env []string `json:"env,omitempty"`
+}
+
+// LoadAllowedExecCommands reads the commands that the exec plugins of clusters may run from a YAML or JSON file
+// containing a list of AllowedExecCommand under the commands key.
+func LoadAllowedExecCommands(path string) ([]AllowedExecCommand, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allowed exec commands: %w", err)
+	}
+
+	var policy struct {
+		Commands []AllowedExecCommand `json:"commands"`
+	}
+	if err := yaml.UnmarshalStrict(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse allowed exec commands: %w", err)
+	}
+
+	commands := sets.NewString()
+	for _, command := range policy.Commands {
+		if len(command.Command) == 0 {
+			return nil, fmt.Errorf("allowed exec command must not be empty")
+		}
+		if commands.Has(command.Command) {
+			return nil, fmt.Errorf("exec command %q is allowed more than once", command.Command)
+		}
+		commands.Insert(command.Command)
+		for _, pattern := range command.Args {
+			if _, err := compileExecArgPattern(pattern); err != nil {
+				return nil, fmt.Errorf("invalid arg pattern of exec command %q: %w", command.Command, err)
+			}
+		}
+	}
+	return policy.Commands, nil
+}
+
+func compileExecArgPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// PopulateClusterConfig populates the proxy and the credentials of the cluster in clusterConfig. The credentials are
+// the service account token in the cluster secret if useServiceAccount is true, and otherwise the alternative
+// credentials configured in the cluster's spec or the client certificate in the cluster secret. The alternative
+// credentials are restricted by authConfig, which may be nil.
+func PopulateClusterConfig(
+	clusterConfig *restclient.Config,
+	cluster *fedcorev1a1.FederatedCluster,
+	secret *corev1.Secret,
+	useServiceAccount bool,
+	authConfig *ClusterAuthConfig,
+) error {
+	if len(cluster.Spec.ProxyURL) > 0 {
+		proxyURL, err := url.Parse(cluster.Spec.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("failed to parse proxy url: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("unsupported proxy url scheme %q", proxyURL.Scheme)
+		}
+		clusterConfig.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	auth := cluster.Spec.Auth
+	if useServiceAccount || auth == nil {
+		return PopulateAuthDetailsFromSecret(clusterConfig, cluster.Spec.Insecure, secret, useServiceAccount)
+	}
+
+	if cluster.Spec.Insecure {
+		clusterConfig.Insecure = true
+	} else {
+		var exists bool
+		clusterConfig.CAData, exists = secret.Data[CertificateAuthorityKey]
+		if !exists {
+			return fmt.Errorf("%q data is missing from secret and insecure is false", CertificateAuthorityKey)
+		}
+	}
+
+	switch {
+	case auth.Exec != nil:
+		if err := validateExecAuth(auth.Exec, authConfig); err != nil {
+			return err
+		}
+		clusterConfig.ExecProvider = newExecConfig(auth.Exec)
+	case auth.OIDC != nil:
+		var secretsClient corev1client.SecretsGetter
+		if authConfig != nil {
+			secretsClient = authConfig.SecretsClient
+		}
+		tokenSource, err := getOIDCTokenSource(cluster.Name, auth.OIDC, secret, secretsClient)
+		if err != nil {
+			return err
+		}
+		clusterConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &oauth2.Transport{Source: tokenSource, Base: rt}
+		})
+	default:
+		return fmt.Errorf("no credentials are configured in the auth of the cluster")
+	}
+
+	return nil
+}
+
+// validateExecAuth rejects exec plugins whose command is not allowed, or whose args or env are not allowed for the
+// command.
+func validateExecAuth(exec *fedcorev1a1.ClusterExecAuth, authConfig *ClusterAuthConfig) error {
+	var allowed *AllowedExecCommand
+	if authConfig != nil {
+		for i := range authConfig.AllowedExecCommands {
+			if authConfig.AllowedExecCommands[i].Command == exec.Command {
+				allowed = &authConfig.AllowedExecCommands[i]
+				break
+			}
+		}
+	}
+	if allowed == nil {
+		return fmt.Errorf("exec command %q is not allowed by the controller manager", exec.Command)
+	}
+
+	allowedEnv := sets.NewString(allowed.Env...)
+	for _, env := range exec.Env {
+		if !allowedEnv.Has(env.Name) {
+			return fmt.Errorf("exec env %q is not allowed for command %q", env.Name, exec.Command)
+		}
+	}
+
+	if len(exec.Args) != len(allowed.Args) {
+		return fmt.Errorf("exec command %q must have %d args, got %d", exec.Command, len(allowed.Args), len(exec.Args))
+	}
+	for i, arg := range exec.Args {
+		pattern, err := compileExecArgPattern(allowed.Args[i])
+		if err != nil {
+			return fmt.Errorf("invalid arg pattern of exec command %q: %w", exec.Command, err)
+		}
+		if !pattern.MatchString(arg) {
+			return fmt.Errorf("exec arg %q of command %q is not allowed", arg, exec.Command)
+		}
+	}
+	return nil
+}
+
+func newExecConfig(exec *fedcorev1a1.ClusterExecAuth) *clientcmdapi.ExecConfig {
+	config := &clientcmdapi.ExecConfig{
+		APIVersion:         exec.APIVersion,
+		Command:            exec.Command,
+		Args:               exec.Args,
+		ProvideClusterInfo: exec.ProvideClusterInfo,
+		// the controller manager cannot interact with a user
+		InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+	}
+	for _, env := range exec.Env {
+		config.Env = append(config.Env, clientcmdapi.ExecEnvVar{Name: env.Name, Value: env.Value})
+	}
+	return config
+}
+
+type oidcTokenSourceKey struct {
+	issuerURL    string
+	clientID     string
+	clientSecret string
+	refreshToken string
+	issuerCA     string
+}
+
+type cachedOIDCTokenSource struct {
+	key    oidcTokenSourceKey
+	source oauth2.TokenSource
+}
+
+// oidcTokenSources caches the token sources of clusters by cluster name, so that the clients of a cluster share its
+// ID token and refresh token. The token source of a cluster is replaced when the credentials in the cluster secret
+// change, and removed by ForgetClusterAuth when the cluster is deleted.
+var oidcTokenSources = struct {
+	sync.Mutex
+	sources map[string]cachedOIDCTokenSource
+}{sources: map[string]cachedOIDCTokenSource{}}
+
+// ForgetClusterAuth releases the cached credentials of a deleted cluster.
+func ForgetClusterAuth(clusterName string) {
+	oidcTokenSources.Lock()
+	defer oidcTokenSources.Unlock()
+	delete(oidcTokenSources.sources, clusterName)
+}
+
+func getOIDCTokenSource(
+	clusterName string,
+	oidc *fedcorev1a1.ClusterOIDCAuth,
+	secret *corev1.Secret,
+	secretsClient corev1client.SecretsGetter,
+) (oauth2.TokenSource, error) {
+	refreshToken := secret.Data[OIDCRefreshTokenKey]
+	if len(refreshToken) == 0 {
+		return nil, fmt.Errorf("%q data is missing from secret", OIDCRefreshTokenKey)
+	}
+
+	key := oidcTokenSourceKey{
+		issuerURL:    oidc.IssuerURL,
+		clientID:     oidc.ClientID,
+		clientSecret: string(secret.Data[OIDCClientSecretKey]),
+		refreshToken: string(refreshToken),
+		issuerCA:     string(secret.Data[OIDCIssuerCAKey]),
+	}
+
+	oidcTokenSources.Lock()
+	defer oidcTokenSources.Unlock()
+
+	if cached, ok := oidcTokenSources.sources[clusterName]; ok && cached.key == key {
+		return cached.source, nil
+	}
+
+	transport, err := restclient.TransportFor(&restclient.Config{
+		TLSClientConfig: restclient.TLSClientConfig{CAData: secret.Data[OIDCIssuerCAKey]},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transport for oidc issuer: %w", err)
+	}
+
+	// the initial ID token is used until it expires
+	var initialToken *oauth2.Token
+	if idToken := string(secret.Data[OIDCIDTokenKey]); len(idToken) > 0 {
+		if claims, ok := ParseTokenClaims([]byte(idToken)); ok && claims.Expiry != nil {
+			initialToken = &oauth2.Token{AccessToken: idToken, TokenType: "Bearer", Expiry: time.Unix(*claims.Expiry, 0)}
+		}
+	}
+
+	tokenSource := &oidcTokenSource{
+		client:       &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		issuerURL:    key.issuerURL,
+		clientID:     key.clientID,
+		clientSecret: key.clientSecret,
+		refreshToken: key.refreshToken,
+	}
+	if secretsClient != nil && len(secret.Name) > 0 {
+		tokenSource.secretsClient = secretsClient.Secrets(secret.Namespace)
+		tokenSource.secretName = secret.Name
+	}
+	source := oauth2.ReuseTokenSource(initialToken, tokenSource)
+	oidcTokenSources.sources[clusterName] = cachedOIDCTokenSource{key: key, source: source}
+	return source, nil
+}
+
+// oidcTokenSource returns ID tokens obtained with a refresh token. It must be wrapped with oauth2.ReuseTokenSource,
+// which serializes the calls to Token.
+type oidcTokenSource struct {
+	client       *http.Client
+	issuerURL    string
+	clientID     string
+	clientSecret string
+	// refreshToken is replaced if the issuer rotates the refresh token.
+	refreshToken string
+	tokenURL     string
+
+	// secretsClient and secretName identify the cluster secret that rotated refresh tokens are written back to.
+	secretsClient corev1client.SecretInterface
+	secretName    string
+}
+
+func (s *oidcTokenSource) Token() (*oauth2.Token, error) {
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, s.client)
+
+	if len(s.tokenURL) == 0 {
+		tokenURL, err := discoverOIDCTokenURL(ctx, s.client, s.issuerURL)
+		if err != nil {
+			return nil, err
+		}
+		s.tokenURL = tokenURL
+	}
+
+	config := oauth2.Config{
+		ClientID:     s.clientID,
+		ClientSecret: s.clientSecret,
+		Endpoint:     oauth2.Endpoint{TokenURL: s.tokenURL},
+	}
+	token, err := config.TokenSource(ctx, &oauth2.Token{RefreshToken: s.refreshToken}).Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh oidc token: %w", err)
+	}
+
+	idToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("oidc token response does not contain an id_token")
+	}
+	if len(token.RefreshToken) > 0 && token.RefreshToken != s.refreshToken {
+		s.refreshToken = token.RefreshToken
+		// The previous refresh token may no longer be valid, so the rotated refresh token is persisted to survive
+		// restarts. Failures are only logged since the ID token is usable anyway.
+		if err := s.persistRefreshToken(ctx, idToken); err != nil {
+			klog.ErrorS(err, "Failed to write rotated oidc refresh token to cluster secret", "secret", s.secretName)
+		}
+	}
+
+	claims, ok := ParseTokenClaims([]byte(idToken))
+	if !ok || claims.Expiry == nil {
+		return nil, fmt.Errorf("oidc id_token does not have an expiry")
+	}
+	return &oauth2.Token{AccessToken: idToken, TokenType: "Bearer", Expiry: time.Unix(*claims.Expiry, 0)}, nil
+}
+
+// persistRefreshToken writes the current refresh token and the given ID token to the cluster secret.
+func (s *oidcTokenSource) persistRefreshToken(ctx context.Context, idToken string) error {
+	if s.secretsClient == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := s.secretsClient.Get(ctx, s.secretName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[OIDCRefreshTokenKey] = []byte(s.refreshToken)
+		secret.Data[OIDCIDTokenKey] = []byte(idToken)
+		_, err = s.secretsClient.Update(ctx, secret, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func discoverOIDCTokenURL(ctx context.Context, client *http.Client, issuerURL string) (string, error) {
+	discoveryURL := strings.TrimSuffix(issuerURL, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get oidc discovery document: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get oidc discovery document: %s", resp.Status)
+	}
+
+	var metadata struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return "", fmt.Errorf("failed to decode oidc discovery document: %w", err)
+	}
+	if len(metadata.TokenEndpoint) == 0 {
+		return "", fmt.Errorf("oidc discovery document does not contain a token_endpoint")
+	}
+	return metadata.TokenEndpoint, nil
+}
+
+// TokenClaims are the claims of a JWT bearer token that are relevant to its expiration.
+type TokenClaims struct {
+	IssuedAt *int64 `json:"iat,omitempty"`
+	Expiry   *int64 `json:"exp,omitempty"`
+}
+
+// ParseTokenClaims returns the claims of a JWT bearer token without verifying it. It returns false if the token is
+// not a JWT.
+func ParseTokenClaims(token []byte) (*TokenClaims, bool) {
+	parts := strings.Split(string(token), ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, false
+	}
+
+	claims := &TokenClaims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, false
+	}
+	return claims, true
+}
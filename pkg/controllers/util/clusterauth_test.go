@@ -0,0 +1,392 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+func newTestIDToken(expiry time.Time) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	claims := fmt.Sprintf(`{"iss":"https://issuer.example.com","exp":%d}`, expiry.Unix())
+	return encode([]byte(`{"alg":"RS256"}`)) + "." + encode([]byte(claims)) + ".signature"
+}
+
+func TestPopulateClusterConfig(t *testing.T) {
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			CertificateAuthorityKey: []byte("ca"),
+			ClientCertificateKey:    []byte("cert"),
+			ClientKeyKey:            []byte("key"),
+			ServiceAccountTokenKey:  []byte("token"),
+			ServiceAccountCAKey:     []byte("sa-ca"),
+		},
+	}
+	exec := &fedcorev1a1.ClusterExecAuth{
+		APIVersion: "client.authentication.k8s.io/v1",
+		Command:    "aws",
+		Args:       []string{"eks", "get-token", "--cluster-name", "member"},
+		Env:        []fedcorev1a1.ExecEnvVar{{Name: "AWS_PROFILE", Value: "federation"}},
+	}
+
+	testCases := []struct {
+		name              string
+		spec              fedcorev1a1.FederatedClusterSpec
+		useServiceAccount bool
+		authConfig        *ClusterAuthConfig
+		isErrorExpected   bool
+		expectedConfig    *restclient.Config
+		expectedProxy     string
+	}{
+		{
+			name: "client certificate",
+			expectedConfig: &restclient.Config{
+				TLSClientConfig: restclient.TLSClientConfig{
+					CAData:   []byte("ca"),
+					CertData: []byte("cert"),
+					KeyData:  []byte("key"),
+				},
+			},
+		},
+		{
+			name:              "service account token takes precedence over auth",
+			spec:              fedcorev1a1.FederatedClusterSpec{Auth: &fedcorev1a1.ClusterAuth{Exec: exec}},
+			useServiceAccount: true,
+			expectedConfig: &restclient.Config{
+				BearerToken:     "token",
+				TLSClientConfig: restclient.TLSClientConfig{CAData: []byte("sa-ca")},
+			},
+		},
+		{
+			name: "exec plugin",
+			spec: fedcorev1a1.FederatedClusterSpec{Auth: &fedcorev1a1.ClusterAuth{Exec: exec}},
+			expectedConfig: &restclient.Config{
+				TLSClientConfig: restclient.TLSClientConfig{CAData: []byte("ca")},
+				ExecProvider: &clientcmdapi.ExecConfig{
+					APIVersion:      "client.authentication.k8s.io/v1",
+					Command:         "aws",
+					Args:            []string{"eks", "get-token", "--cluster-name", "member"},
+					Env:             []clientcmdapi.ExecEnvVar{{Name: "AWS_PROFILE", Value: "federation"}},
+					InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+				},
+			},
+		},
+		{
+			name:            "exec plugin without allowed commands",
+			spec:            fedcorev1a1.FederatedClusterSpec{Auth: &fedcorev1a1.ClusterAuth{Exec: exec}},
+			authConfig:      &ClusterAuthConfig{},
+			isErrorExpected: true,
+		},
+		{
+			name: "exec plugin with command not allowed",
+			spec: fedcorev1a1.FederatedClusterSpec{
+				Auth: &fedcorev1a1.ClusterAuth{Exec: &fedcorev1a1.ClusterExecAuth{Command: "/bin/sh"}},
+			},
+			isErrorExpected: true,
+		},
+		{
+			name: "exec plugin with env not allowed",
+			spec: fedcorev1a1.FederatedClusterSpec{
+				Auth: &fedcorev1a1.ClusterAuth{Exec: &fedcorev1a1.ClusterExecAuth{
+					Command: "aws",
+					Args:    []string{"eks", "get-token", "--cluster-name", "member"},
+					Env:     []fedcorev1a1.ExecEnvVar{{Name: "BASH_ENV", Value: "/tmp/script.sh"}},
+				}},
+			},
+			isErrorExpected: true,
+		},
+		{
+			name: "exec plugin with arg not allowed",
+			spec: fedcorev1a1.FederatedClusterSpec{
+				Auth: &fedcorev1a1.ClusterAuth{Exec: &fedcorev1a1.ClusterExecAuth{
+					Command: "aws",
+					Args:    []string{"eks", "get-token", "--cluster-name", "member --profile other"},
+				}},
+			},
+			isErrorExpected: true,
+		},
+		{
+			name: "exec plugin with additional args",
+			spec: fedcorev1a1.FederatedClusterSpec{
+				Auth: &fedcorev1a1.ClusterAuth{Exec: &fedcorev1a1.ClusterExecAuth{
+					Command: "aws",
+					Args:    []string{"eks", "get-token", "--cluster-name", "member", "--debug"},
+				}},
+			},
+			isErrorExpected: true,
+		},
+		{
+			name: "insecure exec plugin",
+			spec: fedcorev1a1.FederatedClusterSpec{
+				Insecure: true,
+				Auth:     &fedcorev1a1.ClusterAuth{Exec: &fedcorev1a1.ClusterExecAuth{Command: "get-token"}},
+			},
+			expectedConfig: &restclient.Config{
+				TLSClientConfig: restclient.TLSClientConfig{Insecure: true},
+				ExecProvider: &clientcmdapi.ExecConfig{
+					Command:         "get-token",
+					InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+				},
+			},
+		},
+		{
+			name: "oidc without refresh token",
+			spec: fedcorev1a1.FederatedClusterSpec{
+				Auth: &fedcorev1a1.ClusterAuth{
+					OIDC: &fedcorev1a1.ClusterOIDCAuth{IssuerURL: "https://issuer.example.com", ClientID: "kubeadmiral"},
+				},
+			},
+			isErrorExpected: true,
+		},
+		{
+			name: "socks5 proxy",
+			spec: fedcorev1a1.FederatedClusterSpec{ProxyURL: "socks5://proxy.example.com:1080"},
+			expectedConfig: &restclient.Config{
+				TLSClientConfig: restclient.TLSClientConfig{
+					CAData:   []byte("ca"),
+					CertData: []byte("cert"),
+					KeyData:  []byte("key"),
+				},
+			},
+			expectedProxy: "socks5://proxy.example.com:1080",
+		},
+		{
+			name:            "unsupported proxy scheme",
+			spec:            fedcorev1a1.FederatedClusterSpec{ProxyURL: "ftp://proxy.example.com"},
+			isErrorExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &fedcorev1a1.FederatedCluster{Spec: tc.spec}
+			authConfig := tc.authConfig
+			if authConfig == nil {
+				authConfig = &ClusterAuthConfig{AllowedExecCommands: []AllowedExecCommand{
+					{
+						Command: "aws",
+						Args:    []string{"eks", "get-token", "--cluster-name", "[a-z0-9-]+"},
+						Env:     []string{"AWS_PROFILE"},
+					},
+					{Command: "get-token"},
+				}}
+			}
+			config := &restclient.Config{}
+			err := PopulateClusterConfig(config, cluster, secret, tc.useServiceAccount, authConfig)
+			if tc.isErrorExpected {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			if len(tc.expectedProxy) > 0 {
+				proxyURL, err := config.Proxy(&http.Request{})
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedProxy, proxyURL.String())
+			} else {
+				assert.Nil(t, config.Proxy)
+			}
+			config.Proxy = nil
+			assert.Equal(t, tc.expectedConfig, config)
+		})
+	}
+}
+
+func TestLoadAllowedExecCommands(t *testing.T) {
+	testCases := []struct {
+		name             string
+		content          string
+		expectedCommands []AllowedExecCommand
+		isErrorExpected  bool
+	}{
+		{
+			name: "valid policy",
+			content: `commands:
+- command: aws
+  args: ["eks", "get-token", "--cluster-name", "[a-z0-9-]+"]
+  env: ["AWS_PROFILE"]
+- command: get-token
+`,
+			expectedCommands: []AllowedExecCommand{
+				{
+					Command: "aws",
+					Args:    []string{"eks", "get-token", "--cluster-name", "[a-z0-9-]+"},
+					Env:     []string{"AWS_PROFILE"},
+				},
+				{Command: "get-token"},
+			},
+		},
+		{
+			name:            "invalid arg pattern",
+			content:         "commands:\n- command: aws\n  args: [\"(\"]\n",
+			isErrorExpected: true,
+		},
+		{
+			name:            "duplicate command",
+			content:         "commands:\n- command: aws\n- command: aws\n",
+			isErrorExpected: true,
+		},
+		{
+			name:            "unknown field",
+			content:         "commands:\n- command: aws\n  environment: [\"AWS_PROFILE\"]\n",
+			isErrorExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0o600))
+
+			commands, err := LoadAllowedExecCommands(path)
+			if tc.isErrorExpected {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCommands, commands)
+		})
+	}
+}
+
+func TestOIDCTokenSource(t *testing.T) {
+	idToken := newTestIDToken(time.Now().Add(time.Hour))
+	var refreshTokens []string
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"token_endpoint": server.URL + "/token"})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		refreshTokens = append(refreshTokens, r.FormValue("refresh_token"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "access-token",
+			"token_type":    "Bearer",
+			"refresh_token": "rotated-refresh-token",
+			"id_token":      idToken,
+		})
+	})
+
+	source := &oidcTokenSource{
+		client:       server.Client(),
+		issuerURL:    server.URL,
+		clientID:     "kubeadmiral",
+		refreshToken: "refresh-token",
+	}
+
+	for i := 0; i < 2; i++ {
+		token, err := source.Token()
+		assert.NoError(t, err)
+		assert.Equal(t, idToken, token.AccessToken)
+		assert.False(t, token.Expiry.IsZero())
+	}
+	assert.Equal(t, []string{"refresh-token", "rotated-refresh-token"}, refreshTokens)
+}
+
+func TestOIDCTokenSourcePersistsRotatedRefreshToken(t *testing.T) {
+	idToken := newTestIDToken(time.Now().Add(time.Hour))
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"token_endpoint": server.URL + "/token"})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "access-token",
+			"token_type":    "Bearer",
+			"refresh_token": "rotated-" + r.FormValue("refresh_token"),
+			"id_token":      idToken,
+		})
+	})
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-admiral-system", Name: "member"},
+		Data:       map[string][]byte{OIDCRefreshTokenKey: []byte("refresh-token")},
+	}
+	kubeClient := fake.NewSimpleClientset(secret)
+
+	source := &oidcTokenSource{
+		client:        server.Client(),
+		issuerURL:     server.URL,
+		clientID:      "kubeadmiral",
+		refreshToken:  "refresh-token",
+		secretsClient: kubeClient.CoreV1().Secrets(secret.Namespace),
+		secretName:    secret.Name,
+	}
+	_, err := source.Token()
+	assert.NoError(t, err)
+
+	updated, err := kubeClient.CoreV1().Secrets(secret.Namespace).Get(context.TODO(), secret.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "rotated-refresh-token", string(updated.Data[OIDCRefreshTokenKey]))
+	assert.Equal(t, idToken, string(updated.Data[OIDCIDTokenKey]))
+}
+
+func TestGetOIDCTokenSourceEviction(t *testing.T) {
+	oidc := &fedcorev1a1.ClusterOIDCAuth{IssuerURL: "https://issuer.example.com", ClientID: "kubeadmiral"}
+	newSecret := func(refreshToken string) *corev1.Secret {
+		return &corev1.Secret{Data: map[string][]byte{OIDCRefreshTokenKey: []byte(refreshToken)}}
+	}
+	cachedClusters := func() []string {
+		oidcTokenSources.Lock()
+		defer oidcTokenSources.Unlock()
+		clusters := []string{}
+		for cluster := range oidcTokenSources.sources {
+			clusters = append(clusters, cluster)
+		}
+		return clusters
+	}
+	defer ForgetClusterAuth("eviction-test")
+
+	source, err := getOIDCTokenSource("eviction-test", oidc, newSecret("refresh-token"), nil)
+	assert.NoError(t, err)
+	same, err := getOIDCTokenSource("eviction-test", oidc, newSecret("refresh-token"), nil)
+	assert.NoError(t, err)
+	assert.Same(t, source, same, "the token source should be shared while the secret is unchanged")
+
+	replaced, err := getOIDCTokenSource("eviction-test", oidc, newSecret("new-refresh-token"), nil)
+	assert.NoError(t, err)
+	assert.NotSame(t, source, replaced, "the token source should be replaced when the secret changes")
+	assert.Contains(t, cachedClusters(), "eviction-test")
+
+	ForgetClusterAuth("eviction-test")
+	assert.NotContains(t, cachedClusters(), "eviction-test")
+}
@@ -54,6 +54,7 @@ func BuildClusterConfig(
 	fedClient kubeclient.Interface,
 	restConfig *restclient.Config,
 	fedSystemNamespace string,
+	authConfig *ClusterAuthConfig,
 ) (*restclient.Config, error) {
 	return buildClusterConfig(
 		cluster,
@@ -61,6 +62,7 @@ func BuildClusterConfig(
 		restConfig,
 		fedSystemNamespace,
 		cluster.Spec.UseServiceAccountToken,
+		authConfig,
 	)
 }
 
@@ -71,6 +73,7 @@ func BuildRawClusterConfig(
 	fedClient kubeclient.Interface,
 	restConfig *restclient.Config,
 	fedSystemNamespace string,
+	authConfig *ClusterAuthConfig,
 ) (*restclient.Config, error) {
 	return buildClusterConfig(
 		cluster,
@@ -78,6 +81,7 @@ func BuildRawClusterConfig(
 		restConfig,
 		fedSystemNamespace,
 		false,
+		authConfig,
 	)
 }
 
@@ -87,6 +91,7 @@ func buildClusterConfig(
 	restConfig *restclient.Config,
 	fedSystemNamespace string,
 	useServiceAccountToken bool,
+	authConfig *ClusterAuthConfig,
 ) (*restclient.Config, error) {
 	apiEndpoint := cluster.Spec.APIEndpoint
 	if len(apiEndpoint) == 0 {
@@ -115,7 +120,7 @@ func buildClusterConfig(
 		return nil, err
 	}
 
-	err = PopulateAuthDetailsFromSecret(clusterConfig, cluster.Spec.Insecure, secret, useServiceAccountToken)
+	err = PopulateClusterConfig(clusterConfig, cluster, secret, useServiceAccountToken, authConfig)
 	if err != nil {
 		return nil, fmt.Errorf("cannot build rest config from cluster secret: %w", err)
 	}
@@ -176,6 +181,7 @@ func BuildClusterConfigWithGenericClient(
 	fedClient generic.Client,
 	restConfig *restclient.Config,
 	fedSystemNamespace string,
+	authConfig *ClusterAuthConfig,
 ) (*restclient.Config, error) {
 	apiEndpoint := cluster.Spec.APIEndpoint
 	if len(apiEndpoint) == 0 {
@@ -196,7 +202,7 @@ func BuildClusterConfigWithGenericClient(
 		return nil, err
 	}
 
-	err = PopulateAuthDetailsFromSecret(clusterConfig, cluster.Spec.Insecure, secret, cluster.Spec.UseServiceAccountToken)
+	err = PopulateClusterConfig(clusterConfig, cluster, secret, cluster.Spec.UseServiceAccountToken, authConfig)
 	if err != nil {
 		return nil, fmt.Errorf("cannot build rest config from cluster secret: %w", err)
 	}
@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	restclient "k8s.io/client-go/rest"
 
+	"github.com/kubewharf/kubeadmiral/pkg/rolloutaudit"
 	"github.com/kubewharf/kubeadmiral/pkg/stats"
 )
 
@@ -49,8 +50,10 @@ type ControllerConfig struct {
 	WorkerCount                           int
 	NamespaceAutoPropagationExcludeRegexp *regexp.Regexp
 	CreateCrdForFtcs                      bool
+	ClusterAuthConfig                     *ClusterAuthConfig
 
-	Metrics stats.Metrics
+	Metrics          stats.Metrics
+	RolloutAuditSink rolloutaudit.Sink
 }
 
 func (c *ControllerConfig) LimitedScope() bool {
@@ -108,6 +108,7 @@ func NewDelayingDelivererWithChannel(targetChannel chan *DelayingDelivererItem)
 type MetricTags struct {
 	controller string
 	kind       string
+	ftc        string
 }
 
 func NewMetricTags(controller string, kind string) MetricTags {
@@ -115,7 +116,26 @@ func NewMetricTags(controller string, kind string) MetricTags {
 		kind = "nil"
 	}
 
-	return MetricTags{controller, kind}
+	return MetricTags{controller: controller, kind: kind, ftc: "nil"}
+}
+
+// NewFTCMetricTags returns the metric tags of a component that runs for the FederatedTypeConfig named ftcName. The
+// FTC name is tagged besides the kind since several FTCs may share a kind, e.g. for different versions of a CRD.
+func NewFTCMetricTags(controller string, kind string, ftcName string) MetricTags {
+	t := NewMetricTags(controller, kind)
+	if ftcName != "" {
+		t.ftc = ftcName
+	}
+	return t
+}
+
+// StatsTags returns the tags to attach to the stats points of the component identified by t.
+func (t MetricTags) StatsTags() []stats.Tag {
+	return []stats.Tag{
+		{Name: "controller", Value: t.controller},
+		{Name: "kind", Value: t.kind},
+		{Name: "ftc", Value: t.ftc},
+	}
 }
 
 func (d *DelayingDeliverer) RunMetricLoop(
@@ -124,10 +144,7 @@ func (d *DelayingDeliverer) RunMetricLoop(
 	metrics stats.Metrics,
 	metricTags MetricTags,
 ) {
-	tags := []stats.Tag{
-		{Name: "controller", Value: metricTags.controller},
-		{Name: "kind", Value: metricTags.kind},
-	}
+	tags := metricTags.StatsTags()
 
 	d.updateQueue.RunMetricLoop(stopCh, interval, func(md unboundedqueue.MetricData) {
 		metrics.Store("delayingDeliverer.queueLength", md.MaxLength, tags...)
@@ -20,12 +20,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"sync"
 	"time"
 
 	"golang.org/x/sync/semaphore"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -33,6 +35,7 @@ import (
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	kubeinformer "k8s.io/client-go/informers"
 	kubeclient "k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
@@ -53,8 +56,14 @@ type federatedClientFactory struct {
 	informer   fedcorev1a1informers.FederatedClusterInformer
 	handle     cache.ResourceEventHandlerRegistration
 
+	// The secrets of the clusters are watched to rebuild the clients of a cluster when its credentials change.
+	secretInformerFactory kubeinformer.SharedInformerFactory
+	secretLister          corev1listers.SecretLister
+	secretSynced          cache.InformerSynced
+
 	fedSystemNamespace string
 	baseRestConfig     *rest.Config
+	clusterAuthConfig  *util.ClusterAuthConfig
 
 	clientUpdateHandlers []ClientUpdateHandler
 	queue                workqueue.Interface
@@ -64,6 +73,8 @@ type federatedClientFactory struct {
 	dynamicClientsetCache map[string]dynamicclient.Interface
 	kubeInformerCache     map[string]kubeinformer.SharedInformerFactory
 	dynamicInformerCache  map[string]dynamicinformer.DynamicSharedInformerFactory
+	// informerStopChans stop the informer factories of a cluster when they are replaced or removed.
+	informerStopChans map[string]chan struct{}
 
 	availablePodListers *semaphore.Weighted
 	enablePodPruning    bool
@@ -75,6 +86,7 @@ func NewFederatedClientsetFactory(
 	informer fedcorev1a1informers.FederatedClusterInformer,
 	fedSystemNamespace string,
 	baseRestConfig *rest.Config,
+	clusterAuthConfig *util.ClusterAuthConfig,
 	maxPodListers int64,
 	enablePodPruning bool,
 ) FederatedClientFactory {
@@ -85,6 +97,7 @@ func NewFederatedClientsetFactory(
 		informer:              informer,
 		fedSystemNamespace:    fedSystemNamespace,
 		baseRestConfig:        baseRestConfig,
+		clusterAuthConfig:     clusterAuthConfig,
 		queue:                 workqueue.NewRateLimitingQueue(workqueue.DefaultItemBasedRateLimiter()),
 		clientUpdateHandlers:  []ClientUpdateHandler{},
 		clusterErrors:         map[string]error{},
@@ -92,6 +105,7 @@ func NewFederatedClientsetFactory(
 		dynamicClientsetCache: map[string]dynamicclient.Interface{},
 		kubeInformerCache:     map[string]kubeinformer.SharedInformerFactory{},
 		dynamicInformerCache:  map[string]dynamicinformer.DynamicSharedInformerFactory{},
+		informerStopChans:     map[string]chan struct{}{},
 		enablePodPruning:      enablePodPruning,
 	}
 	if maxPodListers > 0 {
@@ -124,6 +138,27 @@ func NewFederatedClientsetFactory(
 			factory.enqueueCluster(c)
 		},
 	})
+
+	factory.secretInformerFactory = kubeinformer.NewSharedInformerFactoryWithOptions(
+		kubeClient,
+		util.NoResyncPeriod,
+		kubeinformer.WithNamespace(fedSystemNamespace),
+	)
+	secretInformer := factory.secretInformerFactory.Core().V1().Secrets()
+	factory.secretLister = secretInformer.Lister()
+	factory.secretSynced = secretInformer.Informer().HasSynced
+	_, _ = secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldSecret := oldObj.(*corev1.Secret)
+			newSecret := newObj.(*corev1.Secret)
+
+			// only rebuild the clients when the credentials in the secret have changed
+			if !reflect.DeepEqual(oldSecret.Data, newSecret.Data) {
+				factory.enqueueClustersWithSecret(newSecret.Name)
+			}
+		},
+	})
+
 	return factory
 }
 
@@ -131,11 +166,18 @@ func (f *federatedClientFactory) Start(ctx context.Context) {
 	go func() {
 		defer f.queue.ShutDown()
 		defer f.informer.Informer().RemoveEventHandler(f.handle)
+		defer f.stopAllInformerFactories()
 
 		<-ctx.Done()
 	}()
 
-	if !cache.WaitForNamedCacheSync("federated-client-factory", ctx.Done(), f.informer.Informer().HasSynced) {
+	f.secretInformerFactory.Start(ctx.Done())
+	if !cache.WaitForNamedCacheSync(
+		"federated-client-factory",
+		ctx.Done(),
+		f.informer.Informer().HasSynced,
+		f.secretSynced,
+	) {
 		return
 	}
 
@@ -245,6 +287,21 @@ func (f *federatedClientFactory) enqueueCluster(obj runtime.Object) {
 	f.queue.Add(key)
 }
 
+// enqueueClustersWithSecret enqueues the clusters in Push mode that reference the secret with the given name.
+func (f *federatedClientFactory) enqueueClustersWithSecret(secretName string) {
+	clusters, err := f.informer.Lister().List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list clusters: %w", err))
+		return
+	}
+
+	for _, cluster := range clusters {
+		if !cluster.IsPullMode() && cluster.Spec.SecretRef.Name == secretName {
+			f.enqueueCluster(cluster)
+		}
+	}
+}
+
 func (f *federatedClientFactory) processQueueItem(ctx context.Context) {
 	key, quit := f.queue.Get()
 	if quit {
@@ -270,6 +327,7 @@ func (f *federatedClientFactory) processQueueItem(ctx context.Context) {
 	if err != nil && apierrors.IsNotFound(err) {
 		// cluster was deleted so we clear the caches
 		f.clearCaches(name)
+		util.ForgetClusterAuth(name)
 		return
 	}
 	if !util.IsClusterJoined(&cluster.Status) {
@@ -277,6 +335,11 @@ func (f *federatedClientFactory) processQueueItem(ctx context.Context) {
 		f.clearCaches(name)
 		return
 	}
+	if cluster.IsPullMode() {
+		// clusters in pull mode are not reachable from the control plane
+		f.clearCaches(name)
+		return
+	}
 
 	var kubeClientset kubeclient.Interface
 	var dynamicClientset dynamicclient.Interface
@@ -286,20 +349,19 @@ func (f *federatedClientFactory) processQueueItem(ctx context.Context) {
 	restConfig := copyRestConfig(f.baseRestConfig)
 	restConfig.Host = cluster.Spec.APIEndpoint
 
-	clusterSecretRef, err := f.kubeClient.CoreV1().
-		Secrets(f.fedSystemNamespace).
-		Get(ctx, cluster.Spec.SecretRef.Name, metav1.GetOptions{})
+	clusterSecretRef, err := f.secretLister.Secrets(f.fedSystemNamespace).Get(cluster.Spec.SecretRef.Name)
 	if err != nil {
 		f.updateCachesWithError(name, fmt.Errorf("failed to retrieve cluster secret: %w", err))
 		f.queue.Add(key)
 		return
 	}
 
-	if err := util.PopulateAuthDetailsFromSecret(
+	if err := util.PopulateClusterConfig(
 		restConfig,
-		cluster.Spec.Insecure,
+		cluster,
 		clusterSecretRef,
 		cluster.Spec.UseServiceAccountToken,
+		f.clusterAuthConfig,
 	); err != nil {
 		f.updateCachesWithError(name, fmt.Errorf("failed to build rest config from cluster secret: %w", err))
 		f.queue.Add(key)
@@ -322,6 +384,8 @@ func (f *federatedClientFactory) processQueueItem(ctx context.Context) {
 	dynamicInformerFactory = dynamicinformer.NewDynamicSharedInformerFactory(dynamicClientset, util.NoResyncPeriod)
 
 	f.mu.Lock()
+	f.stopInformerFactoriesUnlocked(name)
+	f.informerStopChans[name] = make(chan struct{})
 	f.clusterErrors[name] = nil
 	f.kubeClientsetCache[name] = kubeClientset
 	f.dynamicClientsetCache[name] = dynamicClientset
@@ -335,6 +399,7 @@ func (f *federatedClientFactory) updateCachesWithError(cluster string, err error
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	f.stopInformerFactoriesUnlocked(cluster)
 	f.clusterErrors[cluster] = err
 	f.kubeClientsetCache[cluster] = nil
 	f.dynamicClientsetCache[cluster] = nil
@@ -345,6 +410,7 @@ func (f *federatedClientFactory) updateCachesWithError(cluster string, err error
 func (f *federatedClientFactory) clearCaches(cluster string) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	f.stopInformerFactoriesUnlocked(cluster)
 	delete(f.clusterErrors, cluster)
 	delete(f.kubeClientsetCache, cluster)
 	delete(f.dynamicClientsetCache, cluster)
@@ -365,18 +431,38 @@ func (f *federatedClientFactory) startInformerFactories(ctx context.Context) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
-	for _, factory := range f.kubeInformerCache {
-		if factory != nil {
-			factory.Start(ctx.Done())
+	for cluster, factory := range f.kubeInformerCache {
+		if stopChan := f.informerStopChans[cluster]; factory != nil && stopChan != nil {
+			factory.Start(stopChan)
 		}
 	}
-	for _, factory := range f.dynamicInformerCache {
-		if factory != nil {
-			factory.Start(ctx.Done())
+	for cluster, factory := range f.dynamicInformerCache {
+		if stopChan := f.informerStopChans[cluster]; factory != nil && stopChan != nil {
+			factory.Start(stopChan)
 		}
 	}
 }
 
+// stopInformerFactoriesUnlocked stops the informer factories of the cluster, which are replaced or removed by the
+// caller. The caller must hold the lock.
+func (f *federatedClientFactory) stopInformerFactoriesUnlocked(cluster string) {
+	if stopChan, ok := f.informerStopChans[cluster]; ok {
+		close(stopChan)
+		delete(f.informerStopChans, cluster)
+	}
+}
+
+func (f *federatedClientFactory) stopAllInformerFactories() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for cluster := range f.informerStopChans {
+		f.stopInformerFactoriesUnlocked(cluster)
+	}
+}
+
+// copyRestConfig copies the client settings of the base config. The host, the proxy and the credentials of a cluster
+// are populated separately by util.PopulateClusterConfig.
 func copyRestConfig(config *rest.Config) *rest.Config {
 	return &rest.Config{
 		QPS:       config.QPS,
@@ -92,7 +92,8 @@ type RegisteredClustersView interface {
 	// GetUnreadyClusters returns a list of all clusters that are not ready yet.
 	GetUnreadyClusters() ([]*fedcorev1a1.FederatedCluster, error)
 
-	// GetReadyClusters returns all clusters for which the sub-informers are run.
+	// GetReadyClusters returns all clusters for which the sub-informers are run. Clusters in Pull mode are excluded
+	// since they are not reachable from the control plane.
 	GetReadyClusters() ([]*fedcorev1a1.FederatedCluster, error)
 
 	// GetJoinedClusters returns a list of all joined clusters.
@@ -183,6 +184,7 @@ func NewFederatedInformer(
 				fedClient,
 				restConfig,
 				config.FedSystemNamespace,
+				config.ClusterAuthConfig,
 			)
 			if err != nil {
 				return nil, err
@@ -260,6 +262,7 @@ func NewFederatedInformer(
 						clusterLifecycle.ClusterUnavailable(oldCluster, data)
 					}
 				} else if IsClusterReady(&oldCluster.Status) != IsClusterReady(&curCluster.Status) ||
+					getSecretResourceVersion(oldCluster) != getSecretResourceVersion(curCluster) ||
 					!reflect.DeepEqual(oldCluster.Spec, curCluster.Spec) ||
 					!reflect.DeepEqual(oldCluster.ObjectMeta.Labels, curCluster.ObjectMeta.Labels) ||
 					!reflect.DeepEqual(oldCluster.ObjectMeta.Annotations, curCluster.ObjectMeta.Annotations) {
@@ -288,6 +291,15 @@ func NewFederatedInformer(
 	return federatedInformer, err
 }
 
+// getSecretResourceVersion returns the resource version of the cluster secret when the credentials of the cluster were
+// last observed, which changes when the credentials are rotated.
+func getSecretResourceVersion(cluster *fedcorev1a1.FederatedCluster) string {
+	if cluster.Status.Credentials == nil {
+		return ""
+	}
+	return cluster.Status.Credentials.SecretResourceVersion
+}
+
 func IsClusterReady(clusterStatus *fedcorev1a1.FederatedClusterStatus) bool {
 	for _, condition := range clusterStatus.Conditions {
 		if condition.Type == fedcorev1a1.ClusterReady {
@@ -436,7 +448,7 @@ func (f *federatedInformerImpl) getJoinedClusters(onlyReady bool) ([]*fedcorev1a
 	result := make([]*fedcorev1a1.FederatedCluster, 0, len(items))
 	for _, item := range items {
 		if cluster, ok := item.(*fedcorev1a1.FederatedCluster); ok {
-			if IsClusterJoined(&cluster.Status) && (!onlyReady || IsClusterReady(&cluster.Status)) {
+			if IsClusterJoined(&cluster.Status) && (!onlyReady || (IsClusterReady(&cluster.Status) && !cluster.IsPullMode())) {
 				result = append(result, cluster)
 			}
 		} else {
@@ -488,6 +500,11 @@ func (f *federatedInformerImpl) ClustersSynced() bool {
 
 // Adds the given cluster to federated informer.
 func (f *federatedInformerImpl) addCluster(cluster *fedcorev1a1.FederatedCluster) {
+	if cluster.IsPullMode() {
+		// objects in clusters in pull mode are applied by their agents and cannot be watched from the control plane
+		return
+	}
+
 	f.Lock()
 	defer f.Unlock()
 	name := cluster.Name
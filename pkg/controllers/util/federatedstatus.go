@@ -22,7 +22,9 @@ package util
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -60,6 +62,32 @@ type ResourceClusterStatus struct {
 	CollectedFields map[string]interface{} `json:"collectedFields,omitempty"`
 }
 
+// CollectStatusFields copies the given dot-separated fields of the object. The fields that could not be collected are
+// returned in sorted order together with the reasons.
+func CollectStatusFields(obj map[string]interface{}, fields []string) (map[string]interface{}, []string) {
+	collectedFields := map[string]interface{}{}
+	failedFields := []string{}
+
+	for _, field := range fields {
+		path := strings.Split(field, ".")
+		fieldVal, found, err := unstructured.NestedFieldCopy(obj, path...)
+		if err != nil {
+			failedFields = append(failedFields, fmt.Sprintf("%s: %s", field, err.Error()))
+			continue
+		}
+		if !found {
+			failedFields = append(failedFields, fmt.Sprintf("%s: not found", field))
+			continue
+		}
+		if err := unstructured.SetNestedField(collectedFields, fieldVal, path...); err != nil {
+			failedFields = append(failedFields, fmt.Sprintf("%s: %s", field, err.Error()))
+		}
+	}
+
+	sort.Strings(failedFields)
+	return collectedFields, failedFields
+}
+
 type LatestReplicasetDigest struct {
 	ClusterName        string `json:"clusterName,omitempty"`
 	ReplicasetName     string `json:"replicasetName,omitempty"`
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+// PullNamespacePrefix is the prefix of the namespaces holding the PulledObjects of clusters in Pull mode. The agent of
+// a cluster is only granted access to the namespace of its cluster.
+const PullNamespacePrefix = "kubeadmiral-pull-"
+
+// PulledObjectTypeLabel records the FTC of the object of a PulledObject, so that the PulledObjects of a single type
+// can be watched.
+var PulledObjectTypeLabel = common.DefaultPrefix + "pulled-object-type"
+
+// PullNamespace returns the namespace holding the PulledObjects of the given cluster in Pull mode.
+func PullNamespace(clusterName string) string {
+	namespace := PullNamespacePrefix + clusterName
+	if len(validation.IsDNS1123Label(namespace)) == 0 {
+		return namespace
+	}
+	// Cluster names are DNS subdomains, which may be too long or contain dots for a namespace.
+	return nameWithHash(strings.ReplaceAll(namespace, ".", "-"), clusterName, validation.DNS1123LabelMaxLength)
+}
+
+// PulledObjectName returns the name of the PulledObject of the target object of the given FTC. The target namespace
+// and name are hashed since they may not fit in the name of the PulledObject together.
+func PulledObjectName(typeConfigName string, targetName common.QualifiedName) string {
+	return nameWithHash(typeConfigName, targetName.String(), validation.DNS1123SubdomainMaxLength)
+}
+
+// PulledObjectTypeSelector returns the label selector of the PulledObjects of the given FTC.
+func PulledObjectTypeSelector(typeConfigName string) labels.Set {
+	value := typeConfigName
+	if len(validation.IsValidLabelValue(value)) != 0 {
+		value = nameWithHash(value, value, validation.LabelValueMaxLength)
+	}
+	return labels.Set{PulledObjectTypeLabel: value}
+}
+
+// PulledObjectTargetName returns the qualified name of the target object of the PulledObject.
+func PulledObjectTargetName(pulledObject *fedcorev1a1.PulledObject) (common.QualifiedName, error) {
+	template := struct {
+		Metadata struct {
+			Namespace string `json:"namespace"`
+			Name      string `json:"name"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(pulledObject.Spec.Template.Raw, &template); err != nil {
+		return common.QualifiedName{}, fmt.Errorf("failed to unmarshal template: %w", err)
+	}
+	return common.QualifiedName{Namespace: template.Metadata.Namespace, Name: template.Metadata.Name}, nil
+}
+
+// PulledObjectTemplate returns the object to apply in the cluster of the PulledObject.
+func PulledObjectTemplate(pulledObject *fedcorev1a1.PulledObject) (*unstructured.Unstructured, error) {
+	template := &unstructured.Unstructured{}
+	if err := template.UnmarshalJSON(pulledObject.Spec.Template.Raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal template: %w", err)
+	}
+	return template, nil
+}
+
+// nameWithHash appends the hash of the given input to the name, truncating the name to keep the result within the
+// maximum length.
+func nameWithHash(name, hashInput string, maxLength int) string {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(hashInput))
+	suffix := fmt.Sprintf("-%016x", hasher.Sum64())
+	if len(name)+len(suffix) > maxLength {
+		name = name[:maxLength-len(suffix)]
+	}
+	return strings.TrimRight(name, "-.") + suffix
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+func TestPullNamespace(t *testing.T) {
+	assert.Equal(t, "kubeadmiral-pull-member-1", PullNamespace("member-1"))
+
+	for _, clusterName := range []string{"member.example.com", strings.Repeat("a", 63)} {
+		namespace := PullNamespace(clusterName)
+		assert.Empty(t, validation.IsDNS1123Label(namespace), "namespace %q should be a valid label", namespace)
+		assert.True(t, strings.HasPrefix(namespace, PullNamespacePrefix))
+	}
+	assert.NotEqual(t, PullNamespace("a.b"), PullNamespace("a-b"), "distinct clusters should not share a namespace")
+}
+
+func TestPulledObjectName(t *testing.T) {
+	name := PulledObjectName("deployments.apps", common.QualifiedName{Namespace: "default", Name: "nginx"})
+	assert.True(t, strings.HasPrefix(name, "deployments.apps-"))
+	assert.Empty(t, validation.IsDNS1123Subdomain(name))
+
+	assert.NotEqual(
+		t,
+		name,
+		PulledObjectName("deployments.apps", common.QualifiedName{Namespace: "nginx", Name: "default"}),
+	)
+
+	long := PulledObjectName(strings.Repeat("a", 300), common.QualifiedName{Name: "nginx"})
+	assert.Empty(t, validation.IsDNS1123Subdomain(long))
+}
+
+func TestPulledObjectTargetName(t *testing.T) {
+	pulledObject := &fedcorev1a1.PulledObject{
+		Spec: fedcorev1a1.PulledObjectSpec{
+			Template: apiextensionsv1.JSON{
+				Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"namespace":"default","name":"nginx"}}`),
+			},
+		},
+	}
+
+	targetName, err := PulledObjectTargetName(pulledObject)
+	assert.NoError(t, err)
+	assert.Equal(t, common.QualifiedName{Namespace: "default", Name: "nginx"}, targetName)
+
+	template, err := PulledObjectTemplate(pulledObject)
+	assert.NoError(t, err)
+	assert.Equal(t, "Deployment", template.GetKind())
+	assert.Equal(t, targetName, common.NewQualifiedName(template))
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicaestimator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	estimatorv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/replicaestimator/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+// ErrNoEstimator is returned if the cluster does not have a replica estimator.
+var ErrNoEstimator = errors.New("cluster has no replica estimator")
+
+// Estimator estimates the number of replicas of a workload that can be scheduled in member clusters.
+type Estimator interface {
+	// MaxAvailableReplicas returns the maximum number of additional replicas that can be scheduled in the cluster.
+	MaxAvailableReplicas(
+		ctx context.Context,
+		cluster *fedcorev1a1.FederatedCluster,
+		request *estimatorv1a1.MaxAvailableReplicasRequest,
+	) (int64, error)
+
+	// Forget drops the client and cached results of the cluster, e.g. when the cluster is deleted.
+	Forget(clusterName string)
+}
+
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type cachedClient struct {
+	config *fedcorev1a1.ReplicaEstimatorConfig
+	client HTTPClient
+}
+
+type cachedResult struct {
+	maxReplicas int64
+	expiry      time.Time
+}
+
+// DefaultResultTTL is the duration for which estimation results are reused for identical requests.
+const DefaultResultTTL = 10 * time.Second
+
+// httpEstimator calls the replica estimators configured in the specs of member clusters.
+type httpEstimator struct {
+	// clients caches the HTTP clients of the clusters' estimators, keyed by cluster name.
+	clients sync.Map
+
+	resultTTL time.Duration
+	// results caches successful estimations, keyed by cluster name and then by request hash.
+	resultsLock sync.Mutex
+	results     map[string]map[string]cachedResult
+}
+
+var _ Estimator = &httpEstimator{}
+
+// NewHTTPEstimator returns an Estimator that reuses the result of identical requests to the same cluster for resultTTL.
+// Results are not cached if resultTTL is 0.
+func NewHTTPEstimator(resultTTL time.Duration) Estimator {
+	return &httpEstimator{
+		resultTTL: resultTTL,
+		results:   map[string]map[string]cachedResult{},
+	}
+}
+
+func (e *httpEstimator) MaxAvailableReplicas(
+	ctx context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+	request *estimatorv1a1.MaxAvailableReplicasRequest,
+) (int64, error) {
+	config := cluster.Spec.ReplicaEstimator
+	if config == nil {
+		return 0, ErrNoEstimator
+	}
+
+	client, err := e.getClient(cluster.Name, config)
+	if err != nil {
+		return 0, err
+	}
+
+	requestHash, err := hashRequest(request)
+	if err != nil {
+		return 0, err
+	}
+	if replicas, exists := e.getCachedResult(cluster.Name, requestHash); exists {
+		return replicas, nil
+	}
+
+	resp := estimatorv1a1.MaxAvailableReplicasResponse{}
+	if err := doRequest(ctx, client, config.URL, request, &resp); err != nil {
+		return 0, err
+	}
+	if len(resp.Error) > 0 {
+		return 0, fmt.Errorf("replica estimator returned error: %s", resp.Error)
+	}
+	if resp.MaxReplicas < 0 {
+		return 0, fmt.Errorf("replica estimator returned negative replicas: %d", resp.MaxReplicas)
+	}
+
+	e.setCachedResult(cluster.Name, requestHash, resp.MaxReplicas)
+	return resp.MaxReplicas, nil
+}
+
+func (e *httpEstimator) Forget(clusterName string) {
+	e.clients.Delete(clusterName)
+
+	e.resultsLock.Lock()
+	defer e.resultsLock.Unlock()
+	delete(e.results, clusterName)
+}
+
+func (e *httpEstimator) getCachedResult(clusterName, requestHash string) (int64, bool) {
+	e.resultsLock.Lock()
+	defer e.resultsLock.Unlock()
+
+	result, exists := e.results[clusterName][requestHash]
+	if !exists || time.Now().After(result.expiry) {
+		return 0, false
+	}
+	return result.maxReplicas, true
+}
+
+func (e *httpEstimator) setCachedResult(clusterName, requestHash string, maxReplicas int64) {
+	if e.resultTTL <= 0 {
+		return
+	}
+
+	e.resultsLock.Lock()
+	defer e.resultsLock.Unlock()
+
+	now := time.Now()
+	clusterResults := e.results[clusterName]
+	if clusterResults == nil {
+		clusterResults = map[string]cachedResult{}
+		e.results[clusterName] = clusterResults
+	}
+	// Drop expired results so that the cache does not grow with every distinct workload ever estimated.
+	for hash, result := range clusterResults {
+		if now.After(result.expiry) {
+			delete(clusterResults, hash)
+		}
+	}
+	clusterResults[requestHash] = cachedResult{maxReplicas: maxReplicas, expiry: now.Add(e.resultTTL)}
+}
+
+func hashRequest(request *estimatorv1a1.MaxAvailableReplicasRequest) (string, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute request hash: %w", err)
+	}
+
+	hash := fnv.New64a()
+	if _, err := hash.Write(requestBytes); err != nil {
+		return "", fmt.Errorf("failed to compute request hash: %w", err)
+	}
+	return strconv.FormatUint(hash.Sum64(), 16), nil
+}
+
+func (e *httpEstimator) getClient(clusterName string, config *fedcorev1a1.ReplicaEstimatorConfig) (HTTPClient, error) {
+	if cached, exists := e.clients.Load(clusterName); exists {
+		if reflect.DeepEqual(cached.(*cachedClient).config, config) {
+			return cached.(*cachedClient).client, nil
+		}
+		// The estimator config changed, results from the previous estimator should not be reused.
+		e.resultsLock.Lock()
+		delete(e.results, clusterName)
+		e.resultsLock.Unlock()
+	}
+
+	transport, err := util.NewWebhookTransport(config.TLSConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replica estimator transport: %w", err)
+	}
+
+	timeout := config.HTTPTimeout.Duration
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}
+
+	e.clients.Store(clusterName, &cachedClient{config: config.DeepCopy(), client: client})
+	return client, nil
+}
+
+func doRequest(ctx context.Context, client HTTPClient, url string, body any, response any) error {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("User-Agent", "kubeadmiral-replica-estimator-client")
+
+	logger := klog.FromContext(ctx).WithValues("url", url)
+	logger.V(4).Info("Sending request to replica estimator")
+	start := time.Now()
+
+	httpResp, err := client.Do(req)
+	logger = logger.WithValues("duration", time.Since(start))
+	if err != nil {
+		logger.Error(err, "Replica estimator request failed")
+		return fmt.Errorf("request failed: %w", err)
+	}
+	logger = logger.WithValues("status", httpResp.StatusCode)
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(httpResp.Body)
+		if err != nil {
+			logger.Error(err, "Received non-200 response from replica estimator and failed to read body")
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		logger.Error(nil, "Received non-200 response from replica estimator", "body", string(body))
+		return fmt.Errorf("unexpected status code: %d, body: %s", httpResp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(httpResp.Body).Decode(response); err != nil {
+		logger.Error(err, "Failed to decode response from replica estimator")
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	logger.V(4).Info("Received response from replica estimator")
+	return nil
+}
+
+// NewRequest returns the request for estimating the replicas of a workload with the given pod spec in a cluster.
+func NewRequest(clusterName string, podSpec *corev1.PodSpec) *estimatorv1a1.MaxAvailableReplicasRequest {
+	request := &estimatorv1a1.MaxAvailableReplicasRequest{
+		ClusterName:     clusterName,
+		ResourceRequest: util.GetPodResourceRequests(podSpec),
+		NodeSelector:    podSpec.NodeSelector,
+		Tolerations:     podSpec.Tolerations,
+	}
+	if affinity := podSpec.Affinity; affinity != nil && affinity.NodeAffinity != nil {
+		request.NodeAffinity = affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	}
+	return request
+}
+
+// GetPodSpec returns the pod spec at the given path of the template of a federated object, e.g. spec.template.spec.
+func GetPodSpec(fedObject *unstructured.Unstructured, podSpecPath string) (*corev1.PodSpec, error) {
+	if len(podSpecPath) == 0 {
+		return nil, fmt.Errorf("pod spec path is not specified")
+	}
+
+	path := append(append([]string{}, common.TemplatePath...), strings.Split(podSpecPath, ".")...)
+	podSpecMap, found, err := unstructured.NestedMap(fedObject.Object, path...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod spec: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("pod spec does not exist at path %q", podSpecPath)
+	}
+
+	podSpec := &corev1.PodSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(podSpecMap, podSpec); err != nil {
+		return nil, fmt.Errorf("failed to convert pod spec: %w", err)
+	}
+	return podSpec, nil
+}
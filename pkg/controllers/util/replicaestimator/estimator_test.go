@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicaestimator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	estimatorv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/replicaestimator/v1alpha1"
+)
+
+func TestHTTPEstimator(t *testing.T) {
+	var receivedRequest estimatorv1a1.MaxAvailableReplicasRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&receivedRequest); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp := estimatorv1a1.MaxAvailableReplicasResponse{MaxReplicas: 3}
+		if receivedRequest.ClusterName == "failing" {
+			resp = estimatorv1a1.MaxAvailableReplicasResponse{Error: "caches not synced"}
+		}
+		_ = json.NewEncoder(w).Encode(&resp)
+	}))
+	defer server.Close()
+
+	makeCluster := func(name string, config *fedcorev1a1.ReplicaEstimatorConfig) *fedcorev1a1.FederatedCluster {
+		return &fedcorev1a1.FederatedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       fedcorev1a1.FederatedClusterSpec{ReplicaEstimator: config},
+		}
+	}
+	podSpec := &corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				},
+			},
+			{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				},
+			},
+		},
+		NodeSelector: map[string]string{"pool": "gpu"},
+	}
+
+	estimator := NewHTTPEstimator(0)
+	config := &fedcorev1a1.ReplicaEstimatorConfig{URL: server.URL}
+
+	replicas, err := estimator.MaxAvailableReplicas(
+		context.Background(),
+		makeCluster("cluster1", config),
+		NewRequest("cluster1", podSpec),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), replicas)
+	assert.Equal(t, "cluster1", receivedRequest.ClusterName)
+	assert.Equal(t, map[string]string{"pool": "gpu"}, receivedRequest.NodeSelector)
+	cpu := receivedRequest.ResourceRequest[corev1.ResourceCPU]
+	assert.Equal(t, int64(1500), cpu.MilliValue())
+
+	_, err = estimator.MaxAvailableReplicas(
+		context.Background(),
+		makeCluster("failing", config),
+		NewRequest("failing", podSpec),
+	)
+	assert.ErrorContains(t, err, "caches not synced")
+
+	_, err = estimator.MaxAvailableReplicas(
+		context.Background(),
+		makeCluster("cluster2", nil),
+		NewRequest("cluster2", podSpec),
+	)
+	assert.ErrorIs(t, err, ErrNoEstimator)
+}
+
+func TestHTTPEstimatorCachesResults(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_ = json.NewEncoder(w).Encode(&estimatorv1a1.MaxAvailableReplicasResponse{MaxReplicas: 3})
+	}))
+	defer server.Close()
+
+	cluster := &fedcorev1a1.FederatedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1"},
+		Spec: fedcorev1a1.FederatedClusterSpec{
+			ReplicaEstimator: &fedcorev1a1.ReplicaEstimatorConfig{URL: server.URL},
+		},
+	}
+	smallPodSpec := &corev1.PodSpec{NodeSelector: map[string]string{"pool": "small"}}
+	largePodSpec := &corev1.PodSpec{NodeSelector: map[string]string{"pool": "large"}}
+
+	estimator := NewHTTPEstimator(time.Minute)
+	for i := 0; i < 3; i++ {
+		replicas, err := estimator.MaxAvailableReplicas(context.Background(), cluster, NewRequest("cluster1", smallPodSpec))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), replicas)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	_, err := estimator.MaxAvailableReplicas(context.Background(), cluster, NewRequest("cluster1", largePodSpec))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	estimator.Forget("cluster1")
+	_, err = estimator.MaxAvailableReplicas(context.Background(), cluster, NewRequest("cluster1", smallPodSpec))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestGetPodSpec(t *testing.T) {
+	fedObject := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"nodeSelector": map[string]interface{}{"pool": "gpu"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	podSpec, err := GetPodSpec(fedObject, "spec.template.spec")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"pool": "gpu"}, podSpec.NodeSelector)
+
+	_, err = GetPodSpec(fedObject, "spec.jobTemplate.spec.template.spec")
+	assert.Error(t, err)
+
+	_, err = GetPodSpec(fedObject, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetPodResourceRequests returns the resources requested by a pod with the given spec, which is the maximum of the
+// sum of its containers' requests and each of its init containers' requests, plus its overhead.
+func GetPodResourceRequests(podSpec *corev1.PodSpec) corev1.ResourceList {
+	requests := make(corev1.ResourceList)
+	for _, container := range podSpec.Containers {
+		addResources(container.Resources.Requests, requests)
+	}
+	for _, container := range podSpec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if existing, ok := requests[name]; !ok || quantity.Cmp(existing) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	addResources(podSpec.Overhead, requests)
+	return requests
+}
+
+func addResources(src, dst corev1.ResourceList) {
+	for name, quantity := range src {
+		if existing, ok := dst[name]; ok {
+			existing.Add(quantity)
+			dst[name] = existing
+		} else {
+			dst[name] = quantity.DeepCopy()
+		}
+	}
+}
@@ -23,7 +23,9 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/klog/v2"
 
@@ -34,10 +36,13 @@ import (
 )
 
 const (
-	ReplicaPath        = "/spec/replicas"
-	MaxSurgePath       = "/spec/strategy/rollingUpdate/maxSurge"
-	MaxUnavailablePath = "/spec/strategy/rollingUpdate/maxUnavailable"
-	Nil                = "nil"
+	ReplicaPath                      = "/spec/replicas"
+	MaxSurgePath                     = "/spec/strategy/rollingUpdate/maxSurge"
+	MaxUnavailablePath               = "/spec/strategy/rollingUpdate/maxUnavailable"
+	UpdateStrategyMaxSurgePath       = "/spec/updateStrategy/rollingUpdate/maxSurge"
+	UpdateStrategyMaxUnavailablePath = "/spec/updateStrategy/rollingUpdate/maxUnavailable"
+	PartitionPath                    = "/spec/updateStrategy/rollingUpdate/partition"
+	Nil                              = "nil"
 )
 
 var (
@@ -53,17 +58,60 @@ var (
 		common.RollingUpdateField,
 		common.MaxUnavailableField,
 	}
+	UpdateStrategyMaxSurgePathSlice = []string{
+		common.SpecField,
+		common.UpdateStrategyField,
+		common.RollingUpdateField,
+		common.MaxSurgeField,
+	}
+	UpdateStrategyMaxUnavailablePathSlice = []string{
+		common.SpecField,
+		common.UpdateStrategyField,
+		common.RollingUpdateField,
+		common.MaxUnavailableField,
+	}
+	PartitionPathSlice = []string{
+		common.SpecField,
+		common.UpdateStrategyField,
+		common.RollingUpdateField,
+		common.PartitionField,
+	}
 )
 
+// IsRolloutPlanSupported returns whether rollout plans can be computed for the workload kind.
+func IsRolloutPlanSupported(kind string) bool {
+	switch kind {
+	case common.DeploymentKind, common.StatefulSetKind, common.DaemonSetKind:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetFencepostPaths returns the paths of maxSurge and maxUnavailable in the rolling update strategy of the workload
+// kind. The maxSurge path is nil for StatefulSets, since their pods are updated in place.
+func GetFencepostPaths(kind string) (maxSurgePath, maxUnavailablePath []string) {
+	switch kind {
+	case common.StatefulSetKind:
+		return nil, UpdateStrategyMaxUnavailablePathSlice
+	case common.DaemonSetKind:
+		return UpdateStrategyMaxSurgePathSlice, UpdateStrategyMaxUnavailablePathSlice
+	default:
+		return MaxSurgePathSlice, MaxUnavailablePathSlice
+	}
+}
+
 type RolloutPlan struct {
 	Replicas          *int32
 	MaxSurge          *int32
 	MaxUnavailable    *int32
 	OnlyPatchReplicas bool
+	// Partition is only planned for StatefulSets, in place of MaxSurge and MaxUnavailable.
+	Partition *int32
 }
 
 func (p RolloutPlan) String() string {
-	r, s, u := Nil, Nil, Nil
+	r, s, u, pt := Nil, Nil, Nil, Nil
 	if p.Replicas != nil {
 		r = fmt.Sprintf("%d", *p.Replicas)
 	}
@@ -73,19 +121,31 @@ func (p RolloutPlan) String() string {
 	if p.MaxUnavailable != nil {
 		u = fmt.Sprintf("%d", *p.MaxUnavailable)
 	}
-	return fmt.Sprintf("%s,%s,%s,%t", r, s, u, p.OnlyPatchReplicas)
+	if p.Partition != nil {
+		pt = fmt.Sprintf("%d", *p.Partition)
+	}
+	return fmt.Sprintf("%s,%s,%s,%s,%t", r, s, u, pt, p.OnlyPatchReplicas)
 }
 
-func (p RolloutPlan) toOverrides() fedtypesv1a1.OverridePatches {
+func (p RolloutPlan) toOverrides(kind string) fedtypesv1a1.OverridePatches {
+	maxSurgePath, maxUnavailablePath := MaxSurgePath, MaxUnavailablePath
+	if kind == common.StatefulSetKind || kind == common.DaemonSetKind {
+		maxSurgePath, maxUnavailablePath = UpdateStrategyMaxSurgePath, UpdateStrategyMaxUnavailablePath
+	}
+
 	overrides := fedtypesv1a1.OverridePatches{}
-	if p.Replicas != nil {
+	// The replicas of DaemonSets are determined by the nodes of the cluster.
+	if p.Replicas != nil && kind != common.DaemonSetKind {
 		overrides = append(overrides, fedtypesv1a1.OverridePatch{Path: ReplicaPath, Value: *p.Replicas})
 	}
 	if p.MaxSurge != nil {
-		overrides = append(overrides, fedtypesv1a1.OverridePatch{Path: MaxSurgePath, Value: *p.MaxSurge})
+		overrides = append(overrides, fedtypesv1a1.OverridePatch{Path: maxSurgePath, Value: *p.MaxSurge})
 	}
 	if p.MaxUnavailable != nil {
-		overrides = append(overrides, fedtypesv1a1.OverridePatch{Path: MaxUnavailablePath, Value: *p.MaxUnavailable})
+		overrides = append(overrides, fedtypesv1a1.OverridePatch{Path: maxUnavailablePath, Value: *p.MaxUnavailable})
+	}
+	if p.Partition != nil {
+		overrides = append(overrides, fedtypesv1a1.OverridePatch{Path: PartitionPath, Value: *p.Partition})
 	}
 	return overrides
 }
@@ -121,12 +181,13 @@ func (r RolloutPlans) String() string {
 	return strings.Join(strs, "; ")
 }
 
-func (r RolloutPlans) GetRolloutOverrides(clusterName string) fedtypesv1a1.OverridePatches {
+// GetRolloutOverrides returns the overrides that apply the plan of the cluster to an object of the workload kind.
+func (r RolloutPlans) GetRolloutOverrides(clusterName, kind string) fedtypesv1a1.OverridePatches {
 	p, ok := r[clusterName]
 	if !ok {
 		return fedtypesv1a1.OverridePatches{}
 	}
-	return p.toOverrides()
+	return p.toOverrides(kind)
 }
 
 type Targets []*TargetInfo
@@ -371,6 +432,21 @@ func unstructuredObjToTargetInfo(clusterName string, unstructuredObj *unstructur
 		}, nil
 	}
 
+	switch typeConfig.GetTargetType().Kind {
+	case common.StatefulSetKind:
+		status, err := statefulSetToTargetStatus(unstructuredObj, desiredRevision)
+		if err != nil {
+			return nil, err
+		}
+		return &TargetInfo{ClusterName: clusterName, Status: *status, DesiredReplicas: desiredReplicas}, nil
+	case common.DaemonSetKind:
+		status, err := daemonSetToTargetStatus(unstructuredObj, desiredRevision)
+		if err != nil {
+			return nil, err
+		}
+		return &TargetInfo{ClusterName: clusterName, Status: *status, DesiredReplicas: desiredReplicas}, nil
+	}
+
 	replicas, err := utilunstructured.GetInt64FromPath(unstructuredObj, typeConfig.Spec.PathDefinition.ReplicasSpec, nil)
 	if err != nil || replicas == nil {
 		return nil, errors.Errorf("failed to retrieve replicas, err: %v", err)
@@ -446,14 +522,107 @@ func unstructuredObjToTargetInfo(clusterName string, unstructuredObj *unstructur
 	return t, nil
 }
 
+// statefulSetToTargetStatus returns the status of a StatefulSet in a cluster. StatefulSets update their pods in place
+// without surging, so the unavailable pods that are allowed by the current partition are taken as its maxUnavailable.
+func statefulSetToTargetStatus(unstructuredObj *unstructured.Unstructured, desiredRevision string) (*TargetStatus, error) {
+	statefulSet := &appsv1.StatefulSet{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj.Object, statefulSet); err != nil {
+		return nil, errors.Wrap(err, "failed to convert statefulset")
+	}
+	updated, err := isTemplateUpdated(unstructuredObj, desiredRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+	var partition int32
+	if rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil {
+		partition = *rollingUpdate.Partition
+	}
+
+	// The available replicas are only reported by recent versions of Kubernetes, so ready replicas are used instead.
+	// The updated replicas are assumed to be the unavailable ones, since they are the ones being restarted.
+	unavailable := Int32Max(statefulSet.Status.Replicas-statefulSet.Status.ReadyReplicas, 0)
+	currentNewReplicas := statefulSet.Status.UpdatedReplicas
+	currentNewAvailableReplicas := Int32Max(currentNewReplicas-unavailable, 0)
+
+	status := &TargetStatus{
+		Replicas:                    replicas,
+		ActualReplicas:              statefulSet.Status.Replicas,
+		AvailableReplicas:           statefulSet.Status.ReadyReplicas,
+		CurrentNewReplicas:          currentNewReplicas,
+		CurrentNewAvailableReplicas: currentNewAvailableReplicas,
+		Updated:                     updated,
+		MaxUnavailable:              Int32Max(replicas-partition-currentNewAvailableReplicas, 0),
+	}
+	if updated {
+		status.UpdatedReplicas, status.UpdatedAvailableReplicas = currentNewReplicas, currentNewAvailableReplicas
+	}
+	return status, nil
+}
+
+// daemonSetToTargetStatus returns the status of a DaemonSet in a cluster. The replicas of a DaemonSet are the number
+// of nodes that should run its pods.
+func daemonSetToTargetStatus(unstructuredObj *unstructured.Unstructured, desiredRevision string) (*TargetStatus, error) {
+	daemonSet := &appsv1.DaemonSet{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj.Object, daemonSet); err != nil {
+		return nil, errors.Wrap(err, "failed to convert daemonset")
+	}
+	updated, err := isTemplateUpdated(unstructuredObj, desiredRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := daemonSet.Status.DesiredNumberScheduled
+	maxSurgePath, maxUnavailablePath := GetFencepostPaths(common.DaemonSetKind)
+	maxSurge, maxUnavailable, err := RetrieveFencepost(unstructuredObj, maxSurgePath, maxUnavailablePath, replicas)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve fencepost")
+	}
+
+	// The updated pods are assumed to be the unavailable ones, since they are the ones being rolled out.
+	currentNewReplicas := daemonSet.Status.UpdatedNumberScheduled
+	currentNewAvailableReplicas := Int32Max(currentNewReplicas-daemonSet.Status.NumberUnavailable, 0)
+
+	status := &TargetStatus{
+		Replicas:                    replicas,
+		ActualReplicas:              daemonSet.Status.CurrentNumberScheduled,
+		AvailableReplicas:           daemonSet.Status.NumberAvailable,
+		CurrentNewReplicas:          currentNewReplicas,
+		CurrentNewAvailableReplicas: currentNewAvailableReplicas,
+		Updated:                     updated,
+		MaxSurge:                    maxSurge,
+		MaxUnavailable:              maxUnavailable,
+	}
+	if updated {
+		status.UpdatedReplicas, status.UpdatedAvailableReplicas = currentNewReplicas, currentNewAvailableReplicas
+	}
+	return status, nil
+}
+
+// isTemplateUpdated returns whether the template of the object in a cluster is at the desired revision.
+func isTemplateUpdated(unstructuredObj *unstructured.Unstructured, desiredRevision string) (bool, error) {
+	revision, ok := unstructuredObj.GetAnnotations()[common.CurrentRevisionAnnotation]
+	if !ok {
+		return false, errors.Errorf("failed to retrieve annotation %s", common.CurrentRevisionAnnotation)
+	}
+	return revision == desiredRevision, nil
+}
+
 type RolloutPlanner struct {
 	typeConfig     *fedcorev1a1.FederatedTypeConfig
+	kind           string
 	Key            string
 	Targets        Targets
 	MaxSurge       int32
 	MaxUnavailable int32
-	Replicas       int32
-	Revision       string
+	// Partition is the partition of the template of a StatefulSet, below which no partition is planned.
+	Partition int32
+	Replicas  int32
+	Revision  string
 }
 
 func NewRolloutPlanner(
@@ -462,13 +631,21 @@ func NewRolloutPlanner(
 	federatedResource *unstructured.Unstructured,
 	replicas int32,
 ) (*RolloutPlanner, error) {
+	kind := typeConfig.GetTargetType().Kind
 	pathPrefix := []string{common.SpecField, common.TemplateField}
-	maxSurgePath := append(pathPrefix, MaxSurgePathSlice...)
-	maxUnavailablePath := append(pathPrefix, MaxUnavailablePathSlice...)
+	maxSurgePath, maxUnavailablePath := GetFencepostPaths(kind)
+	if maxSurgePath != nil {
+		maxSurgePath = append(append([]string{}, pathPrefix...), maxSurgePath...)
+	}
+	maxUnavailablePath = append(append([]string{}, pathPrefix...), maxUnavailablePath...)
 	maxSurge, maxUnavailable, err := RetrieveFencepost(federatedResource, maxSurgePath, maxUnavailablePath, replicas)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to retrieve maxSurge or maxUnavailable from federated resource")
 	}
+	partition, _, err := unstructured.NestedInt64(federatedResource.Object, append(pathPrefix, PartitionPathSlice...)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve partition from federated resource")
+	}
 	desiredRevision, ok := federatedResource.GetAnnotations()[common.CurrentRevisionAnnotation]
 	if !ok {
 		return nil, errors.Errorf(
@@ -478,9 +655,11 @@ func NewRolloutPlanner(
 	}
 	return &RolloutPlanner{
 		typeConfig:     typeConfig,
+		kind:           kind,
 		Key:            key,
 		MaxSurge:       maxSurge,
 		MaxUnavailable: maxUnavailable,
+		Partition:      int32(partition),
 		Replicas:       replicas,
 		Revision:       desiredRevision,
 	}, nil
@@ -691,9 +870,38 @@ func (p *RolloutPlanner) Plan() RolloutPlans {
 		klog.Errorf("Failed to generate rollout plan for %s: %v. Current status: %s", p.Key, err, p)
 		return RolloutPlans{}
 	}
+	if p.kind == common.StatefulSetKind {
+		p.planPartitions(plans)
+	}
 	return plans
 }
 
+// planPartitions converts the planned fenceposts of StatefulSets to partitions. The partition of a StatefulSet
+// allows its updated and available pods, plus the planned number of unavailable pods, to be updated. Since pods are
+// updated from the highest ordinal, these are exactly the pods above the partition.
+func (p *RolloutPlanner) planPartitions(plans RolloutPlans) {
+	for _, t := range p.Targets {
+		plan, ok := plans[t.ClusterName]
+		if !ok || plan == nil || plan.OnlyPatchReplicas {
+			continue
+		}
+		if plan.MaxUnavailable == nil {
+			// The update is completed, so the partition of the template is used.
+			plan.MaxSurge = nil
+			continue
+		}
+
+		replicas := t.Status.Replicas
+		if plan.Replicas != nil {
+			replicas = *plan.Replicas
+		}
+		partition := replicas - t.Status.UpdatedAvailableReplicas - *plan.MaxUnavailable
+		partition = Int32Min(Int32Max(partition, p.Partition), replicas)
+		plan.Partition = &partition
+		plan.MaxSurge, plan.MaxUnavailable = nil, nil
+	}
+}
+
 func sortTargets(targets []*TargetInfo) ([]*TargetInfo, []*TargetInfo, []*TargetInfo) {
 	// sort the list to first update the targets that are already in update process
 	sort.Slice(targets, func(i, j int) bool {
@@ -757,30 +965,17 @@ func resolveFenceposts(maxSurge, maxUnavailable *intstrutil.IntOrString, desired
 	return int32(surge), int32(unavailable), nil
 }
 
+// RetrieveFencepost returns the maxSurge and maxUnavailable of the object resolved against the replicas. A nil
+// maxSurgePath means that the workload cannot surge, e.g. a StatefulSet. If both resolve to zero, maxUnavailable is
+// set to one, which is also the default for StatefulSets and DaemonSets.
 func RetrieveFencepost(unstructuredObj *unstructured.Unstructured, maxSurgePath []string, maxUnavailablePath []string,
 	replicas int32,
 ) (int32, int32, error) {
-	var maxSurge, maxUnavailable *intstrutil.IntOrString
-	if ms, ok, err := unstructured.NestedString(unstructuredObj.Object, maxSurgePath...); ok && err == nil {
-		maxSurge = &intstrutil.IntOrString{Type: intstrutil.String, StrVal: ms}
-	} else {
-		if ms, ok, err2 := unstructured.NestedInt64(unstructuredObj.Object, maxSurgePath...); ok && err2 == nil {
-			maxSurge = &intstrutil.IntOrString{Type: intstrutil.Int, IntVal: int32(ms)}
-		} else {
-			klog.V(4).Infof("Failed to retrieve maxSurge from %s/%s: %v, %v",
-				unstructuredObj.GetNamespace(), unstructuredObj.GetName(), err, err2)
-		}
-	}
-	if mu, ok, err := unstructured.NestedString(unstructuredObj.Object, maxUnavailablePath...); ok && err == nil {
-		maxUnavailable = &intstrutil.IntOrString{Type: intstrutil.String, StrVal: mu}
-	} else {
-		if mu, ok, err2 := unstructured.NestedInt64(unstructuredObj.Object, maxUnavailablePath...); ok && err2 == nil {
-			maxUnavailable = &intstrutil.IntOrString{Type: intstrutil.Int, IntVal: int32(mu)}
-		} else {
-			klog.V(4).Infof("Failed to retrieve maxUnavailable from %s/%s: %v, %v",
-				unstructuredObj.GetNamespace(), unstructuredObj.GetName(), err, err2)
-		}
+	var maxSurge *intstrutil.IntOrString
+	if len(maxSurgePath) > 0 {
+		maxSurge = retrieveIntOrString(unstructuredObj, maxSurgePath, "maxSurge")
 	}
+	maxUnavailable := retrieveIntOrString(unstructuredObj, maxUnavailablePath, "maxUnavailable")
 
 	ms, mu, err := resolveFenceposts(maxSurge, maxUnavailable, replicas)
 	if err != nil {
@@ -795,6 +990,20 @@ func RetrieveFencepost(unstructuredObj *unstructured.Unstructured, maxSurgePath
 	return ms, mu, nil
 }
 
+func retrieveIntOrString(unstructuredObj *unstructured.Unstructured, path []string, field string) *intstrutil.IntOrString {
+	s, ok, err := unstructured.NestedString(unstructuredObj.Object, path...)
+	if ok && err == nil {
+		return &intstrutil.IntOrString{Type: intstrutil.String, StrVal: s}
+	}
+	i, ok, err2 := unstructured.NestedInt64(unstructuredObj.Object, path...)
+	if ok && err2 == nil {
+		return &intstrutil.IntOrString{Type: intstrutil.Int, IntVal: int32(i)}
+	}
+	klog.V(4).Infof("Failed to retrieve %s from %s/%s: %v, %v",
+		field, unstructuredObj.GetNamespace(), unstructuredObj.GetName(), err, err2)
+	return nil
+}
+
 func retrieveNewReplicaSetInfo(unstructuredObj *unstructured.Unstructured) (int32, int32, error) {
 	ann, ok := unstructuredObj.GetAnnotations()[LatestReplicasetReplicasAnnotation]
 	if !ok || ann == "" {
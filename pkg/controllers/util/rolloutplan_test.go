@@ -19,6 +19,15 @@ package util
 import (
 	"reflect"
 	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
+
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 )
 
 type PlanTestSuit struct {
@@ -148,9 +157,9 @@ func TestPlanWholeProcessWithMaxUnavailable(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(5), ptr(0), ptr(5), false},
-		"c": {ptr(0), nil, nil, false},
-		"b": {ptr(15), nil, nil, true},
+		"a": {ptr(5), ptr(0), ptr(5), false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"b": {ptr(15), nil, nil, true, nil},
 	}
 	tests = append(tests, s)
 
@@ -165,10 +174,10 @@ func TestPlanWholeProcessWithMaxUnavailable(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(5), nil, nil, false},
-		"c": {ptr(0), nil, nil, false},
-		"b": {ptr(10), nil, nil, true},
-		"d": {nil, ptr(5), ptr(0), false},
+		"a": {ptr(5), nil, nil, false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"b": {ptr(10), nil, nil, true, nil},
+		"d": {nil, ptr(5), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -183,10 +192,10 @@ func TestPlanWholeProcessWithMaxUnavailable(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(5), nil, nil, false},
-		"c": {ptr(0), nil, nil, false},
-		"d": {nil, ptr(5), ptr(0), false},
-		"b": {nil, ptr(5), ptr(0), false},
+		"a": {ptr(5), nil, nil, false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"d": {nil, ptr(5), ptr(0), false, nil},
+		"b": {nil, ptr(5), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -201,11 +210,11 @@ func TestPlanWholeProcessWithMaxUnavailable(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(5), nil, nil, false},
-		"c": {ptr(0), nil, nil, false},
-		"b": {nil, ptr(5), ptr(0), false},
-		"d": {nil, ptr(1), ptr(0), false},
-		"f": {nil, ptr(5), ptr(0), false},
+		"a": {ptr(5), nil, nil, false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"b": {nil, ptr(5), ptr(0), false, nil},
+		"d": {nil, ptr(1), ptr(0), false, nil},
+		"f": {nil, ptr(5), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -220,11 +229,11 @@ func TestPlanWholeProcessWithMaxUnavailable(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(15), nil, nil, false},
-		"c": {ptr(0), nil, nil, false},
-		"b": {nil, ptr(1), ptr(0), false},
-		"d": {nil, nil, nil, false},
-		"f": {nil, ptr(1), ptr(0), false},
+		"a": {ptr(15), nil, nil, false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"b": {nil, ptr(1), ptr(0), false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, ptr(1), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -239,12 +248,12 @@ func TestPlanWholeProcessWithMaxUnavailable(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"c": {ptr(0), nil, nil, false},
-		"e": {ptr(0), ptr(0), ptr(2), false},
-		"a": {nil, nil, nil, false},
-		"b": {nil, nil, nil, false},
-		"d": {nil, nil, nil, false},
-		"f": {nil, nil, nil, false},
+		"c": {ptr(0), nil, nil, false, nil},
+		"e": {ptr(0), ptr(0), ptr(2), false, nil},
+		"a": {nil, nil, nil, false, nil},
+		"b": {nil, nil, nil, false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -258,11 +267,11 @@ func TestPlanWholeProcessWithMaxUnavailable(t *testing.T) {
 		newTarget("b", true, 10, 10, 10, 10, 0, 10),
 	}
 	s.Plans = RolloutPlans{
-		"c": {nil, nil, nil, false},
-		"a": {nil, nil, nil, false},
-		"b": {nil, nil, nil, false},
-		"d": {nil, nil, nil, false},
-		"f": {nil, nil, nil, false},
+		"c": {nil, nil, nil, false, nil},
+		"a": {nil, nil, nil, false, nil},
+		"b": {nil, nil, nil, false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -276,11 +285,11 @@ func TestPlanWholeProcessWithMaxUnavailable(t *testing.T) {
 		newTarget("b", true, 10, 10, 10, 10, 0, 10),
 	}
 	s.Plans = RolloutPlans{
-		"a": {nil, nil, nil, false},
-		"b": {nil, nil, nil, false},
-		"c": {nil, nil, nil, false},
-		"d": {nil, nil, nil, false},
-		"f": {nil, nil, nil, false},
+		"a": {nil, nil, nil, false, nil},
+		"b": {nil, nil, nil, false, nil},
+		"c": {nil, nil, nil, false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -310,9 +319,9 @@ func TestPlanWholeProcessWithBoth(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(5), ptr(5), ptr(5), false},
-		"c": {ptr(0), nil, nil, false},
-		"b": {ptr(15), nil, nil, true},
+		"a": {ptr(5), ptr(5), ptr(5), false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"b": {ptr(15), nil, nil, true, nil},
 	}
 	tests = append(tests, s)
 
@@ -327,10 +336,10 @@ func TestPlanWholeProcessWithBoth(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(5), nil, nil, false},
-		"c": {ptr(0), nil, nil, false},
-		"b": {ptr(10), nil, nil, true},
-		"d": {nil, ptr(10), ptr(0), false},
+		"a": {ptr(5), nil, nil, false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"b": {ptr(10), nil, nil, true, nil},
+		"d": {nil, ptr(10), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -345,11 +354,11 @@ func TestPlanWholeProcessWithBoth(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(5), nil, nil, false},
-		"c": {ptr(0), nil, nil, false},
-		"d": {nil, ptr(1), ptr(0), false},
-		"b": {nil, ptr(10), ptr(0), false},
-		"f": {nil, ptr(5), ptr(0), false},
+		"a": {ptr(5), nil, nil, false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"d": {nil, ptr(1), ptr(0), false, nil},
+		"b": {nil, ptr(10), ptr(0), false, nil},
+		"f": {nil, ptr(5), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -364,11 +373,11 @@ func TestPlanWholeProcessWithBoth(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(15), nil, nil, false},
-		"c": {ptr(5), nil, nil, false},
-		"b": {nil, ptr(1), ptr(0), false},
-		"d": {nil, nil, nil, false},
-		"f": {nil, ptr(1), ptr(0), false},
+		"a": {ptr(15), nil, nil, false, nil},
+		"c": {ptr(5), nil, nil, false, nil},
+		"b": {nil, ptr(1), ptr(0), false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, ptr(1), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -383,12 +392,12 @@ func TestPlanWholeProcessWithBoth(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"e": {ptr(0), ptr(0), ptr(5), false},
-		"a": {nil, nil, nil, false},
-		"b": {nil, nil, nil, false},
-		"c": {nil, nil, nil, false},
-		"d": {nil, nil, nil, false},
-		"f": {nil, nil, nil, false},
+		"e": {ptr(0), ptr(0), ptr(5), false, nil},
+		"a": {nil, nil, nil, false, nil},
+		"b": {nil, nil, nil, false, nil},
+		"c": {nil, nil, nil, false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -402,11 +411,11 @@ func TestPlanWholeProcessWithBoth(t *testing.T) {
 		newTarget("b", true, 10, 10, 10, 10, 5, 10),
 	}
 	s.Plans = RolloutPlans{
-		"a": {nil, nil, nil, false},
-		"b": {nil, nil, nil, false},
-		"c": {nil, nil, nil, false},
-		"d": {nil, nil, nil, false},
-		"f": {nil, nil, nil, false},
+		"a": {nil, nil, nil, false, nil},
+		"b": {nil, nil, nil, false, nil},
+		"c": {nil, nil, nil, false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -436,7 +445,7 @@ func TestPlanWholeProcessWithSurge(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(5), ptr(5), ptr(0), false},
+		"a": {ptr(5), ptr(5), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -451,9 +460,9 @@ func TestPlanWholeProcessWithSurge(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(5), nil, nil, false},
-		"c": {ptr(0), nil, nil, false},
-		"d": {nil, ptr(5), ptr(0), false},
+		"a": {ptr(5), nil, nil, false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"d": {nil, ptr(5), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -468,9 +477,9 @@ func TestPlanWholeProcessWithSurge(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(5), nil, nil, false},
-		"c": {ptr(0), nil, nil, false},
-		"d": {nil, ptr(5), ptr(0), false},
+		"a": {ptr(5), nil, nil, false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"d": {nil, ptr(5), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -485,10 +494,10 @@ func TestPlanWholeProcessWithSurge(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(5), nil, nil, false},
-		"c": {ptr(0), nil, nil, false},
-		"d": {nil, nil, nil, false},
-		"f": {nil, ptr(5), ptr(0), false},
+		"a": {ptr(5), nil, nil, false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, ptr(5), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -503,10 +512,10 @@ func TestPlanWholeProcessWithSurge(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(10), nil, nil, false},
-		"c": {ptr(0), nil, nil, false},
-		"d": {nil, nil, nil, false},
-		"f": {nil, nil, nil, false},
+		"a": {ptr(10), nil, nil, false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -521,11 +530,11 @@ func TestPlanWholeProcessWithSurge(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(10), nil, nil, false},
-		"c": {ptr(0), nil, nil, false},
-		"b": {ptr(17), nil, nil, true},
-		"d": {nil, nil, nil, false},
-		"f": {nil, nil, nil, false},
+		"a": {ptr(10), nil, nil, false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"b": {ptr(17), nil, nil, true, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -540,11 +549,11 @@ func TestPlanWholeProcessWithSurge(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(13), nil, nil, false},
-		"c": {ptr(0), nil, nil, false},
-		"b": {ptr(15), nil, nil, true},
-		"d": {nil, nil, nil, false},
-		"f": {nil, nil, nil, false},
+		"a": {ptr(13), nil, nil, false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"b": {ptr(15), nil, nil, true, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -559,11 +568,11 @@ func TestPlanWholeProcessWithSurge(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(15), nil, nil, false},
-		"c": {ptr(0), nil, nil, false},
-		"b": {ptr(12), nil, nil, true},
-		"d": {nil, nil, nil, false},
-		"f": {nil, nil, nil, false},
+		"a": {ptr(15), nil, nil, false, nil},
+		"c": {ptr(0), nil, nil, false, nil},
+		"b": {ptr(12), nil, nil, true, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -578,11 +587,11 @@ func TestPlanWholeProcessWithSurge(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"c": {ptr(3), nil, nil, false},
-		"b": {ptr(10), nil, nil, true},
-		"a": {nil, nil, nil, false},
-		"d": {nil, nil, nil, false},
-		"f": {nil, nil, nil, false},
+		"c": {ptr(3), nil, nil, false, nil},
+		"b": {ptr(10), nil, nil, true, nil},
+		"a": {nil, nil, nil, false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -597,12 +606,12 @@ func TestPlanWholeProcessWithSurge(t *testing.T) {
 		newTarget("e", false, 5, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"c": {ptr(3), nil, nil, false},
-		"e": {ptr(2), nil, nil, true},
-		"a": {nil, nil, nil, false},
-		"d": {nil, nil, nil, false},
-		"f": {nil, nil, nil, false},
-		"b": {nil, ptr(2), ptr(0), false},
+		"c": {ptr(3), nil, nil, false, nil},
+		"e": {ptr(2), nil, nil, true, nil},
+		"a": {nil, nil, nil, false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, nil, nil, false, nil},
+		"b": {nil, ptr(2), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -617,11 +626,11 @@ func TestPlanWholeProcessWithSurge(t *testing.T) {
 		newTarget("e", false, 2, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"c": {ptr(3), nil, nil, false},
-		"a": {nil, nil, nil, false},
-		"b": {nil, ptr(5), ptr(0), false},
-		"d": {nil, nil, nil, false},
-		"f": {nil, nil, nil, false},
+		"c": {ptr(3), nil, nil, false, nil},
+		"a": {nil, nil, nil, false, nil},
+		"b": {nil, ptr(5), ptr(0), false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -636,11 +645,11 @@ func TestPlanWholeProcessWithSurge(t *testing.T) {
 		newTarget("e", false, 2, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"c": {ptr(5), nil, nil, false},
-		"a": {nil, nil, nil, false},
-		"b": {nil, ptr(3), ptr(0), false},
-		"d": {nil, nil, nil, false},
-		"f": {nil, nil, nil, false},
+		"c": {ptr(5), nil, nil, false, nil},
+		"a": {nil, nil, nil, false, nil},
+		"b": {nil, ptr(3), ptr(0), false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"f": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -693,9 +702,9 @@ func TestPlanScale(t *testing.T) {
 		newTargetWithActualInfo("k", true, 0, 1, 0, 0, 0, 0, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"c": {nil, nil, nil, false},
-		"k": {nil, nil, nil, false},
-		"b": {nil, nil, nil, false},
+		"c": {nil, nil, nil, false, nil},
+		"k": {nil, nil, nil, false, nil},
+		"b": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -754,7 +763,7 @@ func TestPlanUnexceptedCases(t *testing.T) {
 		newTargetWithActualInfo("b", false, 300, 300, 0, 0, 300, 100, 0, 1),
 	}
 	s.Plans = RolloutPlans{
-		"b": {nil, ptr(0), ptr(1), false},
+		"b": {nil, ptr(0), ptr(1), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -765,7 +774,7 @@ func TestPlanUnexceptedCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 300, 600, 0, 0, 300, 300, 0, 15),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(400), ptr(0), ptr(35), false},
+		"b": {ptr(400), ptr(0), ptr(35), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -785,7 +794,7 @@ func TestPlanUnexceptedCases(t *testing.T) {
 		newTargetWithActualInfo("b", false, 300, 300, 0, 0, 300, 300, 0, 1),
 	}
 	s.Plans = RolloutPlans{
-		"a": {nil, ptr(10), ptr(15), false},
+		"a": {nil, ptr(10), ptr(15), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -796,7 +805,7 @@ func TestPlanUnexceptedCases(t *testing.T) {
 		newTargetWithActualInfo("b", false, 300, 300, 0, 0, 200, 200, 0, 1),
 	}
 	s.Plans = RolloutPlans{
-		"b": {nil, ptr(0), ptr(1), false},
+		"b": {nil, ptr(0), ptr(1), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -807,8 +816,8 @@ func TestPlanUnexceptedCases(t *testing.T) {
 		newTargetWithActualInfo("b", false, 300, 300, 0, 0, 298, 295, 0, 1),
 	}
 	s.Plans = RolloutPlans{
-		"a": {nil, ptr(0), ptr(20), false},
-		"b": {nil, ptr(0), ptr(5), false},
+		"a": {nil, ptr(0), ptr(20), false, nil},
+		"b": {nil, ptr(0), ptr(5), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -819,7 +828,7 @@ func TestPlanUnexceptedCases(t *testing.T) {
 		newTargetWithActualInfo("b", false, 300, 300, 0, 0, 300, 300, 0, 1),
 	}
 	s.Plans = RolloutPlans{
-		"a": {nil, ptr(0), ptr(25), false},
+		"a": {nil, ptr(0), ptr(25), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -830,7 +839,7 @@ func TestPlanUnexceptedCases(t *testing.T) {
 		newTargetWithActualInfo("b", true, 102, 400, 100, 71, 100, 71, 0, 5),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(102), ptr(0), ptr(31), false},
+		"b": {ptr(102), ptr(0), ptr(31), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -841,7 +850,7 @@ func TestPlanUnexceptedCases(t *testing.T) {
 		newTargetWithActualInfo("b", false, 300, 300, 0, 0, 300, 300, 0, 10),
 	}
 	s.Plans = RolloutPlans{
-		"a": {nil, ptr(0), ptr(25), false},
+		"a": {nil, ptr(0), ptr(25), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -853,7 +862,7 @@ func TestPlanUnexceptedCases(t *testing.T) {
 		newTargetWithActualInfo("b", false, 300, 400, 0, 0, 300, 300, 0, 10),
 	}
 	s.Plans = RolloutPlans{
-		"a": {ptr(200), ptr(0), ptr(25), false},
+		"a": {ptr(200), ptr(0), ptr(25), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -864,8 +873,8 @@ func TestPlanUnexceptedCases(t *testing.T) {
 		newTargetWithActualInfo("b", false, 300, 400, 0, 0, 300, 300, 0, 10),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(300), ptr(0), ptr(25), false},
-		"a": {ptr(200), ptr(0), ptr(25), false},
+		"b": {ptr(300), ptr(0), ptr(25), false, nil},
+		"a": {ptr(200), ptr(0), ptr(25), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -876,8 +885,8 @@ func TestPlanUnexceptedCases(t *testing.T) {
 		newTargetWithActualInfo("b", true, 300, 400, 25, 25, 300, 300, 0, 25),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(300), ptr(25), ptr(24), false},
-		"a": {ptr(175), ptr(0), ptr(1), false},
+		"b": {ptr(300), ptr(25), ptr(24), false, nil},
+		"a": {ptr(175), ptr(0), ptr(1), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -902,8 +911,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 380, 300, 380, 292, 380, 292, 0, 19),
 	}
 	s.Plans = RolloutPlans{
-		"b": {nil, nil, nil, false},
-		"c": {nil, nil, nil, false},
+		"b": {nil, nil, nil, false, nil},
+		"c": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -914,8 +923,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 290, 290, 290, 290, 290, 290, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"c": {nil, nil, nil, false},
-		"b": {nil, nil, nil, false},
+		"c": {nil, nil, nil, false, nil},
+		"b": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -926,8 +935,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 290, 290, 290, 290, 290, 290, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"b": {nil, nil, nil, false},
-		"c": {nil, nil, nil, false},
+		"b": {nil, nil, nil, false, nil},
+		"c": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -938,7 +947,7 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 290, 290, 0, 0, 290, 290, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"b": {nil, ptr(0), ptr(25), false},
+		"b": {nil, ptr(0), ptr(25), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -949,7 +958,7 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 290, 290, 0, 0, 290, 290, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"b": {nil, ptr(0), ptr(25), false},
+		"b": {nil, ptr(0), ptr(25), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -960,7 +969,7 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 290, 290, 0, 0, 290, 290, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"b": {nil, ptr(0), ptr(25), false},
+		"b": {nil, ptr(0), ptr(25), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -971,8 +980,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 290, 290, 0, 0, 290, 290, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"b": {nil, nil, nil, false},
-		"c": {nil, ptr(0), ptr(7), false},
+		"b": {nil, nil, nil, false, nil},
+		"c": {nil, ptr(0), ptr(7), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -983,8 +992,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 290, 290, 16, 0, 290, 274, 0, 25),
 	}
 	s.Plans = RolloutPlans{
-		"b": {nil, nil, nil, false},
-		"c": {nil, ptr(0), ptr(25), false},
+		"b": {nil, nil, nil, false, nil},
+		"c": {nil, ptr(0), ptr(25), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -995,8 +1004,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 290, 190, 0, 0, 290, 281, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(220), ptr(0), ptr(16), false},
-		"c": {ptr(281), nil, nil, true},
+		"b": {ptr(220), ptr(0), ptr(16), false, nil},
+		"c": {ptr(281), nil, nil, true, nil},
 	}
 	tests = append(tests, s)
 
@@ -1007,8 +1016,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 290, 190, 0, 0, 290, 281, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(220), ptr(0), ptr(16), false},
-		"c": {ptr(281), nil, nil, true},
+		"b": {ptr(220), ptr(0), ptr(16), false, nil},
+		"c": {ptr(281), nil, nil, true, nil},
 	}
 	tests = append(tests, s)
 
@@ -1019,8 +1028,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithAllInfo("c", false, 290, 190, 0, 0, 290, 282, 290, 282, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(220), ptr(0), ptr(17), false},
-		"c": {ptr(282), nil, nil, true},
+		"b": {ptr(220), ptr(0), ptr(17), false, nil},
+		"c": {ptr(282), nil, nil, true, nil},
 	}
 	tests = append(tests, s)
 
@@ -1031,8 +1040,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 290, 190, 0, 0, 290, 283, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(220), nil, nil, false},
-		"c": {ptr(268), nil, nil, true},
+		"b": {ptr(220), nil, nil, false, nil},
+		"c": {ptr(268), nil, nil, true, nil},
 	}
 	tests = append(tests, s)
 
@@ -1043,8 +1052,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 268, 190, 0, 0, 268, 268, 0, 13),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(242), nil, nil, false},
-		"c": {ptr(265), nil, nil, true},
+		"b": {ptr(242), nil, nil, false, nil},
+		"c": {ptr(265), nil, nil, true, nil},
 	}
 	tests = append(tests, s)
 
@@ -1055,8 +1064,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 263, 190, 0, 0, 263, 263, 0, 13),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(247), nil, nil, false},
-		"c": {ptr(243), nil, nil, true},
+		"b": {ptr(247), nil, nil, false, nil},
+		"c": {ptr(243), nil, nil, true, nil},
 	}
 	tests = append(tests, s)
 
@@ -1067,7 +1076,7 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 243, 190, 0, 0, 243, 243, 0, 12),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(262), ptr(5), ptr(5), false},
+		"b": {ptr(262), ptr(5), ptr(5), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1078,8 +1087,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 203, 190, 0, 0, 203, 203, 0, 10),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(307), nil, nil, false},
-		"c": {ptr(190), ptr(0), ptr(2), false},
+		"b": {ptr(307), nil, nil, false, nil},
+		"c": {ptr(190), ptr(0), ptr(2), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1090,8 +1099,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithAllInfo("c", true, 190, 190, 0, 0, 203, 203, 203, 203, 0, 2),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(307), ptr(0), ptr(10), false},
-		"c": {nil, ptr(13), ptr(2), false},
+		"b": {ptr(307), ptr(0), ptr(10), false, nil},
+		"c": {nil, ptr(13), ptr(2), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1102,8 +1111,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 190, 190, 19, 4, 203, 188, 13, 12),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(307), nil, nil, false},
-		"c": {nil, ptr(13), ptr(12), false},
+		"b": {ptr(307), nil, nil, false, nil},
+		"c": {nil, ptr(13), ptr(12), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1114,8 +1123,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 190, 190, 190, 173, 195, 178, 13, 12),
 	}
 	s.Plans = RolloutPlans{
-		"b": {nil, nil, nil, false},
-		"c": {nil, nil, nil, false},
+		"b": {nil, nil, nil, false, nil},
+		"c": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1128,7 +1137,7 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 190, 290, 0, 0, 190, 190, 0, 9),
 	}
 	s.Plans = RolloutPlans{
-		"c": {ptr(190), ptr(100), ptr(0), false},
+		"c": {ptr(190), ptr(100), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1139,8 +1148,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 190, 190, 190, 182, 190, 182, 0, 9),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(310), ptr(10), ptr(7), false},
-		"c": {nil, nil, nil, false},
+		"b": {ptr(310), ptr(10), ptr(7), false, nil},
+		"c": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1157,8 +1166,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("h", false, 15, 15, 0, 0, 15, 15, 0, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {nil, ptr(0), ptr(2), false},
-		"b": {nil, nil, nil, false},
+		"a": {nil, ptr(0), ptr(2), false, nil},
+		"b": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1169,8 +1178,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 190, 290, 190, 190, 190, 190, 0, 9),
 	}
 	s.Plans = RolloutPlans{
-		"c": {ptr(190), nil, nil, false},
-		"b": {ptr(295), nil, nil, false},
+		"c": {ptr(190), nil, nil, false, nil},
+		"b": {ptr(295), nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1181,8 +1190,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 190, 290, 190, 190, 190, 190, 0, 9),
 	}
 	s.Plans = RolloutPlans{
-		"c": {ptr(215), nil, nil, false},
-		"b": {ptr(295), nil, nil, false},
+		"c": {ptr(215), nil, nil, false, nil},
+		"b": {ptr(295), nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1193,8 +1202,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 267, 290, 267, 267, 267, 267, 0, 13),
 	}
 	s.Plans = RolloutPlans{
-		"b": {nil, nil, nil, false},
-		"c": {nil, nil, nil, false},
+		"b": {nil, nil, nil, false, nil},
+		"c": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1205,8 +1214,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 290, 190, 0, 0, 290, 275, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(220), ptr(0), ptr(10), false},
-		"c": {ptr(275), nil, nil, true},
+		"b": {ptr(220), ptr(0), ptr(10), false, nil},
+		"c": {ptr(275), nil, nil, true, nil},
 	}
 	tests = append(tests, s)
 
@@ -1217,8 +1226,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 290, 190, 0, 0, 290, 275, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(220), nil, nil, false},
-		"c": {ptr(271), nil, nil, true},
+		"b": {ptr(220), nil, nil, false, nil},
+		"c": {ptr(271), nil, nil, true, nil},
 	}
 	tests = append(tests, s)
 
@@ -1229,8 +1238,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 317, 290, 0, 0, 317, 292, 0, 15),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(120), ptr(0), ptr(20), false},
-		"c": {ptr(290), nil, nil, true},
+		"b": {ptr(120), ptr(0), ptr(20), false, nil},
+		"c": {ptr(290), nil, nil, true, nil},
 	}
 	tests = append(tests, s)
 
@@ -1241,8 +1250,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 290, 290, 23, 4, 290, 271, 0, 20),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(120), nil, nil, false},
-		"c": {nil, ptr(100), ptr(0), false},
+		"b": {ptr(120), nil, nil, false, nil},
+		"c": {nil, ptr(100), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1253,8 +1262,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 290, 290, 265, 245, 290, 270, 0, 20),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(195), nil, nil, false},
-		"c": {nil, ptr(25), ptr(0), false},
+		"b": {ptr(195), nil, nil, false, nil},
+		"c": {nil, ptr(25), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1265,8 +1274,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 290, 190, 290, 279, 290, 279, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(220), nil, nil, false},
-		"c": {ptr(270), nil, nil, false},
+		"b": {ptr(220), nil, nil, false, nil},
+		"c": {ptr(270), nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1277,8 +1286,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 190, 290, 0, 0, 190, 190, 0, 9),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(120), ptr(120), ptr(0), false},
-		"c": {ptr(190), ptr(80), ptr(0), false},
+		"b": {ptr(120), ptr(120), ptr(0), false, nil},
+		"c": {ptr(190), ptr(80), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1289,8 +1298,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 190, 290, 96, 0, 286, 190, 94, 0),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(220), nil, nil, false},
-		"c": {ptr(194), ptr(94), ptr(0), false},
+		"b": {ptr(220), nil, nil, false, nil},
+		"c": {ptr(194), ptr(94), ptr(0), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1301,8 +1310,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 290, 90, 0, 0, 290, 281, 0, 14),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(220), ptr(0), ptr(16), false},
-		"c": {ptr(281), nil, nil, true},
+		"b": {ptr(220), ptr(0), ptr(16), false, nil},
+		"c": {ptr(281), nil, nil, true, nil},
 	}
 	tests = append(tests, s)
 
@@ -1315,8 +1324,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithAllInfo("c", false, 150, 150, 0, 0, 0, 0, 150, 150, 0, 20),
 	}
 	s.Plans = RolloutPlans{
-		"b": {nil, ptr(0), ptr(30), false},
-		"c": {nil, ptr(0), ptr(20), false},
+		"b": {nil, ptr(0), ptr(30), false, nil},
+		"c": {nil, ptr(0), ptr(20), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1327,7 +1336,7 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", true, 239, 180, 239, 189, 239, 189, 0, 23),
 	}
 	s.Plans = RolloutPlans{
-		"c": {ptr(189), nil, nil, false},
+		"c": {ptr(189), nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1339,8 +1348,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithActualInfo("c", false, 180, 280, 0, 0, 180, 173, 0, 18),
 	}
 	s.Plans = RolloutPlans{
-		"b": {ptr(320), ptr(0), ptr(43), false},
-		"c": {ptr(180), ptr(0), ptr(7), false},
+		"b": {ptr(320), ptr(0), ptr(43), false, nil},
+		"c": {ptr(180), ptr(0), ptr(7), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1355,12 +1364,12 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithAllInfo("h", false, 4, 4, 0, 0, 4, 1, 4, 1, 0, 1),
 	}
 	s.Plans = RolloutPlans{
-		"a": {nil, ptr(0), ptr(1), false},
-		"b": {nil, ptr(0), ptr(1), false},
-		"c": {nil, ptr(0), ptr(1), false},
-		"e": {nil, ptr(0), ptr(1), false},
-		"f": {nil, ptr(0), ptr(1), false},
-		"h": {nil, ptr(0), ptr(1), false},
+		"a": {nil, ptr(0), ptr(1), false, nil},
+		"b": {nil, ptr(0), ptr(1), false, nil},
+		"c": {nil, ptr(0), ptr(1), false, nil},
+		"e": {nil, ptr(0), ptr(1), false, nil},
+		"f": {nil, ptr(0), ptr(1), false, nil},
+		"h": {nil, ptr(0), ptr(1), false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1374,7 +1383,7 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithAllInfo("f", false, 2, 0, 0, 0, 2, 2, 2, 2, 0, 1),
 	}
 	s.Plans = RolloutPlans{
-		"d": {ptr(0), nil, nil, false},
+		"d": {ptr(0), nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1385,7 +1394,7 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithAllInfo("d", true, 0, 7, 0, 0, 0, 0, 0, 0, 0, 1),
 	}
 	s.Plans = RolloutPlans{
-		"d": {ptr(0), nil, nil, false},
+		"d": {ptr(0), nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1400,11 +1409,11 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithAllInfo("h", true, 4, 4, 4, 4, 4, 4, 4, 4, 1, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {nil, nil, nil, false},
-		"b": {nil, ptr(2), ptr(0), false},
-		"d": {nil, nil, nil, false},
-		"e": {nil, nil, nil, false},
-		"h": {nil, nil, nil, false},
+		"a": {nil, nil, nil, false, nil},
+		"b": {nil, ptr(2), ptr(0), false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"e": {nil, nil, nil, false, nil},
+		"h": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1419,12 +1428,12 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithAllInfo("h", true, 4, 4, 4, 4, 4, 4, 4, 4, 1, 0),
 	}
 	s.Plans = RolloutPlans{
-		"a": {nil, nil, nil, false},
-		"b": {nil, ptr(2), ptr(0), false},
-		"d": {nil, nil, nil, false},
-		"e": {nil, nil, nil, false},
-		"f": {nil, ptr(1), ptr(0), false},
-		"h": {nil, nil, nil, false},
+		"a": {nil, nil, nil, false, nil},
+		"b": {nil, ptr(2), ptr(0), false, nil},
+		"d": {nil, nil, nil, false, nil},
+		"e": {nil, nil, nil, false, nil},
+		"f": {nil, ptr(1), ptr(0), false, nil},
+		"h": {nil, nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1435,8 +1444,8 @@ func TestPlanActualCases(t *testing.T) {
 		newTargetWithAllInfo("e", true, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1),
 	}
 	s.Plans = RolloutPlans{
-		"a": {nil, ptr(1), ptr(0), false},
-		"e": {ptr(0), nil, nil, false},
+		"a": {nil, ptr(1), ptr(0), false, nil},
+		"e": {ptr(0), nil, nil, false, nil},
 	}
 	tests = append(tests, s)
 
@@ -1450,3 +1459,256 @@ func TestPlanActualCases(t *testing.T) {
 		})
 	}
 }
+
+func TestPlanStatefulSetPartitions(t *testing.T) {
+	var tests []PlanTestSuit
+
+	s := PlanTestSuit{Replicas: 6, MaxSurge: 0, MaxUnavailable: 1}
+	s.Name = "first cluster starts with one pod"
+	s.Targets = Targets{
+		newTargetWithAllInfo("a", false, 3, 3, 0, 0, 3, 3, 3, 3, 0, 0),
+		newTargetWithAllInfo("b", false, 3, 3, 0, 0, 3, 3, 3, 3, 0, 0),
+	}
+	s.Plans = RolloutPlans{
+		"a": {Partition: ptr(2)},
+	}
+	tests = append(tests, s)
+
+	s = PlanTestSuit{Replicas: 6, MaxSurge: 0, MaxUnavailable: 1}
+	s.Name = "partition waits for the updated pod to be available"
+	s.Targets = Targets{
+		newTargetWithAllInfo("a", true, 3, 3, 1, 0, 1, 0, 3, 2, 0, 1),
+		newTargetWithAllInfo("b", false, 3, 3, 0, 0, 3, 3, 3, 3, 0, 0),
+	}
+	s.Plans = RolloutPlans{
+		"a": {Partition: ptr(2)},
+	}
+	tests = append(tests, s)
+
+	s = PlanTestSuit{Replicas: 6, MaxSurge: 0, MaxUnavailable: 1}
+	s.Name = "partition advances once the updated pod is available"
+	s.Targets = Targets{
+		newTargetWithAllInfo("a", true, 3, 3, 1, 1, 1, 1, 3, 3, 0, 0),
+		newTargetWithAllInfo("b", false, 3, 3, 0, 0, 3, 3, 3, 3, 0, 0),
+	}
+	s.Plans = RolloutPlans{
+		"a": {Partition: ptr(1)},
+	}
+	tests = append(tests, s)
+
+	s = PlanTestSuit{Replicas: 6, MaxSurge: 0, MaxUnavailable: 1}
+	s.Name = "next cluster starts after the first one completes"
+	s.Targets = Targets{
+		newTargetWithAllInfo("a", true, 3, 3, 3, 3, 3, 3, 3, 3, 0, 0),
+		newTargetWithAllInfo("b", false, 3, 3, 0, 0, 3, 3, 3, 3, 0, 0),
+	}
+	s.Plans = RolloutPlans{
+		"a": {},
+		"b": {Partition: ptr(2)},
+	}
+	tests = append(tests, s)
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			planner := &RolloutPlanner{
+				kind:           common.StatefulSetKind,
+				Targets:        test.Targets,
+				MaxSurge:       test.MaxSurge,
+				MaxUnavailable: test.MaxUnavailable,
+				Replicas:       test.Replicas,
+			}
+			got := planner.Plan()
+			if !reflect.DeepEqual(got, test.Plans) {
+				t.Errorf("%s: got: %v, expected: %v", test.Name, got, test.Plans)
+			}
+		})
+	}
+
+	t.Run("partition of the template is respected", func(t *testing.T) {
+		planner := &RolloutPlanner{
+			kind: common.StatefulSetKind,
+			Targets: Targets{
+				newTargetWithAllInfo("a", true, 3, 3, 1, 1, 1, 1, 3, 3, 0, 0),
+			},
+			MaxSurge:       0,
+			MaxUnavailable: 1,
+			Partition:      2,
+			Replicas:       3,
+		}
+		got := planner.Plan()
+		expected := RolloutPlans{"a": {Partition: ptr(2)}}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("got: %v, expected: %v", got, expected)
+		}
+	})
+}
+
+func TestStatefulSetToTargetStatus(t *testing.T) {
+	statefulSet := &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: common.StatefulSetKind},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "sts",
+			Annotations: map[string]string{common.CurrentRevisionAnnotation: "rev-2"},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr(5),
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr(3)},
+			},
+		},
+		Status: appsv1.StatefulSetStatus{Replicas: 5, ReadyReplicas: 4, UpdatedReplicas: 2},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(statefulSet)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	status, err := statefulSetToTargetStatus(&unstructured.Unstructured{Object: obj}, "rev-2")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	expected := &TargetStatus{
+		Replicas:                    5,
+		ActualReplicas:              5,
+		AvailableReplicas:           4,
+		UpdatedReplicas:             2,
+		UpdatedAvailableReplicas:    1,
+		CurrentNewReplicas:          2,
+		CurrentNewAvailableReplicas: 1,
+		Updated:                     true,
+		MaxUnavailable:              1,
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("got: %+v, expected: %+v", status, expected)
+	}
+}
+
+func TestDaemonSetToTargetStatus(t *testing.T) {
+	maxUnavailable := intstrutil.FromString("20%")
+	daemonSet := &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: common.DaemonSetKind},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ds",
+			Annotations: map[string]string{common.CurrentRevisionAnnotation: "rev-1"},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+			},
+		},
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 10,
+			CurrentNumberScheduled: 10,
+			UpdatedNumberScheduled: 10,
+			NumberAvailable:        9,
+			NumberUnavailable:      1,
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(daemonSet)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	status, err := daemonSetToTargetStatus(&unstructured.Unstructured{Object: obj}, "rev-2")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	expected := &TargetStatus{
+		Replicas:                    10,
+		ActualReplicas:              10,
+		AvailableReplicas:           9,
+		CurrentNewReplicas:          10,
+		CurrentNewAvailableReplicas: 9,
+		MaxUnavailable:              2,
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("got: %+v, expected: %+v", status, expected)
+	}
+}
+
+func TestGetRolloutOverrides(t *testing.T) {
+	plans := RolloutPlans{
+		"a": {Replicas: ptr(3), MaxSurge: ptr(1), MaxUnavailable: ptr(0)},
+		"b": {Replicas: ptr(3), Partition: ptr(2)},
+	}
+
+	tests := []struct {
+		name        string
+		clusterName string
+		kind        string
+		expected    fedtypesv1a1.OverridePatches
+	}{
+		{
+			name:        "deployment",
+			clusterName: "a",
+			kind:        common.DeploymentKind,
+			expected: fedtypesv1a1.OverridePatches{
+				{Path: ReplicaPath, Value: int32(3)},
+				{Path: MaxSurgePath, Value: int32(1)},
+				{Path: MaxUnavailablePath, Value: int32(0)},
+			},
+		},
+		{
+			name:        "daemonset",
+			clusterName: "a",
+			kind:        common.DaemonSetKind,
+			expected: fedtypesv1a1.OverridePatches{
+				{Path: UpdateStrategyMaxSurgePath, Value: int32(1)},
+				{Path: UpdateStrategyMaxUnavailablePath, Value: int32(0)},
+			},
+		},
+		{
+			name:        "statefulset",
+			clusterName: "b",
+			kind:        common.StatefulSetKind,
+			expected: fedtypesv1a1.OverridePatches{
+				{Path: ReplicaPath, Value: int32(3)},
+				{Path: PartitionPath, Value: int32(2)},
+			},
+		},
+		{
+			name:        "no plan",
+			clusterName: "c",
+			kind:        common.StatefulSetKind,
+			expected:    fedtypesv1a1.OverridePatches{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := plans.GetRolloutOverrides(test.clusterName, test.kind)
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("got: %v, expected: %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestRetrieveFencepostWithoutSurge(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"updateStrategy": map[string]interface{}{
+				"rollingUpdate": map[string]interface{}{"maxUnavailable": int64(2)},
+			},
+		},
+	}}
+
+	maxSurgePath, maxUnavailablePath := GetFencepostPaths(common.StatefulSetKind)
+	maxSurge, maxUnavailable, err := RetrieveFencepost(obj, maxSurgePath, maxUnavailablePath, 5)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if maxSurge != 0 || maxUnavailable != 2 {
+		t.Errorf("got: %d,%d, expected: 0,2", maxSurge, maxUnavailable)
+	}
+
+	// StatefulSets default to updating one pod at a time.
+	maxSurge, maxUnavailable, err = RetrieveFencepost(&unstructured.Unstructured{Object: map[string]interface{}{}},
+		maxSurgePath, maxUnavailablePath, 5)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if maxSurge != 0 || maxUnavailable != 1 {
+		t.Errorf("got: %d,%d, expected: 0,1", maxSurge, maxUnavailable)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"net/http"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+// NewWebhookTransport returns a transport for calling a webhook with the given TLS config, which may be nil.
+func NewWebhookTransport(config *fedcorev1a1.WebhookTLSConfig) (http.RoundTripper, error) {
+	var restConfig rest.Config
+	if config != nil {
+		restConfig.TLSClientConfig.Insecure = config.Insecure
+		restConfig.TLSClientConfig.ServerName = config.ServerName
+		restConfig.TLSClientConfig.CertData = config.CertData
+		restConfig.TLSClientConfig.KeyData = config.KeyData
+		restConfig.TLSClientConfig.CAData = config.CAData
+	}
+	tlsConfig, err := rest.TLSConfigFor(&restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating TLS config: %w", err)
+	}
+	return utilnet.SetTransportDefaults(&http.Transport{TLSClientConfig: tlsConfig}), nil
+}
@@ -66,3 +66,28 @@ var (
 	// should not be reenqueued.
 	StatusErrorNoRetry = Result{Success: false, RequeueAfter: nil, Backoff: false}
 )
+
+// Outcomes of a reconciliation, as reported in the metrics of a ReconcileWorker.
+const (
+	OutcomeAllOK        = "AllOK"
+	OutcomeError        = "Error"
+	OutcomeConflict     = "Conflict"
+	OutcomeNeedsRecheck = "NeedsRecheck"
+	OutcomeErrorNoRetry = "ErrorNoRetry"
+)
+
+// Outcome classifies the result into one of the Outcome* constants.
+func (r *Result) Outcome() string {
+	switch {
+	case r.Success && r.RequeueAfter == nil:
+		return OutcomeAllOK
+	case r.Success:
+		return OutcomeNeedsRecheck
+	case r.Backoff:
+		return OutcomeError
+	case r.RequeueAfter != nil:
+		return OutcomeConflict
+	default:
+		return OutcomeErrorNoRetry
+	}
+}
@@ -21,6 +21,7 @@ are Copyright 2023 The KubeAdmiral Authors.
 package worker
 
 import (
+	"sync"
 	"time"
 
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
@@ -71,6 +72,11 @@ type asyncWorker struct {
 
 	metrics    stats.Metrics
 	metricTags deliverutil.MetricTags
+
+	// enqueueTimes records when each key waiting in the queue was added, and is used to report the queue latency and
+	// the age of the oldest item in the queue.
+	enqueueTimesLock sync.Mutex
+	enqueueTimes     map[string]time.Time
 }
 
 func NewReconcileWorker(
@@ -94,14 +100,15 @@ func NewReconcileWorker(
 		workerCount = 1
 	}
 	return &asyncWorker{
-		reconcile:   reconcile,
-		timing:      timing,
-		deliverer:   deliverutil.NewDelayingDeliverer(),
-		queue:       workqueue.New(),
-		backoff:     flowcontrol.NewBackOff(timing.InitialBackoff, timing.MaxBackoff),
-		workerCount: workerCount,
-		metrics:     metrics,
-		metricTags:  metricTags,
+		reconcile:    reconcile,
+		timing:       timing,
+		deliverer:    deliverutil.NewDelayingDeliverer(),
+		queue:        workqueue.New(),
+		backoff:      flowcontrol.NewBackOff(timing.InitialBackoff, timing.MaxBackoff),
+		workerCount:  workerCount,
+		metrics:      metrics,
+		metricTags:   metricTags,
+		enqueueTimes: map[string]time.Time{},
 	}
 }
 
@@ -125,9 +132,11 @@ func (w *asyncWorker) EnqueueWithDelay(qualifiedName common.QualifiedName, delay
 func (w *asyncWorker) Run(stopChan <-chan struct{}) {
 	util.StartBackoffGC(w.backoff, stopChan)
 	w.deliverer.StartWithHandler(func(item *deliverutil.DelayingDelivererItem) {
+		w.recordEnqueue(item.Key)
 		w.queue.Add(item.Key)
 	})
 	go w.deliverer.RunMetricLoop(stopChan, 30*time.Second, w.metrics, w.metricTags)
+	go wait.Until(w.reportQueueMetrics, 30*time.Second, stopChan)
 
 	for i := 0; i < w.workerCount; i++ {
 		go wait.Until(w.worker, w.timing.Interval, stopChan)
@@ -161,9 +170,14 @@ func (w *asyncWorker) worker() {
 			return
 		}
 
-		qualifiedName := common.NewQualifiedFromString(obj.(string))
+		key := obj.(string)
+		w.recordDequeue(key)
+
+		qualifiedName := common.NewQualifiedFromString(key)
+		startTime := time.Now()
 		result := w.reconcile(qualifiedName)
 		w.queue.Done(obj)
+		w.recordResult(result, startTime)
 
 		if result.Backoff {
 			w.EnqueueForBackoff(qualifiedName)
@@ -172,3 +186,53 @@ func (w *asyncWorker) worker() {
 		}
 	}
 }
+
+func (w *asyncWorker) recordEnqueue(key string) {
+	w.enqueueTimesLock.Lock()
+	defer w.enqueueTimesLock.Unlock()
+
+	// The queue deduplicates keys that are already waiting, so we keep the earliest enqueue time.
+	if _, exists := w.enqueueTimes[key]; !exists {
+		w.enqueueTimes[key] = time.Now()
+	}
+}
+
+func (w *asyncWorker) recordDequeue(key string) {
+	w.enqueueTimesLock.Lock()
+	enqueueTime, exists := w.enqueueTimes[key]
+	delete(w.enqueueTimes, key)
+	w.enqueueTimesLock.Unlock()
+
+	if exists {
+		w.metrics.Timer("reconcileWorker.queueLatency.ms", time.Since(enqueueTime).Milliseconds(), w.metricTags.StatsTags()...)
+	}
+}
+
+func (w *asyncWorker) recordResult(result Result, startTime time.Time) {
+	outcome := result.Outcome()
+	tags := append(w.metricTags.StatsTags(), stats.Tag{Name: "result", Value: outcome})
+	w.metrics.Duration("reconcileWorker.reconcileLatency", startTime, tags...)
+	w.metrics.Counter("reconcileWorker.reconcileCount", 1, tags...)
+
+	// Only failed reconciliations are retried; a successful reconciliation requeued for a recheck is not a retry.
+	if outcome == OutcomeError || outcome == OutcomeConflict {
+		w.metrics.Counter("reconcileWorker.retryCount", 1, tags...)
+	}
+}
+
+func (w *asyncWorker) reportQueueMetrics() {
+	var oldestItemAge time.Duration
+	now := time.Now()
+
+	w.enqueueTimesLock.Lock()
+	for _, enqueueTime := range w.enqueueTimes {
+		if age := now.Sub(enqueueTime); age > oldestItemAge {
+			oldestItemAge = age
+		}
+	}
+	w.enqueueTimesLock.Unlock()
+
+	tags := w.metricTags.StatsTags()
+	w.metrics.Store("reconcileWorker.queueLength", w.queue.Len(), tags...)
+	w.metrics.Store("reconcileWorker.oldestItemAge.ms", oldestItemAge.Milliseconds(), tags...)
+}
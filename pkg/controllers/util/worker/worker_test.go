@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/pointer"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+)
+
+func TestResultOutcome(t *testing.T) {
+	testCases := map[string]struct {
+		result  Result
+		outcome string
+	}{
+		"all ok":         {result: StatusAllOK, outcome: OutcomeAllOK},
+		"error":          {result: StatusError, outcome: OutcomeError},
+		"conflict":       {result: StatusConflict, outcome: OutcomeConflict},
+		"error no retry": {result: StatusErrorNoRetry, outcome: OutcomeErrorNoRetry},
+		"needs recheck": {
+			result:  Result{Success: true, RequeueAfter: pointer.Duration(time.Minute)},
+			outcome: OutcomeNeedsRecheck,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.outcome, tc.result.Outcome())
+		})
+	}
+}
+
+func TestReconcileWorkerMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics := stats.NewPrometheus("kubeadmiral", registry)
+
+	reconciled := make(chan common.QualifiedName, 1)
+	w := NewReconcileWorker(
+		func(qualifiedName common.QualifiedName) Result {
+			reconciled <- qualifiedName
+			return StatusErrorNoRetry
+		},
+		WorkerTiming{},
+		1,
+		metrics,
+		delayingdeliver.NewFTCMetricTags("test-worker", "Deployment", "deployments.apps"),
+	)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	w.Run(stopCh)
+
+	w.Enqueue(common.QualifiedName{Namespace: "default", Name: "foo"})
+	select {
+	case <-reconciled:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("timed out waiting for reconcile")
+	}
+
+	expected := `
+# HELP kubeadmiral_reconcileWorker_reconcileCount KubeAdmiral counter reconcileWorker.reconcileCount.
+# TYPE kubeadmiral_reconcileWorker_reconcileCount counter
+kubeadmiral_reconcileWorker_reconcileCount{controller="test-worker",ftc="deployments.apps",kind="Deployment",result="ErrorNoRetry"} 1
+`
+	assert.Eventually(t, func() bool {
+		return testutil.GatherAndCompare(
+			registry,
+			strings.NewReader(expected),
+			"kubeadmiral_reconcileWorker_reconcileCount",
+		) == nil
+	}, wait.ForeverTestTimeout, 10*time.Millisecond)
+
+	count, err := testutil.GatherAndCount(registry, "kubeadmiral_reconcileWorker_queueLatency_ms")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestReconcileWorkerRetryCount(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	w := NewReconcileWorker(
+		func(common.QualifiedName) Result { return StatusAllOK },
+		WorkerTiming{},
+		1,
+		stats.NewPrometheus("kubeadmiral", registry),
+		delayingdeliver.NewFTCMetricTags("test-worker", "Deployment", "deployments.apps"),
+	).(*asyncWorker)
+
+	for _, result := range []Result{
+		StatusAllOK,
+		StatusError,
+		StatusConflict,
+		StatusErrorNoRetry,
+		{Success: true, RequeueAfter: pointer.Duration(time.Second)},
+	} {
+		w.recordResult(result, time.Now())
+	}
+
+	expected := `
+# HELP kubeadmiral_reconcileWorker_retryCount KubeAdmiral counter reconcileWorker.retryCount.
+# TYPE kubeadmiral_reconcileWorker_retryCount counter
+kubeadmiral_reconcileWorker_retryCount{controller="test-worker",ftc="deployments.apps",kind="Deployment",result="Conflict"} 1
+kubeadmiral_reconcileWorker_retryCount{controller="test-worker",ftc="deployments.apps",kind="Deployment",result="Error"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(
+		registry,
+		strings.NewReader(expected),
+		"kubeadmiral_reconcileWorker_retryCount",
+	))
+}
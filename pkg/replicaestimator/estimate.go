@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicaestimator
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	estimatorv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/replicaestimator/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+// MaxAvailableReplicas simulates scheduling replicas with the given request onto the nodes, taking into account the
+// resources requested by the pods already assigned to them, and returns the number of replicas that fit.
+func MaxAvailableReplicas(
+	nodes []*corev1.Node,
+	pods []*corev1.Pod,
+	request *estimatorv1a1.MaxAvailableReplicasRequest,
+) (int64, error) {
+	nodeSelector := labels.SelectorFromSet(request.NodeSelector)
+
+	podsByNode := make(map[string][]*corev1.Pod, len(nodes))
+	for _, pod := range pods {
+		if len(pod.Spec.NodeName) == 0 ||
+			pod.Status.Phase == corev1.PodSucceeded ||
+			pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod)
+	}
+
+	replicas := int64(0)
+	for _, node := range nodes {
+		if !isNodeSchedulable(node, request.Tolerations) || !nodeSelector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		if request.NodeAffinity != nil {
+			matches, err := matchesNodeSelectorTerms(node, request.NodeAffinity.NodeSelectorTerms)
+			if err != nil {
+				return 0, err
+			}
+			if !matches {
+				continue
+			}
+		}
+
+		replicas += getReplicasOnNode(node, podsByNode[node.Name], request.ResourceRequest)
+	}
+	return replicas, nil
+}
+
+// isNodeSchedulable returns true if the node is ready and schedulable for pods with the given tolerations.
+func isNodeSchedulable(node *corev1.Node, tolerations []corev1.Toleration) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status != corev1.ConditionTrue {
+			return false
+		}
+	}
+
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesNodeSelectorTerms returns true if the node matches any of the terms.
+func matchesNodeSelectorTerms(node *corev1.Node, terms []corev1.NodeSelectorTerm) (bool, error) {
+	for _, term := range terms {
+		// An empty term matches no nodes.
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+
+		matches, err := matchesNodeSelectorRequirements(labels.Set(node.Labels), term.MatchExpressions)
+		if err != nil || !matches {
+			return false, err
+		}
+		matches, err = matchesNodeSelectorRequirements(labels.Set{"metadata.name": node.Name}, term.MatchFields)
+		if err != nil {
+			return false, err
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+func matchesNodeSelectorRequirements(set labels.Set, requirements []corev1.NodeSelectorRequirement) (bool, error) {
+	selector := labels.NewSelector()
+	for _, requirement := range requirements {
+		operator, ok := nodeSelectorOperators[requirement.Operator]
+		if !ok {
+			return false, fmt.Errorf("invalid node selector operator %q", requirement.Operator)
+		}
+		r, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil {
+			return false, fmt.Errorf("invalid node selector requirement: %w", err)
+		}
+		selector = selector.Add(*r)
+	}
+	return selector.Matches(set), nil
+}
+
+// getReplicasOnNode returns the number of replicas with the given resource request that fit on the node in addition
+// to the given pods.
+func getReplicasOnNode(node *corev1.Node, pods []*corev1.Pod, resourceRequest corev1.ResourceList) int64 {
+	available := node.Status.Allocatable.DeepCopy()
+	for _, pod := range pods {
+		for name, requested := range util.GetPodResourceRequests(&pod.Spec) {
+			if quantity, ok := available[name]; ok {
+				quantity.Sub(requested)
+				available[name] = quantity
+			}
+		}
+	}
+
+	// Each replica takes up one of the node's pod slots.
+	replicas, limited := int64(0), false
+	if podSlots, ok := available[corev1.ResourcePods]; ok {
+		replicas, limited = podSlots.Value()-int64(len(pods)), true
+	}
+
+	for name, requested := range resourceRequest {
+		if name == corev1.ResourcePods || requested.IsZero() {
+			continue
+		}
+
+		quantity := available[name]
+		var resourceReplicas int64
+		if name == corev1.ResourceCPU {
+			resourceReplicas = quantity.MilliValue() / requested.MilliValue()
+		} else {
+			resourceReplicas = quantity.Value() / requested.Value()
+		}
+		if !limited || resourceReplicas < replicas {
+			replicas, limited = resourceReplicas, true
+		}
+	}
+
+	// If neither the node's pod slots nor the request limits the number of replicas, which we do not expect in
+	// practice since nodes report their pod capacity, we do not count the node.
+	if !limited || replicas < 0 {
+		return 0
+	}
+	return replicas
+}
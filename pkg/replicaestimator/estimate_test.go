@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicaestimator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	estimatorv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/replicaestimator/v1alpha1"
+)
+
+const gpuResource corev1.ResourceName = "nvidia.com/gpu"
+
+func makeNode(name string, labels map[string]string, allocatable corev1.ResourceList) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{
+			Allocatable: allocatable,
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func makePod(nodeName string, requests corev1.ResourceList) *corev1.Pod {
+	return &corev1.Pod{
+		Spec: corev1.PodSpec{
+			NodeName:   nodeName,
+			Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: requests}}},
+		},
+	}
+}
+
+func TestMaxAvailableReplicas(t *testing.T) {
+	cpuNode := makeNode("cpu", map[string]string{"pool": "cpu"}, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("16"),
+		corev1.ResourceMemory: resource.MustParse("64Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	})
+	gpuNode := makeNode("gpu", map[string]string{"pool": "gpu"}, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("32"),
+		corev1.ResourceMemory: resource.MustParse("128Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+		gpuResource:           resource.MustParse("8"),
+	})
+	gpuNode.Spec.Taints = []corev1.Taint{{Key: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+	smallNode := makeNode("small", map[string]string{"pool": "cpu"}, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("16Gi"),
+		corev1.ResourcePods:   resource.MustParse("3"),
+	})
+	notReadyNode := makeNode("not-ready", map[string]string{"pool": "cpu"}, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("64"),
+		corev1.ResourceMemory: resource.MustParse("256Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	})
+	notReadyNode.Status.Conditions[0].Status = corev1.ConditionFalse
+	nodes := []*corev1.Node{cpuNode, gpuNode, smallNode, notReadyNode}
+
+	pods := []*corev1.Pod{
+		makePod("cpu", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}),
+		makePod("gpu", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), gpuResource: resource.MustParse("3")}),
+		makePod("small", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}),
+		makePod("", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("64")}),
+	}
+
+	gpuToleration := corev1.Toleration{Key: "gpu", Operator: corev1.TolerationOpExists}
+
+	testCases := map[string]struct {
+		request          *estimatorv1a1.MaxAvailableReplicasRequest
+		expectedReplicas int64
+	}{
+		"cpu requests fit on untainted nodes": {
+			request: &estimatorv1a1.MaxAvailableReplicasRequest{
+				ResourceRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			},
+			// 6 on the cpu node and 1 on the small node.
+			expectedReplicas: 7,
+		},
+		"tolerations allow tainted nodes": {
+			request: &estimatorv1a1.MaxAvailableReplicasRequest{
+				ResourceRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				Tolerations:     []corev1.Toleration{gpuToleration},
+			},
+			expectedReplicas: 22,
+		},
+		"extended resources are considered": {
+			request: &estimatorv1a1.MaxAvailableReplicasRequest{
+				ResourceRequest: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("1"),
+					gpuResource:        resource.MustParse("2"),
+				},
+				Tolerations: []corev1.Toleration{gpuToleration},
+			},
+			expectedReplicas: 2,
+		},
+		"pod slots are considered": {
+			request: &estimatorv1a1.MaxAvailableReplicasRequest{
+				ResourceRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				NodeSelector:    map[string]string{"pool": "cpu"},
+				NodeAffinity: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchFields: []corev1.NodeSelectorRequirement{
+								{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"small"}},
+							},
+						},
+					},
+				},
+			},
+			expectedReplicas: 2,
+		},
+		"node affinity is considered": {
+			request: &estimatorv1a1.MaxAvailableReplicasRequest{
+				ResourceRequest: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+				Tolerations:     []corev1.Toleration{gpuToleration},
+				NodeAffinity: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"cpu"}},
+							},
+						},
+					},
+				},
+			},
+			expectedReplicas: 16,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			replicas, err := MaxAvailableReplicas(nodes, pods, tc.request)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedReplicas, replicas)
+		})
+	}
+}
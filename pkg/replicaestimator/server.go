@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicaestimator
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	estimatorv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/replicaestimator/v1alpha1"
+)
+
+// MaxAvailableReplicasPath is the path at which the server serves MaxAvailableReplicasRequests.
+const MaxAvailableReplicasPath = "/" + estimatorv1a1.PayloadVersion + "/max-available-replicas"
+
+var errNotSynced = errors.New("node and pod caches are not synced")
+
+// Server estimates replicas for a member cluster from the node and pod caches of the cluster.
+type Server struct {
+	nodeLister  corev1listers.NodeLister
+	nodesSynced cache.InformerSynced
+	podLister   corev1listers.PodLister
+	podsSynced  cache.InformerSynced
+
+	logger klog.Logger
+}
+
+func NewServer(logger klog.Logger, informerFactory informers.SharedInformerFactory) *Server {
+	return &Server{
+		nodeLister:  informerFactory.Core().V1().Nodes().Lister(),
+		nodesSynced: informerFactory.Core().V1().Nodes().Informer().HasSynced,
+		podLister:   informerFactory.Core().V1().Pods().Lister(),
+		podsSynced:  informerFactory.Core().V1().Pods().Informer().HasSynced,
+		logger:      logger.WithValues("component", "replica-estimator"),
+	}
+}
+
+func (s *Server) HasSynced() bool {
+	return s.nodesSynced() && s.podsSynced()
+}
+
+// Handler returns the HTTP handler of the server.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(MaxAvailableReplicasPath, s.handleMaxAvailableReplicas)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if !s.HasSynced() {
+			http.Error(w, "caches not synced", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+	return mux
+}
+
+// ClientAuthTLSConfig returns the TLS config that only accepts clients presenting a certificate signed by a CA in
+// clientCAFile. Replica estimation requests reveal the capacity of the member cluster and must be authenticated.
+func ClientAuthTLSConfig(clientCAFile string) (*tls.Config, error) {
+	caBytes, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caBytes) {
+		return nil, fmt.Errorf("no certificates found in client CA file %q", clientCAFile)
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientCAs:  clientCAs,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+	}, nil
+}
+
+func (s *Server) handleMaxAvailableReplicas(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	request := &estimatorv1a1.MaxAvailableReplicasRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		http.Error(w, "failed to decode request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	logger := s.logger.WithValues("cluster", request.ClusterName)
+	response := &estimatorv1a1.MaxAvailableReplicasResponse{}
+	if replicas, err := s.maxAvailableReplicas(request); err != nil {
+		logger.Error(err, "Failed to estimate replicas")
+		response.Error = err.Error()
+	} else {
+		logger.V(4).Info("Estimated replicas", "replicas", replicas)
+		response.MaxReplicas = replicas
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error(err, "Failed to write response")
+	}
+}
+
+func (s *Server) maxAvailableReplicas(request *estimatorv1a1.MaxAvailableReplicasRequest) (int64, error) {
+	if !s.HasSynced() {
+		return 0, errNotSynced
+	}
+
+	nodes, err := s.nodeLister.List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+	pods, err := s.podLister.List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+	return MaxAvailableReplicas(nodes, pods, request)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicaestimator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(t *testing.T, template *x509.Certificate, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func newTestCertTemplate(serial int64, commonName string, isCA bool, extKeyUsage ...x509.ExtKeyUsage) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           extKeyUsage,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if isCA {
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+	return template
+}
+
+func TestClientAuthTLSConfig(t *testing.T) {
+	ca := newTestCert(t, newTestCertTemplate(1, "ca", true), nil)
+	serverCert := newTestCert(t, newTestCertTemplate(2, "server", false, x509.ExtKeyUsageServerAuth), ca)
+	clientCert := newTestCert(t, newTestCertTemplate(3, "client", false, x509.ExtKeyUsageClientAuth), ca)
+	otherCA := newTestCert(t, newTestCertTemplate(4, "other-ca", true), nil)
+	untrustedClientCert := newTestCert(t, newTestCertTemplate(5, "client", false, x509.ExtKeyUsageClientAuth), otherCA)
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, ca.certPEM, 0o600))
+
+	tlsConfig, err := ClientAuthTLSConfig(caFile)
+	require.NoError(t, err)
+	serverKeyPair, err := tls.X509KeyPair(serverCert.certPEM, serverCert.keyPEM)
+	require.NoError(t, err)
+	tlsConfig.Certificates = []tls.Certificate{serverKeyPair}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = tlsConfig
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ca.cert)
+	newClient := func(cert *testCert) *http.Client {
+		clientTLSConfig := &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+		if cert != nil {
+			keyPair, err := tls.X509KeyPair(cert.certPEM, cert.keyPEM)
+			require.NoError(t, err)
+			clientTLSConfig.Certificates = []tls.Certificate{keyPair}
+		}
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLSConfig}}
+	}
+
+	resp, err := newClient(clientCert).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	for name, cert := range map[string]*testCert{"no client certificate": nil, "untrusted client certificate": untrustedClientCert} {
+		if resp, err := newClient(cert).Get(server.URL); err == nil {
+			resp.Body.Close()
+			t.Errorf("%s: expected request to be rejected", name)
+		}
+	}
+
+	_, err = ClientAuthTLSConfig(filepath.Join(t.TempDir(), "missing.crt"))
+	assert.Error(t, err)
+}
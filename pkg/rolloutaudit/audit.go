@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rolloutaudit records the rollout plans computed by the sync controller, so that the decisions of a
+// cross-cluster rollout can be inspected after the fact.
+package rolloutaudit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	SinkElasticsearch = "elasticsearch"
+	SinkFile          = "file"
+	SinkEvent         = "event"
+)
+
+// Record is a rollout plan decision made for a federated object.
+type Record struct {
+	Timestamp  time.Time `json:"@timestamp"`
+	APIVersion string    `json:"apiVersion"`
+	Kind       string    `json:"kind"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Name       string    `json:"name"`
+	UID        types.UID `json:"uid"`
+	Generation int64     `json:"generation"`
+
+	// Replicas is the total number of replicas across the selected clusters.
+	Replicas       int32  `json:"replicas"`
+	MaxSurge       int32  `json:"maxSurge"`
+	MaxUnavailable int32  `json:"maxUnavailable"`
+	Revision       string `json:"revision,omitempty"`
+
+	// Targets is the observed state of the object in each cluster that the plans were computed from.
+	Targets []Target `json:"targets,omitempty"`
+	// Plans maps the name of each cluster to the plan computed for it.
+	Plans map[string]Plan `json:"plans,omitempty"`
+	// Error is set if no plans could be computed.
+	Error string `json:"error,omitempty"`
+}
+
+type Target struct {
+	Cluster                     string `json:"cluster"`
+	DesiredReplicas             int32  `json:"desiredReplicas"`
+	Replicas                    int32  `json:"replicas"`
+	ActualReplicas              int32  `json:"actualReplicas"`
+	AvailableReplicas           int32  `json:"availableReplicas"`
+	UpdatedReplicas             int32  `json:"updatedReplicas"`
+	UpdatedAvailableReplicas    int32  `json:"updatedAvailableReplicas"`
+	CurrentNewReplicas          int32  `json:"currentNewReplicas"`
+	CurrentNewAvailableReplicas int32  `json:"currentNewAvailableReplicas"`
+	Updated                     bool   `json:"updated"`
+	MaxSurge                    int32  `json:"maxSurge"`
+	MaxUnavailable              int32  `json:"maxUnavailable"`
+}
+
+type Plan struct {
+	Replicas          *int32 `json:"replicas,omitempty"`
+	MaxSurge          *int32 `json:"maxSurge,omitempty"`
+	MaxUnavailable    *int32 `json:"maxUnavailable,omitempty"`
+	Partition         *int32 `json:"partition,omitempty"`
+	OnlyPatchReplicas bool   `json:"onlyPatchReplicas,omitempty"`
+}
+
+// Sink receives the rollout plan records of federated objects. Send is called from the reconcile loop of the sync
+// controller and must not block on I/O.
+type Sink interface {
+	// Send records the rollout plan decision made for the federated object.
+	Send(fedObject runtime.Object, record *Record)
+	// Run runs the background work of the sink until the context is cancelled.
+	Run(ctx context.Context)
+}
+
+// Config configures the sinks created by NewSink.
+type Config struct {
+	// Sinks is the list of enabled sinks. Valid values are SinkElasticsearch, SinkFile and SinkEvent.
+	Sinks []string
+
+	ElasticsearchURL   string
+	ElasticsearchIndex string
+	// ElasticsearchCredentialsFile is the path of a file containing the basic auth credentials of the Elasticsearch
+	// cluster in the format "username:password".
+	ElasticsearchCredentialsFile string
+
+	FilePath string
+}
+
+// NewSink creates a sink that sends the records to each of the configured sinks.
+func NewSink(config *Config, eventRecorder record.EventRecorder) (Sink, error) {
+	sinks := multiSink{}
+	for _, name := range config.Sinks {
+		switch name {
+		case SinkElasticsearch:
+			sink, err := NewElasticsearchSink(
+				config.ElasticsearchURL,
+				config.ElasticsearchIndex,
+				config.ElasticsearchCredentialsFile,
+			)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case SinkFile:
+			sink, err := NewFileSink(config.FilePath)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case SinkEvent:
+			sinks = append(sinks, NewEventSink(eventRecorder))
+		default:
+			return nil, fmt.Errorf("unknown rollout audit sink %q", name)
+		}
+	}
+	return sinks, nil
+}
+
+type multiSink []Sink
+
+func (s multiSink) Send(fedObject runtime.Object, record *Record) {
+	for _, sink := range s {
+		sink.Send(fedObject, record)
+	}
+}
+
+func (s multiSink) Run(ctx context.Context) {
+	for _, sink := range s {
+		go sink.Run(ctx)
+	}
+	<-ctx.Done()
+}
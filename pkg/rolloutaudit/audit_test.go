@@ -0,0 +1,205 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolloutaudit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+)
+
+func newTestRecord(name string) *Record {
+	return &Record{
+		Timestamp:      time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+		APIVersion:     "types.kubeadmiral.io/v1alpha1",
+		Kind:           "FederatedDeployment",
+		Namespace:      "default",
+		Name:           name,
+		Replicas:       10,
+		MaxSurge:       2,
+		MaxUnavailable: 1,
+		Revision:       "rev-2",
+		Plans: map[string]Plan{
+			"cluster2": {Replicas: pointer.Int32(6), MaxSurge: pointer.Int32(0), MaxUnavailable: pointer.Int32(1)},
+			"cluster1": {Replicas: pointer.Int32(4), OnlyPatchReplicas: true},
+		},
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	_, err := NewSink(&Config{Sinks: []string{"kafka"}}, record.NewFakeRecorder(1))
+	assert.Error(t, err)
+
+	_, err = NewSink(&Config{Sinks: []string{SinkFile}}, record.NewFakeRecorder(1))
+	assert.Error(t, err, "the file sink requires a path")
+
+	_, err = NewSink(&Config{Sinks: []string{SinkElasticsearch}, ElasticsearchURL: "ftp://es"}, record.NewFakeRecorder(1))
+	assert.Error(t, err)
+
+	_, err = NewSink(
+		&Config{Sinks: []string{SinkElasticsearch}, ElasticsearchURL: "https://user:pass@es"},
+		record.NewFakeRecorder(1),
+	)
+	if assert.Error(t, err, "credentials must not be included in the url") {
+		assert.NotContains(t, err.Error(), "pass")
+	}
+
+	_, err = NewSink(
+		&Config{
+			Sinks:                        []string{SinkElasticsearch},
+			ElasticsearchURL:             "https://es",
+			ElasticsearchCredentialsFile: filepath.Join(t.TempDir(), "missing"),
+		},
+		record.NewFakeRecorder(1),
+	)
+	assert.Error(t, err)
+
+	sink, err := NewSink(&Config{Sinks: []string{SinkEvent}}, record.NewFakeRecorder(1))
+	assert.NoError(t, err)
+	assert.Len(t, sink, 1)
+}
+
+func TestElasticsearchSink(t *testing.T) {
+	requests := make(chan []string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		if r.URL.Path != "/es/_bulk" || username != "user" || password != "pass" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		requests <- strings.Split(strings.TrimSpace(string(body)), "\n")
+		_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+	}))
+	defer server.Close()
+
+	credentialsFile := filepath.Join(t.TempDir(), "credentials")
+	assert.NoError(t, os.WriteFile(credentialsFile, []byte("user:pass\n"), 0o600))
+
+	sink, err := NewElasticsearchSink(server.URL+"/es/", "", credentialsFile)
+	assert.NoError(t, err)
+	sink.batchSize = 2
+	sink.flushInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sink.Run(ctx)
+
+	sink.Send(nil, newTestRecord("foo"))
+	sink.Send(nil, newTestRecord("bar"))
+
+	select {
+	case lines := <-requests:
+		assert.Len(t, lines, 4)
+		assert.JSONEq(t, `{"index":{"_index":"federation_placement_rollout"}}`, lines[0])
+		assert.JSONEq(t, `{"index":{"_index":"federation_placement_rollout"}}`, lines[2])
+
+		written := &Record{}
+		assert.NoError(t, json.Unmarshal([]byte(lines[3]), written))
+		assert.Equal(t, newTestRecord("bar"), written)
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("timed out waiting for the bulk request")
+	}
+
+	// The buffered records are written when the sink is stopped.
+	sink.Send(nil, newTestRecord("baz"))
+	cancel()
+	select {
+	case lines := <-requests:
+		assert.Len(t, lines, 2)
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("timed out waiting for the bulk request on shutdown")
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rollout.jsonl")
+	sink, err := NewFileSink(path)
+	assert.NoError(t, err)
+
+	sink.Send(nil, newTestRecord("foo"))
+	sink.Send(nil, &Record{Name: "bar", Error: "unsupported target type"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sink.Run(ctx)
+	// The file is closed by Run, so records sent afterwards must not be written.
+	sink.Send(nil, newTestRecord("baz"))
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var records []*Record
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		record := &Record{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), record))
+		records = append(records, record)
+	}
+	assert.NoError(t, scanner.Err())
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, newTestRecord("foo"), records[0])
+		assert.Equal(t, "unsupported target type", records[1].Error)
+	}
+}
+
+func TestEventSink(t *testing.T) {
+	recorder := record.NewFakeRecorder(3)
+	sink := NewEventSink(recorder)
+	fedObject := &unstructured.Unstructured{}
+	fedObject.SetUID("uid")
+
+	sink.Send(fedObject, newTestRecord("foo"))
+	sink.Send(fedObject, &Record{Name: "foo", Error: "unsupported target type"})
+
+	assert.Equal(
+		t,
+		`Normal RolloutPlanned Planned rollout of revision "rev-2" with replicas=10, maxSurge=2, maxUnavailable=1: `+
+			`cluster1(replicas=4,onlyPatchReplicas) cluster2(replicas=6,maxSurge=0,maxUnavailable=1)`,
+		<-recorder.Events,
+	)
+	assert.Equal(t, "Warning RolloutPlanFailed Failed to plan rollout: unsupported target type", <-recorder.Events)
+
+	// Plans are recorded again after a failure, but unchanged plans are not recorded again.
+	sink.Send(fedObject, newTestRecord("foo"))
+	sink.Send(fedObject, newTestRecord("foo"))
+	changed := newTestRecord("foo")
+	changed.Revision = "rev-3"
+	sink.Send(fedObject, changed)
+	assert.Contains(t, <-recorder.Events, `Planned rollout of revision "rev-2"`)
+	assert.Contains(t, <-recorder.Events, `Planned rollout of revision "rev-3"`)
+	assert.Empty(t, recorder.Events)
+
+	long := &Record{Error: strings.Repeat("x", 2*maxEventMessageLength)}
+	sink.Send(fedObject, long)
+	assert.Len(t, <-recorder.Events, len("Warning RolloutPlanFailed ")+maxEventMessageLength)
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolloutaudit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultElasticsearchIndex = "federation_placement_rollout"
+
+	elasticsearchBufferSize    = 1000
+	elasticsearchBatchSize     = 100
+	elasticsearchFlushInterval = 5 * time.Second
+	elasticsearchTimeout       = 30 * time.Second
+	// elasticsearchShutdownTimeout bounds the time spent writing the buffered records when the sink is stopped.
+	elasticsearchShutdownTimeout = 10 * time.Second
+)
+
+// ElasticsearchSink writes the records to an Elasticsearch or OpenSearch index with the bulk API. Records are
+// buffered and written in batches; records are dropped if the buffer is full or a batch cannot be written. The
+// buffered records are written when the sink is stopped.
+type ElasticsearchSink struct {
+	bulkURL    string
+	index      string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	records       chan *Record
+	batchSize     int
+	flushInterval time.Duration
+}
+
+var _ Sink = &ElasticsearchSink{}
+
+// NewElasticsearchSink creates a sink that writes to the index of the cluster at the URL. Basic auth credentials
+// are read from credentialsFile in the format "username:password" if it is set. Credentials must not be included in
+// the URL, so that they do not leak through the logged flags.
+func NewElasticsearchSink(rawURL, index, credentialsFile string) (*ElasticsearchSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid elasticsearch url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid elasticsearch url %q: scheme must be http or https", u.Redacted())
+	}
+	if u.User != nil {
+		return nil, fmt.Errorf(
+			"invalid elasticsearch url %q: credentials must be provided in the credentials file", u.Redacted())
+	}
+	if len(index) == 0 {
+		index = DefaultElasticsearchIndex
+	}
+
+	sink := &ElasticsearchSink{
+		index:         index,
+		httpClient:    &http.Client{Timeout: elasticsearchTimeout},
+		records:       make(chan *Record, elasticsearchBufferSize),
+		batchSize:     elasticsearchBatchSize,
+		flushInterval: elasticsearchFlushInterval,
+	}
+	if len(credentialsFile) > 0 {
+		if sink.username, sink.password, err = readElasticsearchCredentials(credentialsFile); err != nil {
+			return nil, err
+		}
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/_bulk"
+	sink.bulkURL = u.String()
+	return sink, nil
+}
+
+func (s *ElasticsearchSink) Send(_ runtime.Object, record *Record) {
+	select {
+	case s.records <- record:
+	default:
+		klog.V(2).Infof("Dropping rollout audit record of %s %s/%s: elasticsearch buffer is full",
+			record.Kind, record.Namespace, record.Name)
+	}
+}
+
+func (s *ElasticsearchSink) Run(ctx context.Context) {
+	logger := klog.FromContext(ctx).WithValues("rollout-audit-sink", SinkElasticsearch)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Record, 0, s.batchSize)
+	flush := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.write(ctx, batch); err != nil {
+			logger.Error(err, "Failed to write rollout audit records", "count", len(batch))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// ctx is already cancelled, so the buffered records are written with a separate context that bounds the
+			// time spent on them during shutdown.
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), elasticsearchShutdownTimeout)
+			defer cancel()
+			for {
+				select {
+				case record := <-s.records:
+					batch = append(batch, record)
+					if len(batch) >= s.batchSize {
+						flush(shutdownCtx)
+					}
+				default:
+					flush(shutdownCtx)
+					return
+				}
+			}
+		case record := <-s.records:
+			batch = append(batch, record)
+			if len(batch) >= s.batchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		}
+	}
+}
+
+func (s *ElasticsearchSink) write(ctx context.Context, records []*Record) error {
+	body := &bytes.Buffer{}
+	encoder := json.NewEncoder(body)
+	action := map[string]interface{}{"index": map[string]string{"_index": s.index}}
+	for _, record := range records {
+		if err := encoder.Encode(action); err != nil {
+			return err
+		}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.bulkURL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if len(s.username) > 0 {
+		req.SetBasicAuth(s.username, s.password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("bulk request failed with status %d: %s", resp.StatusCode, respBody)
+	}
+
+	result := struct {
+		Errors bool `json:"errors"`
+	}{}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if result.Errors {
+		return fmt.Errorf("bulk request failed for some records: %s", respBody)
+	}
+	return nil
+}
+
+func readElasticsearchCredentials(path string) (username, password string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read elasticsearch credentials file: %w", err)
+	}
+	username, password, ok := strings.Cut(strings.TrimSpace(string(data)), ":")
+	if !ok || len(username) == 0 {
+		return "", "", fmt.Errorf("invalid elasticsearch credentials file %q: expected the format username:password", path)
+	}
+	return username, password, nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolloutaudit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	EventReasonRolloutPlanned    = "RolloutPlanned"
+	EventReasonRolloutPlanFailed = "RolloutPlanFailed"
+
+	// maxEventMessageLength is the length that event messages are truncated to, so that the plans of objects placed
+	// in many clusters do not produce oversized events.
+	maxEventMessageLength = 1024
+
+	// eventCacheSize and eventCacheTTL bound the last messages remembered to only emit an event when the plan of an
+	// object changes.
+	eventCacheSize = 10000
+	eventCacheTTL  = 24 * time.Hour
+)
+
+// EventSink records the rollout plans as events of the federated objects. Since a plan is computed on every
+// reconcile of an object, an event is only recorded when the plan or the revision it is planned for changes.
+type EventSink struct {
+	eventRecorder record.EventRecorder
+	// lastMessages are the messages of the last events recorded for each object, keyed by the object's UID.
+	lastMessages *cache.LRUExpireCache
+}
+
+var _ Sink = &EventSink{}
+
+func NewEventSink(eventRecorder record.EventRecorder) *EventSink {
+	return &EventSink{
+		eventRecorder: eventRecorder,
+		lastMessages:  cache.NewLRUExpireCache(eventCacheSize),
+	}
+}
+
+func (s *EventSink) Send(fedObject runtime.Object, record *Record) {
+	eventType, reason, message := corev1.EventTypeNormal, EventReasonRolloutPlanned, truncate(formatPlans(record))
+	if len(record.Error) > 0 {
+		eventType, reason = corev1.EventTypeWarning, EventReasonRolloutPlanFailed
+		message = truncate(fmt.Sprintf("Failed to plan rollout: %s", record.Error))
+	}
+
+	key := fmt.Sprintf("%s/%s/%s", record.Kind, record.Namespace, record.Name)
+	if metaObject, err := meta.Accessor(fedObject); err == nil && len(metaObject.GetUID()) > 0 {
+		key = string(metaObject.GetUID())
+	}
+	if lastMessage, exists := s.lastMessages.Get(key); exists && lastMessage == message {
+		return
+	}
+	s.lastMessages.Add(key, message, eventCacheTTL)
+
+	s.eventRecorder.Event(fedObject, eventType, reason, message)
+}
+
+func (s *EventSink) Run(ctx context.Context) {}
+
+func formatPlans(record *Record) string {
+	clusters := make([]string, 0, len(record.Plans))
+	for cluster := range record.Plans {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+
+	plans := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		plan := record.Plans[cluster]
+		fields := []string{}
+		if plan.Replicas != nil {
+			fields = append(fields, fmt.Sprintf("replicas=%d", *plan.Replicas))
+		}
+		if plan.MaxSurge != nil {
+			fields = append(fields, fmt.Sprintf("maxSurge=%d", *plan.MaxSurge))
+		}
+		if plan.MaxUnavailable != nil {
+			fields = append(fields, fmt.Sprintf("maxUnavailable=%d", *plan.MaxUnavailable))
+		}
+		if plan.OnlyPatchReplicas {
+			fields = append(fields, "onlyPatchReplicas")
+		}
+		plans = append(plans, fmt.Sprintf("%s(%s)", cluster, strings.Join(fields, ",")))
+	}
+
+	return fmt.Sprintf(
+		"Planned rollout of revision %q with replicas=%d, maxSurge=%d, maxUnavailable=%d: %s",
+		record.Revision,
+		record.Replicas,
+		record.MaxSurge,
+		record.MaxUnavailable,
+		strings.Join(plans, " "),
+	)
+}
+
+func truncate(message string) string {
+	if len(message) <= maxEventMessageLength {
+		return message
+	}
+	return message[:maxEventMessageLength-3] + "..."
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolloutaudit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+)
+
+const fileBufferSize = 1000
+
+// FileSink appends the records to a file in the JSON lines format. Records are buffered and written by Run; records
+// are dropped if the buffer is full.
+type FileSink struct {
+	file    *os.File
+	records chan *Record
+}
+
+var _ Sink = &FileSink{}
+
+func NewFileSink(path string) (*FileSink, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("rollout audit file path is not set")
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rollout audit file: %w", err)
+	}
+	return &FileSink{file: file, records: make(chan *Record, fileBufferSize)}, nil
+}
+
+func (s *FileSink) Send(_ runtime.Object, record *Record) {
+	select {
+	case s.records <- record:
+	default:
+		klog.V(2).Infof("Dropping rollout audit record of %s %s/%s: file buffer is full",
+			record.Kind, record.Namespace, record.Name)
+	}
+}
+
+// Run writes the buffered records until the context is cancelled, and then writes the remaining buffered records and
+// closes the file. The file is only accessed by Run, so records sent afterwards are never written to a closed file.
+func (s *FileSink) Run(ctx context.Context) {
+	logger := klog.FromContext(ctx).WithValues("rollout-audit-sink", SinkFile)
+
+	defer func() {
+		if err := s.file.Close(); err != nil {
+			logger.Error(err, "Failed to close rollout audit file")
+		}
+	}()
+
+	for {
+		select {
+		case record := <-s.records:
+			s.write(logger, record)
+		case <-ctx.Done():
+			for {
+				select {
+				case record := <-s.records:
+					s.write(logger, record)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *FileSink) write(logger klog.Logger, record *Record) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		logger.Error(err, "Failed to marshal rollout audit record", "kind", record.Kind,
+			"namespace", record.Namespace, "name", record.Name)
+		return
+	}
+	line = append(line, '\n')
+
+	// A single write of the whole line keeps the lines intact if the file is appended to concurrently.
+	if _, err := s.file.Write(line); err != nil {
+		logger.Error(err, "Failed to write rollout audit record", "kind", record.Kind,
+			"namespace", record.Namespace, "name", record.Name)
+	}
+}
@@ -0,0 +1,318 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// DefaultTimerBuckets are the histogram buckets used for Timer and Duration, in milliseconds.
+var DefaultTimerBuckets = prometheus.ExponentialBuckets(1, 2, 22)
+
+// NewPrometheus creates a metrics client which records stats as prometheus series in the given registerer.
+//
+// A stats name such as "totalsync.latency.ms" is exported as "<namespace>_totalsync_latency_ms", and the tags of a
+// stats point are exported as labels. Since prometheus requires a fixed set of label names for each metric, the label
+// names are determined by the first point recorded for a name. Later points that omit some of these tags are recorded
+// with empty labels, while points with other tags, points of another kind and points of names that are exported
+// under the same name as another stats name are rejected. Rejected points are returned as errors, logged once per
+// stats name and counted in "<namespace>_rejected_metric_points_total", so that they are visible even if the error is
+// discarded by the caller.
+func NewPrometheus(namespace string, registerer prometheus.Registerer) Metrics {
+	rejectedPoints := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rejected_metric_points_total",
+			Help:      "KubeAdmiral stats points that could not be recorded, by stats name.",
+		},
+		[]string{"metric"},
+	)
+	if err := registerer.Register(rejectedPoints); err != nil {
+		klog.ErrorS(err, "Failed to register the rejected metric points counter")
+	}
+
+	return &prometheusMetrics{
+		namespace:        namespace,
+		registerer:       registerer,
+		collectors:       map[string]*prometheusCollector{},
+		metricNames:      map[string]string{},
+		rejectedPoints:   rejectedPoints,
+		loggedRejections: sets.New[string](),
+	}
+}
+
+type metricKind string
+
+const (
+	metricKindGauge     metricKind = "gauge"
+	metricKindCounter   metricKind = "counter"
+	metricKindHistogram metricKind = "histogram"
+)
+
+type prometheusCollector struct {
+	kind       metricKind
+	labelNames []string
+
+	gauge     *prometheus.GaugeVec
+	counter   *prometheus.CounterVec
+	histogram *prometheus.HistogramVec
+}
+
+type prometheusMetrics struct {
+	namespace  string
+	registerer prometheus.Registerer
+
+	lock       sync.Mutex
+	collectors map[string]*prometheusCollector
+	// metricNames maps the exported names of the registered metrics to their stats names to detect stats names that
+	// collide after sanitization.
+	metricNames map[string]string
+
+	rejectedPoints *prometheus.CounterVec
+	// loggedRejections are the stats names whose rejected points were logged.
+	loggedRejections sets.Set[string]
+}
+
+var _ Metrics = &prometheusMetrics{}
+
+func (s *prometheusMetrics) Store(name string, val interface{}, tags ...Tag) error {
+	value, err := toFloat64(val)
+	if err != nil {
+		return s.reject(name, err)
+	}
+
+	collector, labels, err := s.getCollector(name, metricKindGauge, tags)
+	if err != nil {
+		return s.reject(name, err)
+	}
+	collector.gauge.With(labels).Set(value)
+	return nil
+}
+
+func (s *prometheusMetrics) Counter(name string, val interface{}, tags ...Tag) error {
+	value, err := toFloat64(val)
+	if err != nil {
+		return s.reject(name, err)
+	}
+	if value < 0 {
+		return s.reject(name, fmt.Errorf("counter %q cannot be decreased by %v", name, val))
+	}
+
+	collector, labels, err := s.getCollector(name, metricKindCounter, tags)
+	if err != nil {
+		return s.reject(name, err)
+	}
+	collector.counter.With(labels).Add(value)
+	return nil
+}
+
+// Rate is exported as a counter, the rate should be computed by the query, e.g. with rate() in PromQL.
+func (s *prometheusMetrics) Rate(name string, val interface{}, tags ...Tag) error {
+	return s.Counter(name, val, tags...)
+}
+
+func (s *prometheusMetrics) Timer(name string, val interface{}, tags ...Tag) error {
+	value, err := toFloat64(val)
+	if err != nil {
+		return s.reject(name, err)
+	}
+
+	collector, labels, err := s.getCollector(name, metricKindHistogram, tags)
+	if err != nil {
+		return s.reject(name, err)
+	}
+	collector.histogram.With(labels).Observe(value)
+	return nil
+}
+
+func (s *prometheusMetrics) Duration(name string, start time.Time, tags ...Tag) error {
+	duration := time.Since(start).Milliseconds()
+	key := fmt.Sprintf("%s.ms", name)
+	return s.Timer(key, duration, tags...)
+}
+
+// reject counts a stats point that could not be recorded and logs the first rejection of each stats name, since
+// callers commonly discard the errors of the Metrics interface.
+func (s *prometheusMetrics) reject(name string, err error) error {
+	s.rejectedPoints.WithLabelValues(name).Inc()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.loggedRejections.Has(name) {
+		s.loggedRejections.Insert(name)
+		klog.ErrorS(err, "Failed to record metric, further failures of this metric are only counted", "metric", name)
+	}
+	return err
+}
+
+// getCollector returns the collector for the given name, registering it on first use, together with the labels
+// built from the given tags.
+func (s *prometheusMetrics) getCollector(
+	name string,
+	kind metricKind,
+	tags []Tag,
+) (*prometheusCollector, prometheus.Labels, error) {
+	labels := make(prometheus.Labels, len(tags))
+	tagNames := make(map[string]string, len(tags))
+	labelNames := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		labelName := sanitizeMetricName(tag.Name)
+		if tagName, exists := tagNames[labelName]; exists {
+			if tagName != tag.Name {
+				return nil, nil, fmt.Errorf("tags %q and %q of metric %q are both exported as %q", tagName, tag.Name, name, labelName)
+			}
+		} else {
+			tagNames[labelName] = tag.Name
+			labelNames = append(labelNames, labelName)
+		}
+		labels[labelName] = tag.Value
+	}
+	sort.Strings(labelNames)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if collector, exists := s.collectors[name]; exists {
+		if collector.kind != kind {
+			return nil, nil, fmt.Errorf("metric %q is already recorded as a %s", name, collector.kind)
+		}
+		for _, labelName := range labelNames {
+			if !slices.Contains(collector.labelNames, labelName) {
+				return nil, nil, fmt.Errorf(
+					"metric %q is already recorded with tags %v, got %v",
+					name,
+					collector.labelNames,
+					labelNames,
+				)
+			}
+		}
+		// Tags that are omitted are recorded as empty labels.
+		for _, labelName := range collector.labelNames {
+			if _, exists := labels[labelName]; !exists {
+				labels[labelName] = ""
+			}
+		}
+		return collector, labels, nil
+	}
+
+	metricName := sanitizeMetricName(name)
+	if existingName, exists := s.metricNames[metricName]; exists {
+		return nil, nil, fmt.Errorf("metrics %q and %q are both exported as %q", existingName, name, metricName)
+	}
+
+	collector := &prometheusCollector{kind: kind, labelNames: labelNames}
+	help := fmt.Sprintf("KubeAdmiral %s %s.", kind, name)
+
+	var toRegister prometheus.Collector
+	switch kind {
+	case metricKindGauge:
+		collector.gauge = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{Namespace: s.namespace, Name: metricName, Help: help},
+			labelNames,
+		)
+		toRegister = collector.gauge
+	case metricKindCounter:
+		collector.counter = prometheus.NewCounterVec(
+			prometheus.CounterOpts{Namespace: s.namespace, Name: metricName, Help: help},
+			labelNames,
+		)
+		toRegister = collector.counter
+	case metricKindHistogram:
+		collector.histogram = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{Namespace: s.namespace, Name: metricName, Help: help, Buckets: DefaultTimerBuckets},
+			labelNames,
+		)
+		toRegister = collector.histogram
+	}
+
+	if err := s.registerer.Register(toRegister); err != nil {
+		return nil, nil, fmt.Errorf("failed to register metric %q: %w", name, err)
+	}
+	s.collectors[name] = collector
+	s.metricNames[metricName] = name
+
+	return collector, labels, nil
+}
+
+// sanitizeMetricName converts a stats name or tag name into a valid prometheus metric or label name.
+func sanitizeMetricName(name string) string {
+	var sb strings.Builder
+	sb.Grow(len(name))
+
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+
+	return sb.String()
+}
+
+func toFloat64(val interface{}) (float64, error) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), nil
+	case int8:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case uint8:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case time.Duration:
+		return float64(v.Milliseconds()), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("unsupported metric value type %T", val)
+	}
+}
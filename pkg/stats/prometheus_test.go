@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+	metrics := NewPrometheus("kubeadmiral", registry)
+
+	assert.NoError(metrics.Store("totalsync.throughput", 3))
+	assert.NoError(metrics.Store("totalsync.throughput", int64(5)))
+	assert.NoError(metrics.Counter("sync.count", 1, Tag{Name: "type", Value: "deployments"}))
+	assert.NoError(metrics.Counter("sync.count", 2, Tag{Name: "type", Value: "deployments"}))
+	assert.NoError(metrics.Rate("sync.rate", 1.5))
+	assert.NoError(metrics.Timer("totalsync.latency.ms", int64(120), Tag{Name: "resourcename", Value: "default/foo"}))
+	assert.NoError(metrics.Duration("reconcile", time.Now(), Tag{Name: "controller", Value: "sync"}))
+
+	expected := `
+# HELP kubeadmiral_sync_count KubeAdmiral counter sync.count.
+# TYPE kubeadmiral_sync_count counter
+kubeadmiral_sync_count{type="deployments"} 3
+# HELP kubeadmiral_totalsync_throughput KubeAdmiral gauge totalsync.throughput.
+# TYPE kubeadmiral_totalsync_throughput gauge
+kubeadmiral_totalsync_throughput 5
+`
+	assert.NoError(testutil.GatherAndCompare(
+		registry,
+		strings.NewReader(expected),
+		"kubeadmiral_sync_count",
+		"kubeadmiral_totalsync_throughput",
+	))
+
+	count, err := testutil.GatherAndCount(registry, "kubeadmiral_totalsync_latency_ms", "kubeadmiral_reconcile_ms")
+	assert.NoError(err)
+	assert.Equal(2, count)
+}
+
+func TestPrometheusMetricsErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+	metrics := NewPrometheus("kubeadmiral", registry)
+
+	assert.NoError(metrics.Store("queue.length", 1, Tag{Name: "controller", Value: "sync"}, Tag{Name: "ftc", Value: "a"}))
+	assert.NoError(metrics.Store("queue.length", 2, Tag{Name: "controller", Value: "status"}))
+	assert.Error(metrics.Store("queue.length", 1, Tag{Name: "kind", Value: "Deployment"}))
+	assert.Error(metrics.Counter("queue.length", 1, Tag{Name: "controller", Value: "sync"}))
+	assert.Error(metrics.Counter("retries", -1))
+	assert.Error(metrics.Store("invalid", "1"))
+	assert.Error(metrics.Store("queue_length", 1))
+	assert.Error(metrics.Store("tags", 1, Tag{Name: "a.b", Value: "1"}, Tag{Name: "a_b", Value: "2"}))
+
+	expected := `
+# HELP kubeadmiral_queue_length KubeAdmiral gauge queue.length.
+# TYPE kubeadmiral_queue_length gauge
+kubeadmiral_queue_length{controller="status",ftc=""} 2
+kubeadmiral_queue_length{controller="sync",ftc="a"} 1
+# HELP kubeadmiral_rejected_metric_points_total KubeAdmiral stats points that could not be recorded, by stats name.
+# TYPE kubeadmiral_rejected_metric_points_total counter
+kubeadmiral_rejected_metric_points_total{metric="invalid"} 1
+kubeadmiral_rejected_metric_points_total{metric="queue.length"} 2
+kubeadmiral_rejected_metric_points_total{metric="queue_length"} 1
+kubeadmiral_rejected_metric_points_total{metric="retries"} 1
+kubeadmiral_rejected_metric_points_total{metric="tags"} 1
+`
+	assert.NoError(testutil.GatherAndCompare(
+		registry,
+		strings.NewReader(expected),
+		"kubeadmiral_queue_length",
+		"kubeadmiral_rejected_metric_points_total",
+	))
+}
+
+func TestSanitizeMetricName(t *testing.T) {
+	testCases := map[string]string{
+		"totalsync.latency.ms":          "totalsync_latency_ms",
+		"delayingDeliverer.queueLength": "delayingDeliverer_queueLength",
+		"5min-latency":                  "_5min_latency",
+	}
+
+	for name, expected := range testCases {
+		if actual := sanitizeMetricName(name); actual != expected {
+			t.Errorf("sanitizeMetricName(%q) = %q, want %q", name, actual, expected)
+		}
+	}
+}
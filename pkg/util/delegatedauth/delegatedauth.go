@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package delegatedauth authenticates and authorizes HTTP requests by delegating to the apiserver of the host
+// cluster with TokenReviews and SubjectAccessReviews.
+package delegatedauth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// WithAuth returns a handler that only passes requests to handler if their bearer token is authenticated by a
+// TokenReview and the user is allowed to access the request path with the lowercase request method as verb by a
+// SubjectAccessReview of the non-resource URL, e.g. verb "post" for path "/scheduler/simulate/*".
+func WithAuth(handler http.Handler, client kubernetes.Interface) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		token, found := bearerToken(request)
+		if !found {
+			http.Error(writer, "bearer token is required", http.StatusUnauthorized)
+			return
+		}
+
+		tokenReview, err := client.AuthenticationV1().TokenReviews().Create(
+			request.Context(),
+			&authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}},
+			metav1.CreateOptions{},
+		)
+		if err != nil {
+			klog.Errorf("Failed to review token: %v", err)
+			http.Error(writer, "failed to authenticate request", http.StatusInternalServerError)
+			return
+		}
+		if !tokenReview.Status.Authenticated {
+			http.Error(writer, "invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		user := tokenReview.Status.User
+		extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+		for key, value := range user.Extra {
+			extra[key] = authorizationv1.ExtraValue(value)
+		}
+		accessReview, err := client.AuthorizationV1().SubjectAccessReviews().Create(
+			request.Context(),
+			&authorizationv1.SubjectAccessReview{
+				Spec: authorizationv1.SubjectAccessReviewSpec{
+					User:   user.Username,
+					Groups: user.Groups,
+					UID:    user.UID,
+					Extra:  extra,
+					NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+						Path: request.URL.Path,
+						Verb: strings.ToLower(request.Method),
+					},
+				},
+			},
+			metav1.CreateOptions{},
+		)
+		if err != nil {
+			klog.Errorf("Failed to review access of user %q: %v", user.Username, err)
+			http.Error(writer, "failed to authorize request", http.StatusInternalServerError)
+			return
+		}
+		if !accessReview.Status.Allowed {
+			http.Error(
+				writer,
+				fmt.Sprintf("user %q cannot %s path %q", user.Username, request.Method, request.URL.Path),
+				http.StatusForbidden,
+			)
+			return
+		}
+
+		handler.ServeHTTP(writer, request)
+	})
+}
+
+func bearerToken(request *http.Request) (string, bool) {
+	header := request.Header.Get("Authorization")
+	token := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	if !strings.HasPrefix(header, "Bearer ") || len(token) == 0 {
+		return "", false
+	}
+	return token, true
+}
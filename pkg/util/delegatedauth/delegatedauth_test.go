@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delegatedauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func newFakeClient() *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		review := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		switch review.Spec.Token {
+		case "admin-token":
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: "admin"},
+			}
+		case "viewer-token":
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: "viewer"},
+			}
+		}
+		return true, review, nil
+	})
+	client.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := review.Spec.NonResourceAttributes
+		review.Status.Allowed = review.Spec.User == "admin" && attributes != nil &&
+			attributes.Verb == "post" && attributes.Path == "/scheduler/simulate/deployments.apps"
+		return true, review, nil
+	})
+	return client
+}
+
+func TestWithAuth(t *testing.T) {
+	handler := WithAuth(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}), newFakeClient())
+
+	testCases := map[string]struct {
+		authorization  string
+		expectedStatus int
+	}{
+		"missing token is rejected": {
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"invalid token is rejected": {
+			authorization:  "Bearer unknown-token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"unauthorized user is rejected": {
+			authorization:  "Bearer viewer-token",
+			expectedStatus: http.StatusForbidden,
+		},
+		"authorized user is allowed": {
+			authorization:  "Bearer admin-token",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/scheduler/simulate/deployments.apps", nil)
+			if tc.authorization != "" {
+				request.Header.Set("Authorization", tc.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}